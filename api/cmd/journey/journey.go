@@ -2,14 +2,25 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
 	"errors"
+	"expvar"
 	"fmt"
 	"journey/internal/api"
 	"journey/internal/api/spec"
+	"journey/internal/mailer"
+	"journey/internal/mailer/logmailer"
 	"journey/internal/mailer/mailpit"
+	"journey/internal/mailer/outbox"
+	"journey/internal/mailer/sendgrid"
+	"journey/internal/memstore"
+	"journey/internal/pgstore"
+	"journey/internal/sqlitestore"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
 
@@ -28,49 +39,416 @@ func main() {
 	ctx, cancel := signal.NotifyContext(ctx, os.Interrupt, os.Kill, syscall.SIGTERM, syscall.SIGKILL)
 	defer cancel()
 
-	if err := run(ctx); err != nil {
+	var err error
+	switch args := os.Args[1:]; {
+	case len(args) == 0:
+		err = run(ctx)
+	case len(args) == 1 && args[0] == "migrate":
+		err = migrate(ctx)
+	default:
+		err = errors.New("usage: journey [migrate]")
+	}
+	if err != nil {
 		fmt.Fprintln(os.Stderr, err.Error())
 		os.Exit(1)
 	}
 	fmt.Println("goodbye :)")
 }
 
-func run(ctx context.Context) error {
+// migrate runs the migrations the database is missing and exits, for the
+// deployments that migrate explicitly rather than with JOURNEY_AUTO_MIGRATE.
+func migrate(ctx context.Context) error {
+	logger, err := newLogger()
+	if err != nil {
+		return err
+	}
+	defer logger.Sync()
+
+	pool, err := newPool(ctx, databaseURL(), logger)
+	if err != nil {
+		return err
+	}
+	defer pool.Close()
+
+	if err := waitForDatabase(ctx, pool, logger); err != nil {
+		return err
+	}
+
+	return pgstore.Migrate(ctx, pool, logger)
+}
+
+const (
+	defaultConnectAttempts  = 5
+	defaultConnectBaseDelay = time.Second
+)
+
+// stores are what the API, the mailer and the outbox read and write.
+type stores struct {
+	api    api.Store
+	mailer mailer.Store
+	outbox outbox.Store
+}
+
+// openPostgres connects to the database once it answers, and to its read
+// replica if there is one, migrating it first with JOURNEY_AUTO_MIGRATE. close releases the
+// connections.
+func openPostgres(ctx context.Context, logger *zap.Logger) (_ stores, _ func(), err error) {
+	pool, err := newPool(ctx, databaseURL(), logger)
+	if err != nil {
+		return stores{}, nil, err
+	}
+	closers := []func(){pool.Close}
+	closeAll := func() {
+		for _, c := range closers {
+			c()
+		}
+	}
+	defer func() {
+		if err != nil {
+			closeAll()
+		}
+	}()
+
+	if err := waitForDatabase(ctx, pool, logger); err != nil {
+		return stores{}, nil, err
+	}
+
+	autoMigrate := false
+	if value := os.Getenv("JOURNEY_AUTO_MIGRATE"); value != "" {
+		autoMigrate, err = strconv.ParseBool(value)
+		if err != nil {
+			return stores{}, nil, fmt.Errorf("invalid JOURNEY_AUTO_MIGRATE %q: must be true or false", value)
+		}
+	}
+
+	// Starting on a schema that's behind fails on the first missing table, so
+	// the migrations run before anything else touches the database
+	if autoMigrate {
+		if err := pgstore.Migrate(ctx, pool, logger); err != nil {
+			return stores{}, nil, err
+		}
+	}
+
+	queryTimeout := pgstore.DefaultQueryTimeout
+	if value := os.Getenv("JOURNEY_DATABASE_QUERY_TIMEOUT"); value != "" {
+		queryTimeout, err = time.ParseDuration(value)
+		if err != nil || queryTimeout <= 0 {
+			return stores{}, nil, fmt.Errorf("invalid JOURNEY_DATABASE_QUERY_TIMEOUT %q: must be a positive duration such as 2s", value)
+		}
+	}
+
+	// The read-heavy endpoints are served from a replica when one is set up
+	var readPool *pgxpool.Pool
+	if value := os.Getenv("JOURNEY_DATABASE_READ_URL"); value != "" {
+		readPool, err = newPool(ctx, value, logger)
+		if err != nil {
+			return stores{}, nil, fmt.Errorf("invalid JOURNEY_DATABASE_READ_URL: %w", err)
+		}
+		closers = append(closers, readPool.Close)
+
+		if err := readPool.Ping(ctx); err != nil {
+			return stores{}, nil, fmt.Errorf("failed to reach the read replica: %w", err)
+		}
+	}
+
+	go pgstore.SamplePoolStats(ctx, "primary", pool)
+	if readPool != nil {
+		go pgstore.SamplePoolStats(ctx, "replica", readPool)
+	}
+
+	// The mailer and the outbox run in the background, so they keep going
+	// without the timeout and the replica of the API
+	queries := pgstore.New(pool)
+	return stores{pgstore.NewStore(pool, readPool, queryTimeout), queries, queries}, closeAll, nil
+}
+
+func newLogger() (*zap.Logger, error) {
 	cfg := zap.NewDevelopmentConfig()
 	cfg.EncoderConfig.EncodeLevel = zapcore.CapitalColorLevelEncoder
 
 	logger, err := cfg.Build()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	logger = logger.Named("journey_app")
-	defer logger.Sync()
+	return logger.Named("journey_app"), nil
+}
 
-	pool, err := pgxpool.New(ctx, fmt.Sprintf(
+// databaseURL is the connection string of the database the JOURNEY_DATABASE_*
+// variables point to.
+func databaseURL() string {
+	return fmt.Sprintf(
 		"user=%s password=%s host=%s port=%s dbname=%s",
 		os.Getenv("JOURNEY_DATABASE_USER"),
 		os.Getenv("JOURNEY_DATABASE_PASSWORD"),
 		os.Getenv("JOURNEY_DATABASE_HOST"),
 		os.Getenv("JOURNEY_DATABASE_PORT"),
 		os.Getenv("JOURNEY_DATABASE_NAME"),
-	))
+	)
+}
+
+// newPool connects to the database at connString, measuring the queries it
+// runs and logging them unless JOURNEY_DATABASE_QUERY_LOG is false.
+func newPool(ctx context.Context, connString string, logger *zap.Logger) (*pgxpool.Pool, error) {
+	config, err := pgxpool.ParseConfig(connString)
+	if err != nil {
+		return nil, err
+	}
+
+	if value := os.Getenv("JOURNEY_DATABASE_QUERY_LOG"); value != "" {
+		queryLog, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JOURNEY_DATABASE_QUERY_LOG %q: must be true or false", value)
+		}
+		if !queryLog {
+			logger = nil
+		}
+	}
+	slowThreshold := pgstore.DefaultSlowQueryThreshold
+	if value := os.Getenv("JOURNEY_DATABASE_SLOW_QUERY_THRESHOLD"); value != "" {
+		slowThreshold, err = time.ParseDuration(value)
+		if err != nil || slowThreshold < 0 {
+			return nil, fmt.Errorf("invalid JOURNEY_DATABASE_SLOW_QUERY_THRESHOLD %q: must be a duration such as 500ms", value)
+		}
+	}
+	config.ConnConfig.Tracer = pgstore.NewQueryTracer(logger, slowThreshold)
+
+	return pgxpool.NewWithConfig(ctx, config)
+}
+
+// waitForDatabase pings the database until it answers, as it may still be
+// starting along with the app. It's tried JOURNEY_DATABASE_CONNECT_ATTEMPTS
+// times, waiting JOURNEY_DATABASE_CONNECT_BASE_DELAY after the first failure,
+// doubled after every following one.
+func waitForDatabase(ctx context.Context, pool *pgxpool.Pool, logger *zap.Logger) error {
+	var err error
+	attempts := defaultConnectAttempts
+	if value := os.Getenv("JOURNEY_DATABASE_CONNECT_ATTEMPTS"); value != "" {
+		attempts, err = strconv.Atoi(value)
+		if err != nil || attempts < 1 {
+			return fmt.Errorf("invalid JOURNEY_DATABASE_CONNECT_ATTEMPTS %q: must be a positive integer", value)
+		}
+	}
+	delay := defaultConnectBaseDelay
+	if value := os.Getenv("JOURNEY_DATABASE_CONNECT_BASE_DELAY"); value != "" {
+		delay, err = time.ParseDuration(value)
+		if err != nil || delay < 0 {
+			return fmt.Errorf("invalid JOURNEY_DATABASE_CONNECT_BASE_DELAY %q: must be a duration such as 1s", value)
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		err = pool.Ping(ctx)
+		if err == nil {
+			return nil
+		}
+		if attempt >= attempts {
+			break
+		}
+
+		logger.Warn("Failed to reach the database, retrying",
+			zap.Error(err),
+			zap.Int("attempt", attempt),
+			zap.Int("attempts", attempts),
+			zap.Duration("retry_in", delay),
+		)
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("gave up reaching the database after %d attempts on shutdown: %w", attempt, err)
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+
+	return fmt.Errorf("failed to reach the database after %d attempts: %w", attempts, err)
+}
+
+func run(ctx context.Context) error {
+	logger, err := newLogger()
 	if err != nil {
 		return err
 	}
-	defer pool.Close()
+	defer logger.Sync()
+
+	var backend stores
+	switch kind := os.Getenv("JOURNEY_STORE"); kind {
+	case "", "postgres":
+		var closeStores func()
+		backend, closeStores, err = openPostgres(ctx, logger)
+		if err != nil {
+			return err
+		}
+		defer closeStores()
+	case "memory":
+		// Nothing is kept once the process stops, which is enough to try the API
+		logger.Warn("Keeping the trips in memory, they are lost on shutdown")
+		store := memstore.New()
+		backend = stores{api: store, mailer: store, outbox: store}
+	case "sqlite":
+		path := os.Getenv("JOURNEY_SQLITE_PATH")
+		if path == "" {
+			path = "journey.db"
+		}
+		store, err := sqlitestore.Open(ctx, path)
+		if err != nil {
+			return err
+		}
+		defer store.Close()
+		logger.Info("Keeping the trips in SQLite", zap.String("path", path))
+		backend = stores{api: store, mailer: store, outbox: store}
+	default:
+		return fmt.Errorf("invalid JOURNEY_STORE %q: must be postgres, sqlite or memory", kind)
+	}
+
+	maxParticipants := api.DefaultMaxParticipants
+	if value := os.Getenv("JOURNEY_MAX_PARTICIPANTS_PER_TRIP"); value != "" {
+		maxParticipants, err = strconv.Atoi(value)
+		if err != nil || maxParticipants <= 0 {
+			return fmt.Errorf("invalid JOURNEY_MAX_PARTICIPANTS_PER_TRIP %q: must be a positive integer", value)
+		}
+	}
+
+	maxTripDays := api.DefaultMaxTripDays
+	if value := os.Getenv("JOURNEY_MAX_TRIP_DURATION_DAYS"); value != "" {
+		maxTripDays, err = strconv.Atoi(value)
+		if err != nil || maxTripDays <= 0 {
+			return fmt.Errorf("invalid JOURNEY_MAX_TRIP_DURATION_DAYS %q: must be a positive integer", value)
+		}
+	}
+
+	maxInvitesPerRequest := api.DefaultMaxInvitesPerRequest
+	if value := os.Getenv("JOURNEY_MAX_INVITES_PER_REQUEST"); value != "" {
+		maxInvitesPerRequest, err = strconv.Atoi(value)
+		if err != nil || maxInvitesPerRequest <= 0 {
+			return fmt.Errorf("invalid JOURNEY_MAX_INVITES_PER_REQUEST %q: must be a positive integer", value)
+		}
+	}
+
+	notifyTripUpdates := true
+	if value := os.Getenv("JOURNEY_NOTIFY_TRIP_UPDATES"); value != "" {
+		notifyTripUpdates, err = strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid JOURNEY_NOTIFY_TRIP_UPDATES %q: must be true or false", value)
+		}
+	}
+
+	// The instances behind the same load balancer must share the key, or the
+	// cursors one of them signs are rejected by the others
+	cursorKey := []byte(os.Getenv("JOURNEY_CURSOR_KEY"))
+	switch {
+	case len(cursorKey) == 0:
+		cursorKey = make([]byte, 32)
+		if _, err := rand.Read(cursorKey); err != nil {
+			return fmt.Errorf("failed to generate the cursor key: %w", err)
+		}
+		logger.Warn("JOURNEY_CURSOR_KEY is not set, the GET /trips cursors are only valid until a restart")
+	case len(cursorKey) < 32:
+		return errors.New("invalid JOURNEY_CURSOR_KEY: must be at least 32 characters")
+	}
+
+	mailerConfig := mailer.DefaultConfig()
+	if value := os.Getenv("JOURNEY_MAIL_FROM_ADDRESS"); value != "" {
+		mailerConfig.FromAddress = value
+	}
+	if value := os.Getenv("JOURNEY_MAIL_FROM_NAME"); value != "" {
+		mailerConfig.FromName = value
+	}
+	if value := os.Getenv("JOURNEY_PUBLIC_URL"); value != "" {
+		mailerConfig.PublicURL = value
+	}
+
+	outboxConfig := outbox.DefaultConfig()
+	if value := os.Getenv("JOURNEY_OUTBOX_POLL_INTERVAL"); value != "" {
+		outboxConfig.PollInterval, err = time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid JOURNEY_OUTBOX_POLL_INTERVAL %q: must be a duration such as 5s", value)
+		}
+	}
+	if value := os.Getenv("JOURNEY_MAIL_WORKERS"); value != "" {
+		outboxConfig.Workers, err = strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid JOURNEY_MAIL_WORKERS %q: must be an integer", value)
+		}
+	}
+	if value := os.Getenv("JOURNEY_MAIL_QUEUE_SIZE"); value != "" {
+		outboxConfig.QueueSize, err = strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid JOURNEY_MAIL_QUEUE_SIZE %q: must be an integer", value)
+		}
+	}
+	if value := os.Getenv("JOURNEY_OUTBOX_MAX_ATTEMPTS"); value != "" {
+		outboxConfig.MaxAttempts, err = strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid JOURNEY_OUTBOX_MAX_ATTEMPTS %q: must be an integer", value)
+		}
+	}
+	if err := outboxConfig.Validate(); err != nil {
+		return err
+	}
+
+	// JOURNEY_MAIL_WORKERS is how many emails are sent at once, by the outbox
+	// as well as to the participants of a trip within one of its emails
+	if os.Getenv("JOURNEY_MAIL_CONCURRENCY") != "" {
+		logger.Warn("JOURNEY_MAIL_CONCURRENCY is no longer read, JOURNEY_MAIL_WORKERS sets how many emails are sent at once")
+	}
+	mailerConfig.Concurrency = outboxConfig.Workers
+	if err := mailerConfig.Validate(); err != nil {
+		return err
+	}
+
+	rateLimitConfig := mailer.DefaultRateLimitConfig()
+	if value := os.Getenv("JOURNEY_MAIL_TRIP_RATE_LIMIT"); value != "" {
+		rateLimitConfig.Limit, err = strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("invalid JOURNEY_MAIL_TRIP_RATE_LIMIT %q: must be an integer", value)
+		}
+	}
+	if value := os.Getenv("JOURNEY_MAIL_TRIP_RATE_WINDOW"); value != "" {
+		rateLimitConfig.Window, err = time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("invalid JOURNEY_MAIL_TRIP_RATE_WINDOW %q: must be a duration such as 1h", value)
+		}
+	}
+	if err := rateLimitConfig.Validate(); err != nil {
+		return err
+	}
+
+	provider := os.Getenv("JOURNEY_MAILER")
+	if provider == "" {
+		provider = "mailpit"
+	}
+	deliverer, err := newDeliverer(provider, logger)
+	if err != nil {
+		return err
+	}
+	// Every provider is counted the same way, see /debug/vars
+	deliverer = mailer.NewInstrumentedDeliverer(provider, deliverer)
+
+	// Sending outlives ctx so the queued emails are drained on shutdown,
+	// mailCtx is canceled once the shutdown window is over
+	mailCtx, cancelMail := context.WithCancel(context.Background())
+	defer cancelMail()
 
-	if err := pool.Ping(ctx); err != nil {
+	mail, err := mailer.NewMailer(mailCtx, backend.mailer, logger, mailerConfig, deliverer)
+	if err != nil {
 		return err
 	}
 
-	mailer := mailpit.NewMailpit(pool)
+	// The emails enqueued by the API are sent in the background until shutdown
+	dispatcher := outbox.NewDispatcher(backend.outbox, mail, logger, outboxConfig)
+	dispatcher.Start(mailCtx)
 
-	si := api.NewAPI(pool, logger, mailer)
+	si := api.NewAPI(backend.api, logger, maxParticipants, maxTripDays, maxInvitesPerRequest, notifyTripUpdates, mailer.NewRateLimiter(rateLimitConfig), cursorKey)
 
 	r := chi.NewRouter()
 	r.Use(middleware.RequestID, middleware.Recoverer, httputils.ChiLogger(logger))
-	r.Mount("/", spec.Handler(si))
+	r.Mount("/", spec.Handler(si, spec.WithErrorHandler(api.ParamErrorHandler)))
+
+	// Setup the metrics, the mailer's are under "mailer" and the database's
+	// under "pgstore"
+	r.Handle("/debug/vars", expvar.Handler())
 
 	// Setup Swagger UI
 	r.Get("/swagger.json", func(w http.ResponseWriter, r *http.Request) {
@@ -105,6 +483,10 @@ func run(ctx context.Context) error {
 		WriteTimeout: 5 * time.Second,	
 	}
 
+	// The event streams never end on their own, Shutdown would wait for them
+	// until its deadline
+	srv.RegisterOnShutdown(si.Shutdown)
+
 	defer func() {
 		const timeout = 30 * time.Second
 		ctx, cancel := context.WithTimeout(context.Background(), timeout)
@@ -113,6 +495,14 @@ func run(ctx context.Context) error {
 		if err := srv.Shutdown(ctx); err != nil {
 			logger.Error("Failed to shutdown server", zap.Error(err))
 		}
+
+		// The queued emails get a window of their own, however long the
+		// requests took
+		const mailTimeout = 15 * time.Second
+		stopCtx, cancelStop := context.WithTimeout(context.Background(), mailTimeout)
+		defer cancelStop()
+
+		dispatcher.Stop(stopCtx)
 	}()
 
 	errChan := make(chan error, 1)
@@ -134,4 +524,62 @@ func run(ctx context.Context) error {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// newDeliverer sets up the email provider named by JOURNEY_MAILER, mailpit when
+// it isn't set, from its own variables.
+func newDeliverer(provider string, logger *zap.Logger) (mailer.Deliverer, error) {
+	var err error
+	switch provider {
+	case "", "mailpit":
+		mailpitConfig := mailpit.DefaultMailpitConfig()
+		if value := os.Getenv("JOURNEY_SMTP_HOST"); value != "" {
+			mailpitConfig.Host = value
+		}
+		if value := os.Getenv("JOURNEY_SMTP_PORT"); value != "" {
+			mailpitConfig.Port, err = strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid JOURNEY_SMTP_PORT %q: must be an integer", value)
+			}
+		}
+		mailpitConfig.Username = os.Getenv("JOURNEY_SMTP_USERNAME")
+		mailpitConfig.Password = os.Getenv("JOURNEY_SMTP_PASSWORD")
+		mailpitConfig.AuthMechanism = strings.ToUpper(os.Getenv("JOURNEY_SMTP_AUTH"))
+		if value := os.Getenv("JOURNEY_SMTP_TLS"); value != "" {
+			mailpitConfig.TLSPolicy = strings.ToLower(value)
+		}
+		if value := os.Getenv("JOURNEY_SMTP_RETRY_ATTEMPTS"); value != "" {
+			mailpitConfig.RetryAttempts, err = strconv.Atoi(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid JOURNEY_SMTP_RETRY_ATTEMPTS %q: must be an integer", value)
+			}
+		}
+		if value := os.Getenv("JOURNEY_SMTP_RETRY_BASE_DELAY"); value != "" {
+			mailpitConfig.RetryBaseDelay, err = time.ParseDuration(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid JOURNEY_SMTP_RETRY_BASE_DELAY %q: must be a duration such as 1s", value)
+			}
+		}
+		if err := mailpitConfig.Validate(); err != nil {
+			return nil, err
+		}
+
+		return mailpit.NewMailpit(mailpitConfig), nil
+	case "sendgrid":
+		sendGridConfig := sendgrid.DefaultSendGridConfig()
+		sendGridConfig.APIKey = os.Getenv("JOURNEY_SENDGRID_API_KEY")
+		if value := os.Getenv("JOURNEY_SENDGRID_BASE_URL"); value != "" {
+			sendGridConfig.BaseURL = value
+		}
+		if err := sendGridConfig.Validate(); err != nil {
+			return nil, err
+		}
+
+		return sendgrid.NewSendGrid(sendGridConfig), nil
+	case "log":
+		// Without JOURNEY_MAIL_LOG_DIR the emails only go to the logs
+		return logmailer.NewLogMailer(logger, os.Getenv("JOURNEY_MAIL_LOG_DIR"))
+	default:
+		return nil, fmt.Errorf("invalid JOURNEY_MAILER %q: must be mailpit, sendgrid or log", provider)
+	}
+}
@@ -3,4 +3,31 @@ set JOURNEY_DATABASE_HOST=localhost
 set JOURNEY_DATABASE_PORT=5432
 set JOURNEY_DATABASE_NAME=journey
 set JOURNEY_DATABASE_USER=postgres
-set JOURNEY_DATABASE_PASSWORD=123456789
\ No newline at end of file
+set JOURNEY_DATABASE_PASSWORD=123456789
+set JOURNEY_DATABASE_QUERY_TIMEOUT=2s
+set JOURNEY_DATABASE_CONNECT_ATTEMPTS=5
+set JOURNEY_DATABASE_CONNECT_BASE_DELAY=1s
+set JOURNEY_DATABASE_QUERY_LOG=true
+set JOURNEY_DATABASE_SLOW_QUERY_THRESHOLD=500ms
+set JOURNEY_MAX_PARTICIPANTS_PER_TRIP=100
+set JOURNEY_MAX_TRIP_DURATION_DAYS=90
+set JOURNEY_MAX_INVITES_PER_REQUEST=50
+set JOURNEY_NOTIFY_TRIP_UPDATES=true
+set JOURNEY_AUTO_MIGRATE=true
+set JOURNEY_STORE=postgres
+set JOURNEY_SQLITE_PATH=journey.db
+set JOURNEY_MAILER=mailpit
+set JOURNEY_SMTP_HOST=localhost
+set JOURNEY_SMTP_PORT=1025
+set JOURNEY_MAIL_FROM_ADDRESS=mailpit@journey.com
+set JOURNEY_MAIL_FROM_NAME=plann.er
+set JOURNEY_SMTP_TLS=none
+set JOURNEY_SMTP_RETRY_ATTEMPTS=3
+set JOURNEY_SMTP_RETRY_BASE_DELAY=1s
+set JOURNEY_MAIL_TRIP_RATE_LIMIT=60
+set JOURNEY_MAIL_TRIP_RATE_WINDOW=1h
+set JOURNEY_OUTBOX_POLL_INTERVAL=5s
+set JOURNEY_MAIL_WORKERS=4
+set JOURNEY_MAIL_QUEUE_SIZE=50
+set JOURNEY_OUTBOX_MAX_ATTEMPTS=5
+set JOURNEY_PUBLIC_URL=http://localhost:8080
\ No newline at end of file
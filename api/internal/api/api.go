@@ -4,411 +4,2283 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"html/template"
+	"io"
 	"journey/internal/api/spec"
+	"journey/internal/events"
+	"journey/internal/locales"
+	"journey/internal/mailer"
+	"journey/internal/ics"
+	"journey/internal/itinerary"
 	"journey/internal/pgstore"
+	"math"
 	"net/http"
 	"slices"
+	"strconv"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/discord-gophers/goapi-gen/types"
 	"github.com/go-playground/validator/v10"
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
-	"github.com/jackc/pgx/v5/pgxpool"
 	"go.uber.org/zap"
 )
 
-type store interface {
-	CreateTrip(ctx context.Context, pool *pgxpool.Pool, params spec.CreateTripRequest) (uuid.UUID, error)
+// Store is what the API reads and writes, the Postgres pgstore.Store or the
+// in-memory memstore.Store. The handlers depend on the part of it about what
+// they handle, so a fake only has to implement that part.
+type Store interface {
+	TripStore
+	ParticipantStore
+	ActivityStore
+	LinkStore
+	EmailStore
+}
+
+// TripStore is what the API reads and writes about the trips.
+type TripStore interface {
+	CreateTrip(ctx context.Context, params spec.CreateTripRequest) (uuid.UUID, error)
+	DuplicateTrip(ctx context.Context, tripID uuid.UUID, offsetDays int) (uuid.UUID, error)
+	ExportTrip(ctx context.Context, tripID uuid.UUID) (pgstore.TripExport, error)
 	GetTrip(ctx context.Context, id uuid.UUID) (pgstore.Trip, error)
-	GetAllTrips(ctx context.Context) ([]pgstore.Trip, error)
-	UpdateTrip(ctx context.Context, arg pgstore.UpdateTripParams) error
+	GetTripDetails(ctx context.Context, id uuid.UUID) (pgstore.Trip, error)
+	GetTripSummary(ctx context.Context, id uuid.UUID) (pgstore.GetTripSummaryRow, error)
+	ListTrips(ctx context.Context, arg pgstore.ListTripsParams) ([]pgstore.Trip, error)
+	ListTripsAfter(ctx context.Context, arg pgstore.ListTripsAfterParams) ([]pgstore.Trip, error)
+	CountTrips(ctx context.Context, arg pgstore.CountTripsParams) (int64, error)
+	SearchTrips(ctx context.Context, arg pgstore.SearchTripsParams) ([]pgstore.Trip, error)
+	CountSearchTrips(ctx context.Context, arg pgstore.CountSearchTripsParams) (int64, error)
+	ListTripsByParticipantEmail(ctx context.Context, arg pgstore.ListTripsByParticipantEmailParams) ([]pgstore.ListTripsByParticipantEmailRow, error)
+	CountTripsByParticipantEmail(ctx context.Context, email string) (int64, error)
+	UpdateTrip(ctx context.Context, arg pgstore.UpdateTripParams) (int64, error)
+	UpdateTripAndNotify(ctx context.Context, params pgstore.UpdateTripParams, changes *pgstore.TripChanges) (int64, error)
+	SetTripArchived(ctx context.Context, arg pgstore.SetTripArchivedParams) error
+	ConfirmTripAndInvite(ctx context.Context, tripID uuid.UUID) (int64, error)
+	CancelTripAndNotify(ctx context.Context, tripID uuid.UUID) (int64, error)
+	DeleteTrip(ctx context.Context, id uuid.UUID) error
+}
+
+// ParticipantStore is what the API reads and writes about the participants.
+type ParticipantStore interface {
 	GetParticipant(ctx context.Context, participantID uuid.UUID) (pgstore.Participant, error)
-	GetParticipants(ctx context.Context, tripID uuid.UUID) ([]pgstore.Participant, error)
+	GetParticipantWithTrip(ctx context.Context, participantID uuid.UUID) (pgstore.GetParticipantWithTripRow, error)
+	GetParticipantByEmail(ctx context.Context, arg pgstore.GetParticipantByEmailParams) (pgstore.Participant, error)
+	ListParticipants(ctx context.Context, arg pgstore.ListParticipantsParams) ([]pgstore.Participant, error)
+	GetUnconfirmedParticipants(ctx context.Context, arg pgstore.GetUnconfirmedParticipantsParams) ([]pgstore.Participant, error)
+	CountParticipants(ctx context.Context, arg pgstore.CountParticipantsParams) (int64, error)
 	ConfirmParticipant(ctx context.Context, participantID uuid.UUID) error
-	GetTripActivities(ctx context.Context, tripID uuid.UUID) ([]pgstore.Activity, error)
+	ResetParticipantsConfirmation(ctx context.Context, tripID uuid.UUID) error
+	ResendParticipantInvite(ctx context.Context, params pgstore.MarkParticipantInviteResentParams) (int64, error)
+	DeclineParticipant(ctx context.Context, participantID uuid.UUID) error
+	InviteParticipant(ctx context.Context, params pgstore.InviteParticipantToTripParams, notify bool) (uuid.UUID, bool, error)
+	DeleteParticipant(ctx context.Context, participantID uuid.UUID) error
+	UpdateParticipantName(ctx context.Context, arg pgstore.UpdateParticipantNameParams) error
+}
+
+// ActivityStore is what the API reads and writes about the activities.
+type ActivityStore interface {
+	GetTripActivities(ctx context.Context, arg pgstore.GetTripActivitiesParams) ([]pgstore.Activity, error)
+	ListActivitiesOutsideRange(ctx context.Context, arg pgstore.ListActivitiesOutsideRangeParams) ([]pgstore.ListActivitiesOutsideRangeRow, error)
+	GetActivity(ctx context.Context, arg pgstore.GetActivityParams) (pgstore.Activity, error)
 	CreateActivity(ctx context.Context, arg pgstore.CreateActivityParams) (uuid.UUID, error)
+	CreateActivities(ctx context.Context, params []pgstore.CreateActivityParams) ([]uuid.UUID, error)
+}
+
+// LinkStore is what the API reads and writes about the links.
+type LinkStore interface {
+	ListTripLinks(ctx context.Context, arg pgstore.ListTripLinksParams) ([]pgstore.Link, error)
+	CountTripLinks(ctx context.Context, tripID uuid.UUID) (int64, error)
+	GetLink(ctx context.Context, linkID uuid.UUID) (pgstore.Link, error)
+	CreateTripLink(ctx context.Context, arg pgstore.CreateTripLinkParams) (uuid.UUID, error)
+	UpdateLink(ctx context.Context, arg pgstore.UpdateLinkParams) error
 }
 
-type mailer interface {
-	SendConfirmTripEmailToTripOwner(tripID uuid.UUID) error
-	SendConfirmTripEmailToTripParticipants(tripID uuid.UUID) error
+// EmailStore is what the API reads and writes about the emails sent and the
+// addresses that unsubscribed.
+type EmailStore interface {
+	GetEmailByUnsubscribeToken(ctx context.Context, unsubscribeToken uuid.UUID) (string, error)
+	SuppressEmail(ctx context.Context, email string) error
+	IsEmailSuppressed(ctx context.Context, email string) (bool, error)
+	ListSuppressedEmails(ctx context.Context, emails []string) ([]string, error)
+	ListTripEmailLog(ctx context.Context, arg pgstore.ListTripEmailLogParams) ([]pgstore.EmailLog, error)
+	CountTripEmailLog(ctx context.Context, tripID uuid.UUID) (int64, error)
 }
 
+const (
+	defaultTripsLimit = 50
+	maxTripsLimit = 200
+
+	// minSearchQueryLength keeps GET /trips/search from matching, and scanning, every trip.
+	minSearchQueryLength = 2
+
+	defaultParticipantsLimit = 100
+	maxParticipantsLimit = 500
+
+	maxLinksLimit = 200
+
+	defaultEmailsLimit = 50
+	maxEmailsLimit = 200
+
+	// tripExportVersion must be bumped whenever the GET /trips/{tripId}/export format changes.
+	tripExportVersion = 3
+
+	// maxTripEventsSubscribers caps the GET /trips/{tripId}/events streams open on a single trip.
+	maxTripEventsSubscribers = 50
+	// tripEventsHeartbeat keeps proxies from closing idle event streams.
+	tripEventsHeartbeat = 30 * time.Second
+
+	// inviteResendCooldown is how long a participant must wait between two resent invitations.
+	inviteResendCooldown = 5 * time.Minute
+
+	// maxDuplicateOffsetDays is how far POST /trips/{tripId}/duplicate may move a trip, either way,
+	// enough to repeat it for years while keeping the dates in range of the database.
+	maxDuplicateOffsetDays = 10 * 366
+)
+
+// DefaultMaxParticipants is how many participants a trip may have when
+// JOURNEY_MAX_PARTICIPANTS_PER_TRIP isn't set.
+const DefaultMaxParticipants = 100
+
+// DefaultMaxTripDays is how many days a trip may last when
+// JOURNEY_MAX_TRIP_DURATION_DAYS isn't set.
+const DefaultMaxTripDays = 90
+
+// DefaultMaxInvitesPerRequest is how many emails a single request may invite
+// when JOURNEY_MAX_INVITES_PER_REQUEST isn't set.
+const DefaultMaxInvitesPerRequest = 50
+
+// tripStatusCancelled is the trips.status of a cancelled trip, any other trip is active.
+const tripStatusCancelled = "cancelled"
+
+// tripsSortKeys are the columns GET /trips can be sorted by, the first one being the default.
+var tripsSortKeys = []string{"starts_at", "ends_at", "destination", "created_at"}
+
 type API struct{
-	store store
+	tripStore TripStore
+	participantStore ParticipantStore
+	activityStore ActivityStore
+	linkStore LinkStore
+	emailStore EmailStore
 	logger *zap.Logger
 	validator *validator.Validate
-	pool *pgxpool.Pool
-	mailer mailer
+	events *events.Broker
+	maxParticipants int
+	maxTripDays int
+	maxInvitesPerRequest int
+	// notifyTripUpdates emails the confirmed participants when PUT /trips/{tripId}
+	// changes the destination or dates of their trip.
+	notifyTripUpdates bool
+	// mailLimiter caps the emails the invite endpoints enqueue for a trip.
+	mailLimiter *mailer.RateLimiter
+	// cursorKey signs the cursors of GET /trips.
+	cursorKey []byte
 }
 
-func NewAPI(pool *pgxpool.Pool, logger *zap.Logger, mailer mailer) API {
+// NewAPI serves the API from store. The queries taking longer than the timeout
+// of a pgstore.Store are answered with a 504. cursorKey signs the cursors of
+// GET /trips, which only the instances sharing it accept.
+func NewAPI(store Store, logger *zap.Logger, maxParticipants, maxTripDays, maxInvitesPerRequest int, notifyTripUpdates bool, mailLimiter *mailer.RateLimiter, cursorKey []byte) API {
 	validator := validator.New(validator.WithRequiredStructEnabled())
+	validator.RegisterTagNameFunc(jsonFieldName)
+
+	return API{store, store, store, store, store, logger, validator, events.NewBroker(maxTripEventsSubscribers), maxParticipants, maxTripDays, maxInvitesPerRequest, notifyTripUpdates, mailLimiter, cursorKey}
+}
 
-	return API{pgstore.New(pool), logger, validator, pool, mailer}
+// Shutdown ends the GET /trips/{tripId}/events streams, which would otherwise
+// keep http.Server.Shutdown waiting until its deadline.
+func (api API) Shutdown() {
+	api.events.Close()
 }
 
 // Confirms a participant on a trip.
 // (PATCH /participants/{participantId}/confirm)
-func (api API) PatchParticipantsParticipantIDConfirm(w http.ResponseWriter, r *http.Request, participantID string) *spec.Response {
-	id, err := uuid.Parse(participantID)
+func (api API) PatchParticipantsParticipantIDConfirm(w http.ResponseWriter, r *http.Request, participantID uuid.UUID, params spec.PatchParticipantsParticipantIDConfirmParams) *spec.Response {
+	particiapant, err := api.participantStore.GetParticipantWithTrip(r.Context(), participantID)
 	if err != nil {
-		return spec.PatchParticipantsParticipantIDConfirmJSON400Response(spec.Error{Message: "Invalid participant ID"})
+		if errors.Is(err, pgx.ErrNoRows){
+			return spec.PatchParticipantsParticipantIDConfirmJSON400Response(spec.Error{Message: "Participant not found"})
+		}
+		api.logger.Error("Failed to get participant", zap.Error(err), zap.String("participant_id", participantID.String()))
+		return somethingWentWrong(spec.PatchParticipantsParticipantIDConfirmJSON400Response, err) 
 	}
 
-	particiapant, err := api.store.GetParticipant(r.Context(), id)
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows){
+	// A participant from another trip than the expected one is reported the same way as a missing one
+	if params.TripID != nil {
+		tripID, err := uuid.Parse(*params.TripID)
+		if err != nil {
+			return spec.PatchParticipantsParticipantIDConfirmJSON400Response(spec.Error{Message: "Invalid trip_id: must be a UUID"})
+		}
+		if particiapant.TripID != tripID {
 			return spec.PatchParticipantsParticipantIDConfirmJSON400Response(spec.Error{Message: "Participant not found"})
 		}
-		api.logger.Error("Failed to get participant", zap.Error(err), zap.String("participant_id", participantID))
-		return spec.PatchParticipantsParticipantIDConfirmJSON400Response(spec.Error{Message: "Something went wrong, try again"}) 
 	}
 
+	// Confirming twice, e.g. by clicking the e-mail link again, keeps the
+	// participant confirmed, so it succeeds without doing anything
 	if particiapant.IsConfirmed {
-		return spec.PatchParticipantsParticipantIDConfirmJSON400Response(spec.Error{Message: "Participant already confirmed"})
+		w.Header().Set("X-Already-Confirmed", "true")
+		return spec.PatchParticipantsParticipantIDConfirmJSON204Response(nil)
 	}
 
-	if err := api.store.ConfirmParticipant(r.Context(), id); err != nil {
-		api.logger.Error("Failed to confirm participant", zap.Error(err), zap.String("participant_id", participantID))
-		return spec.PatchParticipantsParticipantIDConfirmJSON400Response(spec.Error{Message: "Something went wrong, try again"}) 
+	if err := api.participantStore.ConfirmParticipant(r.Context(), participantID); err != nil {
+		api.logger.Error("Failed to confirm participant", zap.Error(err), zap.String("participant_id", participantID.String()))
+		return somethingWentWrong(spec.PatchParticipantsParticipantIDConfirmJSON400Response, err) 
 	}
 
+	api.events.Publish(particiapant.TripID, events.Event{Type: events.ParticipantConfirmed, ID: participantID.String()})
+
 	return spec.PatchParticipantsParticipantIDConfirmJSON204Response(nil)
 }
 
-// Create a new trip
-// (POST /trips)
-func (api API) PostTrips(w http.ResponseWriter, r *http.Request) *spec.Response {
-	var body spec.CreateTripRequest;
-	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
-		return spec.PostTripsJSON400Response(spec.Error{Message: "Invalid JSON: " + err.Error()}) 
+// confirmParticipantPage asks a participant to confirm they join the trip, then
+// POSTs to its own URL. Without scripts the form is posted as is.
+var confirmParticipantPage = template.Must(template.New("confirm").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Confirm your presence</title></head>
+<body>
+{{if .IsConfirmed -}}
+<p>You already confirmed your presence on the trip to {{.Destination}}.</p>
+{{- else -}}
+<p id="message">Confirm your presence on the trip to {{.Destination}}?</p>
+<form id="confirm" method="post">
+<button type="submit">Confirm</button>
+</form>
+<script>
+document.getElementById("confirm").addEventListener("submit", function (event) {
+	event.preventDefault();
+	fetch(location.href, {method: "POST"}).then(function (response) {
+		document.getElementById("message").textContent = response.ok ? "Your presence is confirmed." : "Something went wrong, try again.";
+		if (response.ok) event.target.remove();
+	});
+});
+</script>
+{{- end}}
+</body>
+</html>
+`))
+
+// Ask to confirm a participant on a trip, from the link of the invitation e-mail.
+// (GET /participants/{participantId}/confirm)
+func (api API) GetParticipantsParticipantIDConfirm(w http.ResponseWriter, r *http.Request, participantID uuid.UUID, params spec.GetParticipantsParticipantIDConfirmParams) *spec.Response {
+	participant, err := api.participantStore.GetParticipantWithTrip(r.Context(), participantID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows){
+			return spec.GetParticipantsParticipantIDConfirmJSON400Response(spec.Error{Message: "Participant not found"})
+		}
+		api.logger.Error("Failed to get participant", zap.Error(err), zap.String("participant_id", participantID.String()))
+		return somethingWentWrong(spec.GetParticipantsParticipantIDConfirmJSON400Response, err)
 	}
 
-	if err := api.validator.Struct(body); err != nil {
-		return spec.PostTripsJSON400Response(spec.Error{Message: "Invalid request body: "+ err.Error()})
+	// A participant from another trip than the expected one is reported the same way as a missing one
+	if params.TripID != nil {
+		tripID, err := uuid.Parse(*params.TripID)
+		if err != nil {
+			return spec.GetParticipantsParticipantIDConfirmJSON400Response(spec.Error{Message: "Invalid trip_id: must be a UUID"})
+		}
+		if participant.TripID != tripID {
+			return spec.GetParticipantsParticipantIDConfirmJSON400Response(spec.Error{Message: "Participant not found"})
+		}
 	}
 
-	tripID, err := api.store.CreateTrip(r.Context(), api.pool, body)
-	if err != nil {
-		return spec.PostTripsJSON400Response(spec.Error{Message: "Something went wrong, try again"})
+	// The page isn't JSON, so it is written straight to the response instead of going through spec.Response
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	if err := confirmParticipantPage.Execute(w, participant); err != nil {
+		api.logger.Error("Failed to write confirm participant page", zap.Error(err))
 	}
 
-	go func() {
-		if err := api.mailer.SendConfirmTripEmailToTripOwner(tripID); err != nil {
-			api.logger.Error("Failed to send email on PostTrips", zap.Error(err), zap.String("trip_id", tripID.String()), zap.String("owner_email", string(body.OwnerEmail)))
-		}
-	}()
+	return nil
+}
 
-	return spec.PostTripsJSON201Response(spec.CreateTripResponse{TripID: tripID.String()})
+// Confirms a participant on a trip, from the confirmation page.
+// (POST /participants/{participantId}/confirm)
+func (api API) PostParticipantsParticipantIDConfirm(w http.ResponseWriter, r *http.Request, participantID uuid.UUID, params spec.PostParticipantsParticipantIDConfirmParams) *spec.Response {
+	return api.PatchParticipantsParticipantIDConfirm(w, r, participantID, spec.PatchParticipantsParticipantIDConfirmParams{TripID: params.TripID})
 }
 
-// Get all trips.
-// (GET /trips)
-func (api API) GetTrips(w http.ResponseWriter, r *http.Request) *spec.Response {
-	trips, err := api.store.GetAllTrips(r.Context())
+// Declines a participant on a trip.
+// (PATCH /participants/{participantId}/decline)
+func (api API) PatchParticipantsParticipantIDDecline(w http.ResponseWriter, r *http.Request, participantID uuid.UUID) *spec.Response {
+	participant, err := api.participantStore.GetParticipant(r.Context(), participantID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows){
-			return spec.GetTripsJSON400Response(spec.Error{Message: "No trips found"})	
-		} 
+			return spec.PatchParticipantsParticipantIDDeclineJSON400Response(spec.Error{Message: "Participant not found"})
+		}
+		api.logger.Error("Failed to get participant", zap.Error(err), zap.String("participant_id", participantID.String()))
+		return somethingWentWrong(spec.PatchParticipantsParticipantIDDeclineJSON400Response, err)
+	}
 
-		api.logger.Error("Failed to get trips", zap.Error(err))
-		return spec.GetTripsJSON400Response(spec.Error{Message: "Something went wrong, try again"})
+	if participant.IsConfirmed {
+		return spec.PatchParticipantsParticipantIDDeclineJSON400Response(spec.Error{Message: "Participant already confirmed"})
 	}
 
-	tripsResponse := make([]spec.GetTripDetailsResponseTripObj, len(trips))
-	for i, trip := range trips {
-		tripsResponse[i] = spec.GetTripDetailsResponseTripObj{
-			ID: trip.ID.String(),
-			Destination: trip.Destination,
-			EndsAt: trip.EndsAt.Time,
-			StartsAt: trip.StartsAt.Time,
-			IsConfirmed: trip.IsConfirmed,
-		}
+	if participant.IsDeclined {
+		return spec.PatchParticipantsParticipantIDDeclineJSON204Response(nil)
 	}
 
-	return spec.GetTripsJSON200Response(spec.GetTripsResponse{
-		Trips: tripsResponse,
-	})
+	if err := api.participantStore.DeclineParticipant(r.Context(), participantID); err != nil {
+		api.logger.Error("Failed to decline participant", zap.Error(err), zap.String("participant_id", participantID.String()))
+		return somethingWentWrong(spec.PatchParticipantsParticipantIDDeclineJSON400Response, err)
+	}
+
+	api.events.Publish(participant.TripID, events.Event{Type: events.ParticipantDeclined, ID: participantID.String()})
+
+	return spec.PatchParticipantsParticipantIDDeclineJSON204Response(nil)
 }
 
-// Get a trip details.
-// (GET /trips/{tripId})
-func (api API) GetTripsTripID(w http.ResponseWriter, r *http.Request, tripID string) *spec.Response {
-	id, err := uuid.Parse(tripID)
+// Get a participant details.
+// (GET /participants/{participantId})
+func (api API) GetParticipantsParticipantID(w http.ResponseWriter, r *http.Request, participantID uuid.UUID) *spec.Response {
+	participant, err := api.participantStore.GetParticipantWithTrip(r.Context(), participantID)
 	if err != nil {
-		return spec.GetTripsTripIDJSON400Response(spec.Error{Message: "Invalid trip ID"})
+		if errors.Is(err, pgx.ErrNoRows){
+			return spec.GetParticipantsParticipantIDJSON400Response(spec.Error{Message: "Participant not found"})
+		}
+		api.logger.Error("Failed to get participant", zap.Error(err), zap.String("participant_id", participantID.String()))
+		return somethingWentWrong(spec.GetParticipantsParticipantIDJSON400Response, err)
 	}
 
-	trip, err := api.store.GetTrip(r.Context(), id)
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows){
-			return spec.GetTripsTripIDJSON400Response(spec.Error{Message: "Trip not found"})	
-		} 
-		api.logger.Error("Failed to get trip", zap.Error(err), zap.String("trip_id", tripID))
-		return spec.GetTripsTripIDJSON400Response(spec.Error{Message: "Something went wrong, try again"})
+	var name *string
+	if participant.Name.Valid {
+		name = &participant.Name.String
 	}
 
-	return spec.GetTripsTripIDJSON200Response(spec.GetTripDetailsResponse{
-		Trip: spec.GetTripDetailsResponseTripObj{
-			ID: trip.ID.String(),
-			Destination: trip.Destination,
-			EndsAt: trip.EndsAt.Time,
-			StartsAt: trip.StartsAt.Time,
-			IsConfirmed: trip.IsConfirmed,
+	return spec.GetParticipantsParticipantIDJSON200Response(spec.GetParticipantDetailsResponse{
+		Participant: spec.GetParticipantDetailsResponseParticipantObj{
+			ID: participant.ID.String(),
+			Name: name,
+			Email: types.Email(participant.Email),
+			IsConfirmed: participant.IsConfirmed,
+			IsDeclined: participant.IsDeclined,
+			Trip: spec.GetParticipantDetailsResponseTripObj{
+				ID: participant.TripID.String(),
+				Destination: participant.Destination,
+				OwnerName: participant.OwnerName,
+				StartsAt: participant.StartsAt.Time,
+				EndsAt: participant.EndsAt.Time,
+			},
 		},
 	})
 }
 
-// Update a trip.
-// (PUT /trips/{tripId})
-func (api API) PutTripsTripID(w http.ResponseWriter, r *http.Request, tripID string) *spec.Response {
-	id, err := uuid.Parse(tripID)
+// Resend the invitation e-mail to a participant.
+// (POST /participants/{participantId}/resend-invite)
+func (api API) PostParticipantsParticipantIDResendInvite(w http.ResponseWriter, r *http.Request, participantID uuid.UUID) *spec.Response {
+	participant, err := api.participantStore.GetParticipant(r.Context(), participantID)
 	if err != nil {
-		return spec.PutTripsTripIDJSON400Response(spec.Error{Message: "Invalid trip ID"})
+		if errors.Is(err, pgx.ErrNoRows){
+			return spec.PostParticipantsParticipantIDResendInviteJSON400Response(spec.Error{Message: "Participant not found"})
+		}
+		api.logger.Error("Failed to get participant", zap.Error(err), zap.String("participant_id", participantID.String()))
+		return somethingWentWrong(spec.PostParticipantsParticipantIDResendInviteJSON400Response, err)
 	}
 
-	trip, err := api.store.GetTrip(r.Context(), id)
+	if participant.IsConfirmed {
+		return spec.PostParticipantsParticipantIDResendInviteJSON400Response(spec.Error{Message: "Participant already confirmed"})
+	}
+
+	suppressed, err := api.emailStore.IsEmailSuppressed(r.Context(), participant.Email)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows){
-			return spec.PutTripsTripIDJSON400Response(spec.Error{Message: "Trip not found"})	
-		} 
-		api.logger.Error("Failed to get trip", zap.Error(err), zap.String("trip_id", tripID))
-		return spec.PutTripsTripIDJSON400Response(spec.Error{Message: "Something went wrong, try again"})
+		api.logger.Error("Failed to check suppression list", zap.Error(err), zap.String("participant_id", participantID.String()))
+		return somethingWentWrong(spec.PostParticipantsParticipantIDResendInviteJSON400Response, err)
+	}
+	if suppressed {
+		return spec.PostParticipantsParticipantIDResendInviteJSON400Response(spec.Error{Message: "Participant unsubscribed from the e-mails"})
 	}
 
-	var body spec.PutTripsTripIDJSONRequestBody
-	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
-		return spec.PutTripsTripIDJSON400Response(spec.Error{Message: "Invalid JSON: " + err.Error()})
+	if err := api.mailLimiter.Reserve(participant.TripID, 1); err != nil {
+		rateLimitErr, _ := mailer.AsRateLimitError(err)
+		setRetryAfter(w, rateLimitErr.RetryAfter)
+		return spec.PostParticipantsParticipantIDResendInviteJSON429Response(spec.Error{Message: "Too many e-mails sent for this trip, try again later"})
 	}
 
-	if err := api.validator.Struct(body); err != nil {
-		return spec.PutTripsTripIDJSON400Response(spec.Error{Message: "Invalid request body: " + err.Error()})
+	// The cooldown is checked and stamped in a single statement so concurrent requests can't both pass it
+	resent, err := api.participantStore.ResendParticipantInvite(r.Context(), pgstore.MarkParticipantInviteResentParams{
+		ID: participantID,
+		Cooldown: pgtype.Interval{Valid: true, Microseconds: inviteResendCooldown.Microseconds()},
+	})
+	if err != nil {
+		api.logger.Error("Failed to mark invite as resent", zap.Error(err), zap.String("participant_id", participantID.String()))
+		return somethingWentWrong(spec.PostParticipantsParticipantIDResendInviteJSON400Response, err)
 	}
 
-	if err := api.store.UpdateTrip(r.Context(), pgstore.UpdateTripParams{
-		ID: id,
-		Destination: body.Destination,
-		EndsAt: pgtype.Timestamp{Valid: true, Time: body.EndsAt},
-		StartsAt: pgtype.Timestamp{Valid: true, Time: body.StartsAt},
-		IsConfirmed: trip.IsConfirmed,
-	}); err != nil {
-		api.logger.Error("Failed to update trip", zap.Error(err), zap.String("trip_id", tripID))
-		return spec.PutTripsTripIDJSON400Response(spec.Error{Message: "Something went wrong, try again"})
+	if resent == 0 {
+		return spec.PostParticipantsParticipantIDResendInviteJSON400Response(spec.Error{Message: "Invitation was resent recently, try again later"})
 	}
 
-	return spec.PutTripsTripIDJSON204Response(nil)
+	return spec.PostParticipantsParticipantIDResendInviteJSON204Response(nil)
 }
 
-// Get a trip activities.
-// (GET /trips/{tripId}/activities)
-func (api API) GetTripsTripIDActivities(w http.ResponseWriter, r *http.Request, tripID string) *spec.Response {
-	id, err := uuid.Parse(tripID)
+// unsubscribePage asks to confirm unsubscribing, then POSTs to its own URL.
+// Without scripts the form is posted as is.
+var unsubscribePage = template.Must(template.New("unsubscribe").Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>Unsubscribe</title></head>
+<body>
+<p id="message">Stop every e-mail about trips to {{.}}?</p>
+<form id="unsubscribe" method="post">
+<button type="submit">Unsubscribe</button>
+</form>
+<script>
+document.getElementById("unsubscribe").addEventListener("submit", function (event) {
+	event.preventDefault();
+	fetch(location.href, {method: "POST"}).then(function (response) {
+		document.getElementById("message").textContent = response.ok ? "You won't receive any more e-mails." : "Something went wrong, try again.";
+		if (response.ok) event.target.remove();
+	});
+});
+</script>
+</body>
+</html>
+`))
+
+// Ask to confirm stopping the e-mails to an address, from the link in an e-mail.
+// (GET /unsubscribe/{token})
+func (api API) GetUnsubscribeToken(w http.ResponseWriter, r *http.Request, token uuid.UUID) *spec.Response {
+	email, err := api.emailStore.GetEmailByUnsubscribeToken(r.Context(), token)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return spec.GetUnsubscribeTokenJSON400Response(spec.Error{Message: "Unsubscribe link not found"})
+		}
+		api.logger.Error("Failed to get email by unsubscribe token", zap.Error(err))
+		return somethingWentWrong(spec.GetUnsubscribeTokenJSON400Response, err)
+	}
+
+	// The page isn't JSON, so it is written straight to the response instead of going through spec.Response
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(http.StatusOK)
+	if err := unsubscribePage.Execute(w, email); err != nil {
+		api.logger.Error("Failed to write unsubscribe page", zap.Error(err))
+	}
+
+	return nil
+}
+
+// Stop the e-mails to an address, from the confirmation page or a one-click unsubscribe.
+// (POST /unsubscribe/{token})
+func (api API) PostUnsubscribeToken(w http.ResponseWriter, r *http.Request, token uuid.UUID) *spec.Response {
+	if message, ok := api.unsubscribe(r.Context(), token); !ok {
+		return spec.PostUnsubscribeTokenJSON400Response(spec.Error{Message: message})
+	}
+	return spec.PostUnsubscribeTokenJSON204Response(nil)
+}
+
+// unsubscribe adds the address token was sent to to the suppression list,
+// returning the error message for the caller when it can't.
+func (api API) unsubscribe(ctx context.Context, token uuid.UUID) (string, bool) {
+	email, err := api.emailStore.GetEmailByUnsubscribeToken(ctx, token)
 	if err != nil {
-		return spec.GetTripsTripIDActivitiesJSON400Response(spec.Error{Message: "Invalid trip ID"})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "Unsubscribe link not found", false
+		}
+		api.logger.Error("Failed to get email by unsubscribe token", zap.Error(err))
+		return "Something went wrong, try again", false
 	}
 
-	activities, err := api.store.GetTripActivities(r.Context(), id)
+	if err := api.emailStore.SuppressEmail(ctx, email); err != nil {
+		api.logger.Error("Failed to suppress email", zap.Error(err))
+		return "Something went wrong, try again", false
+	}
+
+	return "", true
+}
+
+// Update a participant.
+// (PATCH /participants/{participantId})
+func (api API) PatchParticipantsParticipantID(w http.ResponseWriter, r *http.Request, participantID uuid.UUID) *spec.Response {
+	participant, err := api.participantStore.GetParticipant(r.Context(), participantID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows){
-			return spec.GetTripsTripIDActivitiesJSON400Response(spec.Error{Message: "Activities not found"})
+			return spec.PatchParticipantsParticipantIDJSON400Response(spec.Error{Message: "Participant not found"})
 		}
+		api.logger.Error("Failed to get participant", zap.Error(err), zap.String("participant_id", participantID.String()))
+		return somethingWentWrong(spec.PatchParticipantsParticipantIDJSON400Response, err)
+	}
+
+	var body spec.UpdateParticipantRequest
+	if err := decodeJSON(r.Body, &body); err != nil {
+		return spec.PatchParticipantsParticipantIDJSON400Response(spec.Error{Message: "Invalid JSON: " + err.Error()})
+	}
 
-		api.logger.Error("Failed to get activities", zap.Error(err), zap.String("trip_id", tripID))
-		return spec.GetTripsTripIDActivitiesJSON400Response(spec.Error{Message: "Something went wrong, try again"})
+	body.Name = collapseSpaces(body.Name)
+
+	if err := api.validator.Struct(body); err != nil {
+		return spec.PatchParticipantsParticipantIDJSON400Response(spec.Error{Message: "Invalid request body: " + validationMessage(err)})
 	}
 
-	type Activity struct {
-		Time time.Time
-		Amount *int
+	if err := api.participantStore.UpdateParticipantName(r.Context(), pgstore.UpdateParticipantNameParams{
+		ID: participantID,
+		Name: pgtype.Text{Valid: true, String: body.Name},
+	}); err != nil {
+		api.logger.Error("Failed to update participant", zap.Error(err), zap.String("participant_id", participantID.String()))
+		return somethingWentWrong(spec.PatchParticipantsParticipantIDJSON400Response, err)
 	}
 
+	api.events.Publish(participant.TripID, events.Event{Type: events.ParticipantUpdated, ID: participantID.String()})
 
-	var qtd int
-	differentDates := make([]Activity, 0, len(activities))
-	for _, activity := range activities {
-		// Check if the date isnt already in the slice
-		if !slices.ContainsFunc(
-			differentDates, 
-			func(item Activity) bool { 
-				itemYear, itemMonth, itemDay := item.Time.Date()
-				activityYear, activityMonth, activityDay := activity.OccursAt.Time.Date()
-				return itemYear == activityYear && itemMonth == activityMonth && itemDay == activityDay
-			}) {
-			var amount int = 1;
-			itemYear, itemMonth, itemDay := activity.OccursAt.Time.Date()
-			differentDates = append(differentDates, Activity{
-				Time: time.Date(itemYear, itemMonth, itemDay, 0, 0, 0, 0, time.UTC),
-				Amount: &amount,
-			})	
-			qtd++
-		} else {
-			for i, date := range differentDates {
-				dateYear, dateMonth, dateDay := date.Time.Date()
-				activityYear, activityMonth, activityDay := activity.OccursAt.Time.Date()
-				if dateYear == activityYear && dateMonth == activityMonth && dateDay == activityDay {
-        			*date.Amount++
-					differentDates[i] = date
-				}
-			}
-		}
+	return spec.PatchParticipantsParticipantIDJSON204Response(nil)
+}
+
+// Create a new trip
+// (POST /trips)
+func (api API) PostTrips(w http.ResponseWriter, r *http.Request) *spec.Response {
+	var body spec.CreateTripRequest;
+	if err := decodeJSON(r.Body, &body); err != nil {
+		return spec.PostTripsJSON400Response(spec.Error{Message: "Invalid JSON: " + err.Error()}) 
 	}
 
-	activitiesResponse := make([]spec.GetTripActivitiesResponseOuterArray, qtd)
-	for i, item := range differentDates {
-		activitiesInnerResponse := make([]spec.GetTripActivitiesResponseInnerArray, 0, *differentDates[i].Amount)
+	body.Destination = collapseSpaces(body.Destination)
+	body.OwnerName = collapseSpaces(body.OwnerName)
 
-		for _, activity := range activities {
-			dateYear, dateMonth, dateDay := item.Time.Date()
-			activityYear, activityMonth, activityDay := activity.OccursAt.Time.Date()
-			if dateYear == activityYear && dateMonth == activityMonth && dateDay == activityDay {
-        			activitiesInnerResponse = append(activitiesInnerResponse, spec.GetTripActivitiesResponseInnerArray{
-					ID: activity.ID.String(),
-					Title: activity.Title,
-					OccursAt: activity.OccursAt.Time,
-				})
-			}
-		}
-		
-		activitiesResponse[i] = spec.GetTripActivitiesResponseOuterArray{
-			Date: item.Time,
-			Activities: activitiesInnerResponse,
+	if err := api.validator.Struct(body); err != nil {
+		return spec.PostTripsJSON400Response(spec.Error{Message: "Invalid request body: "+ validationMessage(err)})
+	}
+
+	if err := validateInvitesCount(len(body.EmailsToInvite), api.maxInvitesPerRequest); err != nil {
+		return spec.PostTripsJSON400Response(spec.Error{Message: "Invalid request body: " + err.Error()})
+	}
+
+	if body.ImageURL != nil && *body.ImageURL != "" {
+		if err := validateHTTPURL("image_url", *body.ImageURL); err != nil {
+			return spec.PostTripsJSON400Response(spec.Error{Message: "Invalid request body: " + err.Error()})
 		}
 	}
 
-	
-	return spec.GetTripsTripIDActivitiesJSON200Response(spec.GetTripActivitiesResponse{
-		Activities: activitiesResponse,
-	})
-}
+	if err := validateTripDates(body.StartsAt, body.EndsAt, true, api.maxTripDays); err != nil {
+		return spec.PostTripsJSON400Response(spec.Error{Message: "Invalid request body: " + err.Error()})
+	}
 
-// Create a trip activity.
-// (POST /trips/{tripId}/activities)
-func (api API) PostTripsTripIDActivities(w http.ResponseWriter, r *http.Request, tripID string) *spec.Response {
-	id, err := uuid.Parse(tripID)
+	ownerEmail, err := validateEmail("owner_email", body.OwnerEmail)
 	if err != nil {
-		return spec.PostTripsTripIDActivitiesJSON400Response(spec.Error{Message: "Invalid trip ID"})
+		return spec.PostTripsJSON400Response(spec.Error{Message: "Invalid request body: " + err.Error()})
 	}
+	body.OwnerEmail = ownerEmail
 
-	var body spec.CreateActivityRequest
-	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
-		return spec.PostTripsTripIDActivitiesJSON400Response(spec.Error{Message: "Invalid JSON: " + err.Error()})
+	body.EmailsToInvite, err = validateEmails("emails_to_invite", body.EmailsToInvite)
+	if err != nil {
+		return spec.PostTripsJSON400Response(spec.Error{Message: "Invalid request body: " + err.Error()})
 	}
 
-	if err := api.validator.Struct(body); err != nil {
-		return spec.PostTripsTripIDActivitiesJSON400Response(spec.Error{Message: "Invalid request body: " + err.Error()})
+	invites, duplicates := dedupeEmails(body.EmailsToInvite)
+	// The owner takes part in the trip without being invited to it
+	body.EmailsToInvite = slices.DeleteFunc(invites, func(email types.Email) bool {
+		return isTripOwner(string(email), string(body.OwnerEmail))
+	})
+
+	if len(body.EmailsToInvite) > api.maxParticipants {
+		return spec.PostTripsJSON400Response(spec.Error{Message: "Too many participants: a trip allows at most " + strconv.Itoa(api.maxParticipants) + " participants, got " + strconv.Itoa(len(body.EmailsToInvite))})
 	}
 
-	activityID, err := api.store.CreateActivity(r.Context(), pgstore.CreateActivityParams{
-		TripID: id,
-		Title: body.Title,
-		OccursAt: pgtype.Timestamp{Valid: true, Time: body.OccursAt},
-	})
+	if body.Locale == nil {
+		// FromAcceptLanguage only returns supported locales, which the enum accepts
+		var locale spec.CreateTripRequestLocale
+		_ = locale.FromValue(locales.FromAcceptLanguage(r.Header.Get("Accept-Language")))
+		body.Locale = &locale
+	}
+
+	// Unsubscribed addresses are still invited, the owner is only told they won't be e-mailed
+	emailsToInvite := make([]string, len(body.EmailsToInvite))
+	for i, email := range body.EmailsToInvite {
+		emailsToInvite[i] = string(email)
+	}
+	suppressed, err := api.emailStore.ListSuppressedEmails(r.Context(), emailsToInvite)
 	if err != nil {
-		api.logger.Error("Failed to create activity", zap.Error(err), zap.String("trip_id", tripID))
-		return spec.PostTripsTripIDActivitiesJSON400Response(spec.Error{Message: "Something went wrong, try again"})
+		api.logger.Error("Failed to list suppressed emails", zap.Error(err))
+		return somethingWentWrong(spec.PostTripsJSON400Response, err)
 	}
 
-	return spec.PostTripsTripIDActivitiesJSON201Response(spec.CreateActivityResponse{ActivityID: activityID.String()})
+	tripID, err := api.tripStore.CreateTrip(r.Context(), body)
+	if err != nil {
+		return somethingWentWrong(spec.PostTripsJSON400Response, err)
+	}
+
+	return spec.PostTripsJSON201Response(spec.CreateTripResponse{
+		TripID: tripID.String(),
+		DuplicateEmailsDropped: &duplicates,
+		SuppressedEmails: suppressed,
+	})
 }
 
-// Confirm a trip and send e-mail invitations.
-// (GET /trips/{tripId}/confirm)
-func (api API) GetTripsTripIDConfirm(w http.ResponseWriter, r *http.Request, tripID string) *spec.Response {
-	id, err := uuid.Parse(tripID)
-	if err != nil {
-		return spec.GetTripsTripIDConfirmJSON400Response(spec.Error{Message: "Invalid trip ID"})
+// Get all trips.
+// (GET /trips)
+func (api API) GetTrips(w http.ResponseWriter, r *http.Request, params spec.GetTripsParams) *spec.Response {
+	limit := defaultTripsLimit
+	if params.Limit != nil {
+		if *params.Limit < 0 {
+			return spec.GetTripsJSON400Response(spec.Error{Message: "Invalid limit: must not be negative"})
+		}
+		limit = min(*params.Limit, maxTripsLimit)
 	}
 
-	trip, err := api.store.GetTrip(r.Context(), id)
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows){
-			return spec.GetTripsTripIDConfirmJSON400Response(spec.Error{Message: "Trip not found"})	
-		} 
-		api.logger.Error("Failed to get trip", zap.Error(err), zap.String("trip_id", tripID))
-		return spec.GetTripsTripIDConfirmJSON400Response(spec.Error{Message: "Something went wrong, try again"})
+	offset := 0
+	if params.Offset != nil {
+		if *params.Offset < 0 || *params.Offset > math.MaxInt32 {
+			return spec.GetTripsJSON400Response(spec.Error{Message: "Invalid offset: must be between 0 and " + strconv.Itoa(math.MaxInt32)})
+		}
+		offset = *params.Offset
 	}
 
-	if trip.IsConfirmed {
-		return spec.GetTripsTripIDConfirmJSON400Response(spec.Error{Message: "Trip already confirmed"})
+	var isConfirmed pgtype.Bool
+	if params.IsConfirmed != nil {
+		value, err := strconv.ParseBool(*params.IsConfirmed)
+		if err != nil {
+			return spec.GetTripsJSON400Response(spec.Error{Message: "Invalid is_confirmed: must be true or false"})
+		}
+		isConfirmed = pgtype.Bool{Valid: true, Bool: value}
 	}
 
-	// Update trip to confirm
-	if err := api.store.UpdateTrip(r.Context(), pgstore.UpdateTripParams{
-		ID: id,
-		Destination: trip.Destination,
-		EndsAt: trip.EndsAt,
-		StartsAt: trip.StartsAt,
-		IsConfirmed: true,
-	}); err != nil {
-		api.logger.Error("Failed to confirm trip", zap.Error(err), zap.String("trip_id", tripID))
-		return spec.GetTripsTripIDConfirmJSON400Response(spec.Error{Message: "Something went wrong, try again"})
+	var ownerEmail pgtype.Text
+	if params.OwnerEmail != nil {
+		email, err := validateEmail("owner_email", *params.OwnerEmail)
+		if err != nil {
+			return spec.GetTripsJSON400Response(spec.Error{Message: "Invalid " + err.Error()})
+		}
+		ownerEmail = pgtype.Text{Valid: true, String: string(email)}
 	}
 
-	// Send e-mail invitations to participants
-	go func() {
-		if err := api.mailer.SendConfirmTripEmailToTripParticipants(id); err != nil {
-			api.logger.Error("Failed to send email on GetTripsTripIDConfirm", zap.Error(err), zap.String("trip_id", tripID))
+	includeArchived := false
+	if params.IncludeArchived != nil {
+		value, err := strconv.ParseBool(*params.IncludeArchived)
+		if err != nil {
+			return spec.GetTripsJSON400Response(spec.Error{Message: "Invalid include_archived: must be true or false"})
 		}
-	}()
+		includeArchived = value
+	}
 
-	return spec.GetTripsTripIDConfirmJSON204Response(nil)
-}
+	sortBy := tripsSortKeys[0]
+	if params.Sort != nil {
+		if !slices.Contains(tripsSortKeys, string(*params.Sort)) {
+			return spec.GetTripsJSON400Response(spec.Error{Message: "Invalid sort: must be one of " + strings.Join(tripsSortKeys, ", ")})
+		}
+		sortBy = string(*params.Sort)
+	}
 
-// Invite someone to the trip.
-// (POST /trips/{tripId}/invites)
-func (api API) PostTripsTripIDInvites(w http.ResponseWriter, r *http.Request, tripID string) *spec.Response {
-	panic("not implemented") // TODO: Implement
-}
+	sortDesc := false
+	if params.Order != nil {
+		switch *params.Order {
+		case "asc":
+		case "desc":
+			sortDesc = true
+		default:
+			return spec.GetTripsJSON400Response(spec.Error{Message: "Invalid order: must be one of asc, desc"})
+		}
+	}
 
-// Get a trip links.
-// (GET /trips/{tripId}/links)
-func (api API) GetTripsTripIDLinks(w http.ResponseWriter, r *http.Request, tripID string) *spec.Response {
-	panic("not implemented") // TODO: Implement
-}
+	// Only the default order is paginated with cursors, it's the one indexed
+	keyset := sortBy == tripsSortKeys[0] && !sortDesc
 
-// Create a trip link.
-// (POST /trips/{tripId}/links)
-func (api API) PostTripsTripIDLinks(w http.ResponseWriter, r *http.Request, tripID string) *spec.Response {
-	panic("not implemented") // TODO: Implement
-}
+	var cursor *tripsCursor
+	if params.Cursor != nil {
+		if params.Offset != nil {
+			return spec.GetTripsJSON400Response(spec.Error{Message: "Invalid cursor: can't be combined with offset"})
+		}
+		if !keyset {
+			return spec.GetTripsJSON400Response(spec.Error{Message: "Invalid cursor: only allowed with sort starts_at and order asc"})
+		}
 
-// Get a trip participants.
-// (GET /trips/{tripId}/participants)
-func (api API) GetTripsTripIDParticipants(w http.ResponseWriter, r *http.Request, tripID string) *spec.Response {
-	id, err := uuid.Parse(tripID)
+		parsed, err := parseTripsCursor(*params.Cursor, api.cursorKey)
+		if err != nil {
+			return spec.GetTripsJSON400Response(spec.Error{Message: "Invalid cursor: " + err.Error()})
+		}
+		cursor = &parsed
+	}
+
+	var trips []pgstore.Trip
+	var err error
+	if cursor != nil {
+		trips, err = api.tripStore.ListTripsAfter(r.Context(), pgstore.ListTripsAfterParams{
+			IsConfirmed: isConfirmed,
+			OwnerEmail: ownerEmail,
+			IncludeArchived: includeArchived,
+			AfterStartsAt: pgtype.Timestamp{Valid: true, Time: cursor.StartsAt},
+			AfterID: cursor.ID,
+			Limit: int32(limit),
+		})
+	} else {
+		trips, err = api.tripStore.ListTrips(r.Context(), pgstore.ListTripsParams{
+			IsConfirmed: isConfirmed,
+			OwnerEmail: ownerEmail,
+			IncludeArchived: includeArchived,
+			SortBy: sortBy,
+			SortDesc: sortDesc,
+			Limit: int32(limit),
+			Offset: int32(offset),
+		})
+	}
 	if err != nil {
-		return spec.GetTripsTripIDParticipantsJSON400Response(spec.Error{Message: "Invalid trip ID"})
+		api.logger.Error("Failed to get trips", zap.Error(err))
+		return somethingWentWrong(spec.GetTripsJSON400Response, err)
 	}
 
-	trip, err := api.store.GetTrip(r.Context(), id)
+	total, err := api.tripStore.CountTrips(r.Context(), pgstore.CountTripsParams{
+		IsConfirmed: isConfirmed,
+		OwnerEmail: ownerEmail,
+		IncludeArchived: includeArchived,
+	})
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows){
-			return spec.GetTripsTripIDParticipantsJSON400Response(spec.Error{Message: "Trip not found"})	
-		} 
-		api.logger.Error("Failed to get trip", zap.Error(err), zap.String("trip_id", tripID))
-		return spec.GetTripsTripIDParticipantsJSON400Response(spec.Error{Message: "Something went wrong, try again"})
+		api.logger.Error("Failed to count trips", zap.Error(err))
+		return somethingWentWrong(spec.GetTripsJSON400Response, err)
+	}
+
+	tripsResponse := make([]spec.GetTripDetailsResponseTripObj, len(trips))
+	for i, trip := range trips {
+		tripsResponse[i] = tripObj(trip)
+	}
+
+	// A page that isn't full is the last one
+	var nextCursor *string
+	if keyset && limit > 0 && len(trips) == limit {
+		last := trips[len(trips)-1]
+		cursor := tripsCursor{StartsAt: last.StartsAt.Time, ID: last.ID}.sign(api.cursorKey)
+		nextCursor = &cursor
+	}
+
+	return spec.GetTripsJSON200Response(spec.GetTripsResponse{
+		Trips: tripsResponse,
+		Limit: limit,
+		Offset: offset,
+		Total: int(total),
+		NextCursor: nextCursor,
+	})
+}
+
+// Lists the trips an email was invited to.
+// (GET /trips/participating)
+func (api API) GetTripsParticipating(w http.ResponseWriter, r *http.Request, params spec.GetTripsParticipatingParams) *spec.Response {
+	validEmail, err := validateEmail("email", params.Email)
+	if err != nil {
+		return spec.GetTripsParticipatingJSON400Response(spec.Error{Message: "Invalid " + err.Error()})
+	}
+	email := string(validEmail)
+
+	limit := defaultTripsLimit
+	if params.Limit != nil {
+		if *params.Limit < 0 {
+			return spec.GetTripsParticipatingJSON400Response(spec.Error{Message: "Invalid limit: must not be negative"})
+		}
+		limit = min(*params.Limit, maxTripsLimit)
+	}
+
+	offset := 0
+	if params.Offset != nil {
+		if *params.Offset < 0 || *params.Offset > math.MaxInt32 {
+			return spec.GetTripsParticipatingJSON400Response(spec.Error{Message: "Invalid offset: must be between 0 and " + strconv.Itoa(math.MaxInt32)})
+		}
+		offset = *params.Offset
 	}
 
-	participants, err := api.store.GetParticipants(r.Context(), trip.ID)
+	trips, err := api.tripStore.ListTripsByParticipantEmail(r.Context(), pgstore.ListTripsByParticipantEmailParams{
+		Email: email,
+		Limit: int32(limit),
+		Offset: int32(offset),
+	})
 	if err != nil {
-		api.logger.Error("Failed to get participants", zap.Error(err), zap.String("trip_id", tripID))
-		return spec.GetTripsTripIDParticipantsJSON400Response(spec.Error{Message: "Something went wrong, try again"})
+		api.logger.Error("Failed to list trips by participant email", zap.Error(err))
+		return somethingWentWrong(spec.GetTripsParticipatingJSON400Response, err)
 	}
 
-	participantsResponse := make([]spec.GetTripParticipantsResponseArray , len(participants))
-	for i, participant := range participants {
+	total, err := api.tripStore.CountTripsByParticipantEmail(r.Context(), email)
+	if err != nil {
+		api.logger.Error("Failed to count trips by participant email", zap.Error(err))
+		return somethingWentWrong(spec.GetTripsParticipatingJSON400Response, err)
+	}
 
-		participantsResponse[i] = spec.GetTripParticipantsResponseArray {
-			ID: participant.ID.String(),
-			Email: types.Email(participant.Email),
-			IsConfirmed: participant.IsConfirmed,
+	tripsResponse := make([]spec.GetParticipatingTripsResponseArray, len(trips))
+	for i, trip := range trips {
+		tripsResponse[i] = spec.GetParticipatingTripsResponseArray{
+			Trip: tripObj(trip.Trip()),
+			ParticipantID: trip.ParticipantID.String(),
+			IsConfirmed: trip.ParticipantIsConfirmed,
+			IsDeclined: trip.ParticipantIsDeclined,
 		}
 	}
 
-	return spec.GetTripsTripIDParticipantsJSON200Response(spec.GetTripParticipantsResponse{
-		Participants: participantsResponse,
+	return spec.GetTripsParticipatingJSON200Response(spec.GetParticipatingTripsResponse{
+		Trips: tripsResponse,
+		Limit: limit,
+		Offset: offset,
+		Total: int(total),
 	})
-}
\ No newline at end of file
+}
+
+// Search trips by destination, description or owner name.
+// (GET /trips/search)
+func (api API) GetTripsSearch(w http.ResponseWriter, r *http.Request, params spec.GetTripsSearchParams) *spec.Response {
+	query := strings.TrimSpace(params.Q)
+	if utf8.RuneCountInString(query) < minSearchQueryLength {
+		return spec.GetTripsSearchJSON400Response(spec.Error{Message: "Invalid q: must have at least " + strconv.Itoa(minSearchQueryLength) + " characters"})
+	}
+
+	limit := defaultTripsLimit
+	if params.Limit != nil {
+		if *params.Limit < 0 {
+			return spec.GetTripsSearchJSON400Response(spec.Error{Message: "Invalid limit: must not be negative"})
+		}
+		limit = min(*params.Limit, maxTripsLimit)
+	}
+
+	offset := 0
+	if params.Offset != nil {
+		if *params.Offset < 0 || *params.Offset > math.MaxInt32 {
+			return spec.GetTripsSearchJSON400Response(spec.Error{Message: "Invalid offset: must be between 0 and " + strconv.Itoa(math.MaxInt32)})
+		}
+		offset = *params.Offset
+	}
+
+	pattern := "%" + escapeLikePattern(query) + "%"
+
+	trips, err := api.tripStore.SearchTrips(r.Context(), pgstore.SearchTripsParams{
+		Query: query,
+		Pattern: pattern,
+		Limit: int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		api.logger.Error("Failed to search trips", zap.Error(err), zap.String("q", query))
+		return somethingWentWrong(spec.GetTripsSearchJSON400Response, err)
+	}
+
+	total, err := api.tripStore.CountSearchTrips(r.Context(), pgstore.CountSearchTripsParams{
+		Query: query,
+		Pattern: pattern,
+	})
+	if err != nil {
+		api.logger.Error("Failed to count searched trips", zap.Error(err), zap.String("q", query))
+		return somethingWentWrong(spec.GetTripsSearchJSON400Response, err)
+	}
+
+	tripsResponse := make([]spec.GetTripDetailsResponseTripObj, len(trips))
+	for i, trip := range trips {
+		tripsResponse[i] = tripObj(trip)
+	}
+
+	return spec.GetTripsSearchJSON200Response(spec.GetTripsResponse{
+		Trips: tripsResponse,
+		Limit: limit,
+		Offset: offset,
+		Total: int(total),
+	})
+}
+
+// Get a trip details.
+// (GET /trips/{tripId})
+func (api API) GetTripsTripID(w http.ResponseWriter, r *http.Request, tripID uuid.UUID) *spec.Response {
+	trip, err := api.tripStore.GetTripDetails(r.Context(), tripID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows){
+			return spec.GetTripsTripIDJSON400Response(spec.Error{Message: "Trip not found"})	
+		} 
+		api.logger.Error("Failed to get trip", zap.Error(err), zap.String("trip_id", tripID.String()))
+		return somethingWentWrong(spec.GetTripsTripIDJSON400Response, err)
+	}
+
+	w.Header().Set("ETag", tripETag(trip.Version))
+
+	return spec.GetTripsTripIDJSON200Response(spec.GetTripDetailsResponse{
+		Trip: tripObj(trip),
+	})
+}
+
+// Update a trip.
+// (PUT /trips/{tripId})
+func (api API) PutTripsTripID(w http.ResponseWriter, r *http.Request, tripID uuid.UUID, params spec.PutTripsTripIDParams) *spec.Response {
+	trip, err := api.tripStore.GetTrip(r.Context(), tripID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows){
+			return spec.PutTripsTripIDJSON400Response(spec.Error{Message: "Trip not found"})	
+		} 
+		api.logger.Error("Failed to get trip", zap.Error(err), zap.String("trip_id", tripID.String()))
+		return somethingWentWrong(spec.PutTripsTripIDJSON400Response, err)
+	}
+
+	var body spec.UpdateTripRequest
+	if err := decodeJSON(r.Body, &body); err != nil {
+		return spec.PutTripsTripIDJSON400Response(spec.Error{Message: "Invalid JSON: " + err.Error()})
+	}
+
+	body.Destination = collapseSpaces(body.Destination)
+
+	if err := api.validator.Struct(body); err != nil {
+		return spec.PutTripsTripIDJSON400Response(spec.Error{Message: "Invalid request body: " + validationMessage(err)})
+	}
+
+	return api.updateTrip(r, trip, body, params)
+}
+
+// updateTrip saves body over trip, for PUT and for PATCH once the fields it
+// left out are filled in from trip, answering with the responses of PUT.
+func (api API) updateTrip(r *http.Request, trip pgstore.Trip, body spec.UpdateTripRequest, params spec.PutTripsTripIDParams) *spec.Response {
+	tripID := trip.ID
+
+	// Without If-Match the last write wins, as it did before versions existed
+	var expectedVersion pgtype.Int4
+	if params.IfMatch != nil {
+		var err error
+		expectedVersion, err = parseTripETag(*params.IfMatch)
+		if err != nil {
+			return spec.PutTripsTripIDJSON400Response(spec.Error{Message: "Invalid If-Match: " + err.Error()})
+		}
+	}
+
+	if body.ImageURL != nil && *body.ImageURL != "" {
+		if err := validateHTTPURL("image_url", *body.ImageURL); err != nil {
+			return spec.PutTripsTripIDJSON400Response(spec.Error{Message: "Invalid request body: " + err.Error()})
+		}
+	}
+
+	if err := validateTripDates(body.StartsAt, body.EndsAt, false, api.maxTripDays); err != nil {
+		return spec.PutTripsTripIDJSON400Response(spec.Error{Message: "Invalid request body: " + err.Error()})
+	}
+
+	// With force=true the activities left out by the new dates are kept as they are
+	force := params.Force != nil && *params.Force
+	datesChanged := !body.StartsAt.Equal(trip.StartsAt.Time) || !body.EndsAt.Equal(trip.EndsAt.Time)
+	if datesChanged && !force {
+		outside, err := api.activityStore.ListActivitiesOutsideRange(r.Context(), pgstore.ListActivitiesOutsideRangeParams{
+			TripID: tripID,
+			OccursFrom: pgtype.Timestamp{Valid: true, Time: body.StartsAt},
+			OccursUntil: pgtype.Timestamp{Valid: true, Time: endOfDay(body.EndsAt)},
+		})
+		if err != nil {
+			api.logger.Error("Failed to list activities outside the trip dates", zap.Error(err), zap.String("trip_id", tripID.String()))
+			return somethingWentWrong(spec.PutTripsTripIDJSON400Response, err)
+		}
+
+		if len(outside) > 0 {
+			activities := make([]spec.ActivitiesOutOfRangeResponseArray, len(outside))
+			for i, activity := range outside {
+				activities[i] = spec.ActivitiesOutOfRangeResponseArray{
+					ID: activity.ID.String(),
+					OccursAt: activity.OccursAt.Time,
+				}
+			}
+
+			return spec.PutTripsTripIDJSON409Response(spec.ActivitiesOutOfRangeResponse{
+				Message: strconv.Itoa(len(outside)) + " activities fall outside the new dates, move them or pass force=true to keep them",
+				Activities: activities,
+			})
+		}
+	}
+
+	// Omitted optional fields keep their current value, so older clients don't wipe them
+	description := trip.Description
+	if body.Description != nil {
+		description = nullableText(body.Description)
+	}
+	imageURL := trip.ImageUrl
+	if body.ImageURL != nil {
+		imageURL = nullableText(body.ImageURL)
+	}
+
+	// Participants are only told about what they'd plan around, not a new description
+	var changes *pgstore.TripChanges
+	if api.notifyTripUpdates {
+		diff := pgstore.TripChanges{
+			OldDestination: trip.Destination,
+			NewDestination: body.Destination,
+			OldStartsAt: trip.StartsAt.Time,
+			NewStartsAt: body.StartsAt,
+			OldEndsAt: trip.EndsAt.Time,
+			NewEndsAt: body.EndsAt,
+		}
+		if diff.Any() {
+			changes = &diff
+		}
+	}
+
+	updated, err := api.tripStore.UpdateTripAndNotify(r.Context(), pgstore.UpdateTripParams{
+		ID: tripID,
+		Destination: body.Destination,
+		EndsAt: pgtype.Timestamp{Valid: true, Time: body.EndsAt},
+		StartsAt: pgtype.Timestamp{Valid: true, Time: body.StartsAt},
+		IsConfirmed: trip.IsConfirmed,
+		Description: description,
+		ImageUrl: imageURL,
+		ExpectedVersion: expectedVersion,
+	}, changes)
+	if err != nil {
+		api.logger.Error("Failed to update trip", zap.Error(err), zap.String("trip_id", tripID.String()))
+		return somethingWentWrong(spec.PutTripsTripIDJSON400Response, err)
+	}
+
+	if updated == 0 {
+		if expectedVersion.Valid {
+			return spec.PutTripsTripIDJSON412Response(spec.Error{Message: "Trip was changed since it was read, reload it and try again"})
+		}
+		return spec.PutTripsTripIDJSON400Response(spec.Error{Message: "Trip not found"})
+	}
+
+	return spec.PutTripsTripIDJSON204Response(nil)
+}
+
+// Delete a trip.
+// (DELETE /trips/{tripId})
+func (api API) DeleteTripsTripID(w http.ResponseWriter, r *http.Request, tripID uuid.UUID, params spec.DeleteTripsTripIDParams) *spec.Response {
+	trip, err := api.tripStore.GetTrip(r.Context(), tripID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows){
+			return spec.DeleteTripsTripIDJSON400Response(spec.Error{Message: "Trip not found"})
+		}
+		api.logger.Error("Failed to get trip", zap.Error(err), zap.String("trip_id", tripID.String()))
+		return somethingWentWrong(spec.DeleteTripsTripIDJSON400Response, err)
+	}
+
+	force := params.Force != nil && *params.Force
+	if trip.IsConfirmed && !force {
+		return spec.DeleteTripsTripIDJSON400Response(spec.Error{Message: "Trip is confirmed, pass force=true to delete it"})
+	}
+
+	// Participants, activities and links are removed by ON DELETE CASCADE
+	if err := api.tripStore.DeleteTrip(r.Context(), tripID); err != nil {
+		api.logger.Error("Failed to delete trip", zap.Error(err), zap.String("trip_id", tripID.String()))
+		return somethingWentWrong(spec.DeleteTripsTripIDJSON400Response, err)
+	}
+
+	return spec.DeleteTripsTripIDJSON204Response(nil)
+}
+
+// Partially update a trip.
+// (PATCH /trips/{tripId})
+func (api API) PatchTripsTripID(w http.ResponseWriter, r *http.Request, tripID uuid.UUID, params spec.PatchTripsTripIDParams) *spec.Response {
+	trip, err := api.tripStore.GetTrip(r.Context(), tripID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows){
+			return spec.PatchTripsTripIDJSON400Response(spec.Error{Message: "Trip not found"})
+		}
+		api.logger.Error("Failed to get trip", zap.Error(err), zap.String("trip_id", tripID.String()))
+		return somethingWentWrong(spec.PatchTripsTripIDJSON400Response, err)
+	}
+
+	var body spec.PatchTripRequest
+	if err := decodeJSON(r.Body, &body); err != nil {
+		return spec.PatchTripsTripIDJSON400Response(spec.Error{Message: "Invalid JSON: " + err.Error()})
+	}
+
+	if body.Destination != nil {
+		destination := collapseSpaces(*body.Destination)
+		body.Destination = &destination
+	}
+
+	if err := api.validator.Struct(body); err != nil {
+		return spec.PatchTripsTripIDJSON400Response(spec.Error{Message: "Invalid request body: " + validationMessage(err)})
+	}
+
+	// Merge the present fields into the current trip, the optional fields
+	// left out keep their value as they do on PUT
+	update := spec.UpdateTripRequest{
+		Destination: trip.Destination,
+		StartsAt: trip.StartsAt.Time,
+		EndsAt: trip.EndsAt.Time,
+		Description: body.Description,
+		ImageURL: body.ImageURL,
+	}
+	if body.Destination != nil {
+		update.Destination = *body.Destination
+	}
+	if body.StartsAt != nil {
+		update.StartsAt = *body.StartsAt
+	}
+	if body.EndsAt != nil {
+		update.EndsAt = *body.EndsAt
+	}
+
+	return api.updateTrip(r, trip, update, spec.PutTripsTripIDParams{Force: params.Force, IfMatch: params.IfMatch})
+}
+
+// Get a trip activities.
+// (GET /trips/{tripId}/activities)
+func (api API) GetTripsTripIDActivities(w http.ResponseWriter, r *http.Request, tripID uuid.UUID, params spec.GetTripsTripIDActivitiesParams) *spec.Response {
+	// Limit the itinerary to a single day when a date is given
+	var occursFrom, occursUntil pgtype.Timestamp
+	if params.Date != nil {
+		year, month, day := params.Date.Time.Date()
+		dayStart := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+		occursFrom = pgtype.Timestamp{Valid: true, Time: dayStart}
+		occursUntil = pgtype.Timestamp{Valid: true, Time: dayStart.AddDate(0, 0, 1)}
+	}
+
+	var tag pgtype.Text
+	if params.Tag != nil {
+		tag = pgtype.Text{Valid: true, String: strings.ToLower(strings.TrimSpace(*params.Tag))}
+	}
+
+	activities, err := api.activityStore.GetTripActivities(r.Context(), pgstore.GetTripActivitiesParams{
+		TripID: tripID,
+		OccursFrom: occursFrom,
+		OccursUntil: occursUntil,
+		Tag: tag,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows){
+			return spec.GetTripsTripIDActivitiesJSON400Response(spec.Error{Message: "Activities not found"})
+		}
+
+		api.logger.Error("Failed to get activities", zap.Error(err), zap.String("trip_id", tripID.String()))
+		return somethingWentWrong(spec.GetTripsTripIDActivitiesJSON400Response, err)
+	}
+
+	days := itinerary.GroupByDay(activities)
+	activitiesResponse := make([]spec.GetTripActivitiesResponseOuterArray, len(days))
+	for i, day := range days {
+		activitiesInnerResponse := make([]spec.GetTripActivitiesResponseInnerArray, len(day.Activities))
+		for j, activity := range day.Activities {
+			activitiesInnerResponse[j] = spec.GetTripActivitiesResponseInnerArray{
+				ID: activity.ID.String(),
+				Title: activity.Title,
+				OccursAt: activity.OccursAt.Time,
+				Tags: activity.Tags,
+				CreatedAt: activity.CreatedAt.Time.UTC(),
+				UpdatedAt: activity.UpdatedAt.Time.UTC(),
+			}
+		}
+
+		activitiesResponse[i] = spec.GetTripActivitiesResponseOuterArray{
+			Date: day.Date,
+			Activities: activitiesInnerResponse,
+		}
+	}
+
+	return spec.GetTripsTripIDActivitiesJSON200Response(spec.GetTripActivitiesResponse{
+		Activities: activitiesResponse,
+	})
+}
+
+// Create a trip activity.
+// (POST /trips/{tripId}/activities)
+func (api API) PostTripsTripIDActivities(w http.ResponseWriter, r *http.Request, tripID uuid.UUID) *spec.Response {
+	trip, err := api.tripStore.GetTrip(r.Context(), tripID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows){
+			return spec.PostTripsTripIDActivitiesJSON400Response(spec.Error{Message: "Trip not found"})
+		}
+		api.logger.Error("Failed to get trip", zap.Error(err), zap.String("trip_id", tripID.String()))
+		return somethingWentWrong(spec.PostTripsTripIDActivitiesJSON400Response, err)
+	}
+
+	if trip.Archived {
+		return spec.PostTripsTripIDActivitiesJSON400Response(spec.Error{Message: "Trip is archived, unarchive it to add activities"})
+	}
+
+	if trip.Status == tripStatusCancelled {
+		return spec.PostTripsTripIDActivitiesJSON400Response(spec.Error{Message: "Trip is cancelled, it's not possible to add activities"})
+	}
+
+	var body spec.CreateActivityRequest
+	if err := decodeJSON(r.Body, &body); err != nil {
+		return spec.PostTripsTripIDActivitiesJSON400Response(spec.Error{Message: "Invalid JSON: " + err.Error()})
+	}
+
+	body.Title = collapseSpaces(body.Title)
+
+	if err := api.validator.Struct(body); err != nil {
+		return spec.PostTripsTripIDActivitiesJSON400Response(spec.Error{Message: "Invalid request body: " + validationMessage(err)})
+	}
+
+	if err := validateActivityDate(body.OccursAt, trip.StartsAt.Time, trip.EndsAt.Time); err != nil {
+		return spec.PostTripsTripIDActivitiesJSON400Response(spec.Error{Message: "Invalid request body: " + err.Error()})
+	}
+
+	activityID, err := api.activityStore.CreateActivity(r.Context(), pgstore.CreateActivityParams{
+		TripID: tripID,
+		Title: body.Title,
+		OccursAt: pgtype.Timestamp{Valid: true, Time: body.OccursAt},
+		Tags: normalizeTags(body.Tags),
+	})
+	if err != nil {
+		if errors.Is(err, pgstore.ErrTripNotFound) {
+			return spec.PostTripsTripIDActivitiesJSON400Response(spec.Error{Message: "Trip not found"})
+		}
+		api.logger.Error("Failed to create activity", zap.Error(err), zap.String("trip_id", tripID.String()))
+		return somethingWentWrong(spec.PostTripsTripIDActivitiesJSON400Response, err)
+	}
+
+	api.events.Publish(tripID, events.Event{Type: events.ActivityCreated, ID: activityID.String()})
+
+	return spec.PostTripsTripIDActivitiesJSON201Response(spec.CreateActivityResponse{ActivityID: activityID.String()})
+}
+
+// Get a trip activity.
+// (GET /trips/{tripId}/activities/{activityId})
+func (api API) GetTripsTripIDActivitiesActivityID(w http.ResponseWriter, r *http.Request, tripID uuid.UUID, activityID uuid.UUID) *spec.Response {
+	// An activity from another trip is reported the same way as a missing one
+	activity, err := api.activityStore.GetActivity(r.Context(), pgstore.GetActivityParams{
+		ID: activityID,
+		TripID: tripID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows){
+			return spec.GetTripsTripIDActivitiesActivityIDJSON400Response(spec.Error{Message: "Activity not found"})
+		}
+		api.logger.Error("Failed to get activity", zap.Error(err), zap.String("trip_id", tripID.String()), zap.String("activity_id", activityID.String()))
+		return somethingWentWrong(spec.GetTripsTripIDActivitiesActivityIDJSON400Response, err)
+	}
+
+	return spec.GetTripsTripIDActivitiesActivityIDJSON200Response(spec.GetActivityResponse{
+		Activity: spec.GetTripActivitiesResponseInnerArray{
+			ID: activity.ID.String(),
+			Title: activity.Title,
+			OccursAt: activity.OccursAt.Time,
+			Tags: activity.Tags,
+			CreatedAt: activity.CreatedAt.Time.UTC(),
+			UpdatedAt: activity.UpdatedAt.Time.UTC(),
+		},
+	})
+}
+
+// Export a trip activities as an iCalendar file.
+// (GET /trips/{tripId}/activities.ics)
+func (api API) GetTripsTripIDActivitiesIcs(w http.ResponseWriter, r *http.Request, tripID uuid.UUID) *spec.Response {
+	trip, err := api.tripStore.GetTrip(r.Context(), tripID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows){
+			return spec.GetTripsTripIDActivitiesIcsJSON400Response(spec.Error{Message: "Trip not found"})
+		}
+		api.logger.Error("Failed to get trip", zap.Error(err), zap.String("trip_id", tripID.String()))
+		return somethingWentWrong(spec.GetTripsTripIDActivitiesIcsJSON400Response, err)
+	}
+
+	activities, err := api.activityStore.GetTripActivities(r.Context(), pgstore.GetTripActivitiesParams{TripID: tripID})
+	if err != nil {
+		api.logger.Error("Failed to get trip activities", zap.Error(err), zap.String("trip_id", tripID.String()))
+		return somethingWentWrong(spec.GetTripsTripIDActivitiesIcsJSON400Response, err)
+	}
+
+	events := make([]ics.Event, 0, len(activities)+1)
+	events = append(events, ics.Event{
+		UID: trip.ID.String(),
+		Start: trip.StartsAt.Time,
+		End: trip.EndsAt.Time,
+		Summary: trip.Destination,
+	})
+	for _, activity := range activities {
+		events = append(events, ics.Event{
+			UID: activity.ID.String(),
+			Start: activity.OccursAt.Time,
+			Summary: activity.Title,
+		})
+	}
+
+	calendar := ics.Calendar{
+		Name: trip.Destination,
+		Stamp: time.Now(),
+		Events: events,
+	}
+
+	// The calendar isn't JSON, so it is written straight to the response instead of going through spec.Response
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="trip-`+trip.ID.String()+`.ics"`)
+	w.WriteHeader(http.StatusOK)
+	if err := calendar.Encode(w); err != nil {
+		api.logger.Error("Failed to write calendar", zap.Error(err), zap.String("trip_id", tripID.String()))
+	}
+
+	return nil
+}
+
+// Create many trip activities at once.
+// (POST /trips/{tripId}/activities/batch)
+func (api API) PostTripsTripIDActivitiesBatch(w http.ResponseWriter, r *http.Request, tripID uuid.UUID) *spec.Response {
+	var body spec.CreateActivitiesBatchRequest
+	if err := decodeJSON(r.Body, &body); err != nil {
+		return spec.PostTripsTripIDActivitiesBatchJSON400Response(spec.Error{Message: "Invalid JSON: " + err.Error()})
+	}
+
+	if len(body) == 0 {
+		return spec.PostTripsTripIDActivitiesBatchJSON400Response(spec.Error{Message: "Invalid request body: no activities to create"})
+	}
+
+	trip, err := api.tripStore.GetTrip(r.Context(), tripID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows){
+			return spec.PostTripsTripIDActivitiesBatchJSON400Response(spec.Error{Message: "Trip not found"})
+		}
+		api.logger.Error("Failed to get trip", zap.Error(err), zap.String("trip_id", tripID.String()))
+		return somethingWentWrong(spec.PostTripsTripIDActivitiesBatchJSON400Response, err)
+	}
+
+	if trip.Archived {
+		return spec.PostTripsTripIDActivitiesBatchJSON400Response(spec.Error{Message: "Trip is archived, unarchive it to add activities"})
+	}
+
+	if trip.Status == tripStatusCancelled {
+		return spec.PostTripsTripIDActivitiesBatchJSON400Response(spec.Error{Message: "Trip is cancelled, it's not possible to add activities"})
+	}
+
+	// Validate every activity up front so the client gets all the failures at once
+	var failures []string
+	params := make([]pgstore.CreateActivityParams, len(body))
+	for i, activity := range body {
+		activity.Title = collapseSpaces(activity.Title)
+		if err := api.validator.Struct(activity); err != nil {
+			failures = append(failures, "activities["+strconv.Itoa(i)+"]: "+validationMessage(err))
+			continue
+		}
+		if err := validateActivityDate(activity.OccursAt, trip.StartsAt.Time, trip.EndsAt.Time); err != nil {
+			failures = append(failures, "activities["+strconv.Itoa(i)+"]: "+err.Error())
+			continue
+		}
+		params[i] = pgstore.CreateActivityParams{
+			TripID: tripID,
+			Title: activity.Title,
+			OccursAt: pgtype.Timestamp{Valid: true, Time: activity.OccursAt},
+			Tags: normalizeTags(activity.Tags),
+		}
+	}
+
+	if len(failures) > 0 {
+		return spec.PostTripsTripIDActivitiesBatchJSON400Response(spec.Error{Message: "Invalid request body: " + strings.Join(failures, "; ")})
+	}
+
+	activityIDs, err := api.activityStore.CreateActivities(r.Context(), params)
+	if err != nil {
+		if errors.Is(err, pgstore.ErrTripNotFound) {
+			return spec.PostTripsTripIDActivitiesBatchJSON400Response(spec.Error{Message: "Trip not found"})
+		}
+		api.logger.Error("Failed to create activities", zap.Error(err), zap.String("trip_id", tripID.String()))
+		return somethingWentWrong(spec.PostTripsTripIDActivitiesBatchJSON400Response, err)
+	}
+
+	ids := make([]string, len(activityIDs))
+	for i, activityID := range activityIDs {
+		ids[i] = activityID.String()
+		api.events.Publish(tripID, events.Event{Type: events.ActivityCreated, ID: ids[i]})
+	}
+
+	return spec.PostTripsTripIDActivitiesBatchJSON201Response(spec.CreateActivitiesBatchResponse{ActivityIds: ids})
+}
+
+// Confirm a trip and send e-mail invitations.
+// (GET /trips/{tripId}/confirm)
+func (api API) GetTripsTripIDConfirm(w http.ResponseWriter, r *http.Request, tripID uuid.UUID) *spec.Response {
+	trip, err := api.tripStore.GetTrip(r.Context(), tripID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows){
+			return spec.GetTripsTripIDConfirmJSON400Response(spec.Error{Message: "Trip not found"})	
+		} 
+		api.logger.Error("Failed to get trip", zap.Error(err), zap.String("trip_id", tripID.String()))
+		return somethingWentWrong(spec.GetTripsTripIDConfirmJSON400Response, err)
+	}
+
+	// Confirming again is a no-op, the invitations were enqueued the first time
+	if trip.IsConfirmed {
+		return spec.GetTripsTripIDConfirmJSON204Response(nil)
+	}
+
+	// The invitations are enqueued along with the confirmation, a concurrent request
+	// that confirmed the trip first enqueued them already
+	if _, err := api.tripStore.ConfirmTripAndInvite(r.Context(), tripID); err != nil {
+		api.logger.Error("Failed to confirm trip", zap.Error(err), zap.String("trip_id", tripID.String()))
+		return somethingWentWrong(spec.GetTripsTripIDConfirmJSON400Response, err)
+	}
+
+	return spec.GetTripsTripIDConfirmJSON204Response(nil)
+}
+
+// Unconfirm a trip.
+// (POST /trips/{tripId}/unconfirm)
+func (api API) PostTripsTripIDUnconfirm(w http.ResponseWriter, r *http.Request, tripID uuid.UUID) *spec.Response {
+	trip, err := api.tripStore.GetTrip(r.Context(), tripID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows){
+			return spec.PostTripsTripIDUnconfirmJSON400Response(spec.Error{Message: "Trip not found"})
+		}
+		api.logger.Error("Failed to get trip", zap.Error(err), zap.String("trip_id", tripID.String()))
+		return somethingWentWrong(spec.PostTripsTripIDUnconfirmJSON400Response, err)
+	}
+
+	// The body is optional, an empty one keeps the participants confirmations
+	var body spec.UnconfirmTripRequest
+	if err := decodeJSON(r.Body, &body); err != nil && !errors.Is(err, io.EOF) {
+		return spec.PostTripsTripIDUnconfirmJSON400Response(spec.Error{Message: "Invalid JSON: " + err.Error()})
+	}
+
+	if trip.IsConfirmed {
+		if _, err := api.tripStore.UpdateTrip(r.Context(), pgstore.UpdateTripParams{
+			ID: tripID,
+			Destination: trip.Destination,
+			EndsAt: trip.EndsAt,
+			StartsAt: trip.StartsAt,
+			IsConfirmed: false,
+			Description: trip.Description,
+			ImageUrl: trip.ImageUrl,
+		}); err != nil {
+			api.logger.Error("Failed to unconfirm trip", zap.Error(err), zap.String("trip_id", tripID.String()))
+			return somethingWentWrong(spec.PostTripsTripIDUnconfirmJSON400Response, err)
+		}
+	}
+
+	if body.ResetParticipants != nil && *body.ResetParticipants {
+		if err := api.participantStore.ResetParticipantsConfirmation(r.Context(), tripID); err != nil {
+			api.logger.Error("Failed to reset participants confirmation", zap.Error(err), zap.String("trip_id", tripID.String()))
+			return somethingWentWrong(spec.PostTripsTripIDUnconfirmJSON400Response, err)
+		}
+	}
+
+	return spec.PostTripsTripIDUnconfirmJSON204Response(nil)
+}
+
+// Archive a trip.
+// (POST /trips/{tripId}/archive)
+func (api API) PostTripsTripIDArchive(w http.ResponseWriter, r *http.Request, tripID uuid.UUID) *spec.Response {
+	trip, err := api.tripStore.GetTrip(r.Context(), tripID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows){
+			return spec.PostTripsTripIDArchiveJSON400Response(spec.Error{Message: "Trip not found"})
+		}
+		api.logger.Error("Failed to get trip", zap.Error(err), zap.String("trip_id", tripID.String()))
+		return somethingWentWrong(spec.PostTripsTripIDArchiveJSON400Response, err)
+	}
+
+	if !trip.Archived {
+		if err := api.tripStore.SetTripArchived(r.Context(), pgstore.SetTripArchivedParams{
+			ID: tripID,
+			Archived: true,
+		}); err != nil {
+			api.logger.Error("Failed to archive trip", zap.Error(err), zap.String("trip_id", tripID.String()))
+			return somethingWentWrong(spec.PostTripsTripIDArchiveJSON400Response, err)
+		}
+	}
+
+	return spec.PostTripsTripIDArchiveJSON204Response(nil)
+}
+
+// Unarchive a trip.
+// (POST /trips/{tripId}/unarchive)
+func (api API) PostTripsTripIDUnarchive(w http.ResponseWriter, r *http.Request, tripID uuid.UUID) *spec.Response {
+	trip, err := api.tripStore.GetTrip(r.Context(), tripID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows){
+			return spec.PostTripsTripIDUnarchiveJSON400Response(spec.Error{Message: "Trip not found"})
+		}
+		api.logger.Error("Failed to get trip", zap.Error(err), zap.String("trip_id", tripID.String()))
+		return somethingWentWrong(spec.PostTripsTripIDUnarchiveJSON400Response, err)
+	}
+
+	if trip.Archived {
+		if err := api.tripStore.SetTripArchived(r.Context(), pgstore.SetTripArchivedParams{
+			ID: tripID,
+			Archived: false,
+		}); err != nil {
+			api.logger.Error("Failed to unarchive trip", zap.Error(err), zap.String("trip_id", tripID.String()))
+			return somethingWentWrong(spec.PostTripsTripIDUnarchiveJSON400Response, err)
+		}
+	}
+
+	return spec.PostTripsTripIDUnarchiveJSON204Response(nil)
+}
+
+// Cancel a trip and notify its participants.
+// (POST /trips/{tripId}/cancel)
+func (api API) PostTripsTripIDCancel(w http.ResponseWriter, r *http.Request, tripID uuid.UUID) *spec.Response {
+	if _, err := api.tripStore.GetTrip(r.Context(), tripID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows){
+			return spec.PostTripsTripIDCancelJSON400Response(spec.Error{Message: "Trip not found"})
+		}
+		api.logger.Error("Failed to get trip", zap.Error(err), zap.String("trip_id", tripID.String()))
+		return somethingWentWrong(spec.PostTripsTripIDCancelJSON400Response, err)
+	}
+
+	// A trip already cancelled changes no row and notifies no one again, which
+	// is answered the same
+	if _, err := api.tripStore.CancelTripAndNotify(r.Context(), tripID); err != nil {
+		api.logger.Error("Failed to cancel trip", zap.Error(err), zap.String("trip_id", tripID.String()))
+		return somethingWentWrong(spec.PostTripsTripIDCancelJSON400Response, err)
+	}
+
+	return spec.PostTripsTripIDCancelJSON204Response(nil)
+}
+
+// Invite someone to the trip.
+// (POST /trips/{tripId}/invites)
+func (api API) PostTripsTripIDInvites(w http.ResponseWriter, r *http.Request, tripID uuid.UUID) *spec.Response {
+	trip, err := api.tripStore.GetTrip(r.Context(), tripID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows){
+			return spec.PostTripsTripIDInvitesJSON400Response(spec.Error{Message: "Trip not found"})
+		}
+		api.logger.Error("Failed to get trip", zap.Error(err), zap.String("trip_id", tripID.String()))
+		return somethingWentWrong(spec.PostTripsTripIDInvitesJSON400Response, err)
+	}
+
+	if trip.Archived {
+		return spec.PostTripsTripIDInvitesJSON400Response(spec.Error{Message: "Trip is archived, unarchive it to invite participants"})
+	}
+
+	if trip.Status == tripStatusCancelled {
+		return spec.PostTripsTripIDInvitesJSON400Response(spec.Error{Message: "Trip is cancelled, it's not possible to invite participants"})
+	}
+
+	var body spec.InviteParticipantRequest
+	if err := decodeJSON(r.Body, &body); err != nil {
+		return spec.PostTripsTripIDInvitesJSON400Response(spec.Error{Message: "Invalid JSON: " + err.Error()})
+	}
+
+	if err := api.validator.Struct(body); err != nil {
+		return spec.PostTripsTripIDInvitesJSON400Response(spec.Error{Message: "Invalid request body: " + validationMessage(err)})
+	}
+
+	email, err := validateEmail("email", body.Email)
+	if err != nil {
+		return spec.PostTripsTripIDInvitesJSON400Response(spec.Error{Message: "Invalid request body: " + err.Error()})
+	}
+	body.Email = email
+
+	if isTripOwner(string(body.Email), trip.OwnerEmail) {
+		return spec.PostTripsTripIDInvitesJSON400Response(spec.Error{Message: "The trip owner can't be invited to their own trip"})
+	}
+
+	// A participant who declined is invited again, keeping their row
+	participant, err := api.participantStore.GetParticipantByEmail(r.Context(), pgstore.GetParticipantByEmailParams{
+		TripID: trip.ID,
+		Email: string(body.Email),
+	})
+	if err == nil && !participant.IsDeclined {
+		return spec.PostTripsTripIDInvitesJSON409Response(spec.InviteConflictResponse{
+			Message: "Participant already invited to this trip",
+			ParticipantID: participant.ID.String(),
+			IsConfirmed: participant.IsConfirmed,
+		})
+	}
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		api.logger.Error("Failed to get participant", zap.Error(err), zap.String("trip_id", tripID.String()))
+		return somethingWentWrong(spec.PostTripsTripIDInvitesJSON400Response, err)
+	}
+
+	// Only a new participant counts towards the limit, reviving one doesn't add any
+	if errors.Is(err, pgx.ErrNoRows) {
+		count, err := api.participantStore.CountParticipants(r.Context(), pgstore.CountParticipantsParams{TripID: trip.ID})
+		if err != nil {
+			api.logger.Error("Failed to count participants", zap.Error(err), zap.String("trip_id", tripID.String()))
+			return somethingWentWrong(spec.PostTripsTripIDInvitesJSON400Response, err)
+		}
+		if count >= int64(api.maxParticipants) {
+			return spec.PostTripsTripIDInvitesJSON400Response(spec.Error{Message: "Too many participants: a trip allows at most " + strconv.Itoa(api.maxParticipants) + " participants and this one already has " + strconv.FormatInt(count, 10)})
+		}
+	}
+
+	// An unsubscribed address is still invited, the caller is only told it won't be e-mailed
+	suppressed, err := api.emailStore.IsEmailSuppressed(r.Context(), string(body.Email))
+	if err != nil {
+		api.logger.Error("Failed to check suppression list", zap.Error(err), zap.String("trip_id", tripID.String()))
+		return somethingWentWrong(spec.PostTripsTripIDInvitesJSON400Response, err)
+	}
+
+	if trip.IsConfirmed && !suppressed {
+		if err := api.mailLimiter.Reserve(trip.ID, 1); err != nil {
+			rateLimitErr, _ := mailer.AsRateLimitError(err)
+			setRetryAfter(w, rateLimitErr.RetryAfter)
+			return spec.PostTripsTripIDInvitesJSON429Response(spec.Error{Message: "Too many e-mails sent for this trip, try again later"})
+		}
+	}
+
+	// Participants of an unconfirmed trip are e-mailed when the owner confirms it,
+	// so only enqueue the invitation right away if that already happened.
+	participantID, created, err := api.participantStore.InviteParticipant(r.Context(), pgstore.InviteParticipantToTripParams{
+		TripID: trip.ID,
+		Email: string(body.Email),
+	}, trip.IsConfirmed)
+	if err != nil {
+		if errors.Is(err, pgstore.ErrTripNotFound) {
+			return spec.PostTripsTripIDInvitesJSON400Response(spec.Error{Message: "Trip not found"})
+		}
+		// Invited by a concurrent request since the check above
+		if errors.Is(err, pgstore.ErrDuplicateParticipant) {
+			participant, err := api.participantStore.GetParticipantByEmail(r.Context(), pgstore.GetParticipantByEmailParams{
+				TripID: trip.ID,
+				Email: string(body.Email),
+			})
+			if err == nil {
+				return spec.PostTripsTripIDInvitesJSON409Response(spec.InviteConflictResponse{
+					Message: "Participant already invited to this trip",
+					ParticipantID: participant.ID.String(),
+					IsConfirmed: participant.IsConfirmed,
+				})
+			}
+		}
+		api.logger.Error("Failed to invite participant", zap.Error(err), zap.String("trip_id", tripID.String()))
+		return somethingWentWrong(spec.PostTripsTripIDInvitesJSON400Response, err)
+	}
+
+	api.events.Publish(trip.ID, events.Event{Type: events.ParticipantInvited, ID: participantID.String()})
+
+	if suppressed {
+		w.Header().Set("X-Email-Suppressed", "true")
+	}
+	if !created {
+		return spec.PostTripsTripIDInvitesJSON200Response(nil)
+	}
+	return spec.PostTripsTripIDInvitesJSON201Response(nil)
+}
+
+// Get a trip links.
+// (GET /trips/{tripId}/links)
+func (api API) GetTripsTripIDLinks(w http.ResponseWriter, r *http.Request, tripID uuid.UUID, params spec.GetTripsTripIDLinksParams) *spec.Response {
+	// Without a limit every link is returned, as before pagination existed
+	var limit pgtype.Int4
+	if params.Limit != nil {
+		if *params.Limit < 0 {
+			return spec.GetTripsTripIDLinksJSON400Response(spec.Error{Message: "Invalid limit: must not be negative"})
+		}
+		limit = pgtype.Int4{Valid: true, Int32: int32(min(*params.Limit, maxLinksLimit))}
+	}
+
+	offset := 0
+	if params.Offset != nil {
+		if *params.Offset < 0 || *params.Offset > math.MaxInt32 {
+			return spec.GetTripsTripIDLinksJSON400Response(spec.Error{Message: "Invalid offset: must be between 0 and " + strconv.Itoa(math.MaxInt32)})
+		}
+		offset = *params.Offset
+	}
+
+	links, err := api.linkStore.ListTripLinks(r.Context(), pgstore.ListTripLinksParams{
+		TripID: tripID,
+		Limit: limit,
+		Offset: int32(offset),
+	})
+	if err != nil {
+		api.logger.Error("Failed to get links", zap.Error(err), zap.String("trip_id", tripID.String()))
+		return somethingWentWrong(spec.GetTripsTripIDLinksJSON400Response, err)
+	}
+
+	count, err := api.linkStore.CountTripLinks(r.Context(), tripID)
+	if err != nil {
+		api.logger.Error("Failed to count links", zap.Error(err), zap.String("trip_id", tripID.String()))
+		return somethingWentWrong(spec.GetTripsTripIDLinksJSON400Response, err)
+	}
+
+	linksResponse := make([]spec.GetLinksResponseArray, len(links))
+	for i, link := range links {
+		linksResponse[i] = spec.GetLinksResponseArray{
+			ID: link.ID.String(),
+			Title: link.Title,
+			URL: link.Url,
+			CreatedAt: link.CreatedAt.Time.UTC(),
+			UpdatedAt: link.UpdatedAt.Time.UTC(),
+		}
+	}
+
+	return spec.GetTripsTripIDLinksJSON200Response(spec.GetLinksResponse{
+		Links: linksResponse,
+		Count: int(count),
+	})
+}
+
+// Create a trip link.
+// (POST /trips/{tripId}/links)
+func (api API) PostTripsTripIDLinks(w http.ResponseWriter, r *http.Request, tripID uuid.UUID) *spec.Response {
+	trip, err := api.tripStore.GetTrip(r.Context(), tripID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows){
+			return spec.PostTripsTripIDLinksJSON400Response(spec.Error{Message: "Trip not found"})
+		}
+		api.logger.Error("Failed to get trip", zap.Error(err), zap.String("trip_id", tripID.String()))
+		return somethingWentWrong(spec.PostTripsTripIDLinksJSON400Response, err)
+	}
+
+	if trip.Archived {
+		return spec.PostTripsTripIDLinksJSON400Response(spec.Error{Message: "Trip is archived, unarchive it to add links"})
+	}
+
+	if trip.Status == tripStatusCancelled {
+		return spec.PostTripsTripIDLinksJSON400Response(spec.Error{Message: "Trip is cancelled, it's not possible to add links"})
+	}
+
+	var body spec.CreateLinkRequest
+	if err := decodeJSON(r.Body, &body); err != nil {
+		return spec.PostTripsTripIDLinksJSON400Response(spec.Error{Message: "Invalid JSON: " + err.Error()})
+	}
+
+	body.Title = collapseSpaces(body.Title)
+
+	if err := api.validator.Struct(body); err != nil {
+		return spec.PostTripsTripIDLinksJSON400Response(spec.Error{Message: "Invalid request body: " + validationMessage(err)})
+	}
+
+	linkURL, err := normalizeLinkURL(body.URL)
+	if err != nil {
+		return spec.PostTripsTripIDLinksJSON400Response(spec.Error{Message: "Invalid request body: " + err.Error()})
+	}
+
+	linkID, err := api.linkStore.CreateTripLink(r.Context(), pgstore.CreateTripLinkParams{
+		TripID: tripID,
+		Title: body.Title,
+		Url: linkURL,
+	})
+	if err != nil {
+		if errors.Is(err, pgstore.ErrDuplicateLink) {
+			return spec.PostTripsTripIDLinksJSON409Response(spec.Error{Message: "Trip already has a link to this URL"})
+		}
+		if errors.Is(err, pgstore.ErrTripNotFound) {
+			return spec.PostTripsTripIDLinksJSON400Response(spec.Error{Message: "Trip not found"})
+		}
+		api.logger.Error("Failed to create link", zap.Error(err), zap.String("trip_id", tripID.String()))
+		return somethingWentWrong(spec.PostTripsTripIDLinksJSON400Response, err)
+	}
+
+	api.events.Publish(tripID, events.Event{Type: events.LinkCreated, ID: linkID.String()})
+
+	return spec.PostTripsTripIDLinksJSON201Response(spec.CreateLinkResponse{LinkID: linkID.String()})
+}
+
+// Update a trip link.
+// (PUT /trips/{tripId}/links/{linkId})
+func (api API) PutTripsTripIDLinksLinkID(w http.ResponseWriter, r *http.Request, tripID uuid.UUID, linkID uuid.UUID) *spec.Response {
+	link, err := api.linkStore.GetLink(r.Context(), linkID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows){
+			return spec.PutTripsTripIDLinksLinkIDJSON400Response(spec.Error{Message: "Link not found"})
+		}
+		api.logger.Error("Failed to get link", zap.Error(err), zap.String("trip_id", tripID.String()), zap.String("link_id", linkID.String()))
+		return somethingWentWrong(spec.PutTripsTripIDLinksLinkIDJSON400Response, err)
+	}
+
+	// A link from another trip is reported the same way as a missing one
+	if link.TripID != tripID {
+		return spec.PutTripsTripIDLinksLinkIDJSON400Response(spec.Error{Message: "Link not found"})
+	}
+
+	var body spec.UpdateLinkRequest
+	if err := decodeJSON(r.Body, &body); err != nil {
+		return spec.PutTripsTripIDLinksLinkIDJSON400Response(spec.Error{Message: "Invalid JSON: " + err.Error()})
+	}
+
+	body.Title = collapseSpaces(body.Title)
+
+	if err := api.validator.Struct(body); err != nil {
+		return spec.PutTripsTripIDLinksLinkIDJSON400Response(spec.Error{Message: "Invalid request body: " + validationMessage(err)})
+	}
+
+	linkURL, err := normalizeLinkURL(body.URL)
+	if err != nil {
+		return spec.PutTripsTripIDLinksLinkIDJSON400Response(spec.Error{Message: "Invalid request body: " + err.Error()})
+	}
+
+	if err := api.linkStore.UpdateLink(r.Context(), pgstore.UpdateLinkParams{
+		ID: link.ID,
+		Title: body.Title,
+		Url: linkURL,
+	}); err != nil {
+		if errors.Is(err, pgstore.ErrDuplicateLink) {
+			return spec.PutTripsTripIDLinksLinkIDJSON409Response(spec.Error{Message: "Trip already has a link to this URL"})
+		}
+		api.logger.Error("Failed to update link", zap.Error(err), zap.String("trip_id", tripID.String()), zap.String("link_id", linkID.String()))
+		return somethingWentWrong(spec.PutTripsTripIDLinksLinkIDJSON400Response, err)
+	}
+
+	api.events.Publish(link.TripID, events.Event{Type: events.LinkUpdated, ID: linkID.String()})
+
+	return spec.PutTripsTripIDLinksLinkIDJSON204Response(nil)
+}
+
+// Duplicate a trip.
+// (POST /trips/{tripId}/duplicate)
+func (api API) PostTripsTripIDDuplicate(w http.ResponseWriter, r *http.Request, tripID uuid.UUID) *spec.Response {
+	// The body is optional, an empty one means no date shift
+	var body spec.DuplicateTripRequest
+	if err := decodeJSON(r.Body, &body); err != nil && !errors.Is(err, io.EOF) {
+		return spec.PostTripsTripIDDuplicateJSON400Response(spec.Error{Message: "Invalid JSON: " + err.Error()})
+	}
+
+	var offsetDays int
+	if body.OffsetDays != nil {
+		offsetDays = *body.OffsetDays
+	}
+	if offsetDays < -maxDuplicateOffsetDays || offsetDays > maxDuplicateOffsetDays {
+		return spec.PostTripsTripIDDuplicateJSON400Response(spec.Error{Message: "Invalid request body: offset_days must be between -" + strconv.Itoa(maxDuplicateOffsetDays) + " and " + strconv.Itoa(maxDuplicateOffsetDays)})
+	}
+
+	newTripID, err := api.tripStore.DuplicateTrip(r.Context(), tripID, offsetDays)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows){
+			return spec.PostTripsTripIDDuplicateJSON400Response(spec.Error{Message: "Trip not found"})
+		}
+		api.logger.Error("Failed to duplicate trip", zap.Error(err), zap.String("trip_id", tripID.String()))
+		return somethingWentWrong(spec.PostTripsTripIDDuplicateJSON400Response, err)
+	}
+
+	return spec.PostTripsTripIDDuplicateJSON201Response(spec.CreateTripResponse{TripID: newTripID.String()})
+}
+
+// Export a trip with all its data.
+// (GET /trips/{tripId}/export)
+func (api API) GetTripsTripIDExport(w http.ResponseWriter, r *http.Request, tripID uuid.UUID) *spec.Response {
+	export, err := api.tripStore.ExportTrip(r.Context(), tripID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows){
+			return spec.GetTripsTripIDExportJSON400Response(spec.Error{Message: "Trip not found"})
+		}
+		api.logger.Error("Failed to export trip", zap.Error(err), zap.String("trip_id", tripID.String()))
+		return somethingWentWrong(spec.GetTripsTripIDExportJSON400Response, err)
+	}
+
+	participants := make([]spec.GetTripParticipantsResponseArray, len(export.Participants))
+	for i, participant := range export.Participants {
+		var name *string
+		if participant.Name.Valid {
+			name = &participant.Name.String
+		}
+
+		participants[i] = spec.GetTripParticipantsResponseArray{
+			ID: participant.ID.String(),
+			Name: name,
+			Email: types.Email(participant.Email),
+			IsConfirmed: participant.IsConfirmed,
+			IsDeclined: participant.IsDeclined,
+			IsOwner: isTripOwner(participant.Email, export.Trip.OwnerEmail),
+			InviteSentAt: timestampPointer(participant.InviteSentAt),
+			LastInviteError: textPointer(participant.LastInviteError),
+		}
+	}
+
+	activities := make([]spec.GetTripActivitiesResponseInnerArray, len(export.Activities))
+	for i, activity := range export.Activities {
+		activities[i] = spec.GetTripActivitiesResponseInnerArray{
+			ID: activity.ID.String(),
+			Title: activity.Title,
+			OccursAt: activity.OccursAt.Time,
+			Tags: activity.Tags,
+			CreatedAt: activity.CreatedAt.Time.UTC(),
+			UpdatedAt: activity.UpdatedAt.Time.UTC(),
+		}
+	}
+
+	links := make([]spec.GetLinksResponseArray, len(export.Links))
+	for i, link := range export.Links {
+		links[i] = spec.GetLinksResponseArray{
+			ID: link.ID.String(),
+			Title: link.Title,
+			URL: link.Url,
+			CreatedAt: link.CreatedAt.Time.UTC(),
+			UpdatedAt: link.UpdatedAt.Time.UTC(),
+		}
+	}
+
+	return spec.GetTripsTripIDExportJSON200Response(spec.GetTripExportResponse{
+		Version: tripExportVersion,
+		Trip: spec.GetTripExportResponseTripObj{
+			ID: export.Trip.ID.String(),
+			Destination: export.Trip.Destination,
+			OwnerName: export.Trip.OwnerName,
+			OwnerEmail: types.Email(export.Trip.OwnerEmail),
+			StartsAt: export.Trip.StartsAt.Time,
+			EndsAt: export.Trip.EndsAt.Time,
+			IsConfirmed: export.Trip.IsConfirmed,
+			Description: textPointer(export.Trip.Description),
+			ImageURL: textPointer(export.Trip.ImageUrl),
+			CreatedAt: export.Trip.CreatedAt.Time.UTC(),
+			UpdatedAt: export.Trip.UpdatedAt.Time.UTC(),
+			Participants: participants,
+			Activities: activities,
+			Links: links,
+		},
+	})
+}
+
+// Stream a trip changes as server-sent events.
+// (GET /trips/{tripId}/events)
+func (api API) GetTripsTripIDEvents(w http.ResponseWriter, r *http.Request, tripID uuid.UUID) *spec.Response {
+	if _, err := api.tripStore.GetTrip(r.Context(), tripID); err != nil {
+		if errors.Is(err, pgx.ErrNoRows){
+			return spec.GetTripsTripIDEventsJSON400Response(spec.Error{Message: "Trip not found"})
+		}
+		api.logger.Error("Failed to get trip", zap.Error(err), zap.String("trip_id", tripID.String()))
+		return somethingWentWrong(spec.GetTripsTripIDEventsJSON400Response, err)
+	}
+
+	tripEvents, unsubscribe, err := api.events.Subscribe(tripID)
+	if err != nil {
+		if errors.Is(err, events.ErrTooManySubscribers) {
+			return spec.GetTripsTripIDEventsJSON400Response(spec.Error{Message: "Too many clients are following this trip, try again later"})
+		}
+		if errors.Is(err, events.ErrClosed) {
+			return spec.GetTripsTripIDEventsJSON400Response(spec.Error{Message: "Server is shutting down, try again later"})
+		}
+		api.logger.Error("Failed to subscribe to trip events", zap.Error(err), zap.String("trip_id", tripID.String()))
+		return somethingWentWrong(spec.GetTripsTripIDEventsJSON400Response, err)
+	}
+	defer unsubscribe()
+
+	// The stream outlives the server write timeout, so lift it for this response only
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		api.logger.Error("Failed to clear write deadline", zap.Error(err), zap.String("trip_id", tripID.String()))
+		return somethingWentWrong(spec.GetTripsTripIDEventsJSON400Response, err)
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	if err := rc.Flush(); err != nil {
+		return nil
+	}
+
+	heartbeat := time.NewTicker(tripEventsHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return nil
+		case <-heartbeat.C:
+			if _, err := io.WriteString(w, ": heartbeat\n\n"); err != nil {
+				return nil
+			}
+		case event, ok := <-tripEvents:
+			// The server is shutting down
+			if !ok {
+				return nil
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				api.logger.Error("Failed to encode trip event", zap.Error(err), zap.String("trip_id", tripID.String()))
+				continue
+			}
+			if _, err := io.WriteString(w, "event: "+event.Type+"\ndata: "+string(data)+"\n\n"); err != nil {
+				return nil
+			}
+		}
+
+		// A failed flush means the client went away
+		if err := rc.Flush(); err != nil {
+			return nil
+		}
+	}
+}
+
+// Get a trip summary.
+// (GET /trips/{tripId}/summary)
+func (api API) GetTripsTripIDSummary(w http.ResponseWriter, r *http.Request, tripID uuid.UUID) *spec.Response {
+	summary, err := api.tripStore.GetTripSummary(r.Context(), tripID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows){
+			return spec.GetTripsTripIDSummaryJSON400Response(spec.Error{Message: "Trip not found"})
+		}
+		api.logger.Error("Failed to get trip summary", zap.Error(err), zap.String("trip_id", tripID.String()))
+		return somethingWentWrong(spec.GetTripsTripIDSummaryJSON400Response, err)
+	}
+
+	return spec.GetTripsTripIDSummaryJSON200Response(spec.GetTripSummaryResponse{
+		Trip: tripObj(summary.Trip()),
+		ParticipantsCount: int(summary.ParticipantsCount),
+		ConfirmedParticipantsCount: int(summary.ConfirmedParticipantsCount),
+		ActivitiesCount: int(summary.ActivitiesCount),
+		LinksCount: int(summary.LinksCount),
+	})
+}
+
+// Get a trip participants.
+// (GET /trips/{tripId}/participants)
+func (api API) GetTripsTripIDParticipants(w http.ResponseWriter, r *http.Request, tripID uuid.UUID, params spec.GetTripsTripIDParticipantsParams) *spec.Response {
+	limit := defaultParticipantsLimit
+	if params.Limit != nil {
+		if *params.Limit < 0 {
+			return spec.GetTripsTripIDParticipantsJSON400Response(spec.Error{Message: "Invalid limit: must not be negative"})
+		}
+		limit = min(*params.Limit, maxParticipantsLimit)
+	}
+
+	offset := 0
+	if params.Offset != nil {
+		if *params.Offset < 0 || *params.Offset > math.MaxInt32 {
+			return spec.GetTripsTripIDParticipantsJSON400Response(spec.Error{Message: "Invalid offset: must be between 0 and " + strconv.Itoa(math.MaxInt32)})
+		}
+		offset = *params.Offset
+	}
+
+	var isConfirmed pgtype.Bool
+	if params.IsConfirmed != nil {
+		value, err := strconv.ParseBool(*params.IsConfirmed)
+		if err != nil {
+			return spec.GetTripsTripIDParticipantsJSON400Response(spec.Error{Message: "Invalid is_confirmed: must be true or false"})
+		}
+		isConfirmed = pgtype.Bool{Valid: true, Bool: value}
+	}
+
+	trip, err := api.tripStore.GetTrip(r.Context(), tripID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows){
+			return spec.GetTripsTripIDParticipantsJSON400Response(spec.Error{Message: "Trip not found"})	
+		} 
+		api.logger.Error("Failed to get trip", zap.Error(err), zap.String("trip_id", tripID.String()))
+		return somethingWentWrong(spec.GetTripsTripIDParticipantsJSON400Response, err)
+	}
+
+	var participants []pgstore.Participant
+	if isConfirmed.Valid && !isConfirmed.Bool {
+		// Its own query, so the unconfirmed participants are found through their partial index
+		participants, err = api.participantStore.GetUnconfirmedParticipants(r.Context(), pgstore.GetUnconfirmedParticipantsParams{
+			TripID: trip.ID,
+			Limit: int32(limit),
+			Offset: int32(offset),
+		})
+	} else {
+		participants, err = api.participantStore.ListParticipants(r.Context(), pgstore.ListParticipantsParams{
+			TripID: trip.ID,
+			IsConfirmed: isConfirmed,
+			Limit: int32(limit),
+			Offset: int32(offset),
+		})
+	}
+	if err != nil {
+		api.logger.Error("Failed to get participants", zap.Error(err), zap.String("trip_id", tripID.String()))
+		return somethingWentWrong(spec.GetTripsTripIDParticipantsJSON400Response, err)
+	}
+
+	total, err := api.participantStore.CountParticipants(r.Context(), pgstore.CountParticipantsParams{
+		TripID: trip.ID,
+		IsConfirmed: isConfirmed,
+	})
+	if err != nil {
+		api.logger.Error("Failed to count participants", zap.Error(err), zap.String("trip_id", tripID.String()))
+		return somethingWentWrong(spec.GetTripsTripIDParticipantsJSON400Response, err)
+	}
+
+	participantsResponse := make([]spec.GetTripParticipantsResponseArray , len(participants))
+	for i, participant := range participants {
+
+		var name *string
+		if participant.Name.Valid {
+			name = &participant.Name.String
+		}
+
+		participantsResponse[i] = spec.GetTripParticipantsResponseArray {
+			ID: participant.ID.String(),
+			Name: name,
+			Email: types.Email(participant.Email),
+			IsConfirmed: participant.IsConfirmed,
+			IsDeclined: participant.IsDeclined,
+			IsOwner: isTripOwner(participant.Email, trip.OwnerEmail),
+			InviteSentAt: timestampPointer(participant.InviteSentAt),
+			LastInviteError: textPointer(participant.LastInviteError),
+		}
+	}
+
+	return spec.GetTripsTripIDParticipantsJSON200Response(spec.GetTripParticipantsResponse{
+		Participants: participantsResponse,
+		Limit: limit,
+		Offset: offset,
+		Total: int(total),
+	})
+}
+
+// Get the e-mails sent about a trip.
+// (GET /trips/{tripId}/emails)
+func (api API) GetTripsTripIDEmails(w http.ResponseWriter, r *http.Request, tripID uuid.UUID, params spec.GetTripsTripIDEmailsParams) *spec.Response {
+	limit := defaultEmailsLimit
+	if params.Limit != nil {
+		if *params.Limit < 0 {
+			return spec.GetTripsTripIDEmailsJSON400Response(spec.Error{Message: "Invalid limit: must not be negative"})
+		}
+		limit = min(*params.Limit, maxEmailsLimit)
+	}
+
+	offset := 0
+	if params.Offset != nil {
+		if *params.Offset < 0 || *params.Offset > math.MaxInt32 {
+			return spec.GetTripsTripIDEmailsJSON400Response(spec.Error{Message: "Invalid offset: must be between 0 and " + strconv.Itoa(math.MaxInt32)})
+		}
+		offset = *params.Offset
+	}
+
+	trip, err := api.tripStore.GetTrip(r.Context(), tripID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows){
+			return spec.GetTripsTripIDEmailsJSON400Response(spec.Error{Message: "Trip not found"})
+		}
+		api.logger.Error("Failed to get trip", zap.Error(err), zap.String("trip_id", tripID.String()))
+		return somethingWentWrong(spec.GetTripsTripIDEmailsJSON400Response, err)
+	}
+
+	emails, err := api.emailStore.ListTripEmailLog(r.Context(), pgstore.ListTripEmailLogParams{
+		TripID: trip.ID,
+		Limit: int32(limit),
+		Offset: int32(offset),
+	})
+	if err != nil {
+		api.logger.Error("Failed to get email log", zap.Error(err), zap.String("trip_id", tripID.String()))
+		return somethingWentWrong(spec.GetTripsTripIDEmailsJSON400Response, err)
+	}
+
+	total, err := api.emailStore.CountTripEmailLog(r.Context(), trip.ID)
+	if err != nil {
+		api.logger.Error("Failed to count email log", zap.Error(err), zap.String("trip_id", tripID.String()))
+		return somethingWentWrong(spec.GetTripsTripIDEmailsJSON400Response, err)
+	}
+
+	emailsResponse := make([]spec.GetTripEmailsResponseArray, len(emails))
+	for i, email := range emails {
+		var participantID *string
+		if email.ParticipantID.Valid {
+			id := uuid.UUID(email.ParticipantID.Bytes).String()
+			participantID = &id
+		}
+
+		// The mailer only records the kinds and statuses the enums list
+		var kind spec.GetTripEmailsResponseArrayKind
+		_ = kind.FromValue(email.Kind)
+		var status spec.GetTripEmailsResponseArrayStatus
+		_ = status.FromValue(email.Status)
+
+		emailsResponse[i] = spec.GetTripEmailsResponseArray{
+			ID: email.ID.String(),
+			ParticipantID: participantID,
+			Recipient: email.Recipient,
+			Kind: kind,
+			Status: status,
+			Error: textPointer(email.Error),
+			CreatedAt: email.CreatedAt.Time.UTC(),
+		}
+	}
+
+	return spec.GetTripsTripIDEmailsJSON200Response(spec.GetTripEmailsResponse{
+		Emails: emailsResponse,
+		Limit: limit,
+		Offset: offset,
+		Total: int(total),
+	})
+}
+
+// Remove a participant from a trip.
+// (DELETE /trips/{tripId}/participants/{participantId})
+func (api API) DeleteTripsTripIDParticipantsParticipantID(w http.ResponseWriter, r *http.Request, tripID uuid.UUID, participantID uuid.UUID, params spec.DeleteTripsTripIDParticipantsParticipantIDParams) *spec.Response {
+	participant, err := api.participantStore.GetParticipant(r.Context(), participantID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows){
+			return spec.DeleteTripsTripIDParticipantsParticipantIDJSON400Response(spec.Error{Message: "Participant not found"})
+		}
+		api.logger.Error("Failed to get participant", zap.Error(err), zap.String("trip_id", tripID.String()), zap.String("participant_id", participantID.String()))
+		return somethingWentWrong(spec.DeleteTripsTripIDParticipantsParticipantIDJSON400Response, err)
+	}
+
+	// A participant from another trip is reported the same way as a missing one
+	if participant.TripID != tripID {
+		return spec.DeleteTripsTripIDParticipantsParticipantIDJSON400Response(spec.Error{Message: "Participant not found"})
+	}
+
+	trip, err := api.tripStore.GetTrip(r.Context(), tripID)
+	if err != nil {
+		api.logger.Error("Failed to get trip", zap.Error(err), zap.String("trip_id", tripID.String()))
+		return somethingWentWrong(spec.DeleteTripsTripIDParticipantsParticipantIDJSON400Response, err)
+	}
+
+	if isTripOwner(participant.Email, trip.OwnerEmail) {
+		return spec.DeleteTripsTripIDParticipantsParticipantIDJSON400Response(spec.Error{Message: "The trip owner can't be removed from the trip"})
+	}
+
+	force := params.Force != nil && *params.Force
+	if participant.IsConfirmed && !force {
+		return spec.DeleteTripsTripIDParticipantsParticipantIDJSON400Response(spec.Error{Message: "Participant already confirmed, pass force=true to remove them"})
+	}
+
+	if err := api.participantStore.DeleteParticipant(r.Context(), participantID); err != nil {
+		api.logger.Error("Failed to delete participant", zap.Error(err), zap.String("trip_id", tripID.String()), zap.String("participant_id", participantID.String()))
+		return somethingWentWrong(spec.DeleteTripsTripIDParticipantsParticipantIDJSON400Response, err)
+	}
+
+	api.events.Publish(tripID, events.Event{Type: events.ParticipantRemoved, ID: participantID.String()})
+
+	return spec.DeleteTripsTripIDParticipantsParticipantIDJSON204Response(nil)
+}
+
+// isTripOwner reports whether a participant is the trip owner, emails being compared case-insensitively.
+func isTripOwner(participantEmail, ownerEmail string) bool {
+	return strings.EqualFold(participantEmail, ownerEmail)
+}
+
+// tripStatus maps a trips.status value to the status exposed on trip responses.
+func tripStatus(status string) spec.GetTripDetailsResponseTripObjStatus {
+	if status == tripStatusCancelled {
+		return spec.GetTripDetailsResponseTripObjStatusCancelled
+	}
+	return spec.GetTripDetailsResponseTripObjStatusActive
+}
+
+// tripETag is the ETag of a trip version, sent back by clients as If-Match.
+func tripETag(version int32) string {
+	return `"` + strconv.Itoa(int(version)) + `"`
+}
+
+// parseTripETag returns the trip version an If-Match header expects, none for "*".
+func parseTripETag(header string) (pgtype.Int4, error) {
+	header = strings.TrimSpace(header)
+	if header == "*" {
+		return pgtype.Int4{}, nil
+	}
+
+	if len(header) < 2 || header[0] != '"' || header[len(header)-1] != '"' {
+		return pgtype.Int4{}, errors.New("must be an ETag returned by GET /trips/{tripId}")
+	}
+
+	version, err := strconv.ParseInt(header[1:len(header)-1], 10, 32)
+	if err != nil {
+		return pgtype.Int4{}, errors.New("must be an ETag returned by GET /trips/{tripId}")
+	}
+
+	return pgtype.Int4{Valid: true, Int32: int32(version)}, nil
+}
+
+// setRetryAfter tells the client how long to wait before retrying, in whole
+// seconds rounded up.
+func setRetryAfter(w http.ResponseWriter, d time.Duration) {
+	w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(d.Seconds()))))
+}
+
+// somethingWentWrong is the error response of an endpoint to an unexpected err,
+// a 504 when it's a query that timed out so the client knows to retry later.
+func somethingWentWrong(response func(spec.Error) *spec.Response, err error) *spec.Response {
+	if errors.Is(err, pgstore.ErrQueryTimeout) {
+		resp := response(spec.Error{Message: "The database took too long to answer, try again"})
+		resp.Code = http.StatusGatewayTimeout
+		return resp
+	}
+	return response(spec.Error{Message: "Something went wrong, try again"})
+}
+
+// textPointer maps a nullable column to an optional response field.
+func textPointer(t pgtype.Text) *string {
+	if !t.Valid {
+		return nil
+	}
+	return &t.String
+}
+
+// timestampPointer maps a nullable column to an optional response field, in UTC.
+func timestampPointer(t pgtype.Timestamp) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	utc := t.Time.UTC()
+	return &utc
+}
+
+// tripObj is how a trip is returned by every endpoint.
+func tripObj(trip pgstore.Trip) spec.GetTripDetailsResponseTripObj {
+	return spec.GetTripDetailsResponseTripObj{
+		ID: trip.ID.String(),
+		Destination: trip.Destination,
+		EndsAt: trip.EndsAt.Time,
+		StartsAt: trip.StartsAt.Time,
+		IsConfirmed: trip.IsConfirmed,
+		Archived: trip.Archived,
+		Status: tripStatus(trip.Status),
+		Description: textPointer(trip.Description),
+		ImageURL: textPointer(trip.ImageUrl),
+		CreatedAt: trip.CreatedAt.Time.UTC(),
+		UpdatedAt: trip.UpdatedAt.Time.UTC(),
+		Locale: trip.Locale,
+	}
+}
+
+// nullableText maps an optional request field to a nullable column, an empty string clearing it.
+func nullableText(s *string) pgtype.Text {
+	if s == nil || *s == "" {
+		return pgtype.Text{}
+	}
+	return pgtype.Text{Valid: true, String: *s}
+}
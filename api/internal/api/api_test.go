@@ -0,0 +1,159 @@
+package api_test
+
+import (
+	"context"
+	"journey/internal/api"
+	"journey/internal/api/spec"
+	"journey/internal/mailer"
+	"journey/internal/memstore"
+	"journey/internal/pgstore"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/discord-gophers/goapi-gen/types"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// testCursorKey signs the cursors of the servers of the tests.
+const testCursorKey = "journey test cursor key, 32 bytes"
+
+// newServer is the API on store behind the router it's served with, with the
+// default limits.
+func newServer(store api.Store) http.Handler {
+	si := api.NewAPI(store, zap.NewNop(), api.DefaultMaxParticipants, api.DefaultMaxTripDays, api.DefaultMaxInvitesPerRequest, false, mailer.NewRateLimiter(mailer.DefaultRateLimitConfig()), []byte(testCursorKey))
+	return spec.Handler(si, spec.WithErrorHandler(api.ParamErrorHandler))
+}
+
+// serve sends r to handler and returns the response.
+func serve(handler http.Handler, r *http.Request) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, r)
+	return rec
+}
+
+// outbox keeps the emails sent instead of sending them.
+type outbox struct {
+	mu       sync.Mutex
+	messages []mailer.Message
+}
+
+func (o *outbox) Deliver(ctx context.Context, message mailer.Message) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.messages = append(o.messages, message)
+	return nil
+}
+
+// createTrip creates a trip on store inviting emails.
+func createTrip(t *testing.T, store api.Store, emails ...string) uuid.UUID {
+	t.Helper()
+
+	invites := make([]types.Email, len(emails))
+	for i, email := range emails {
+		invites[i] = types.Email(email)
+	}
+	startsAt := time.Now().AddDate(0, 1, 0).Truncate(time.Second)
+	tripID, err := store.CreateTrip(context.Background(), spec.CreateTripRequest{
+		Destination:    "Lisbon",
+		OwnerEmail:     "owner@example.com",
+		OwnerName:      "Owner",
+		EmailsToInvite: invites,
+		StartsAt:       startsAt,
+		EndsAt:         startsAt.AddDate(0, 0, 5),
+	})
+	if err != nil {
+		t.Fatalf("CreateTrip: %v", err)
+	}
+	return tripID
+}
+
+var confirmURL = regexp.MustCompile(`http://journey\.test/\S+/confirm`)
+
+// TestConfirmLinks opens the confirmation links of the emails to the owner and
+// to a participant, which must each confirm their own recipient, the one of the
+// participant through the page it opens.
+func TestConfirmLinks(t *testing.T) {
+	ctx := context.Background()
+	store := memstore.New()
+	tripID := createTrip(t, store, "ana@example.com")
+	participant, err := store.GetParticipantByEmail(ctx, pgstore.GetParticipantByEmailParams{TripID: tripID, Email: "ana@example.com"})
+	if err != nil {
+		t.Fatalf("GetParticipantByEmail: %v", err)
+	}
+
+	sent := &outbox{}
+	config := mailer.DefaultConfig()
+	config.PublicURL = "http://journey.test"
+	mail, err := mailer.NewMailer(ctx, store, zap.NewNop(), config, sent)
+	if err != nil {
+		t.Fatalf("NewMailer: %v", err)
+	}
+	if err := mail.SendConfirmTripEmailToTripOwner(tripID); err != nil {
+		t.Fatalf("SendConfirmTripEmailToTripOwner: %v", err)
+	}
+	if err := mail.SendConfirmTripEmailToTripParticipant(participant.ID); err != nil {
+		t.Fatalf("SendConfirmTripEmailToTripParticipant: %v", err)
+	}
+
+	var links []string
+	for _, message := range sent.messages {
+		link := confirmURL.FindString(message.Text)
+		if link == "" {
+			t.Fatalf("no confirmation link in the email to %s:\n%s", message.To, message.Text)
+		}
+		links = append(links, link)
+	}
+	ownerLink, participantLink := links[0], links[1]
+	if ownerLink == participantLink {
+		t.Fatalf("the owner and the participant were sent the same link %s", ownerLink)
+	}
+
+	server := newServer(store)
+	ownerTarget, err := url.Parse(ownerLink)
+	if err != nil {
+		t.Fatalf("url.Parse(%s): %v", ownerLink, err)
+	}
+	if rec := serve(server, httptest.NewRequest(http.MethodGet, ownerTarget.RequestURI(), nil)); rec.Code != http.StatusNoContent {
+		t.Errorf("GET %s = %d %s, want 204", ownerTarget.Path, rec.Code, rec.Body)
+	}
+	trip, err := store.GetTrip(ctx, tripID)
+	if err != nil || !trip.IsConfirmed {
+		t.Errorf("after opening %s, trip confirmed = %v, %v, want true", ownerLink, trip.IsConfirmed, err)
+	}
+
+	// Opening the link of the participant only asks to confirm, a link
+	// scanner fetching it mustn't confirm them
+	participantTarget, err := url.Parse(participantLink)
+	if err != nil {
+		t.Fatalf("url.Parse(%s): %v", participantLink, err)
+	}
+	rec := serve(server, httptest.NewRequest(http.MethodGet, participantTarget.RequestURI(), nil))
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/html") {
+		t.Fatalf("GET %s = %d %s, want a 200 HTML page", participantTarget.Path, rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(rec.Body.String(), `method="post"`) {
+		t.Errorf("GET %s page has no form posting to it:\n%s", participantTarget.Path, rec.Body)
+	}
+	participant, err = store.GetParticipant(ctx, participant.ID)
+	if err != nil || participant.IsConfirmed {
+		t.Fatalf("after opening %s, participant confirmed = %v, %v, want false", participantLink, participant.IsConfirmed, err)
+	}
+
+	if rec := serve(server, httptest.NewRequest(http.MethodPost, participantTarget.RequestURI(), nil)); rec.Code != http.StatusNoContent {
+		t.Fatalf("POST %s = %d %s, want 204", participantTarget.Path, rec.Code, rec.Body)
+	}
+	participant, err = store.GetParticipant(ctx, participant.ID)
+	if err != nil || !participant.IsConfirmed {
+		t.Errorf("after posting to %s, participant confirmed = %v, %v, want true", participantLink, participant.IsConfirmed, err)
+	}
+	if rec := serve(server, httptest.NewRequest(http.MethodGet, participantTarget.RequestURI(), nil)); strings.Contains(rec.Body.String(), "<form") {
+		t.Errorf("GET %s of a confirmed participant still asks to confirm:\n%s", participantTarget.Path, rec.Body)
+	}
+}
@@ -0,0 +1,65 @@
+package api
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// tripsCursor is the last trip of a GET /trips page, the next page starting
+// right after it in (starts_at, id) order. Clients get it as an opaque string,
+// signed so they can't make up one of their own.
+type tripsCursor struct {
+	StartsAt time.Time `json:"starts_at"`
+	ID uuid.UUID `json:"id"`
+}
+
+// sign encodes c followed by its HMAC-SHA256 under key.
+func (c tripsCursor) sign(key []byte) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b) + "." + base64.RawURLEncoding.EncodeToString(cursorMAC(key, b))
+}
+
+func cursorMAC(key, payload []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+var errInvalidCursor = errors.New("must be the next_cursor of a previous page")
+
+// parseTripsCursor decodes a cursor made by tripsCursor.sign with key,
+// rejecting anything it couldn't have made.
+func parseTripsCursor(raw string, key []byte) (tripsCursor, error) {
+	payload, signature, ok := strings.Cut(raw, ".")
+	if !ok {
+		return tripsCursor{}, errInvalidCursor
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return tripsCursor{}, errInvalidCursor
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, cursorMAC(key, b)) {
+		return tripsCursor{}, errInvalidCursor
+	}
+
+	var c tripsCursor
+	if err := decodeJSON(bytes.NewReader(b), &c); err != nil {
+		return tripsCursor{}, errInvalidCursor
+	}
+
+	if c.StartsAt.IsZero() || c.ID == uuid.Nil {
+		return tripsCursor{}, errInvalidCursor
+	}
+
+	return c, nil
+}
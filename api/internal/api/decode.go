@@ -0,0 +1,25 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+)
+
+// decodeJSON decodes a request body into v, rejecting the fields v doesn't
+// have and anything following the JSON document, so a typo like "start_at"
+// isn't silently ignored. An empty body returns io.EOF as is.
+func decodeJSON(r io.Reader, v any) error {
+	dec := json.NewDecoder(r)
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(v); err != nil {
+		return err
+	}
+
+	if err := dec.Decode(&json.RawMessage{}); !errors.Is(err, io.EOF) {
+		return errors.New("unexpected data after the JSON document")
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package api_test
+
+import (
+	"context"
+	"fmt"
+	"journey/internal/memstore"
+	"journey/internal/pgstore"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// failingLinks is a store whose links can't be created, failing as pgstore
+// reports the violation of a constraint.
+type failingLinks struct {
+	*memstore.Store
+	err error
+}
+
+func (s failingLinks) CreateTripLink(ctx context.Context, arg pgstore.CreateTripLinkParams) (uuid.UUID, error) {
+	return uuid.UUID{}, s.err
+}
+
+// TestCreateLinkConstraintErrors checks the constraint violations reported by
+// the store are answered as the API errors, not as something going wrong.
+func TestCreateLinkConstraintErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		err         error
+		wantStatus  int
+		wantMessage string
+	}{
+		{
+			"duplicate link",
+			fmt.Errorf("%w: %w", pgstore.ErrDuplicateLink, &pgconn.PgError{Code: "23505", ConstraintName: "links_trip_id_url_idx"}),
+			http.StatusConflict,
+			"Trip already has a link to this URL",
+		},
+		{
+			"trip deleted meanwhile",
+			fmt.Errorf("%w: %w", pgstore.ErrTripNotFound, &pgconn.PgError{Code: "23503", ConstraintName: "links_trip_id_fkey"}),
+			http.StatusBadRequest,
+			"Trip not found",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := memstore.New()
+			tripID := createTrip(t, store)
+			server := newServer(failingLinks{Store: store, err: tt.err})
+
+			body := `{"title": "Hotel", "url": "https://example.com/hotel"}`
+			rec := serve(server, httptest.NewRequest(http.MethodPost, "/trips/"+tripID.String()+"/links", strings.NewReader(body)))
+			if rec.Code != tt.wantStatus || !strings.Contains(rec.Body.String(), tt.wantMessage) {
+				t.Errorf("POST /trips/{tripId}/links = %d %s, want %d %q", rec.Code, rec.Body, tt.wantStatus, tt.wantMessage)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package api
+
+import (
+	"errors"
+	"journey/internal/api/spec"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+)
+
+// ParamErrorHandler reports the parameters the generated router can't bind
+// with the same JSON body as the handlers, instead of the plain text default.
+// Every ID in the paths is a UUID, so a malformed one gets the same message
+// whatever the endpoint.
+func ParamErrorHandler(w http.ResponseWriter, r *http.Request, err error) {
+	message := "Invalid request: " + err.Error()
+
+	var paramErr spec.ParameterError
+	if errors.As(err, &paramErr) {
+		message = paramErrorMessage(r, paramErr)
+	}
+
+	render.Status(r, http.StatusBadRequest)
+	render.JSON(w, r, spec.Error{Message: message})
+}
+
+// paramErrorMessage names the parameter first, like the messages the handlers
+// write for the parameters they check themselves.
+func paramErrorMessage(r *http.Request, err spec.ParameterError) string {
+	name := err.ParamName()
+
+	var requiredErr *spec.RequiredParamError
+	var requiredHeaderErr *spec.RequiredHeaderError
+	var tooManyErr *spec.TooManyValuesForParamError
+	switch {
+	case chi.URLParam(r, name) != "":
+		return "Invalid " + name + ": must be a UUID"
+	case errors.As(err, &requiredErr), errors.As(err, &requiredHeaderErr):
+		return "Missing " + name + ": it is required"
+	case errors.As(err, &tooManyErr):
+		return "Invalid " + name + ": must be given once"
+	}
+
+	// The innermost error is the one saying what is wrong with the value
+	cause := error(err)
+	for errors.Unwrap(cause) != nil {
+		cause = errors.Unwrap(cause)
+	}
+	if cause == error(err) {
+		return "Invalid " + name
+	}
+
+	return "Invalid " + name + ": " + cause.Error()
+}
@@ -0,0 +1,66 @@
+package api_test
+
+import (
+	"encoding/json"
+	"errors"
+	"journey/internal/api"
+	"journey/internal/memstore"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// TestParamErrors checks the parameters the router can't bind are answered
+// with a 400 and the JSON Error the handlers answer with, naming the
+// parameter.
+func TestParamErrors(t *testing.T) {
+	server := newServer(memstore.New())
+
+	tests := []struct {
+		target      string
+		wantMessage string
+	}{
+		{"/trips/not-a-uuid", "Invalid tripId: must be a UUID"},
+		{"/trips/not-a-uuid/participants", "Invalid tripId: must be a UUID"},
+		{"/participants/not-a-uuid/confirm", "Invalid participantId: must be a UUID"},
+		{"/trips/participating", "Missing email: it is required"},
+		{"/trips/search", "Missing q: it is required"},
+		{"/trips?limit=abc", "Invalid limit: "},
+		{"/trips?limit=1&limit=2", "Invalid limit: "},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			rec := serve(server, httptest.NewRequest(http.MethodGet, tt.target, nil))
+			if rec.Code != http.StatusBadRequest {
+				t.Errorf("GET %s = %d, want 400", tt.target, rec.Code)
+			}
+			if contentType := rec.Header().Get("Content-Type"); !strings.HasPrefix(contentType, "application/json") {
+				t.Errorf("GET %s Content-Type = %q, want application/json", tt.target, contentType)
+			}
+
+			var body map[string]any
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decoding %s: %v", rec.Body, err)
+			}
+			message, ok := body["message"].(string)
+			if len(body) != 1 || !ok {
+				t.Fatalf("GET %s = %s, want only a message", tt.target, rec.Body)
+			}
+			if !strings.HasPrefix(message, tt.wantMessage) {
+				t.Errorf("GET %s message = %q, want %q", tt.target, message, tt.wantMessage)
+			}
+		})
+	}
+}
+
+// TestParamErrorHandlerOtherErrors checks an error that isn't about a
+// parameter gets the same JSON Error.
+func TestParamErrorHandlerOtherErrors(t *testing.T) {
+	rec := httptest.NewRecorder()
+	api.ParamErrorHandler(rec, httptest.NewRequest(http.MethodGet, "/trips", nil), errors.New("unexpected"))
+
+	if rec.Code != http.StatusBadRequest || strings.TrimSpace(rec.Body.String()) != `{"message":"Invalid request: unexpected"}` {
+		t.Errorf("ParamErrorHandler = %d %s, want 400 {\"message\":\"Invalid request: unexpected\"}", rec.Code, rec.Body)
+	}
+}
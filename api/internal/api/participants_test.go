@@ -0,0 +1,236 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"journey/internal/api/spec"
+	"journey/internal/memstore"
+	"journey/internal/pgstore"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestPatchParticipantName(t *testing.T) {
+	tests := []struct {
+		name     string
+		sent     string
+		want     int
+		wantName string
+	}{
+		{"spaces collapsed", "  Ana \t Maria\n", http.StatusNoContent, "Ana Maria"},
+		{"only spaces", "   ", http.StatusBadRequest, ""},
+		{"at most 255 characters", strings.Repeat("a", 255), http.StatusNoContent, strings.Repeat("a", 255)},
+		{"over 255 characters", strings.Repeat("a", 256), http.StatusBadRequest, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := memstore.New()
+			tripID := createTrip(t, store, "ana@example.com")
+			participant, err := store.GetParticipantByEmail(ctx, pgstore.GetParticipantByEmailParams{TripID: tripID, Email: "ana@example.com"})
+			if err != nil {
+				t.Fatalf("GetParticipantByEmail: %v", err)
+			}
+
+			r := httptest.NewRequest(http.MethodPatch, "/participants/"+participant.ID.String(), strings.NewReader(`{"name": `+strconv.Quote(tt.sent)+`}`))
+			r.Header.Set("Content-Type", "application/json")
+			if rec := serve(newServer(store), r); rec.Code != tt.want {
+				t.Fatalf("PATCH /participants/{participantId} = %d %s, want %d", rec.Code, rec.Body, tt.want)
+			}
+
+			participant, err = store.GetParticipant(ctx, participant.ID)
+			if err != nil {
+				t.Fatalf("GetParticipant: %v", err)
+			}
+			if participant.Name.String != tt.wantName {
+				t.Errorf("name = %q, want %q", participant.Name.String, tt.wantName)
+			}
+		})
+	}
+}
+
+// TestUnsubscribe checks opening the link of an email only asks to confirm,
+// a link scanner fetching it mustn't unsubscribe anyone.
+func TestUnsubscribe(t *testing.T) {
+	ctx := context.Background()
+	store := memstore.New()
+	tripID := createTrip(t, store, "ana@example.com")
+	participant, err := store.GetParticipantByEmail(ctx, pgstore.GetParticipantByEmailParams{TripID: tripID, Email: "ana@example.com"})
+	if err != nil {
+		t.Fatalf("GetParticipantByEmail: %v", err)
+	}
+	server := newServer(store)
+	target := "/unsubscribe/" + participant.UnsubscribeToken.String()
+
+	rec := serve(server, httptest.NewRequest(http.MethodGet, target, nil))
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/html") {
+		t.Fatalf("GET %s = %d %s, want a 200 HTML page", target, rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if !strings.Contains(rec.Body.String(), `method="post"`) {
+		t.Errorf("GET %s page has no form posting to it:\n%s", target, rec.Body)
+	}
+	if suppressed, err := store.IsEmailSuppressed(ctx, "ana@example.com"); err != nil || suppressed {
+		t.Fatalf("after GET, suppressed = %v, %v, want false", suppressed, err)
+	}
+
+	if rec := serve(server, httptest.NewRequest(http.MethodPost, target, nil)); rec.Code != http.StatusNoContent {
+		t.Fatalf("POST %s = %d %s, want 204", target, rec.Code, rec.Body)
+	}
+	if suppressed, err := store.IsEmailSuppressed(ctx, "ana@example.com"); err != nil || !suppressed {
+		t.Errorf("after POST, suppressed = %v, %v, want true", suppressed, err)
+	}
+
+	if rec := serve(server, httptest.NewRequest(http.MethodGet, "/unsubscribe/"+tripID.String(), nil)); rec.Code != http.StatusBadRequest {
+		t.Errorf("GET of an unknown token = %d, want 400", rec.Code)
+	}
+}
+
+// TestInviteMixedCaseEmails checks the addresses of a trip are told apart
+// ignoring case: the owner isn't invited, nor anyone twice.
+func TestInviteMixedCaseEmails(t *testing.T) {
+	store := memstore.New()
+	server := newServer(store)
+
+	startsAt := time.Now().AddDate(0, 1, 0).UTC()
+	body, err := json.Marshal(map[string]any{
+		"destination":      "Lisbon",
+		"owner_name":       "Owner",
+		"owner_email":      "Owner@Example.com",
+		"emails_to_invite": []string{"owner@example.COM", "Ana@Example.com", "ana@example.com", "ANA@EXAMPLE.COM"},
+		"starts_at":        startsAt,
+		"ends_at":          startsAt.AddDate(0, 0, 5),
+	})
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+	rec := serve(server, httptest.NewRequest(http.MethodPost, "/trips", bytes.NewReader(body)))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("POST /trips = %d %s, want 201", rec.Code, rec.Body)
+	}
+	var created struct {
+		TripID            uuid.UUID `json:"tripId"`
+		DuplicatesDropped int       `json:"duplicate_emails_dropped"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &created); err != nil {
+		t.Fatalf("decoding %s: %v", rec.Body, err)
+	}
+	if created.DuplicatesDropped != 2 {
+		t.Errorf("POST /trips dropped %d duplicates, want 2", created.DuplicatesDropped)
+	}
+
+	participants, err := store.ListParticipants(context.Background(), pgstore.ListParticipantsParams{TripID: created.TripID, Limit: 10})
+	if err != nil {
+		t.Fatalf("ListParticipants: %v", err)
+	}
+	if len(participants) != 1 || !strings.EqualFold(participants[0].Email, "ana@example.com") {
+		t.Fatalf("participants = %+v, want only ana@example.com", participants)
+	}
+
+	tests := []struct {
+		email string
+		want  int
+	}{
+		{"OWNER@example.com", http.StatusBadRequest},
+		{"owner@EXAMPLE.com", http.StatusBadRequest},
+		{"ANA@EXAMPLE.COM", http.StatusConflict},
+		{"aNa@example.com", http.StatusConflict},
+		{"Bruno@Example.com", http.StatusCreated},
+		{"bruno@example.com", http.StatusConflict},
+	}
+	for _, tt := range tests {
+		target := "/trips/" + created.TripID.String() + "/invites"
+		rec := serve(server, httptest.NewRequest(http.MethodPost, target, strings.NewReader(`{"email": "`+tt.email+`"}`)))
+		if rec.Code != tt.want {
+			t.Errorf("inviting %s = %d %s, want %d", tt.email, rec.Code, rec.Body, tt.want)
+		}
+	}
+}
+
+// TestInviteConcurrently checks inviting an address from concurrent requests
+// invites it once, the other requests being told it already was.
+func TestInviteConcurrently(t *testing.T) {
+	store := memstore.New()
+	server := newServer(store)
+	tripID := createTrip(t, store)
+	target := "/trips/" + tripID.String() + "/invites"
+
+	const requests = 16
+	codes := make(chan int, requests)
+	var wg sync.WaitGroup
+	for i := range requests {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			email := []string{"dora@example.com", "Dora@Example.com"}[i%2]
+			codes <- serve(server, httptest.NewRequest(http.MethodPost, target, strings.NewReader(`{"email": "`+email+`"}`))).Code
+		}()
+	}
+	wg.Wait()
+	close(codes)
+
+	answered := map[int]int{}
+	for code := range codes {
+		answered[code]++
+	}
+	if answered[http.StatusCreated] != 1 || answered[http.StatusConflict] != requests-1 {
+		t.Errorf("concurrent invites answered %v, want one 201 and %d 409", answered, requests-1)
+	}
+
+	participants, err := store.ListParticipants(context.Background(), pgstore.ListParticipantsParams{TripID: tripID, Limit: 100})
+	if err != nil || len(participants) != 1 {
+		t.Errorf("ListParticipants = %+v, %v, want a single participant", participants, err)
+	}
+}
+
+// TestListParticipantsIsConfirmed checks is_confirmed filters the participants
+// of a trip, the unconfirmed ones going through their own query.
+func TestListParticipantsIsConfirmed(t *testing.T) {
+	ctx := context.Background()
+	store := memstore.New()
+	tripID := createTrip(t, store, "ana@example.com", "bruno@example.com", "clara@example.com")
+	bruno, err := store.GetParticipantByEmail(ctx, pgstore.GetParticipantByEmailParams{TripID: tripID, Email: "bruno@example.com"})
+	if err != nil {
+		t.Fatalf("GetParticipantByEmail: %v", err)
+	}
+	if err := store.ConfirmParticipant(ctx, bruno.ID); err != nil {
+		t.Fatalf("ConfirmParticipant: %v", err)
+	}
+	server := newServer(store)
+
+	tests := []struct {
+		isConfirmed string
+		want        []string
+	}{
+		{"false", []string{"ana@example.com", "clara@example.com"}},
+		{"true", []string{"bruno@example.com"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.isConfirmed, func(t *testing.T) {
+			target := "/trips/" + tripID.String() + "/participants?is_confirmed=" + tt.isConfirmed
+			rec := serve(server, httptest.NewRequest(http.MethodGet, target, nil))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("GET %s = %d %s, want 200", target, rec.Code, rec.Body)
+			}
+			var body spec.GetTripParticipantsResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatalf("decoding %s: %v", rec.Body, err)
+			}
+			var got []string
+			for _, participant := range body.Participants {
+				got = append(got, string(participant.Email))
+			}
+			if !slices.Equal(got, tt.want) || body.Total != len(tt.want) {
+				t.Errorf("GET %s = %v of %d, want %v", target, got, body.Total, tt.want)
+			}
+		})
+	}
+}
@@ -21,12 +21,80 @@ import (
 	"github.com/getkin/kin-openapi/openapi3"
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/render"
+	"github.com/google/uuid"
 )
 
+// Defines values for CreateTripRequestLocale.
+var (
+	UnknownCreateTripRequestLocale = CreateTripRequestLocale{}
+
+	CreateTripRequestLocaleEn = CreateTripRequestLocale{"en"}
+
+	CreateTripRequestLocalePtBR = CreateTripRequestLocale{"pt-BR"}
+)
+
+// Defines values for GetTripDetailsResponseTripObjStatus.
+var (
+	UnknownGetTripDetailsResponseTripObjStatus = GetTripDetailsResponseTripObjStatus{}
+
+	GetTripDetailsResponseTripObjStatusActive = GetTripDetailsResponseTripObjStatus{"active"}
+
+	GetTripDetailsResponseTripObjStatusCancelled = GetTripDetailsResponseTripObjStatus{"cancelled"}
+)
+
+// Defines values for GetTripEmailsResponseArrayKind.
+var (
+	UnknownGetTripEmailsResponseArrayKind = GetTripEmailsResponseArrayKind{}
+
+	GetTripEmailsResponseArrayKindConfirmTripOwner = GetTripEmailsResponseArrayKind{"confirm_trip_owner"}
+
+	GetTripEmailsResponseArrayKindConfirmTripParticipant = GetTripEmailsResponseArrayKind{"confirm_trip_participant"}
+
+	GetTripEmailsResponseArrayKindInviteReminder = GetTripEmailsResponseArrayKind{"invite_reminder"}
+
+	GetTripEmailsResponseArrayKindTripCancelled = GetTripEmailsResponseArrayKind{"trip_cancelled"}
+
+	GetTripEmailsResponseArrayKindTripItinerary = GetTripEmailsResponseArrayKind{"trip_itinerary"}
+
+	GetTripEmailsResponseArrayKindTripUpdated = GetTripEmailsResponseArrayKind{"trip_updated"}
+)
+
+// Defines values for GetTripEmailsResponseArrayStatus.
+var (
+	UnknownGetTripEmailsResponseArrayStatus = GetTripEmailsResponseArrayStatus{}
+
+	GetTripEmailsResponseArrayStatusFailed = GetTripEmailsResponseArrayStatus{"failed"}
+
+	GetTripEmailsResponseArrayStatusSent = GetTripEmailsResponseArrayStatus{"sent"}
+
+	GetTripEmailsResponseArrayStatusSuppressed = GetTripEmailsResponseArrayStatus{"suppressed"}
+)
+
+// ActivitiesOutOfRangeResponse defines model for ActivitiesOutOfRangeResponse.
+type ActivitiesOutOfRangeResponse struct {
+	Activities []ActivitiesOutOfRangeResponseArray `json:"activities"`
+	Message    string                              `json:"message"`
+}
+
+// ActivitiesOutOfRangeResponseArray defines model for ActivitiesOutOfRangeResponseArray.
+type ActivitiesOutOfRangeResponseArray struct {
+	ID       string    `json:"id"`
+	OccursAt time.Time `json:"occurs_at"`
+}
+
+// CreateActivitiesBatchRequest defines model for CreateActivitiesBatchRequest.
+type CreateActivitiesBatchRequest []CreateActivityRequest
+
+// CreateActivitiesBatchResponse defines model for CreateActivitiesBatchResponse.
+type CreateActivitiesBatchResponse struct {
+	ActivityIds []string `json:"activityIds"`
+}
+
 // CreateActivityRequest defines model for CreateActivityRequest.
 type CreateActivityRequest struct {
 	OccursAt time.Time `json:"occurs_at" validate:"required"`
-	Title    string    `json:"title" validate:"required"`
+	Tags     []string  `json:"tags,omitempty" validate:"omitempty,max=5,dive,min=1,max=20"`
+	Title    string    `json:"title" validate:"required,max=200"`
 }
 
 // CreateActivityResponse defines model for CreateActivityResponse.
@@ -36,7 +104,7 @@ type CreateActivityResponse struct {
 
 // CreateLinkRequest defines model for CreateLinkRequest.
 type CreateLinkRequest struct {
-	Title string `json:"title" validate:"required"`
+	Title string `json:"title" validate:"required,max=200"`
 	URL   string `json:"url" validate:"required,url"`
 }
 
@@ -47,17 +115,35 @@ type CreateLinkResponse struct {
 
 // CreateTripRequest defines model for CreateTripRequest.
 type CreateTripRequest struct {
-	Destination    string                `json:"destination" validate:"required,min=4"`
-	EmailsToInvite []openapi_types.Email `json:"emails_to_invite" validate:"required,dive,email"`
+	Description *string `json:"description,omitempty" validate:"omitempty,max=2000"`
+	Destination string  `json:"destination" validate:"required,min=4,max=120"`
+
+	// At most 50 emails, unless the server sets another JOURNEY_MAX_INVITES_PER_REQUEST.
+	EmailsToInvite []openapi_types.Email `json:"emails_to_invite" validate:"required"`
 	EndsAt         time.Time             `json:"ends_at" validate:"required"`
-	OwnerEmail     openapi_types.Email   `json:"owner_email" validate:"required,email"`
-	OwnerName      string                `json:"owner_name" validate:"required"`
-	StartsAt       time.Time             `json:"starts_at" validate:"required"`
+	ImageURL       *string               `json:"image_url,omitempty" validate:"omitempty,url,max=2048"`
+
+	// Language of the emails sent about the trip. When missing, it's picked from the Accept-Language header, falling back to pt-BR.
+	Locale     *CreateTripRequestLocale `json:"locale,omitempty"`
+	OwnerEmail openapi_types.Email      `json:"owner_email" validate:"required"`
+	OwnerName  string                   `json:"owner_name" validate:"required,max=100"`
+	StartsAt   time.Time                `json:"starts_at" validate:"required"`
 }
 
 // CreateTripResponse defines model for CreateTripResponse.
 type CreateTripResponse struct {
-	TripID string `json:"tripId"`
+	// How many entries of emails_to_invite were dropped as duplicates of another one.
+	DuplicateEmailsDropped *int `json:"duplicate_emails_dropped,omitempty"`
+
+	// Entries of emails_to_invite that unsubscribed from the e-mails, they're invited but won't be e-mailed.
+	SuppressedEmails []string `json:"suppressed_emails,omitempty"`
+	TripID           string   `json:"tripId"`
+}
+
+// DuplicateTripRequest defines model for DuplicateTripRequest.
+type DuplicateTripRequest struct {
+	// Days the dates of the copy are moved by, at most 10 years either way.
+	OffsetDays *int `json:"offset_days,omitempty"`
 }
 
 // Bad request
@@ -65,16 +151,64 @@ type Error struct {
 	Message string `json:"message"`
 }
 
+// GetActivityResponse defines model for GetActivityResponse.
+type GetActivityResponse struct {
+	Activity GetTripActivitiesResponseInnerArray `json:"activity"`
+}
+
 // GetLinksResponse defines model for GetLinksResponse.
 type GetLinksResponse struct {
+	Count int                     `json:"count"`
 	Links []GetLinksResponseArray `json:"links"`
 }
 
 // GetLinksResponseArray defines model for GetLinksResponseArray.
 type GetLinksResponseArray struct {
-	ID    string `json:"id"`
-	Title string `json:"title"`
-	URL   string `json:"url"`
+	CreatedAt time.Time `json:"created_at"`
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	UpdatedAt time.Time `json:"updated_at"`
+	URL       string    `json:"url"`
+}
+
+// GetParticipantDetailsResponse defines model for GetParticipantDetailsResponse.
+type GetParticipantDetailsResponse struct {
+	Participant GetParticipantDetailsResponseParticipantObj `json:"participant"`
+}
+
+// GetParticipantDetailsResponseParticipantObj defines model for GetParticipantDetailsResponseParticipantObj.
+type GetParticipantDetailsResponseParticipantObj struct {
+	Email       openapi_types.Email                  `json:"email"`
+	ID          string                               `json:"id"`
+	IsConfirmed bool                                 `json:"is_confirmed"`
+	IsDeclined  bool                                 `json:"is_declined"`
+	Name        *string                              `json:"name"`
+	Trip        GetParticipantDetailsResponseTripObj `json:"trip"`
+}
+
+// GetParticipantDetailsResponseTripObj defines model for GetParticipantDetailsResponseTripObj.
+type GetParticipantDetailsResponseTripObj struct {
+	Destination string    `json:"destination"`
+	EndsAt      time.Time `json:"ends_at"`
+	ID          string    `json:"id"`
+	OwnerName   string    `json:"owner_name"`
+	StartsAt    time.Time `json:"starts_at"`
+}
+
+// GetParticipatingTripsResponse defines model for GetParticipatingTripsResponse.
+type GetParticipatingTripsResponse struct {
+	Limit  int                                  `json:"limit"`
+	Offset int                                  `json:"offset"`
+	Total  int                                  `json:"total"`
+	Trips  []GetParticipatingTripsResponseArray `json:"trips"`
+}
+
+// GetParticipatingTripsResponseArray defines model for GetParticipatingTripsResponseArray.
+type GetParticipatingTripsResponseArray struct {
+	IsConfirmed   bool                          `json:"is_confirmed"`
+	IsDeclined    bool                          `json:"is_declined"`
+	ParticipantID string                        `json:"participant_id"`
+	Trip          GetTripDetailsResponseTripObj `json:"trip"`
 }
 
 // GetTripActivitiesResponse defines model for GetTripActivitiesResponse.
@@ -84,9 +218,12 @@ type GetTripActivitiesResponse struct {
 
 // GetTripActivitiesResponseInnerArray defines model for GetTripActivitiesResponseInnerArray.
 type GetTripActivitiesResponseInnerArray struct {
-	ID       string    `json:"id"`
-	OccursAt time.Time `json:"occurs_at"`
-	Title    string    `json:"title"`
+	CreatedAt time.Time `json:"created_at"`
+	ID        string    `json:"id"`
+	OccursAt  time.Time `json:"occurs_at"`
+	Tags      []string  `json:"tags"`
+	Title     string    `json:"title"`
+	UpdatedAt time.Time `json:"updated_at"`
 }
 
 // GetTripActivitiesResponseOuterArray defines model for GetTripActivitiesResponseOuterArray.
@@ -102,58 +239,449 @@ type GetTripDetailsResponse struct {
 
 // GetTripDetailsResponseTripObj defines model for GetTripDetailsResponseTripObj.
 type GetTripDetailsResponseTripObj struct {
+	Archived    bool      `json:"archived"`
+	CreatedAt   time.Time `json:"created_at"`
+	Description *string   `json:"description,omitempty"`
 	Destination string    `json:"destination"`
 	EndsAt      time.Time `json:"ends_at"`
 	ID          string    `json:"id"`
+	ImageURL    *string   `json:"image_url,omitempty"`
 	IsConfirmed bool      `json:"is_confirmed"`
-	StartsAt    time.Time `json:"starts_at"`
+
+	// Language of the emails sent about the trip.
+	Locale    string                              `json:"locale"`
+	StartsAt  time.Time                           `json:"starts_at"`
+	Status    GetTripDetailsResponseTripObjStatus `json:"status"`
+	UpdatedAt time.Time                           `json:"updated_at"`
+}
+
+// GetTripEmailsResponse defines model for GetTripEmailsResponse.
+type GetTripEmailsResponse struct {
+	Emails []GetTripEmailsResponseArray `json:"emails"`
+	Limit  int                          `json:"limit"`
+	Offset int                          `json:"offset"`
+	Total  int                          `json:"total"`
+}
+
+// GetTripEmailsResponseArray defines model for GetTripEmailsResponseArray.
+type GetTripEmailsResponseArray struct {
+	CreatedAt time.Time                      `json:"created_at"`
+	Error     *string                        `json:"error,omitempty"`
+	ID        string                         `json:"id"`
+	Kind      GetTripEmailsResponseArrayKind `json:"kind"`
+
+	// Unset for the e-mails to the owner and once the participant is removed.
+	ParticipantID *string                          `json:"participant_id,omitempty"`
+	Recipient     string                           `json:"recipient"`
+	Status        GetTripEmailsResponseArrayStatus `json:"status"`
+}
+
+// GetTripExportResponse defines model for GetTripExportResponse.
+type GetTripExportResponse struct {
+	Trip    GetTripExportResponseTripObj `json:"trip"`
+	Version int                          `json:"version"`
+}
+
+// GetTripExportResponseTripObj defines model for GetTripExportResponseTripObj.
+type GetTripExportResponseTripObj struct {
+	Activities   []GetTripActivitiesResponseInnerArray `json:"activities"`
+	CreatedAt    time.Time                             `json:"created_at"`
+	Description  *string                               `json:"description,omitempty"`
+	Destination  string                                `json:"destination"`
+	EndsAt       time.Time                             `json:"ends_at"`
+	ID           string                                `json:"id"`
+	ImageURL     *string                               `json:"image_url,omitempty"`
+	IsConfirmed  bool                                  `json:"is_confirmed"`
+	Links        []GetLinksResponseArray               `json:"links"`
+	OwnerEmail   openapi_types.Email                   `json:"owner_email"`
+	OwnerName    string                                `json:"owner_name"`
+	Participants []GetTripParticipantsResponseArray    `json:"participants"`
+	StartsAt     time.Time                             `json:"starts_at"`
+	UpdatedAt    time.Time                             `json:"updated_at"`
 }
 
 // GetTripParticipantsResponse defines model for GetTripParticipantsResponse.
 type GetTripParticipantsResponse struct {
+	Limit        int                                `json:"limit"`
+	Offset       int                                `json:"offset"`
 	Participants []GetTripParticipantsResponseArray `json:"participants"`
+	Total        int                                `json:"total"`
 }
 
 // GetTripParticipantsResponseArray defines model for GetTripParticipantsResponseArray.
 type GetTripParticipantsResponseArray struct {
-	Email       openapi_types.Email `json:"email"`
-	ID          string              `json:"id"`
-	IsConfirmed bool                `json:"is_confirmed"`
-	Name        *string             `json:"name"`
+	Email openapi_types.Email `json:"email"`
+	ID    string              `json:"id"`
+
+	// When the invite was last sent, null while it's pending.
+	InviteSentAt *time.Time `json:"invite_sent_at"`
+	IsConfirmed  bool       `json:"is_confirmed"`
+	IsDeclined   bool       `json:"is_declined"`
+	IsOwner      bool       `json:"is_owner"`
+
+	// Why the last attempt at sending the invite failed, null if it didn't.
+	LastInviteError *string `json:"last_invite_error"`
+	Name            *string `json:"name"`
+}
+
+// GetTripSummaryResponse defines model for GetTripSummaryResponse.
+type GetTripSummaryResponse struct {
+	ActivitiesCount            int                           `json:"activities_count"`
+	ConfirmedParticipantsCount int                           `json:"confirmed_participants_count"`
+	LinksCount                 int                           `json:"links_count"`
+	ParticipantsCount          int                           `json:"participants_count"`
+	Trip                       GetTripDetailsResponseTripObj `json:"trip"`
 }
 
 // GetTripsResponse defines model for GetTripsResponse.
 type GetTripsResponse struct {
-	Trips []GetTripDetailsResponseTripObj `json:"trips"`
+	Limit int `json:"limit"`
+
+	// Passed as cursor to get the trips after this page, set when the page is full and the trips are sorted by starts_at ascending.
+	NextCursor *string                         `json:"next_cursor,omitempty"`
+	Offset     int                             `json:"offset"`
+	Total      int                             `json:"total"`
+	Trips      []GetTripDetailsResponseTripObj `json:"trips"`
+}
+
+// InviteConflictResponse defines model for InviteConflictResponse.
+type InviteConflictResponse struct {
+	IsConfirmed   bool   `json:"is_confirmed"`
+	Message       string `json:"message"`
+	ParticipantID string `json:"participant_id"`
 }
 
 // InviteParticipantRequest defines model for InviteParticipantRequest.
 type InviteParticipantRequest struct {
-	Email openapi_types.Email `json:"email" validate:"required,email"`
+	Email openapi_types.Email `json:"email" validate:"required"`
+}
+
+// PatchTripRequest defines model for PatchTripRequest.
+type PatchTripRequest struct {
+	Description *string    `json:"description,omitempty" validate:"omitempty,max=2000"`
+	Destination *string    `json:"destination,omitempty" validate:"omitempty,min=4,max=120"`
+	EndsAt      *time.Time `json:"ends_at,omitempty"`
+	ImageURL    *string    `json:"image_url,omitempty" validate:"omitempty,url,max=2048"`
+	StartsAt    *time.Time `json:"starts_at,omitempty"`
+}
+
+// UnconfirmTripRequest defines model for UnconfirmTripRequest.
+type UnconfirmTripRequest struct {
+	ResetParticipants *bool `json:"reset_participants,omitempty"`
+}
+
+// UpdateLinkRequest defines model for UpdateLinkRequest.
+type UpdateLinkRequest struct {
+	Title string `json:"title" validate:"required,max=200"`
+	URL   string `json:"url" validate:"required,url"`
+}
+
+// UpdateParticipantRequest defines model for UpdateParticipantRequest.
+type UpdateParticipantRequest struct {
+	Name string `json:"name" validate:"required,max=255"`
 }
 
 // UpdateTripRequest defines model for UpdateTripRequest.
 type UpdateTripRequest struct {
-	Destination string    `json:"destination" validate:"required,min=4"`
+	Description *string   `json:"description,omitempty" validate:"omitempty,max=2000"`
+	Destination string    `json:"destination" validate:"required,min=4,max=120"`
 	EndsAt      time.Time `json:"ends_at" validate:"required"`
+	ImageURL    *string   `json:"image_url,omitempty" validate:"omitempty,url,max=2048"`
 	StartsAt    time.Time `json:"starts_at" validate:"required"`
 }
 
+// Language of the emails sent about the trip. When missing, it's picked from the Accept-Language header, falling back to pt-BR.
+type CreateTripRequestLocale struct {
+	value string
+}
+
+func (t *CreateTripRequestLocale) ToValue() string {
+	return t.value
+}
+func (t CreateTripRequestLocale) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.value)
+}
+func (t *CreateTripRequestLocale) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	return t.FromValue(value)
+}
+func (t *CreateTripRequestLocale) FromValue(value string) error {
+	switch value {
+
+	case CreateTripRequestLocaleEn.value:
+		t.value = value
+		return nil
+
+	case CreateTripRequestLocalePtBR.value:
+		t.value = value
+		return nil
+
+	}
+	return fmt.Errorf("unknown enum value: %v", value)
+}
+
+// GetTripDetailsResponseTripObjStatus defines model for GetTripDetailsResponseTripObj.Status.
+type GetTripDetailsResponseTripObjStatus struct {
+	value string
+}
+
+func (t *GetTripDetailsResponseTripObjStatus) ToValue() string {
+	return t.value
+}
+func (t GetTripDetailsResponseTripObjStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.value)
+}
+func (t *GetTripDetailsResponseTripObjStatus) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	return t.FromValue(value)
+}
+func (t *GetTripDetailsResponseTripObjStatus) FromValue(value string) error {
+	switch value {
+
+	case GetTripDetailsResponseTripObjStatusActive.value:
+		t.value = value
+		return nil
+
+	case GetTripDetailsResponseTripObjStatusCancelled.value:
+		t.value = value
+		return nil
+
+	}
+	return fmt.Errorf("unknown enum value: %v", value)
+}
+
+// GetTripEmailsResponseArrayKind defines model for GetTripEmailsResponseArray.Kind.
+type GetTripEmailsResponseArrayKind struct {
+	value string
+}
+
+func (t *GetTripEmailsResponseArrayKind) ToValue() string {
+	return t.value
+}
+func (t GetTripEmailsResponseArrayKind) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.value)
+}
+func (t *GetTripEmailsResponseArrayKind) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	return t.FromValue(value)
+}
+func (t *GetTripEmailsResponseArrayKind) FromValue(value string) error {
+	switch value {
+
+	case GetTripEmailsResponseArrayKindConfirmTripOwner.value:
+		t.value = value
+		return nil
+
+	case GetTripEmailsResponseArrayKindConfirmTripParticipant.value:
+		t.value = value
+		return nil
+
+	case GetTripEmailsResponseArrayKindInviteReminder.value:
+		t.value = value
+		return nil
+
+	case GetTripEmailsResponseArrayKindTripCancelled.value:
+		t.value = value
+		return nil
+
+	case GetTripEmailsResponseArrayKindTripItinerary.value:
+		t.value = value
+		return nil
+
+	case GetTripEmailsResponseArrayKindTripUpdated.value:
+		t.value = value
+		return nil
+
+	}
+	return fmt.Errorf("unknown enum value: %v", value)
+}
+
+// GetTripEmailsResponseArrayStatus defines model for GetTripEmailsResponseArray.Status.
+type GetTripEmailsResponseArrayStatus struct {
+	value string
+}
+
+func (t *GetTripEmailsResponseArrayStatus) ToValue() string {
+	return t.value
+}
+func (t GetTripEmailsResponseArrayStatus) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.value)
+}
+func (t *GetTripEmailsResponseArrayStatus) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return err
+	}
+	return t.FromValue(value)
+}
+func (t *GetTripEmailsResponseArrayStatus) FromValue(value string) error {
+	switch value {
+
+	case GetTripEmailsResponseArrayStatusFailed.value:
+		t.value = value
+		return nil
+
+	case GetTripEmailsResponseArrayStatusSent.value:
+		t.value = value
+		return nil
+
+	case GetTripEmailsResponseArrayStatusSuppressed.value:
+		t.value = value
+		return nil
+
+	}
+	return fmt.Errorf("unknown enum value: %v", value)
+}
+
+// PatchParticipantsParticipantIDJSONBody defines parameters for PatchParticipantsParticipantID.
+type PatchParticipantsParticipantIDJSONBody UpdateParticipantRequest
+
+// GetParticipantsParticipantIDConfirmParams defines parameters for GetParticipantsParticipantIDConfirm.
+type GetParticipantsParticipantIDConfirmParams struct {
+	// The trip the participant is expected to belong to. A participant of another trip is reported as not found.
+	TripID *string `json:"trip_id,omitempty"`
+}
+
+// PatchParticipantsParticipantIDConfirmParams defines parameters for PatchParticipantsParticipantIDConfirm.
+type PatchParticipantsParticipantIDConfirmParams struct {
+	// The trip the participant is expected to belong to. A participant of another trip is reported as not found.
+	TripID *string `json:"trip_id,omitempty"`
+}
+
+// PostParticipantsParticipantIDConfirmParams defines parameters for PostParticipantsParticipantIDConfirm.
+type PostParticipantsParticipantIDConfirmParams struct {
+	// The trip the participant is expected to belong to. A participant of another trip is reported as not found.
+	TripID *string `json:"trip_id,omitempty"`
+}
+
+// GetTripsParams defines parameters for GetTrips.
+type GetTripsParams struct {
+	Limit           *int                 `json:"limit,omitempty"`
+	Offset          *int                 `json:"offset,omitempty"`
+	IsConfirmed     *string              `json:"is_confirmed,omitempty"`
+	OwnerEmail      *openapi_types.Email `json:"owner_email,omitempty"`
+	Sort            *GetTripsParamsSort  `json:"sort,omitempty"`
+	Order           *GetTripsParamsOrder `json:"order,omitempty"`
+	IncludeArchived *string              `json:"include_archived,omitempty"`
+
+	// The next_cursor of the previous page, as is: a changed one is rejected. Only allowed with the default sort and order, and without an offset.
+	Cursor *string `json:"cursor,omitempty"`
+}
+
+// GetTripsParamsSort defines parameters for GetTrips.
+type GetTripsParamsSort string
+
+// GetTripsParamsOrder defines parameters for GetTrips.
+type GetTripsParamsOrder string
+
 // PostTripsJSONBody defines parameters for PostTrips.
 type PostTripsJSONBody CreateTripRequest
 
+// GetTripsParticipatingParams defines parameters for GetTripsParticipating.
+type GetTripsParticipatingParams struct {
+	Email  openapi_types.Email `json:"email"`
+	Limit  *int                `json:"limit,omitempty"`
+	Offset *int                `json:"offset,omitempty"`
+}
+
+// GetTripsSearchParams defines parameters for GetTripsSearch.
+type GetTripsSearchParams struct {
+	Q      string `json:"q"`
+	Limit  *int   `json:"limit,omitempty"`
+	Offset *int   `json:"offset,omitempty"`
+}
+
+// DeleteTripsTripIDParams defines parameters for DeleteTripsTripID.
+type DeleteTripsTripIDParams struct {
+	Force *bool `json:"force,omitempty"`
+}
+
+// PatchTripsTripIDJSONBody defines parameters for PatchTripsTripID.
+type PatchTripsTripIDJSONBody PatchTripRequest
+
+// PatchTripsTripIDParams defines parameters for PatchTripsTripID.
+type PatchTripsTripIDParams struct {
+	Force   *bool   `json:"force,omitempty"`
+	IfMatch *string `json:"If-Match,omitempty"`
+}
+
 // PutTripsTripIDJSONBody defines parameters for PutTripsTripID.
 type PutTripsTripIDJSONBody UpdateTripRequest
 
+// PutTripsTripIDParams defines parameters for PutTripsTripID.
+type PutTripsTripIDParams struct {
+	Force   *bool   `json:"force,omitempty"`
+	IfMatch *string `json:"If-Match,omitempty"`
+}
+
+// GetTripsTripIDActivitiesParams defines parameters for GetTripsTripIDActivities.
+type GetTripsTripIDActivitiesParams struct {
+	Date *openapi_types.Date `json:"date,omitempty"`
+	Tag  *string             `json:"tag,omitempty"`
+}
+
 // PostTripsTripIDActivitiesJSONBody defines parameters for PostTripsTripIDActivities.
 type PostTripsTripIDActivitiesJSONBody CreateActivityRequest
 
+// PostTripsTripIDActivitiesBatchJSONBody defines parameters for PostTripsTripIDActivitiesBatch.
+type PostTripsTripIDActivitiesBatchJSONBody CreateActivitiesBatchRequest
+
+// PostTripsTripIDDuplicateJSONBody defines parameters for PostTripsTripIDDuplicate.
+type PostTripsTripIDDuplicateJSONBody DuplicateTripRequest
+
+// GetTripsTripIDEmailsParams defines parameters for GetTripsTripIDEmails.
+type GetTripsTripIDEmailsParams struct {
+	Limit  *int `json:"limit,omitempty"`
+	Offset *int `json:"offset,omitempty"`
+}
+
 // PostTripsTripIDInvitesJSONBody defines parameters for PostTripsTripIDInvites.
 type PostTripsTripIDInvitesJSONBody InviteParticipantRequest
 
+// GetTripsTripIDLinksParams defines parameters for GetTripsTripIDLinks.
+type GetTripsTripIDLinksParams struct {
+	Limit  *int `json:"limit,omitempty"`
+	Offset *int `json:"offset,omitempty"`
+}
+
 // PostTripsTripIDLinksJSONBody defines parameters for PostTripsTripIDLinks.
 type PostTripsTripIDLinksJSONBody CreateLinkRequest
 
+// PutTripsTripIDLinksLinkIDJSONBody defines parameters for PutTripsTripIDLinksLinkID.
+type PutTripsTripIDLinksLinkIDJSONBody UpdateLinkRequest
+
+// GetTripsTripIDParticipantsParams defines parameters for GetTripsTripIDParticipants.
+type GetTripsTripIDParticipantsParams struct {
+	Limit       *int    `json:"limit,omitempty"`
+	Offset      *int    `json:"offset,omitempty"`
+	IsConfirmed *string `json:"is_confirmed,omitempty"`
+}
+
+// DeleteTripsTripIDParticipantsParticipantIDParams defines parameters for DeleteTripsTripIDParticipantsParticipantID.
+type DeleteTripsTripIDParticipantsParticipantIDParams struct {
+	Force *bool `json:"force,omitempty"`
+}
+
+// PostTripsTripIDUnconfirmJSONBody defines parameters for PostTripsTripIDUnconfirm.
+type PostTripsTripIDUnconfirmJSONBody UnconfirmTripRequest
+
+// PatchParticipantsParticipantIDJSONRequestBody defines body for PatchParticipantsParticipantID for application/json ContentType.
+type PatchParticipantsParticipantIDJSONRequestBody PatchParticipantsParticipantIDJSONBody
+
+// Bind implements render.Binder.
+func (PatchParticipantsParticipantIDJSONRequestBody) Bind(*http.Request) error {
+	return nil
+}
+
 // PostTripsJSONRequestBody defines body for PostTrips for application/json ContentType.
 type PostTripsJSONRequestBody PostTripsJSONBody
 
@@ -162,6 +690,14 @@ func (PostTripsJSONRequestBody) Bind(*http.Request) error {
 	return nil
 }
 
+// PatchTripsTripIDJSONRequestBody defines body for PatchTripsTripID for application/json ContentType.
+type PatchTripsTripIDJSONRequestBody PatchTripsTripIDJSONBody
+
+// Bind implements render.Binder.
+func (PatchTripsTripIDJSONRequestBody) Bind(*http.Request) error {
+	return nil
+}
+
 // PutTripsTripIDJSONRequestBody defines body for PutTripsTripID for application/json ContentType.
 type PutTripsTripIDJSONRequestBody PutTripsTripIDJSONBody
 
@@ -178,6 +714,22 @@ func (PostTripsTripIDActivitiesJSONRequestBody) Bind(*http.Request) error {
 	return nil
 }
 
+// PostTripsTripIDActivitiesBatchJSONRequestBody defines body for PostTripsTripIDActivitiesBatch for application/json ContentType.
+type PostTripsTripIDActivitiesBatchJSONRequestBody PostTripsTripIDActivitiesBatchJSONBody
+
+// Bind implements render.Binder.
+func (PostTripsTripIDActivitiesBatchJSONRequestBody) Bind(*http.Request) error {
+	return nil
+}
+
+// PostTripsTripIDDuplicateJSONRequestBody defines body for PostTripsTripIDDuplicate for application/json ContentType.
+type PostTripsTripIDDuplicateJSONRequestBody PostTripsTripIDDuplicateJSONBody
+
+// Bind implements render.Binder.
+func (PostTripsTripIDDuplicateJSONRequestBody) Bind(*http.Request) error {
+	return nil
+}
+
 // PostTripsTripIDInvitesJSONRequestBody defines body for PostTripsTripIDInvites for application/json ContentType.
 type PostTripsTripIDInvitesJSONRequestBody PostTripsTripIDInvitesJSONBody
 
@@ -194,6 +746,22 @@ func (PostTripsTripIDLinksJSONRequestBody) Bind(*http.Request) error {
 	return nil
 }
 
+// PutTripsTripIDLinksLinkIDJSONRequestBody defines body for PutTripsTripIDLinksLinkID for application/json ContentType.
+type PutTripsTripIDLinksLinkIDJSONRequestBody PutTripsTripIDLinksLinkIDJSONBody
+
+// Bind implements render.Binder.
+func (PutTripsTripIDLinksLinkIDJSONRequestBody) Bind(*http.Request) error {
+	return nil
+}
+
+// PostTripsTripIDUnconfirmJSONRequestBody defines body for PostTripsTripIDUnconfirm for application/json ContentType.
+type PostTripsTripIDUnconfirmJSONRequestBody PostTripsTripIDUnconfirmJSONBody
+
+// Bind implements render.Binder.
+func (PostTripsTripIDUnconfirmJSONRequestBody) Bind(*http.Request) error {
+	return nil
+}
+
 // Response is a common response struct for all the API calls.
 // A Response object may be instantiated via functions for specific operation responses.
 // It may also be instantiated directly, for the purpose of responding with a single status code.
@@ -235,19 +803,19 @@ func (resp *Response) MarshalXML(e *xml.Encoder, start xml.StartElement) error {
 	return e.Encode(resp.body)
 }
 
-// PatchParticipantsParticipantIDConfirmJSON204Response is a constructor method for a PatchParticipantsParticipantIDConfirm response.
+// GetParticipantsParticipantIDJSON200Response is a constructor method for a GetParticipantsParticipantID response.
 // A *Response is returned with the configured status code and content type from the spec.
-func PatchParticipantsParticipantIDConfirmJSON204Response(body interface{}) *Response {
+func GetParticipantsParticipantIDJSON200Response(body GetParticipantDetailsResponse) *Response {
 	return &Response{
 		body:        body,
-		Code:        204,
+		Code:        200,
 		contentType: "application/json",
 	}
 }
 
-// PatchParticipantsParticipantIDConfirmJSON400Response is a constructor method for a PatchParticipantsParticipantIDConfirm response.
+// GetParticipantsParticipantIDJSON400Response is a constructor method for a GetParticipantsParticipantID response.
 // A *Response is returned with the configured status code and content type from the spec.
-func PatchParticipantsParticipantIDConfirmJSON400Response(body Error) *Response {
+func GetParticipantsParticipantIDJSON400Response(body Error) *Response {
 	return &Response{
 		body:        body,
 		Code:        400,
@@ -255,19 +823,19 @@ func PatchParticipantsParticipantIDConfirmJSON400Response(body Error) *Response
 	}
 }
 
-// GetTripsJSON200Response is a constructor method for a GetTrips response.
+// PatchParticipantsParticipantIDJSON204Response is a constructor method for a PatchParticipantsParticipantID response.
 // A *Response is returned with the configured status code and content type from the spec.
-func GetTripsJSON200Response(body GetTripsResponse) *Response {
+func PatchParticipantsParticipantIDJSON204Response(body interface{}) *Response {
 	return &Response{
 		body:        body,
-		Code:        200,
+		Code:        204,
 		contentType: "application/json",
 	}
 }
 
-// GetTripsJSON400Response is a constructor method for a GetTrips response.
+// PatchParticipantsParticipantIDJSON400Response is a constructor method for a PatchParticipantsParticipantID response.
 // A *Response is returned with the configured status code and content type from the spec.
-func GetTripsJSON400Response(body Error) *Response {
+func PatchParticipantsParticipantIDJSON400Response(body Error) *Response {
 	return &Response{
 		body:        body,
 		Code:        400,
@@ -275,19 +843,9 @@ func GetTripsJSON400Response(body Error) *Response {
 	}
 }
 
-// PostTripsJSON201Response is a constructor method for a PostTrips response.
-// A *Response is returned with the configured status code and content type from the spec.
-func PostTripsJSON201Response(body CreateTripResponse) *Response {
-	return &Response{
-		body:        body,
-		Code:        201,
-		contentType: "application/json",
-	}
-}
-
-// PostTripsJSON400Response is a constructor method for a PostTrips response.
+// GetParticipantsParticipantIDConfirmJSON400Response is a constructor method for a GetParticipantsParticipantIDConfirm response.
 // A *Response is returned with the configured status code and content type from the spec.
-func PostTripsJSON400Response(body Error) *Response {
+func GetParticipantsParticipantIDConfirmJSON400Response(body Error) *Response {
 	return &Response{
 		body:        body,
 		Code:        400,
@@ -295,19 +853,19 @@ func PostTripsJSON400Response(body Error) *Response {
 	}
 }
 
-// GetTripsTripIDJSON200Response is a constructor method for a GetTripsTripID response.
+// PatchParticipantsParticipantIDConfirmJSON204Response is a constructor method for a PatchParticipantsParticipantIDConfirm response.
 // A *Response is returned with the configured status code and content type from the spec.
-func GetTripsTripIDJSON200Response(body GetTripDetailsResponse) *Response {
+func PatchParticipantsParticipantIDConfirmJSON204Response(body interface{}) *Response {
 	return &Response{
 		body:        body,
-		Code:        200,
+		Code:        204,
 		contentType: "application/json",
 	}
 }
 
-// GetTripsTripIDJSON400Response is a constructor method for a GetTripsTripID response.
+// PatchParticipantsParticipantIDConfirmJSON400Response is a constructor method for a PatchParticipantsParticipantIDConfirm response.
 // A *Response is returned with the configured status code and content type from the spec.
-func GetTripsTripIDJSON400Response(body Error) *Response {
+func PatchParticipantsParticipantIDConfirmJSON400Response(body Error) *Response {
 	return &Response{
 		body:        body,
 		Code:        400,
@@ -315,9 +873,9 @@ func GetTripsTripIDJSON400Response(body Error) *Response {
 	}
 }
 
-// PutTripsTripIDJSON204Response is a constructor method for a PutTripsTripID response.
+// PostParticipantsParticipantIDConfirmJSON204Response is a constructor method for a PostParticipantsParticipantIDConfirm response.
 // A *Response is returned with the configured status code and content type from the spec.
-func PutTripsTripIDJSON204Response(body interface{}) *Response {
+func PostParticipantsParticipantIDConfirmJSON204Response(body interface{}) *Response {
 	return &Response{
 		body:        body,
 		Code:        204,
@@ -325,9 +883,9 @@ func PutTripsTripIDJSON204Response(body interface{}) *Response {
 	}
 }
 
-// PutTripsTripIDJSON400Response is a constructor method for a PutTripsTripID response.
+// PostParticipantsParticipantIDConfirmJSON400Response is a constructor method for a PostParticipantsParticipantIDConfirm response.
 // A *Response is returned with the configured status code and content type from the spec.
-func PutTripsTripIDJSON400Response(body Error) *Response {
+func PostParticipantsParticipantIDConfirmJSON400Response(body Error) *Response {
 	return &Response{
 		body:        body,
 		Code:        400,
@@ -335,19 +893,19 @@ func PutTripsTripIDJSON400Response(body Error) *Response {
 	}
 }
 
-// GetTripsTripIDActivitiesJSON200Response is a constructor method for a GetTripsTripIDActivities response.
+// PatchParticipantsParticipantIDDeclineJSON204Response is a constructor method for a PatchParticipantsParticipantIDDecline response.
 // A *Response is returned with the configured status code and content type from the spec.
-func GetTripsTripIDActivitiesJSON200Response(body GetTripActivitiesResponse) *Response {
+func PatchParticipantsParticipantIDDeclineJSON204Response(body interface{}) *Response {
 	return &Response{
 		body:        body,
-		Code:        200,
+		Code:        204,
 		contentType: "application/json",
 	}
 }
 
-// GetTripsTripIDActivitiesJSON400Response is a constructor method for a GetTripsTripIDActivities response.
+// PatchParticipantsParticipantIDDeclineJSON400Response is a constructor method for a PatchParticipantsParticipantIDDecline response.
 // A *Response is returned with the configured status code and content type from the spec.
-func GetTripsTripIDActivitiesJSON400Response(body Error) *Response {
+func PatchParticipantsParticipantIDDeclineJSON400Response(body Error) *Response {
 	return &Response{
 		body:        body,
 		Code:        400,
@@ -355,19 +913,19 @@ func GetTripsTripIDActivitiesJSON400Response(body Error) *Response {
 	}
 }
 
-// PostTripsTripIDActivitiesJSON201Response is a constructor method for a PostTripsTripIDActivities response.
+// PostParticipantsParticipantIDResendInviteJSON204Response is a constructor method for a PostParticipantsParticipantIDResendInvite response.
 // A *Response is returned with the configured status code and content type from the spec.
-func PostTripsTripIDActivitiesJSON201Response(body CreateActivityResponse) *Response {
+func PostParticipantsParticipantIDResendInviteJSON204Response(body interface{}) *Response {
 	return &Response{
 		body:        body,
-		Code:        201,
+		Code:        204,
 		contentType: "application/json",
 	}
 }
 
-// PostTripsTripIDActivitiesJSON400Response is a constructor method for a PostTripsTripIDActivities response.
+// PostParticipantsParticipantIDResendInviteJSON400Response is a constructor method for a PostParticipantsParticipantIDResendInvite response.
 // A *Response is returned with the configured status code and content type from the spec.
-func PostTripsTripIDActivitiesJSON400Response(body Error) *Response {
+func PostParticipantsParticipantIDResendInviteJSON400Response(body Error) *Response {
 	return &Response{
 		body:        body,
 		Code:        400,
@@ -375,39 +933,49 @@ func PostTripsTripIDActivitiesJSON400Response(body Error) *Response {
 	}
 }
 
-// GetTripsTripIDConfirmJSON204Response is a constructor method for a GetTripsTripIDConfirm response.
+// PostParticipantsParticipantIDResendInviteJSON429Response is a constructor method for a PostParticipantsParticipantIDResendInvite response.
 // A *Response is returned with the configured status code and content type from the spec.
-func GetTripsTripIDConfirmJSON204Response(body interface{}) *Response {
+func PostParticipantsParticipantIDResendInviteJSON429Response(body Error) *Response {
 	return &Response{
 		body:        body,
-		Code:        204,
+		Code:        429,
 		contentType: "application/json",
 	}
 }
 
-// GetTripsTripIDConfirmJSON400Response is a constructor method for a GetTripsTripIDConfirm response.
+// GetTripsJSON200Response is a constructor method for a GetTrips response.
 // A *Response is returned with the configured status code and content type from the spec.
-func GetTripsTripIDConfirmJSON400Response(body Error) *Response {
+func GetTripsJSON200Response(body GetTripsResponse) *Response {
 	return &Response{
 		body:        body,
-		Code:        400,
+		Code:        200,
 		contentType: "application/json",
 	}
 }
 
-// PostTripsTripIDInvitesJSON201Response is a constructor method for a PostTripsTripIDInvites response.
+// GetTripsJSON400Response is a constructor method for a GetTrips response.
 // A *Response is returned with the configured status code and content type from the spec.
-func PostTripsTripIDInvitesJSON201Response(body interface{}) *Response {
+func GetTripsJSON400Response(body Error) *Response {
 	return &Response{
 		body:        body,
-		Code:        201,
+		Code:        400,
 		contentType: "application/json",
 	}
 }
 
-// PostTripsTripIDInvitesJSON400Response is a constructor method for a PostTripsTripIDInvites response.
+// PostTripsJSON201Response is a constructor method for a PostTrips response.
 // A *Response is returned with the configured status code and content type from the spec.
-func PostTripsTripIDInvitesJSON400Response(body Error) *Response {
+func PostTripsJSON201Response(body CreateTripResponse) *Response {
+	return &Response{
+		body:        body,
+		Code:        201,
+		contentType: "application/json",
+	}
+}
+
+// PostTripsJSON400Response is a constructor method for a PostTrips response.
+// A *Response is returned with the configured status code and content type from the spec.
+func PostTripsJSON400Response(body Error) *Response {
 	return &Response{
 		body:        body,
 		Code:        400,
@@ -415,9 +983,239 @@ func PostTripsTripIDInvitesJSON400Response(body Error) *Response {
 	}
 }
 
-// GetTripsTripIDLinksJSON200Response is a constructor method for a GetTripsTripIDLinks response.
+// GetTripsParticipatingJSON200Response is a constructor method for a GetTripsParticipating response.
 // A *Response is returned with the configured status code and content type from the spec.
-func GetTripsTripIDLinksJSON200Response(body GetLinksResponse) *Response {
+func GetTripsParticipatingJSON200Response(body GetParticipatingTripsResponse) *Response {
+	return &Response{
+		body:        body,
+		Code:        200,
+		contentType: "application/json",
+	}
+}
+
+// GetTripsParticipatingJSON400Response is a constructor method for a GetTripsParticipating response.
+// A *Response is returned with the configured status code and content type from the spec.
+func GetTripsParticipatingJSON400Response(body Error) *Response {
+	return &Response{
+		body:        body,
+		Code:        400,
+		contentType: "application/json",
+	}
+}
+
+// GetTripsSearchJSON200Response is a constructor method for a GetTripsSearch response.
+// A *Response is returned with the configured status code and content type from the spec.
+func GetTripsSearchJSON200Response(body GetTripsResponse) *Response {
+	return &Response{
+		body:        body,
+		Code:        200,
+		contentType: "application/json",
+	}
+}
+
+// GetTripsSearchJSON400Response is a constructor method for a GetTripsSearch response.
+// A *Response is returned with the configured status code and content type from the spec.
+func GetTripsSearchJSON400Response(body Error) *Response {
+	return &Response{
+		body:        body,
+		Code:        400,
+		contentType: "application/json",
+	}
+}
+
+// DeleteTripsTripIDJSON204Response is a constructor method for a DeleteTripsTripID response.
+// A *Response is returned with the configured status code and content type from the spec.
+func DeleteTripsTripIDJSON204Response(body interface{}) *Response {
+	return &Response{
+		body:        body,
+		Code:        204,
+		contentType: "application/json",
+	}
+}
+
+// DeleteTripsTripIDJSON400Response is a constructor method for a DeleteTripsTripID response.
+// A *Response is returned with the configured status code and content type from the spec.
+func DeleteTripsTripIDJSON400Response(body Error) *Response {
+	return &Response{
+		body:        body,
+		Code:        400,
+		contentType: "application/json",
+	}
+}
+
+// GetTripsTripIDJSON200Response is a constructor method for a GetTripsTripID response.
+// A *Response is returned with the configured status code and content type from the spec.
+func GetTripsTripIDJSON200Response(body GetTripDetailsResponse) *Response {
+	return &Response{
+		body:        body,
+		Code:        200,
+		contentType: "application/json",
+	}
+}
+
+// GetTripsTripIDJSON400Response is a constructor method for a GetTripsTripID response.
+// A *Response is returned with the configured status code and content type from the spec.
+func GetTripsTripIDJSON400Response(body Error) *Response {
+	return &Response{
+		body:        body,
+		Code:        400,
+		contentType: "application/json",
+	}
+}
+
+// PatchTripsTripIDJSON204Response is a constructor method for a PatchTripsTripID response.
+// A *Response is returned with the configured status code and content type from the spec.
+func PatchTripsTripIDJSON204Response(body interface{}) *Response {
+	return &Response{
+		body:        body,
+		Code:        204,
+		contentType: "application/json",
+	}
+}
+
+// PatchTripsTripIDJSON400Response is a constructor method for a PatchTripsTripID response.
+// A *Response is returned with the configured status code and content type from the spec.
+func PatchTripsTripIDJSON400Response(body Error) *Response {
+	return &Response{
+		body:        body,
+		Code:        400,
+		contentType: "application/json",
+	}
+}
+
+// PatchTripsTripIDJSON409Response is a constructor method for a PatchTripsTripID response.
+// A *Response is returned with the configured status code and content type from the spec.
+func PatchTripsTripIDJSON409Response(body ActivitiesOutOfRangeResponse) *Response {
+	return &Response{
+		body:        body,
+		Code:        409,
+		contentType: "application/json",
+	}
+}
+
+// PatchTripsTripIDJSON412Response is a constructor method for a PatchTripsTripID response.
+// A *Response is returned with the configured status code and content type from the spec.
+func PatchTripsTripIDJSON412Response(body Error) *Response {
+	return &Response{
+		body:        body,
+		Code:        412,
+		contentType: "application/json",
+	}
+}
+
+// PutTripsTripIDJSON204Response is a constructor method for a PutTripsTripID response.
+// A *Response is returned with the configured status code and content type from the spec.
+func PutTripsTripIDJSON204Response(body interface{}) *Response {
+	return &Response{
+		body:        body,
+		Code:        204,
+		contentType: "application/json",
+	}
+}
+
+// PutTripsTripIDJSON400Response is a constructor method for a PutTripsTripID response.
+// A *Response is returned with the configured status code and content type from the spec.
+func PutTripsTripIDJSON400Response(body Error) *Response {
+	return &Response{
+		body:        body,
+		Code:        400,
+		contentType: "application/json",
+	}
+}
+
+// PutTripsTripIDJSON409Response is a constructor method for a PutTripsTripID response.
+// A *Response is returned with the configured status code and content type from the spec.
+func PutTripsTripIDJSON409Response(body ActivitiesOutOfRangeResponse) *Response {
+	return &Response{
+		body:        body,
+		Code:        409,
+		contentType: "application/json",
+	}
+}
+
+// PutTripsTripIDJSON412Response is a constructor method for a PutTripsTripID response.
+// A *Response is returned with the configured status code and content type from the spec.
+func PutTripsTripIDJSON412Response(body Error) *Response {
+	return &Response{
+		body:        body,
+		Code:        412,
+		contentType: "application/json",
+	}
+}
+
+// GetTripsTripIDActivitiesJSON200Response is a constructor method for a GetTripsTripIDActivities response.
+// A *Response is returned with the configured status code and content type from the spec.
+func GetTripsTripIDActivitiesJSON200Response(body GetTripActivitiesResponse) *Response {
+	return &Response{
+		body:        body,
+		Code:        200,
+		contentType: "application/json",
+	}
+}
+
+// GetTripsTripIDActivitiesJSON400Response is a constructor method for a GetTripsTripIDActivities response.
+// A *Response is returned with the configured status code and content type from the spec.
+func GetTripsTripIDActivitiesJSON400Response(body Error) *Response {
+	return &Response{
+		body:        body,
+		Code:        400,
+		contentType: "application/json",
+	}
+}
+
+// PostTripsTripIDActivitiesJSON201Response is a constructor method for a PostTripsTripIDActivities response.
+// A *Response is returned with the configured status code and content type from the spec.
+func PostTripsTripIDActivitiesJSON201Response(body CreateActivityResponse) *Response {
+	return &Response{
+		body:        body,
+		Code:        201,
+		contentType: "application/json",
+	}
+}
+
+// PostTripsTripIDActivitiesJSON400Response is a constructor method for a PostTripsTripIDActivities response.
+// A *Response is returned with the configured status code and content type from the spec.
+func PostTripsTripIDActivitiesJSON400Response(body Error) *Response {
+	return &Response{
+		body:        body,
+		Code:        400,
+		contentType: "application/json",
+	}
+}
+
+// GetTripsTripIDActivitiesIcsJSON400Response is a constructor method for a GetTripsTripIDActivitiesIcs response.
+// A *Response is returned with the configured status code and content type from the spec.
+func GetTripsTripIDActivitiesIcsJSON400Response(body Error) *Response {
+	return &Response{
+		body:        body,
+		Code:        400,
+		contentType: "application/json",
+	}
+}
+
+// PostTripsTripIDActivitiesBatchJSON201Response is a constructor method for a PostTripsTripIDActivitiesBatch response.
+// A *Response is returned with the configured status code and content type from the spec.
+func PostTripsTripIDActivitiesBatchJSON201Response(body CreateActivitiesBatchResponse) *Response {
+	return &Response{
+		body:        body,
+		Code:        201,
+		contentType: "application/json",
+	}
+}
+
+// PostTripsTripIDActivitiesBatchJSON400Response is a constructor method for a PostTripsTripIDActivitiesBatch response.
+// A *Response is returned with the configured status code and content type from the spec.
+func PostTripsTripIDActivitiesBatchJSON400Response(body Error) *Response {
+	return &Response{
+		body:        body,
+		Code:        400,
+		contentType: "application/json",
+	}
+}
+
+// GetTripsTripIDActivitiesActivityIDJSON200Response is a constructor method for a GetTripsTripIDActivitiesActivityID response.
+// A *Response is returned with the configured status code and content type from the spec.
+func GetTripsTripIDActivitiesActivityIDJSON200Response(body GetActivityResponse) *Response {
 	return &Response{
 		body:        body,
 		Code:        200,
@@ -425,116 +1223,1435 @@ func GetTripsTripIDLinksJSON200Response(body GetLinksResponse) *Response {
 	}
 }
 
-// GetTripsTripIDLinksJSON400Response is a constructor method for a GetTripsTripIDLinks response.
-// A *Response is returned with the configured status code and content type from the spec.
-func GetTripsTripIDLinksJSON400Response(body Error) *Response {
-	return &Response{
-		body:        body,
-		Code:        400,
-		contentType: "application/json",
+// GetTripsTripIDActivitiesActivityIDJSON400Response is a constructor method for a GetTripsTripIDActivitiesActivityID response.
+// A *Response is returned with the configured status code and content type from the spec.
+func GetTripsTripIDActivitiesActivityIDJSON400Response(body Error) *Response {
+	return &Response{
+		body:        body,
+		Code:        400,
+		contentType: "application/json",
+	}
+}
+
+// PostTripsTripIDArchiveJSON204Response is a constructor method for a PostTripsTripIDArchive response.
+// A *Response is returned with the configured status code and content type from the spec.
+func PostTripsTripIDArchiveJSON204Response(body interface{}) *Response {
+	return &Response{
+		body:        body,
+		Code:        204,
+		contentType: "application/json",
+	}
+}
+
+// PostTripsTripIDArchiveJSON400Response is a constructor method for a PostTripsTripIDArchive response.
+// A *Response is returned with the configured status code and content type from the spec.
+func PostTripsTripIDArchiveJSON400Response(body Error) *Response {
+	return &Response{
+		body:        body,
+		Code:        400,
+		contentType: "application/json",
+	}
+}
+
+// PostTripsTripIDCancelJSON204Response is a constructor method for a PostTripsTripIDCancel response.
+// A *Response is returned with the configured status code and content type from the spec.
+func PostTripsTripIDCancelJSON204Response(body interface{}) *Response {
+	return &Response{
+		body:        body,
+		Code:        204,
+		contentType: "application/json",
+	}
+}
+
+// PostTripsTripIDCancelJSON400Response is a constructor method for a PostTripsTripIDCancel response.
+// A *Response is returned with the configured status code and content type from the spec.
+func PostTripsTripIDCancelJSON400Response(body Error) *Response {
+	return &Response{
+		body:        body,
+		Code:        400,
+		contentType: "application/json",
+	}
+}
+
+// GetTripsTripIDConfirmJSON204Response is a constructor method for a GetTripsTripIDConfirm response.
+// A *Response is returned with the configured status code and content type from the spec.
+func GetTripsTripIDConfirmJSON204Response(body interface{}) *Response {
+	return &Response{
+		body:        body,
+		Code:        204,
+		contentType: "application/json",
+	}
+}
+
+// GetTripsTripIDConfirmJSON400Response is a constructor method for a GetTripsTripIDConfirm response.
+// A *Response is returned with the configured status code and content type from the spec.
+func GetTripsTripIDConfirmJSON400Response(body Error) *Response {
+	return &Response{
+		body:        body,
+		Code:        400,
+		contentType: "application/json",
+	}
+}
+
+// PostTripsTripIDDuplicateJSON201Response is a constructor method for a PostTripsTripIDDuplicate response.
+// A *Response is returned with the configured status code and content type from the spec.
+func PostTripsTripIDDuplicateJSON201Response(body CreateTripResponse) *Response {
+	return &Response{
+		body:        body,
+		Code:        201,
+		contentType: "application/json",
+	}
+}
+
+// PostTripsTripIDDuplicateJSON400Response is a constructor method for a PostTripsTripIDDuplicate response.
+// A *Response is returned with the configured status code and content type from the spec.
+func PostTripsTripIDDuplicateJSON400Response(body Error) *Response {
+	return &Response{
+		body:        body,
+		Code:        400,
+		contentType: "application/json",
+	}
+}
+
+// GetTripsTripIDEmailsJSON200Response is a constructor method for a GetTripsTripIDEmails response.
+// A *Response is returned with the configured status code and content type from the spec.
+func GetTripsTripIDEmailsJSON200Response(body GetTripEmailsResponse) *Response {
+	return &Response{
+		body:        body,
+		Code:        200,
+		contentType: "application/json",
+	}
+}
+
+// GetTripsTripIDEmailsJSON400Response is a constructor method for a GetTripsTripIDEmails response.
+// A *Response is returned with the configured status code and content type from the spec.
+func GetTripsTripIDEmailsJSON400Response(body Error) *Response {
+	return &Response{
+		body:        body,
+		Code:        400,
+		contentType: "application/json",
+	}
+}
+
+// GetTripsTripIDEventsJSON400Response is a constructor method for a GetTripsTripIDEvents response.
+// A *Response is returned with the configured status code and content type from the spec.
+func GetTripsTripIDEventsJSON400Response(body Error) *Response {
+	return &Response{
+		body:        body,
+		Code:        400,
+		contentType: "application/json",
+	}
+}
+
+// GetTripsTripIDExportJSON200Response is a constructor method for a GetTripsTripIDExport response.
+// A *Response is returned with the configured status code and content type from the spec.
+func GetTripsTripIDExportJSON200Response(body GetTripExportResponse) *Response {
+	return &Response{
+		body:        body,
+		Code:        200,
+		contentType: "application/json",
+	}
+}
+
+// GetTripsTripIDExportJSON400Response is a constructor method for a GetTripsTripIDExport response.
+// A *Response is returned with the configured status code and content type from the spec.
+func GetTripsTripIDExportJSON400Response(body Error) *Response {
+	return &Response{
+		body:        body,
+		Code:        400,
+		contentType: "application/json",
+	}
+}
+
+// PostTripsTripIDInvitesJSON200Response is a constructor method for a PostTripsTripIDInvites response.
+// A *Response is returned with the configured status code and content type from the spec.
+func PostTripsTripIDInvitesJSON200Response(body interface{}) *Response {
+	return &Response{
+		body:        body,
+		Code:        200,
+		contentType: "application/json",
+	}
+}
+
+// PostTripsTripIDInvitesJSON201Response is a constructor method for a PostTripsTripIDInvites response.
+// A *Response is returned with the configured status code and content type from the spec.
+func PostTripsTripIDInvitesJSON201Response(body interface{}) *Response {
+	return &Response{
+		body:        body,
+		Code:        201,
+		contentType: "application/json",
+	}
+}
+
+// PostTripsTripIDInvitesJSON400Response is a constructor method for a PostTripsTripIDInvites response.
+// A *Response is returned with the configured status code and content type from the spec.
+func PostTripsTripIDInvitesJSON400Response(body Error) *Response {
+	return &Response{
+		body:        body,
+		Code:        400,
+		contentType: "application/json",
+	}
+}
+
+// PostTripsTripIDInvitesJSON409Response is a constructor method for a PostTripsTripIDInvites response.
+// A *Response is returned with the configured status code and content type from the spec.
+func PostTripsTripIDInvitesJSON409Response(body InviteConflictResponse) *Response {
+	return &Response{
+		body:        body,
+		Code:        409,
+		contentType: "application/json",
+	}
+}
+
+// PostTripsTripIDInvitesJSON429Response is a constructor method for a PostTripsTripIDInvites response.
+// A *Response is returned with the configured status code and content type from the spec.
+func PostTripsTripIDInvitesJSON429Response(body Error) *Response {
+	return &Response{
+		body:        body,
+		Code:        429,
+		contentType: "application/json",
+	}
+}
+
+// GetTripsTripIDLinksJSON200Response is a constructor method for a GetTripsTripIDLinks response.
+// A *Response is returned with the configured status code and content type from the spec.
+func GetTripsTripIDLinksJSON200Response(body GetLinksResponse) *Response {
+	return &Response{
+		body:        body,
+		Code:        200,
+		contentType: "application/json",
+	}
+}
+
+// GetTripsTripIDLinksJSON400Response is a constructor method for a GetTripsTripIDLinks response.
+// A *Response is returned with the configured status code and content type from the spec.
+func GetTripsTripIDLinksJSON400Response(body Error) *Response {
+	return &Response{
+		body:        body,
+		Code:        400,
+		contentType: "application/json",
+	}
+}
+
+// PostTripsTripIDLinksJSON201Response is a constructor method for a PostTripsTripIDLinks response.
+// A *Response is returned with the configured status code and content type from the spec.
+func PostTripsTripIDLinksJSON201Response(body CreateLinkResponse) *Response {
+	return &Response{
+		body:        body,
+		Code:        201,
+		contentType: "application/json",
+	}
+}
+
+// PostTripsTripIDLinksJSON400Response is a constructor method for a PostTripsTripIDLinks response.
+// A *Response is returned with the configured status code and content type from the spec.
+func PostTripsTripIDLinksJSON400Response(body Error) *Response {
+	return &Response{
+		body:        body,
+		Code:        400,
+		contentType: "application/json",
+	}
+}
+
+// PostTripsTripIDLinksJSON409Response is a constructor method for a PostTripsTripIDLinks response.
+// A *Response is returned with the configured status code and content type from the spec.
+func PostTripsTripIDLinksJSON409Response(body Error) *Response {
+	return &Response{
+		body:        body,
+		Code:        409,
+		contentType: "application/json",
+	}
+}
+
+// PutTripsTripIDLinksLinkIDJSON204Response is a constructor method for a PutTripsTripIDLinksLinkID response.
+// A *Response is returned with the configured status code and content type from the spec.
+func PutTripsTripIDLinksLinkIDJSON204Response(body interface{}) *Response {
+	return &Response{
+		body:        body,
+		Code:        204,
+		contentType: "application/json",
+	}
+}
+
+// PutTripsTripIDLinksLinkIDJSON400Response is a constructor method for a PutTripsTripIDLinksLinkID response.
+// A *Response is returned with the configured status code and content type from the spec.
+func PutTripsTripIDLinksLinkIDJSON400Response(body Error) *Response {
+	return &Response{
+		body:        body,
+		Code:        400,
+		contentType: "application/json",
+	}
+}
+
+// PutTripsTripIDLinksLinkIDJSON409Response is a constructor method for a PutTripsTripIDLinksLinkID response.
+// A *Response is returned with the configured status code and content type from the spec.
+func PutTripsTripIDLinksLinkIDJSON409Response(body Error) *Response {
+	return &Response{
+		body:        body,
+		Code:        409,
+		contentType: "application/json",
+	}
+}
+
+// GetTripsTripIDParticipantsJSON200Response is a constructor method for a GetTripsTripIDParticipants response.
+// A *Response is returned with the configured status code and content type from the spec.
+func GetTripsTripIDParticipantsJSON200Response(body GetTripParticipantsResponse) *Response {
+	return &Response{
+		body:        body,
+		Code:        200,
+		contentType: "application/json",
+	}
+}
+
+// GetTripsTripIDParticipantsJSON400Response is a constructor method for a GetTripsTripIDParticipants response.
+// A *Response is returned with the configured status code and content type from the spec.
+func GetTripsTripIDParticipantsJSON400Response(body Error) *Response {
+	return &Response{
+		body:        body,
+		Code:        400,
+		contentType: "application/json",
+	}
+}
+
+// DeleteTripsTripIDParticipantsParticipantIDJSON204Response is a constructor method for a DeleteTripsTripIDParticipantsParticipantID response.
+// A *Response is returned with the configured status code and content type from the spec.
+func DeleteTripsTripIDParticipantsParticipantIDJSON204Response(body interface{}) *Response {
+	return &Response{
+		body:        body,
+		Code:        204,
+		contentType: "application/json",
+	}
+}
+
+// DeleteTripsTripIDParticipantsParticipantIDJSON400Response is a constructor method for a DeleteTripsTripIDParticipantsParticipantID response.
+// A *Response is returned with the configured status code and content type from the spec.
+func DeleteTripsTripIDParticipantsParticipantIDJSON400Response(body Error) *Response {
+	return &Response{
+		body:        body,
+		Code:        400,
+		contentType: "application/json",
+	}
+}
+
+// GetTripsTripIDSummaryJSON200Response is a constructor method for a GetTripsTripIDSummary response.
+// A *Response is returned with the configured status code and content type from the spec.
+func GetTripsTripIDSummaryJSON200Response(body GetTripSummaryResponse) *Response {
+	return &Response{
+		body:        body,
+		Code:        200,
+		contentType: "application/json",
+	}
+}
+
+// GetTripsTripIDSummaryJSON400Response is a constructor method for a GetTripsTripIDSummary response.
+// A *Response is returned with the configured status code and content type from the spec.
+func GetTripsTripIDSummaryJSON400Response(body Error) *Response {
+	return &Response{
+		body:        body,
+		Code:        400,
+		contentType: "application/json",
+	}
+}
+
+// PostTripsTripIDUnarchiveJSON204Response is a constructor method for a PostTripsTripIDUnarchive response.
+// A *Response is returned with the configured status code and content type from the spec.
+func PostTripsTripIDUnarchiveJSON204Response(body interface{}) *Response {
+	return &Response{
+		body:        body,
+		Code:        204,
+		contentType: "application/json",
+	}
+}
+
+// PostTripsTripIDUnarchiveJSON400Response is a constructor method for a PostTripsTripIDUnarchive response.
+// A *Response is returned with the configured status code and content type from the spec.
+func PostTripsTripIDUnarchiveJSON400Response(body Error) *Response {
+	return &Response{
+		body:        body,
+		Code:        400,
+		contentType: "application/json",
+	}
+}
+
+// PostTripsTripIDUnconfirmJSON204Response is a constructor method for a PostTripsTripIDUnconfirm response.
+// A *Response is returned with the configured status code and content type from the spec.
+func PostTripsTripIDUnconfirmJSON204Response(body interface{}) *Response {
+	return &Response{
+		body:        body,
+		Code:        204,
+		contentType: "application/json",
+	}
+}
+
+// PostTripsTripIDUnconfirmJSON400Response is a constructor method for a PostTripsTripIDUnconfirm response.
+// A *Response is returned with the configured status code and content type from the spec.
+func PostTripsTripIDUnconfirmJSON400Response(body Error) *Response {
+	return &Response{
+		body:        body,
+		Code:        400,
+		contentType: "application/json",
+	}
+}
+
+// GetUnsubscribeTokenJSON400Response is a constructor method for a GetUnsubscribeToken response.
+// A *Response is returned with the configured status code and content type from the spec.
+func GetUnsubscribeTokenJSON400Response(body Error) *Response {
+	return &Response{
+		body:        body,
+		Code:        400,
+		contentType: "application/json",
+	}
+}
+
+// PostUnsubscribeTokenJSON204Response is a constructor method for a PostUnsubscribeToken response.
+// A *Response is returned with the configured status code and content type from the spec.
+func PostUnsubscribeTokenJSON204Response(body interface{}) *Response {
+	return &Response{
+		body:        body,
+		Code:        204,
+		contentType: "application/json",
+	}
+}
+
+// PostUnsubscribeTokenJSON400Response is a constructor method for a PostUnsubscribeToken response.
+// A *Response is returned with the configured status code and content type from the spec.
+func PostUnsubscribeTokenJSON400Response(body Error) *Response {
+	return &Response{
+		body:        body,
+		Code:        400,
+		contentType: "application/json",
+	}
+}
+
+// ServerInterface represents all server handlers.
+type ServerInterface interface {
+	// Get a participant details.
+	// (GET /participants/{participantId})
+	GetParticipantsParticipantID(w http.ResponseWriter, r *http.Request, participantID uuid.UUID) *Response
+	// Update a participant.
+	// (PATCH /participants/{participantId})
+	PatchParticipantsParticipantID(w http.ResponseWriter, r *http.Request, participantID uuid.UUID) *Response
+	// Ask to confirm a participant on a trip, from the link of the invitation e-mail.
+	// (GET /participants/{participantId}/confirm)
+	GetParticipantsParticipantIDConfirm(w http.ResponseWriter, r *http.Request, participantID uuid.UUID, params GetParticipantsParticipantIDConfirmParams) *Response
+	// Confirms a participant on a trip.
+	// (PATCH /participants/{participantId}/confirm)
+	PatchParticipantsParticipantIDConfirm(w http.ResponseWriter, r *http.Request, participantID uuid.UUID, params PatchParticipantsParticipantIDConfirmParams) *Response
+	// Confirms a participant on a trip, from the confirmation page.
+	// (POST /participants/{participantId}/confirm)
+	PostParticipantsParticipantIDConfirm(w http.ResponseWriter, r *http.Request, participantID uuid.UUID, params PostParticipantsParticipantIDConfirmParams) *Response
+	// Declines a participant on a trip.
+	// (PATCH /participants/{participantId}/decline)
+	PatchParticipantsParticipantIDDecline(w http.ResponseWriter, r *http.Request, participantID uuid.UUID) *Response
+	// Resend the invitation e-mail to a participant.
+	// (POST /participants/{participantId}/resend-invite)
+	PostParticipantsParticipantIDResendInvite(w http.ResponseWriter, r *http.Request, participantID uuid.UUID) *Response
+	// Lists all trips
+	// (GET /trips)
+	GetTrips(w http.ResponseWriter, r *http.Request, params GetTripsParams) *Response
+	// Create a new trip
+	// (POST /trips)
+	PostTrips(w http.ResponseWriter, r *http.Request) *Response
+	// Lists the trips an email was invited to
+	// (GET /trips/participating)
+	GetTripsParticipating(w http.ResponseWriter, r *http.Request, params GetTripsParticipatingParams) *Response
+	// Search trips by destination, description or owner name.
+	// (GET /trips/search)
+	GetTripsSearch(w http.ResponseWriter, r *http.Request, params GetTripsSearchParams) *Response
+	// Delete a trip.
+	// (DELETE /trips/{tripId})
+	DeleteTripsTripID(w http.ResponseWriter, r *http.Request, tripID uuid.UUID, params DeleteTripsTripIDParams) *Response
+	// Get a trip details.
+	// (GET /trips/{tripId})
+	GetTripsTripID(w http.ResponseWriter, r *http.Request, tripID uuid.UUID) *Response
+	// Partially update a trip.
+	// (PATCH /trips/{tripId})
+	PatchTripsTripID(w http.ResponseWriter, r *http.Request, tripID uuid.UUID, params PatchTripsTripIDParams) *Response
+	// Update a trip.
+	// (PUT /trips/{tripId})
+	PutTripsTripID(w http.ResponseWriter, r *http.Request, tripID uuid.UUID, params PutTripsTripIDParams) *Response
+	// Get a trip activities.
+	// (GET /trips/{tripId}/activities)
+	GetTripsTripIDActivities(w http.ResponseWriter, r *http.Request, tripID uuid.UUID, params GetTripsTripIDActivitiesParams) *Response
+	// Create a trip activity.
+	// (POST /trips/{tripId}/activities)
+	PostTripsTripIDActivities(w http.ResponseWriter, r *http.Request, tripID uuid.UUID) *Response
+	// Export a trip activities as an iCalendar file.
+	// (GET /trips/{tripId}/activities.ics)
+	GetTripsTripIDActivitiesIcs(w http.ResponseWriter, r *http.Request, tripID uuid.UUID) *Response
+	// Create many trip activities at once.
+	// (POST /trips/{tripId}/activities/batch)
+	PostTripsTripIDActivitiesBatch(w http.ResponseWriter, r *http.Request, tripID uuid.UUID) *Response
+	// Get a trip activity.
+	// (GET /trips/{tripId}/activities/{activityId})
+	GetTripsTripIDActivitiesActivityID(w http.ResponseWriter, r *http.Request, tripID uuid.UUID, activityID uuid.UUID) *Response
+	// Archive a trip.
+	// (POST /trips/{tripId}/archive)
+	PostTripsTripIDArchive(w http.ResponseWriter, r *http.Request, tripID uuid.UUID) *Response
+	// Cancel a trip.
+	// (POST /trips/{tripId}/cancel)
+	PostTripsTripIDCancel(w http.ResponseWriter, r *http.Request, tripID uuid.UUID) *Response
+	// Confirm a trip and send e-mail invitations.
+	// (GET /trips/{tripId}/confirm)
+	GetTripsTripIDConfirm(w http.ResponseWriter, r *http.Request, tripID uuid.UUID) *Response
+	// Duplicate a trip.
+	// (POST /trips/{tripId}/duplicate)
+	PostTripsTripIDDuplicate(w http.ResponseWriter, r *http.Request, tripID uuid.UUID) *Response
+	// Get the e-mails sent about a trip.
+	// (GET /trips/{tripId}/emails)
+	GetTripsTripIDEmails(w http.ResponseWriter, r *http.Request, tripID uuid.UUID, params GetTripsTripIDEmailsParams) *Response
+	// Stream a trip changes as server-sent events.
+	// (GET /trips/{tripId}/events)
+	GetTripsTripIDEvents(w http.ResponseWriter, r *http.Request, tripID uuid.UUID) *Response
+	// Export a trip with all its data.
+	// (GET /trips/{tripId}/export)
+	GetTripsTripIDExport(w http.ResponseWriter, r *http.Request, tripID uuid.UUID) *Response
+	// Invite someone to the trip.
+	// (POST /trips/{tripId}/invites)
+	PostTripsTripIDInvites(w http.ResponseWriter, r *http.Request, tripID uuid.UUID) *Response
+	// Get a trip links.
+	// (GET /trips/{tripId}/links)
+	GetTripsTripIDLinks(w http.ResponseWriter, r *http.Request, tripID uuid.UUID, params GetTripsTripIDLinksParams) *Response
+	// Create a trip link.
+	// (POST /trips/{tripId}/links)
+	PostTripsTripIDLinks(w http.ResponseWriter, r *http.Request, tripID uuid.UUID) *Response
+	// Update a trip link.
+	// (PUT /trips/{tripId}/links/{linkId})
+	PutTripsTripIDLinksLinkID(w http.ResponseWriter, r *http.Request, tripID uuid.UUID, linkID uuid.UUID) *Response
+	// Get a trip participants.
+	// (GET /trips/{tripId}/participants)
+	GetTripsTripIDParticipants(w http.ResponseWriter, r *http.Request, tripID uuid.UUID, params GetTripsTripIDParticipantsParams) *Response
+	// Remove a participant from a trip.
+	// (DELETE /trips/{tripId}/participants/{participantId})
+	DeleteTripsTripIDParticipantsParticipantID(w http.ResponseWriter, r *http.Request, tripID uuid.UUID, participantID uuid.UUID, params DeleteTripsTripIDParticipantsParticipantIDParams) *Response
+	// Get a trip summary.
+	// (GET /trips/{tripId}/summary)
+	GetTripsTripIDSummary(w http.ResponseWriter, r *http.Request, tripID uuid.UUID) *Response
+	// Unarchive a trip.
+	// (POST /trips/{tripId}/unarchive)
+	PostTripsTripIDUnarchive(w http.ResponseWriter, r *http.Request, tripID uuid.UUID) *Response
+	// Unconfirm a trip.
+	// (POST /trips/{tripId}/unconfirm)
+	PostTripsTripIDUnconfirm(w http.ResponseWriter, r *http.Request, tripID uuid.UUID) *Response
+	// Ask to confirm stopping the e-mails to an address, from the link in an e-mail.
+	// (GET /unsubscribe/{token})
+	GetUnsubscribeToken(w http.ResponseWriter, r *http.Request, token uuid.UUID) *Response
+	// Stop the e-mails to an address, from the confirmation page or a one-click unsubscribe.
+	// (POST /unsubscribe/{token})
+	PostUnsubscribeToken(w http.ResponseWriter, r *http.Request, token uuid.UUID) *Response
+}
+
+// ServerInterfaceWrapper converts contexts to parameters.
+type ServerInterfaceWrapper struct {
+	Handler          ServerInterface
+	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+}
+
+// GetParticipantsParticipantID operation middleware
+func (siw *ServerInterfaceWrapper) GetParticipantsParticipantID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// ------------- Path parameter "participantId" -------------
+	var participantID uuid.UUID
+
+	if err := runtime.BindStyledParameter("simple", false, "participantId", chi.URLParam(r, "participantId"), &participantID); err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "participantId"})
+		return
+	}
+
+	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := siw.Handler.GetParticipantsParticipantID(w, r, participantID)
+		if resp != nil {
+			if resp.body != nil {
+				render.Render(w, r, resp)
+			} else {
+				w.WriteHeader(resp.Code)
+			}
+		}
+	})
+
+	handler(w, r.WithContext(ctx))
+}
+
+// PatchParticipantsParticipantID operation middleware
+func (siw *ServerInterfaceWrapper) PatchParticipantsParticipantID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// ------------- Path parameter "participantId" -------------
+	var participantID uuid.UUID
+
+	if err := runtime.BindStyledParameter("simple", false, "participantId", chi.URLParam(r, "participantId"), &participantID); err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "participantId"})
+		return
+	}
+
+	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := siw.Handler.PatchParticipantsParticipantID(w, r, participantID)
+		if resp != nil {
+			if resp.body != nil {
+				render.Render(w, r, resp)
+			} else {
+				w.WriteHeader(resp.Code)
+			}
+		}
+	})
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetParticipantsParticipantIDConfirm operation middleware
+func (siw *ServerInterfaceWrapper) GetParticipantsParticipantIDConfirm(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// ------------- Path parameter "participantId" -------------
+	var participantID uuid.UUID
+
+	if err := runtime.BindStyledParameter("simple", false, "participantId", chi.URLParam(r, "participantId"), &participantID); err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "participantId"})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetParticipantsParticipantIDConfirmParams
+
+	// ------------- Optional query parameter "trip_id" -------------
+
+	if err := runtime.BindQueryParameter("form", true, false, "trip_id", r.URL.Query(), &params.TripID); err != nil {
+		err = fmt.Errorf("invalid format for parameter trip_id: %w", err)
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "trip_id"})
+		return
+	}
+
+	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := siw.Handler.GetParticipantsParticipantIDConfirm(w, r, participantID, params)
+		if resp != nil {
+			if resp.body != nil {
+				render.Render(w, r, resp)
+			} else {
+				w.WriteHeader(resp.Code)
+			}
+		}
+	})
+
+	handler(w, r.WithContext(ctx))
+}
+
+// PatchParticipantsParticipantIDConfirm operation middleware
+func (siw *ServerInterfaceWrapper) PatchParticipantsParticipantIDConfirm(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// ------------- Path parameter "participantId" -------------
+	var participantID uuid.UUID
+
+	if err := runtime.BindStyledParameter("simple", false, "participantId", chi.URLParam(r, "participantId"), &participantID); err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "participantId"})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PatchParticipantsParticipantIDConfirmParams
+
+	// ------------- Optional query parameter "trip_id" -------------
+
+	if err := runtime.BindQueryParameter("form", true, false, "trip_id", r.URL.Query(), &params.TripID); err != nil {
+		err = fmt.Errorf("invalid format for parameter trip_id: %w", err)
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "trip_id"})
+		return
+	}
+
+	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := siw.Handler.PatchParticipantsParticipantIDConfirm(w, r, participantID, params)
+		if resp != nil {
+			if resp.body != nil {
+				render.Render(w, r, resp)
+			} else {
+				w.WriteHeader(resp.Code)
+			}
+		}
+	})
+
+	handler(w, r.WithContext(ctx))
+}
+
+// PostParticipantsParticipantIDConfirm operation middleware
+func (siw *ServerInterfaceWrapper) PostParticipantsParticipantIDConfirm(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// ------------- Path parameter "participantId" -------------
+	var participantID uuid.UUID
+
+	if err := runtime.BindStyledParameter("simple", false, "participantId", chi.URLParam(r, "participantId"), &participantID); err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "participantId"})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostParticipantsParticipantIDConfirmParams
+
+	// ------------- Optional query parameter "trip_id" -------------
+
+	if err := runtime.BindQueryParameter("form", true, false, "trip_id", r.URL.Query(), &params.TripID); err != nil {
+		err = fmt.Errorf("invalid format for parameter trip_id: %w", err)
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "trip_id"})
+		return
+	}
+
+	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := siw.Handler.PostParticipantsParticipantIDConfirm(w, r, participantID, params)
+		if resp != nil {
+			if resp.body != nil {
+				render.Render(w, r, resp)
+			} else {
+				w.WriteHeader(resp.Code)
+			}
+		}
+	})
+
+	handler(w, r.WithContext(ctx))
+}
+
+// PatchParticipantsParticipantIDDecline operation middleware
+func (siw *ServerInterfaceWrapper) PatchParticipantsParticipantIDDecline(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// ------------- Path parameter "participantId" -------------
+	var participantID uuid.UUID
+
+	if err := runtime.BindStyledParameter("simple", false, "participantId", chi.URLParam(r, "participantId"), &participantID); err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "participantId"})
+		return
+	}
+
+	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := siw.Handler.PatchParticipantsParticipantIDDecline(w, r, participantID)
+		if resp != nil {
+			if resp.body != nil {
+				render.Render(w, r, resp)
+			} else {
+				w.WriteHeader(resp.Code)
+			}
+		}
+	})
+
+	handler(w, r.WithContext(ctx))
+}
+
+// PostParticipantsParticipantIDResendInvite operation middleware
+func (siw *ServerInterfaceWrapper) PostParticipantsParticipantIDResendInvite(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// ------------- Path parameter "participantId" -------------
+	var participantID uuid.UUID
+
+	if err := runtime.BindStyledParameter("simple", false, "participantId", chi.URLParam(r, "participantId"), &participantID); err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "participantId"})
+		return
+	}
+
+	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := siw.Handler.PostParticipantsParticipantIDResendInvite(w, r, participantID)
+		if resp != nil {
+			if resp.body != nil {
+				render.Render(w, r, resp)
+			} else {
+				w.WriteHeader(resp.Code)
+			}
+		}
+	})
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetTrips operation middleware
+func (siw *ServerInterfaceWrapper) GetTrips(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetTripsParams
+
+	// ------------- Optional query parameter "limit" -------------
+
+	if err := runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit); err != nil {
+		err = fmt.Errorf("invalid format for parameter limit: %w", err)
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "limit"})
+		return
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	if err := runtime.BindQueryParameter("form", true, false, "offset", r.URL.Query(), &params.Offset); err != nil {
+		err = fmt.Errorf("invalid format for parameter offset: %w", err)
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "offset"})
+		return
+	}
+
+	// ------------- Optional query parameter "is_confirmed" -------------
+
+	if err := runtime.BindQueryParameter("form", true, false, "is_confirmed", r.URL.Query(), &params.IsConfirmed); err != nil {
+		err = fmt.Errorf("invalid format for parameter is_confirmed: %w", err)
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "is_confirmed"})
+		return
+	}
+
+	// ------------- Optional query parameter "owner_email" -------------
+
+	if err := runtime.BindQueryParameter("form", true, false, "owner_email", r.URL.Query(), &params.OwnerEmail); err != nil {
+		err = fmt.Errorf("invalid format for parameter owner_email: %w", err)
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "owner_email"})
+		return
+	}
+
+	// ------------- Optional query parameter "sort" -------------
+
+	if err := runtime.BindQueryParameter("form", true, false, "sort", r.URL.Query(), &params.Sort); err != nil {
+		err = fmt.Errorf("invalid format for parameter sort: %w", err)
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "sort"})
+		return
+	}
+
+	// ------------- Optional query parameter "order" -------------
+
+	if err := runtime.BindQueryParameter("form", true, false, "order", r.URL.Query(), &params.Order); err != nil {
+		err = fmt.Errorf("invalid format for parameter order: %w", err)
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "order"})
+		return
+	}
+
+	// ------------- Optional query parameter "include_archived" -------------
+
+	if err := runtime.BindQueryParameter("form", true, false, "include_archived", r.URL.Query(), &params.IncludeArchived); err != nil {
+		err = fmt.Errorf("invalid format for parameter include_archived: %w", err)
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "include_archived"})
+		return
+	}
+
+	// ------------- Optional query parameter "cursor" -------------
+
+	if err := runtime.BindQueryParameter("form", true, false, "cursor", r.URL.Query(), &params.Cursor); err != nil {
+		err = fmt.Errorf("invalid format for parameter cursor: %w", err)
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "cursor"})
+		return
+	}
+
+	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := siw.Handler.GetTrips(w, r, params)
+		if resp != nil {
+			if resp.body != nil {
+				render.Render(w, r, resp)
+			} else {
+				w.WriteHeader(resp.Code)
+			}
+		}
+	})
+
+	handler(w, r.WithContext(ctx))
+}
+
+// PostTrips operation middleware
+func (siw *ServerInterfaceWrapper) PostTrips(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := siw.Handler.PostTrips(w, r)
+		if resp != nil {
+			if resp.body != nil {
+				render.Render(w, r, resp)
+			} else {
+				w.WriteHeader(resp.Code)
+			}
+		}
+	})
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetTripsParticipating operation middleware
+func (siw *ServerInterfaceWrapper) GetTripsParticipating(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetTripsParticipatingParams
+
+	// ------------- Required query parameter "email" -------------
+
+	if err := runtime.BindQueryParameter("form", true, true, "email", r.URL.Query(), &params.Email); err != nil {
+		err = fmt.Errorf("invalid format for parameter email: %w", err)
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{err, "email"})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	if err := runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit); err != nil {
+		err = fmt.Errorf("invalid format for parameter limit: %w", err)
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "limit"})
+		return
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	if err := runtime.BindQueryParameter("form", true, false, "offset", r.URL.Query(), &params.Offset); err != nil {
+		err = fmt.Errorf("invalid format for parameter offset: %w", err)
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "offset"})
+		return
+	}
+
+	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := siw.Handler.GetTripsParticipating(w, r, params)
+		if resp != nil {
+			if resp.body != nil {
+				render.Render(w, r, resp)
+			} else {
+				w.WriteHeader(resp.Code)
+			}
+		}
+	})
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetTripsSearch operation middleware
+func (siw *ServerInterfaceWrapper) GetTripsSearch(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetTripsSearchParams
+
+	// ------------- Required query parameter "q" -------------
+
+	if err := runtime.BindQueryParameter("form", true, true, "q", r.URL.Query(), &params.Q); err != nil {
+		err = fmt.Errorf("invalid format for parameter q: %w", err)
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{err, "q"})
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	if err := runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit); err != nil {
+		err = fmt.Errorf("invalid format for parameter limit: %w", err)
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "limit"})
+		return
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	if err := runtime.BindQueryParameter("form", true, false, "offset", r.URL.Query(), &params.Offset); err != nil {
+		err = fmt.Errorf("invalid format for parameter offset: %w", err)
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "offset"})
+		return
+	}
+
+	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := siw.Handler.GetTripsSearch(w, r, params)
+		if resp != nil {
+			if resp.body != nil {
+				render.Render(w, r, resp)
+			} else {
+				w.WriteHeader(resp.Code)
+			}
+		}
+	})
+
+	handler(w, r.WithContext(ctx))
+}
+
+// DeleteTripsTripID operation middleware
+func (siw *ServerInterfaceWrapper) DeleteTripsTripID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// ------------- Path parameter "tripId" -------------
+	var tripID uuid.UUID
+
+	if err := runtime.BindStyledParameter("simple", false, "tripId", chi.URLParam(r, "tripId"), &tripID); err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "tripId"})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteTripsTripIDParams
+
+	// ------------- Optional query parameter "force" -------------
+
+	if err := runtime.BindQueryParameter("form", true, false, "force", r.URL.Query(), &params.Force); err != nil {
+		err = fmt.Errorf("invalid format for parameter force: %w", err)
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "force"})
+		return
+	}
+
+	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := siw.Handler.DeleteTripsTripID(w, r, tripID, params)
+		if resp != nil {
+			if resp.body != nil {
+				render.Render(w, r, resp)
+			} else {
+				w.WriteHeader(resp.Code)
+			}
+		}
+	})
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetTripsTripID operation middleware
+func (siw *ServerInterfaceWrapper) GetTripsTripID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// ------------- Path parameter "tripId" -------------
+	var tripID uuid.UUID
+
+	if err := runtime.BindStyledParameter("simple", false, "tripId", chi.URLParam(r, "tripId"), &tripID); err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "tripId"})
+		return
+	}
+
+	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := siw.Handler.GetTripsTripID(w, r, tripID)
+		if resp != nil {
+			if resp.body != nil {
+				render.Render(w, r, resp)
+			} else {
+				w.WriteHeader(resp.Code)
+			}
+		}
+	})
+
+	handler(w, r.WithContext(ctx))
+}
+
+// PatchTripsTripID operation middleware
+func (siw *ServerInterfaceWrapper) PatchTripsTripID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// ------------- Path parameter "tripId" -------------
+	var tripID uuid.UUID
+
+	if err := runtime.BindStyledParameter("simple", false, "tripId", chi.URLParam(r, "tripId"), &tripID); err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "tripId"})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PatchTripsTripIDParams
+
+	// ------------- Optional query parameter "force" -------------
+
+	if err := runtime.BindQueryParameter("form", true, false, "force", r.URL.Query(), &params.Force); err != nil {
+		err = fmt.Errorf("invalid format for parameter force: %w", err)
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "force"})
+		return
+	}
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{n, "If-Match"})
+			return
+		}
+
+		if err := runtime.BindStyledParameterWithLocation("simple", false, "If-Match", runtime.ParamLocationHeader, valueList[0], &IfMatch); err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "If-Match"})
+			return
+		}
+
+		params.IfMatch = &IfMatch
+
+	}
+
+	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := siw.Handler.PatchTripsTripID(w, r, tripID, params)
+		if resp != nil {
+			if resp.body != nil {
+				render.Render(w, r, resp)
+			} else {
+				w.WriteHeader(resp.Code)
+			}
+		}
+	})
+
+	handler(w, r.WithContext(ctx))
+}
+
+// PutTripsTripID operation middleware
+func (siw *ServerInterfaceWrapper) PutTripsTripID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// ------------- Path parameter "tripId" -------------
+	var tripID uuid.UUID
+
+	if err := runtime.BindStyledParameter("simple", false, "tripId", chi.URLParam(r, "tripId"), &tripID); err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "tripId"})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PutTripsTripIDParams
+
+	// ------------- Optional query parameter "force" -------------
+
+	if err := runtime.BindQueryParameter("form", true, false, "force", r.URL.Query(), &params.Force); err != nil {
+		err = fmt.Errorf("invalid format for parameter force: %w", err)
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "force"})
+		return
+	}
+
+	headers := r.Header
+
+	// ------------- Optional header parameter "If-Match" -------------
+	if valueList, found := headers[http.CanonicalHeaderKey("If-Match")]; found {
+		var IfMatch string
+		n := len(valueList)
+		if n != 1 {
+			siw.ErrorHandlerFunc(w, r, &TooManyValuesForParamError{n, "If-Match"})
+			return
+		}
+
+		if err := runtime.BindStyledParameterWithLocation("simple", false, "If-Match", runtime.ParamLocationHeader, valueList[0], &IfMatch); err != nil {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "If-Match"})
+			return
+		}
+
+		params.IfMatch = &IfMatch
+
+	}
+
+	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := siw.Handler.PutTripsTripID(w, r, tripID, params)
+		if resp != nil {
+			if resp.body != nil {
+				render.Render(w, r, resp)
+			} else {
+				w.WriteHeader(resp.Code)
+			}
+		}
+	})
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetTripsTripIDActivities operation middleware
+func (siw *ServerInterfaceWrapper) GetTripsTripIDActivities(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// ------------- Path parameter "tripId" -------------
+	var tripID uuid.UUID
+
+	if err := runtime.BindStyledParameter("simple", false, "tripId", chi.URLParam(r, "tripId"), &tripID); err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "tripId"})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetTripsTripIDActivitiesParams
+
+	// ------------- Optional query parameter "date" -------------
+
+	if err := runtime.BindQueryParameter("form", true, false, "date", r.URL.Query(), &params.Date); err != nil {
+		err = fmt.Errorf("invalid format for parameter date: %w", err)
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "date"})
+		return
+	}
+
+	// ------------- Optional query parameter "tag" -------------
+
+	if err := runtime.BindQueryParameter("form", true, false, "tag", r.URL.Query(), &params.Tag); err != nil {
+		err = fmt.Errorf("invalid format for parameter tag: %w", err)
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "tag"})
+		return
+	}
+
+	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := siw.Handler.GetTripsTripIDActivities(w, r, tripID, params)
+		if resp != nil {
+			if resp.body != nil {
+				render.Render(w, r, resp)
+			} else {
+				w.WriteHeader(resp.Code)
+			}
+		}
+	})
+
+	handler(w, r.WithContext(ctx))
+}
+
+// PostTripsTripIDActivities operation middleware
+func (siw *ServerInterfaceWrapper) PostTripsTripIDActivities(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// ------------- Path parameter "tripId" -------------
+	var tripID uuid.UUID
+
+	if err := runtime.BindStyledParameter("simple", false, "tripId", chi.URLParam(r, "tripId"), &tripID); err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "tripId"})
+		return
+	}
+
+	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := siw.Handler.PostTripsTripIDActivities(w, r, tripID)
+		if resp != nil {
+			if resp.body != nil {
+				render.Render(w, r, resp)
+			} else {
+				w.WriteHeader(resp.Code)
+			}
+		}
+	})
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetTripsTripIDActivitiesIcs operation middleware
+func (siw *ServerInterfaceWrapper) GetTripsTripIDActivitiesIcs(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// ------------- Path parameter "tripId" -------------
+	var tripID uuid.UUID
+
+	if err := runtime.BindStyledParameter("simple", false, "tripId", chi.URLParam(r, "tripId"), &tripID); err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "tripId"})
+		return
+	}
+
+	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := siw.Handler.GetTripsTripIDActivitiesIcs(w, r, tripID)
+		if resp != nil {
+			if resp.body != nil {
+				render.Render(w, r, resp)
+			} else {
+				w.WriteHeader(resp.Code)
+			}
+		}
+	})
+
+	handler(w, r.WithContext(ctx))
+}
+
+// PostTripsTripIDActivitiesBatch operation middleware
+func (siw *ServerInterfaceWrapper) PostTripsTripIDActivitiesBatch(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// ------------- Path parameter "tripId" -------------
+	var tripID uuid.UUID
+
+	if err := runtime.BindStyledParameter("simple", false, "tripId", chi.URLParam(r, "tripId"), &tripID); err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "tripId"})
+		return
+	}
+
+	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := siw.Handler.PostTripsTripIDActivitiesBatch(w, r, tripID)
+		if resp != nil {
+			if resp.body != nil {
+				render.Render(w, r, resp)
+			} else {
+				w.WriteHeader(resp.Code)
+			}
+		}
+	})
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetTripsTripIDActivitiesActivityID operation middleware
+func (siw *ServerInterfaceWrapper) GetTripsTripIDActivitiesActivityID(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// ------------- Path parameter "tripId" -------------
+	var tripID uuid.UUID
+
+	if err := runtime.BindStyledParameter("simple", false, "tripId", chi.URLParam(r, "tripId"), &tripID); err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "tripId"})
+		return
+	}
+
+	// ------------- Path parameter "activityId" -------------
+	var activityID uuid.UUID
+
+	if err := runtime.BindStyledParameter("simple", false, "activityId", chi.URLParam(r, "activityId"), &activityID); err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "activityId"})
+		return
+	}
+
+	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := siw.Handler.GetTripsTripIDActivitiesActivityID(w, r, tripID, activityID)
+		if resp != nil {
+			if resp.body != nil {
+				render.Render(w, r, resp)
+			} else {
+				w.WriteHeader(resp.Code)
+			}
+		}
+	})
+
+	handler(w, r.WithContext(ctx))
+}
+
+// PostTripsTripIDArchive operation middleware
+func (siw *ServerInterfaceWrapper) PostTripsTripIDArchive(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// ------------- Path parameter "tripId" -------------
+	var tripID uuid.UUID
+
+	if err := runtime.BindStyledParameter("simple", false, "tripId", chi.URLParam(r, "tripId"), &tripID); err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "tripId"})
+		return
+	}
+
+	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := siw.Handler.PostTripsTripIDArchive(w, r, tripID)
+		if resp != nil {
+			if resp.body != nil {
+				render.Render(w, r, resp)
+			} else {
+				w.WriteHeader(resp.Code)
+			}
+		}
+	})
+
+	handler(w, r.WithContext(ctx))
+}
+
+// PostTripsTripIDCancel operation middleware
+func (siw *ServerInterfaceWrapper) PostTripsTripIDCancel(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// ------------- Path parameter "tripId" -------------
+	var tripID uuid.UUID
+
+	if err := runtime.BindStyledParameter("simple", false, "tripId", chi.URLParam(r, "tripId"), &tripID); err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "tripId"})
+		return
+	}
+
+	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := siw.Handler.PostTripsTripIDCancel(w, r, tripID)
+		if resp != nil {
+			if resp.body != nil {
+				render.Render(w, r, resp)
+			} else {
+				w.WriteHeader(resp.Code)
+			}
+		}
+	})
+
+	handler(w, r.WithContext(ctx))
+}
+
+// GetTripsTripIDConfirm operation middleware
+func (siw *ServerInterfaceWrapper) GetTripsTripIDConfirm(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// ------------- Path parameter "tripId" -------------
+	var tripID uuid.UUID
+
+	if err := runtime.BindStyledParameter("simple", false, "tripId", chi.URLParam(r, "tripId"), &tripID); err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "tripId"})
+		return
 	}
+
+	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := siw.Handler.GetTripsTripIDConfirm(w, r, tripID)
+		if resp != nil {
+			if resp.body != nil {
+				render.Render(w, r, resp)
+			} else {
+				w.WriteHeader(resp.Code)
+			}
+		}
+	})
+
+	handler(w, r.WithContext(ctx))
 }
 
-// PostTripsTripIDLinksJSON201Response is a constructor method for a PostTripsTripIDLinks response.
-// A *Response is returned with the configured status code and content type from the spec.
-func PostTripsTripIDLinksJSON201Response(body CreateLinkResponse) *Response {
-	return &Response{
-		body:        body,
-		Code:        201,
-		contentType: "application/json",
+// PostTripsTripIDDuplicate operation middleware
+func (siw *ServerInterfaceWrapper) PostTripsTripIDDuplicate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// ------------- Path parameter "tripId" -------------
+	var tripID uuid.UUID
+
+	if err := runtime.BindStyledParameter("simple", false, "tripId", chi.URLParam(r, "tripId"), &tripID); err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "tripId"})
+		return
 	}
+
+	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := siw.Handler.PostTripsTripIDDuplicate(w, r, tripID)
+		if resp != nil {
+			if resp.body != nil {
+				render.Render(w, r, resp)
+			} else {
+				w.WriteHeader(resp.Code)
+			}
+		}
+	})
+
+	handler(w, r.WithContext(ctx))
 }
 
-// PostTripsTripIDLinksJSON400Response is a constructor method for a PostTripsTripIDLinks response.
-// A *Response is returned with the configured status code and content type from the spec.
-func PostTripsTripIDLinksJSON400Response(body Error) *Response {
-	return &Response{
-		body:        body,
-		Code:        400,
-		contentType: "application/json",
+// GetTripsTripIDEmails operation middleware
+func (siw *ServerInterfaceWrapper) GetTripsTripIDEmails(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// ------------- Path parameter "tripId" -------------
+	var tripID uuid.UUID
+
+	if err := runtime.BindStyledParameter("simple", false, "tripId", chi.URLParam(r, "tripId"), &tripID); err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "tripId"})
+		return
 	}
-}
 
-// GetTripsTripIDParticipantsJSON200Response is a constructor method for a GetTripsTripIDParticipants response.
-// A *Response is returned with the configured status code and content type from the spec.
-func GetTripsTripIDParticipantsJSON200Response(body GetTripParticipantsResponse) *Response {
-	return &Response{
-		body:        body,
-		Code:        200,
-		contentType: "application/json",
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetTripsTripIDEmailsParams
+
+	// ------------- Optional query parameter "limit" -------------
+
+	if err := runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit); err != nil {
+		err = fmt.Errorf("invalid format for parameter limit: %w", err)
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "limit"})
+		return
 	}
-}
 
-// GetTripsTripIDParticipantsJSON400Response is a constructor method for a GetTripsTripIDParticipants response.
-// A *Response is returned with the configured status code and content type from the spec.
-func GetTripsTripIDParticipantsJSON400Response(body Error) *Response {
-	return &Response{
-		body:        body,
-		Code:        400,
-		contentType: "application/json",
+	// ------------- Optional query parameter "offset" -------------
+
+	if err := runtime.BindQueryParameter("form", true, false, "offset", r.URL.Query(), &params.Offset); err != nil {
+		err = fmt.Errorf("invalid format for parameter offset: %w", err)
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "offset"})
+		return
 	}
-}
 
-// ServerInterface represents all server handlers.
-type ServerInterface interface {
-	// Confirms a participant on a trip.
-	// (PATCH /participants/{participantId}/confirm)
-	PatchParticipantsParticipantIDConfirm(w http.ResponseWriter, r *http.Request, participantID string) *Response
-	// Lists all trips
-	// (GET /trips)
-	GetTrips(w http.ResponseWriter, r *http.Request) *Response
-	// Create a new trip
-	// (POST /trips)
-	PostTrips(w http.ResponseWriter, r *http.Request) *Response
-	// Get a trip details.
-	// (GET /trips/{tripId})
-	GetTripsTripID(w http.ResponseWriter, r *http.Request, tripID string) *Response
-	// Update a trip.
-	// (PUT /trips/{tripId})
-	PutTripsTripID(w http.ResponseWriter, r *http.Request, tripID string) *Response
-	// Get a trip activities.
-	// (GET /trips/{tripId}/activities)
-	GetTripsTripIDActivities(w http.ResponseWriter, r *http.Request, tripID string) *Response
-	// Create a trip activity.
-	// (POST /trips/{tripId}/activities)
-	PostTripsTripIDActivities(w http.ResponseWriter, r *http.Request, tripID string) *Response
-	// Confirm a trip and send e-mail invitations.
-	// (GET /trips/{tripId}/confirm)
-	GetTripsTripIDConfirm(w http.ResponseWriter, r *http.Request, tripID string) *Response
-	// Invite someone to the trip.
-	// (POST /trips/{tripId}/invites)
-	PostTripsTripIDInvites(w http.ResponseWriter, r *http.Request, tripID string) *Response
-	// Get a trip links.
-	// (GET /trips/{tripId}/links)
-	GetTripsTripIDLinks(w http.ResponseWriter, r *http.Request, tripID string) *Response
-	// Create a trip link.
-	// (POST /trips/{tripId}/links)
-	PostTripsTripIDLinks(w http.ResponseWriter, r *http.Request, tripID string) *Response
-	// Get a trip participants.
-	// (GET /trips/{tripId}/participants)
-	GetTripsTripIDParticipants(w http.ResponseWriter, r *http.Request, tripID string) *Response
-}
+	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := siw.Handler.GetTripsTripIDEmails(w, r, tripID, params)
+		if resp != nil {
+			if resp.body != nil {
+				render.Render(w, r, resp)
+			} else {
+				w.WriteHeader(resp.Code)
+			}
+		}
+	})
 
-// ServerInterfaceWrapper converts contexts to parameters.
-type ServerInterfaceWrapper struct {
-	Handler          ServerInterface
-	ErrorHandlerFunc func(w http.ResponseWriter, r *http.Request, err error)
+	handler(w, r.WithContext(ctx))
 }
 
-// PatchParticipantsParticipantIDConfirm operation middleware
-func (siw *ServerInterfaceWrapper) PatchParticipantsParticipantIDConfirm(w http.ResponseWriter, r *http.Request) {
+// GetTripsTripIDEvents operation middleware
+func (siw *ServerInterfaceWrapper) GetTripsTripIDEvents(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	// ------------- Path parameter "participantId" -------------
-	var participantID string
+	// ------------- Path parameter "tripId" -------------
+	var tripID uuid.UUID
 
-	if err := runtime.BindStyledParameter("simple", false, "participantId", chi.URLParam(r, "participantId"), &participantID); err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "participantId"})
+	if err := runtime.BindStyledParameter("simple", false, "tripId", chi.URLParam(r, "tripId"), &tripID); err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "tripId"})
 		return
 	}
 
 	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		resp := siw.Handler.PatchParticipantsParticipantIDConfirm(w, r, participantID)
+		resp := siw.Handler.GetTripsTripIDEvents(w, r, tripID)
 		if resp != nil {
 			if resp.body != nil {
 				render.Render(w, r, resp)
@@ -547,12 +2664,20 @@ func (siw *ServerInterfaceWrapper) PatchParticipantsParticipantIDConfirm(w http.
 	handler(w, r.WithContext(ctx))
 }
 
-// GetTrips operation middleware
-func (siw *ServerInterfaceWrapper) GetTrips(w http.ResponseWriter, r *http.Request) {
+// GetTripsTripIDExport operation middleware
+func (siw *ServerInterfaceWrapper) GetTripsTripIDExport(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	// ------------- Path parameter "tripId" -------------
+	var tripID uuid.UUID
+
+	if err := runtime.BindStyledParameter("simple", false, "tripId", chi.URLParam(r, "tripId"), &tripID); err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "tripId"})
+		return
+	}
+
 	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		resp := siw.Handler.GetTrips(w, r)
+		resp := siw.Handler.GetTripsTripIDExport(w, r, tripID)
 		if resp != nil {
 			if resp.body != nil {
 				render.Render(w, r, resp)
@@ -565,12 +2690,20 @@ func (siw *ServerInterfaceWrapper) GetTrips(w http.ResponseWriter, r *http.Reque
 	handler(w, r.WithContext(ctx))
 }
 
-// PostTrips operation middleware
-func (siw *ServerInterfaceWrapper) PostTrips(w http.ResponseWriter, r *http.Request) {
+// PostTripsTripIDInvites operation middleware
+func (siw *ServerInterfaceWrapper) PostTripsTripIDInvites(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	// ------------- Path parameter "tripId" -------------
+	var tripID uuid.UUID
+
+	if err := runtime.BindStyledParameter("simple", false, "tripId", chi.URLParam(r, "tripId"), &tripID); err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "tripId"})
+		return
+	}
+
 	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		resp := siw.Handler.PostTrips(w, r)
+		resp := siw.Handler.PostTripsTripIDInvites(w, r, tripID)
 		if resp != nil {
 			if resp.body != nil {
 				render.Render(w, r, resp)
@@ -583,20 +2716,39 @@ func (siw *ServerInterfaceWrapper) PostTrips(w http.ResponseWriter, r *http.Requ
 	handler(w, r.WithContext(ctx))
 }
 
-// GetTripsTripID operation middleware
-func (siw *ServerInterfaceWrapper) GetTripsTripID(w http.ResponseWriter, r *http.Request) {
+// GetTripsTripIDLinks operation middleware
+func (siw *ServerInterfaceWrapper) GetTripsTripIDLinks(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	// ------------- Path parameter "tripId" -------------
-	var tripID string
+	var tripID uuid.UUID
 
 	if err := runtime.BindStyledParameter("simple", false, "tripId", chi.URLParam(r, "tripId"), &tripID); err != nil {
 		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "tripId"})
 		return
 	}
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetTripsTripIDLinksParams
+
+	// ------------- Optional query parameter "limit" -------------
+
+	if err := runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit); err != nil {
+		err = fmt.Errorf("invalid format for parameter limit: %w", err)
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "limit"})
+		return
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	if err := runtime.BindQueryParameter("form", true, false, "offset", r.URL.Query(), &params.Offset); err != nil {
+		err = fmt.Errorf("invalid format for parameter offset: %w", err)
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "offset"})
+		return
+	}
+
 	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		resp := siw.Handler.GetTripsTripID(w, r, tripID)
+		resp := siw.Handler.GetTripsTripIDLinks(w, r, tripID, params)
 		if resp != nil {
 			if resp.body != nil {
 				render.Render(w, r, resp)
@@ -609,12 +2761,12 @@ func (siw *ServerInterfaceWrapper) GetTripsTripID(w http.ResponseWriter, r *http
 	handler(w, r.WithContext(ctx))
 }
 
-// PutTripsTripID operation middleware
-func (siw *ServerInterfaceWrapper) PutTripsTripID(w http.ResponseWriter, r *http.Request) {
+// PostTripsTripIDLinks operation middleware
+func (siw *ServerInterfaceWrapper) PostTripsTripIDLinks(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	// ------------- Path parameter "tripId" -------------
-	var tripID string
+	var tripID uuid.UUID
 
 	if err := runtime.BindStyledParameter("simple", false, "tripId", chi.URLParam(r, "tripId"), &tripID); err != nil {
 		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "tripId"})
@@ -622,7 +2774,7 @@ func (siw *ServerInterfaceWrapper) PutTripsTripID(w http.ResponseWriter, r *http
 	}
 
 	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		resp := siw.Handler.PutTripsTripID(w, r, tripID)
+		resp := siw.Handler.PostTripsTripIDLinks(w, r, tripID)
 		if resp != nil {
 			if resp.body != nil {
 				render.Render(w, r, resp)
@@ -635,20 +2787,28 @@ func (siw *ServerInterfaceWrapper) PutTripsTripID(w http.ResponseWriter, r *http
 	handler(w, r.WithContext(ctx))
 }
 
-// GetTripsTripIDActivities operation middleware
-func (siw *ServerInterfaceWrapper) GetTripsTripIDActivities(w http.ResponseWriter, r *http.Request) {
+// PutTripsTripIDLinksLinkID operation middleware
+func (siw *ServerInterfaceWrapper) PutTripsTripIDLinksLinkID(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	// ------------- Path parameter "tripId" -------------
-	var tripID string
+	var tripID uuid.UUID
 
 	if err := runtime.BindStyledParameter("simple", false, "tripId", chi.URLParam(r, "tripId"), &tripID); err != nil {
 		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "tripId"})
 		return
 	}
 
+	// ------------- Path parameter "linkId" -------------
+	var linkID uuid.UUID
+
+	if err := runtime.BindStyledParameter("simple", false, "linkId", chi.URLParam(r, "linkId"), &linkID); err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "linkId"})
+		return
+	}
+
 	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		resp := siw.Handler.GetTripsTripIDActivities(w, r, tripID)
+		resp := siw.Handler.PutTripsTripIDLinksLinkID(w, r, tripID, linkID)
 		if resp != nil {
 			if resp.body != nil {
 				render.Render(w, r, resp)
@@ -661,20 +2821,47 @@ func (siw *ServerInterfaceWrapper) GetTripsTripIDActivities(w http.ResponseWrite
 	handler(w, r.WithContext(ctx))
 }
 
-// PostTripsTripIDActivities operation middleware
-func (siw *ServerInterfaceWrapper) PostTripsTripIDActivities(w http.ResponseWriter, r *http.Request) {
+// GetTripsTripIDParticipants operation middleware
+func (siw *ServerInterfaceWrapper) GetTripsTripIDParticipants(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	// ------------- Path parameter "tripId" -------------
-	var tripID string
+	var tripID uuid.UUID
 
 	if err := runtime.BindStyledParameter("simple", false, "tripId", chi.URLParam(r, "tripId"), &tripID); err != nil {
 		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "tripId"})
 		return
 	}
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetTripsTripIDParticipantsParams
+
+	// ------------- Optional query parameter "limit" -------------
+
+	if err := runtime.BindQueryParameter("form", true, false, "limit", r.URL.Query(), &params.Limit); err != nil {
+		err = fmt.Errorf("invalid format for parameter limit: %w", err)
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "limit"})
+		return
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	if err := runtime.BindQueryParameter("form", true, false, "offset", r.URL.Query(), &params.Offset); err != nil {
+		err = fmt.Errorf("invalid format for parameter offset: %w", err)
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "offset"})
+		return
+	}
+
+	// ------------- Optional query parameter "is_confirmed" -------------
+
+	if err := runtime.BindQueryParameter("form", true, false, "is_confirmed", r.URL.Query(), &params.IsConfirmed); err != nil {
+		err = fmt.Errorf("invalid format for parameter is_confirmed: %w", err)
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "is_confirmed"})
+		return
+	}
+
 	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		resp := siw.Handler.PostTripsTripIDActivities(w, r, tripID)
+		resp := siw.Handler.GetTripsTripIDParticipants(w, r, tripID, params)
 		if resp != nil {
 			if resp.body != nil {
 				render.Render(w, r, resp)
@@ -687,20 +2874,39 @@ func (siw *ServerInterfaceWrapper) PostTripsTripIDActivities(w http.ResponseWrit
 	handler(w, r.WithContext(ctx))
 }
 
-// GetTripsTripIDConfirm operation middleware
-func (siw *ServerInterfaceWrapper) GetTripsTripIDConfirm(w http.ResponseWriter, r *http.Request) {
+// DeleteTripsTripIDParticipantsParticipantID operation middleware
+func (siw *ServerInterfaceWrapper) DeleteTripsTripIDParticipantsParticipantID(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	// ------------- Path parameter "tripId" -------------
-	var tripID string
+	var tripID uuid.UUID
 
 	if err := runtime.BindStyledParameter("simple", false, "tripId", chi.URLParam(r, "tripId"), &tripID); err != nil {
 		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "tripId"})
 		return
 	}
 
+	// ------------- Path parameter "participantId" -------------
+	var participantID uuid.UUID
+
+	if err := runtime.BindStyledParameter("simple", false, "participantId", chi.URLParam(r, "participantId"), &participantID); err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "participantId"})
+		return
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params DeleteTripsTripIDParticipantsParticipantIDParams
+
+	// ------------- Optional query parameter "force" -------------
+
+	if err := runtime.BindQueryParameter("form", true, false, "force", r.URL.Query(), &params.Force); err != nil {
+		err = fmt.Errorf("invalid format for parameter force: %w", err)
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "force"})
+		return
+	}
+
 	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		resp := siw.Handler.GetTripsTripIDConfirm(w, r, tripID)
+		resp := siw.Handler.DeleteTripsTripIDParticipantsParticipantID(w, r, tripID, participantID, params)
 		if resp != nil {
 			if resp.body != nil {
 				render.Render(w, r, resp)
@@ -713,12 +2919,12 @@ func (siw *ServerInterfaceWrapper) GetTripsTripIDConfirm(w http.ResponseWriter,
 	handler(w, r.WithContext(ctx))
 }
 
-// PostTripsTripIDInvites operation middleware
-func (siw *ServerInterfaceWrapper) PostTripsTripIDInvites(w http.ResponseWriter, r *http.Request) {
+// GetTripsTripIDSummary operation middleware
+func (siw *ServerInterfaceWrapper) GetTripsTripIDSummary(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	// ------------- Path parameter "tripId" -------------
-	var tripID string
+	var tripID uuid.UUID
 
 	if err := runtime.BindStyledParameter("simple", false, "tripId", chi.URLParam(r, "tripId"), &tripID); err != nil {
 		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "tripId"})
@@ -726,7 +2932,7 @@ func (siw *ServerInterfaceWrapper) PostTripsTripIDInvites(w http.ResponseWriter,
 	}
 
 	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		resp := siw.Handler.PostTripsTripIDInvites(w, r, tripID)
+		resp := siw.Handler.GetTripsTripIDSummary(w, r, tripID)
 		if resp != nil {
 			if resp.body != nil {
 				render.Render(w, r, resp)
@@ -739,12 +2945,12 @@ func (siw *ServerInterfaceWrapper) PostTripsTripIDInvites(w http.ResponseWriter,
 	handler(w, r.WithContext(ctx))
 }
 
-// GetTripsTripIDLinks operation middleware
-func (siw *ServerInterfaceWrapper) GetTripsTripIDLinks(w http.ResponseWriter, r *http.Request) {
+// PostTripsTripIDUnarchive operation middleware
+func (siw *ServerInterfaceWrapper) PostTripsTripIDUnarchive(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	// ------------- Path parameter "tripId" -------------
-	var tripID string
+	var tripID uuid.UUID
 
 	if err := runtime.BindStyledParameter("simple", false, "tripId", chi.URLParam(r, "tripId"), &tripID); err != nil {
 		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "tripId"})
@@ -752,7 +2958,7 @@ func (siw *ServerInterfaceWrapper) GetTripsTripIDLinks(w http.ResponseWriter, r
 	}
 
 	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		resp := siw.Handler.GetTripsTripIDLinks(w, r, tripID)
+		resp := siw.Handler.PostTripsTripIDUnarchive(w, r, tripID)
 		if resp != nil {
 			if resp.body != nil {
 				render.Render(w, r, resp)
@@ -765,12 +2971,12 @@ func (siw *ServerInterfaceWrapper) GetTripsTripIDLinks(w http.ResponseWriter, r
 	handler(w, r.WithContext(ctx))
 }
 
-// PostTripsTripIDLinks operation middleware
-func (siw *ServerInterfaceWrapper) PostTripsTripIDLinks(w http.ResponseWriter, r *http.Request) {
+// PostTripsTripIDUnconfirm operation middleware
+func (siw *ServerInterfaceWrapper) PostTripsTripIDUnconfirm(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
 	// ------------- Path parameter "tripId" -------------
-	var tripID string
+	var tripID uuid.UUID
 
 	if err := runtime.BindStyledParameter("simple", false, "tripId", chi.URLParam(r, "tripId"), &tripID); err != nil {
 		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "tripId"})
@@ -778,7 +2984,7 @@ func (siw *ServerInterfaceWrapper) PostTripsTripIDLinks(w http.ResponseWriter, r
 	}
 
 	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		resp := siw.Handler.PostTripsTripIDLinks(w, r, tripID)
+		resp := siw.Handler.PostTripsTripIDUnconfirm(w, r, tripID)
 		if resp != nil {
 			if resp.body != nil {
 				render.Render(w, r, resp)
@@ -791,20 +2997,46 @@ func (siw *ServerInterfaceWrapper) PostTripsTripIDLinks(w http.ResponseWriter, r
 	handler(w, r.WithContext(ctx))
 }
 
-// GetTripsTripIDParticipants operation middleware
-func (siw *ServerInterfaceWrapper) GetTripsTripIDParticipants(w http.ResponseWriter, r *http.Request) {
+// GetUnsubscribeToken operation middleware
+func (siw *ServerInterfaceWrapper) GetUnsubscribeToken(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
-	// ------------- Path parameter "tripId" -------------
-	var tripID string
+	// ------------- Path parameter "token" -------------
+	var token uuid.UUID
 
-	if err := runtime.BindStyledParameter("simple", false, "tripId", chi.URLParam(r, "tripId"), &tripID); err != nil {
-		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "tripId"})
+	if err := runtime.BindStyledParameter("simple", false, "token", chi.URLParam(r, "token"), &token); err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "token"})
+		return
+	}
+
+	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		resp := siw.Handler.GetUnsubscribeToken(w, r, token)
+		if resp != nil {
+			if resp.body != nil {
+				render.Render(w, r, resp)
+			} else {
+				w.WriteHeader(resp.Code)
+			}
+		}
+	})
+
+	handler(w, r.WithContext(ctx))
+}
+
+// PostUnsubscribeToken operation middleware
+func (siw *ServerInterfaceWrapper) PostUnsubscribeToken(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// ------------- Path parameter "token" -------------
+	var token uuid.UUID
+
+	if err := runtime.BindStyledParameter("simple", false, "token", chi.URLParam(r, "token"), &token); err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{err, "token"})
 		return
 	}
 
 	var handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		resp := siw.Handler.GetTripsTripIDParticipants(w, r, tripID)
+		resp := siw.Handler.PostUnsubscribeToken(w, r, token)
 		if resp != nil {
 			if resp.body != nil {
 				render.Render(w, r, resp)
@@ -932,18 +3164,44 @@ func Handler(si ServerInterface, opts ...ServerOption) http.Handler {
 	}
 
 	r.Route(options.BaseURL, func(r chi.Router) {
+		r.Get("/participants/{participantId}", wrapper.GetParticipantsParticipantID)
+		r.Patch("/participants/{participantId}", wrapper.PatchParticipantsParticipantID)
+		r.Get("/participants/{participantId}/confirm", wrapper.GetParticipantsParticipantIDConfirm)
 		r.Patch("/participants/{participantId}/confirm", wrapper.PatchParticipantsParticipantIDConfirm)
+		r.Post("/participants/{participantId}/confirm", wrapper.PostParticipantsParticipantIDConfirm)
+		r.Patch("/participants/{participantId}/decline", wrapper.PatchParticipantsParticipantIDDecline)
+		r.Post("/participants/{participantId}/resend-invite", wrapper.PostParticipantsParticipantIDResendInvite)
 		r.Get("/trips", wrapper.GetTrips)
 		r.Post("/trips", wrapper.PostTrips)
+		r.Get("/trips/participating", wrapper.GetTripsParticipating)
+		r.Get("/trips/search", wrapper.GetTripsSearch)
+		r.Delete("/trips/{tripId}", wrapper.DeleteTripsTripID)
 		r.Get("/trips/{tripId}", wrapper.GetTripsTripID)
+		r.Patch("/trips/{tripId}", wrapper.PatchTripsTripID)
 		r.Put("/trips/{tripId}", wrapper.PutTripsTripID)
 		r.Get("/trips/{tripId}/activities", wrapper.GetTripsTripIDActivities)
 		r.Post("/trips/{tripId}/activities", wrapper.PostTripsTripIDActivities)
+		r.Get("/trips/{tripId}/activities.ics", wrapper.GetTripsTripIDActivitiesIcs)
+		r.Post("/trips/{tripId}/activities/batch", wrapper.PostTripsTripIDActivitiesBatch)
+		r.Get("/trips/{tripId}/activities/{activityId}", wrapper.GetTripsTripIDActivitiesActivityID)
+		r.Post("/trips/{tripId}/archive", wrapper.PostTripsTripIDArchive)
+		r.Post("/trips/{tripId}/cancel", wrapper.PostTripsTripIDCancel)
 		r.Get("/trips/{tripId}/confirm", wrapper.GetTripsTripIDConfirm)
+		r.Post("/trips/{tripId}/duplicate", wrapper.PostTripsTripIDDuplicate)
+		r.Get("/trips/{tripId}/emails", wrapper.GetTripsTripIDEmails)
+		r.Get("/trips/{tripId}/events", wrapper.GetTripsTripIDEvents)
+		r.Get("/trips/{tripId}/export", wrapper.GetTripsTripIDExport)
 		r.Post("/trips/{tripId}/invites", wrapper.PostTripsTripIDInvites)
 		r.Get("/trips/{tripId}/links", wrapper.GetTripsTripIDLinks)
 		r.Post("/trips/{tripId}/links", wrapper.PostTripsTripIDLinks)
+		r.Put("/trips/{tripId}/links/{linkId}", wrapper.PutTripsTripIDLinksLinkID)
 		r.Get("/trips/{tripId}/participants", wrapper.GetTripsTripIDParticipants)
+		r.Delete("/trips/{tripId}/participants/{participantId}", wrapper.DeleteTripsTripIDParticipantsParticipantID)
+		r.Get("/trips/{tripId}/summary", wrapper.GetTripsTripIDSummary)
+		r.Post("/trips/{tripId}/unarchive", wrapper.PostTripsTripIDUnarchive)
+		r.Post("/trips/{tripId}/unconfirm", wrapper.PostTripsTripIDUnconfirm)
+		r.Get("/unsubscribe/{token}", wrapper.GetUnsubscribeToken)
+		r.Post("/unsubscribe/{token}", wrapper.PostUnsubscribeToken)
 	})
 	return r
 }
@@ -969,31 +3227,100 @@ func WithErrorHandler(handler func(w http.ResponseWriter, r *http.Request, err e
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+RazW7buBN/FYL//1GJ092cBOyhbYrCi2AbFF3soSgCRhrHbCRSJUdJjcBPs4c97XGf",
-	"oC+2ICnZ1IdjSo43dXppLUXkfPxmfjND6Z4mMi+kAIGaxvdUJ3PImf35WgFDeJkgv+W4eA9fStBo/sDS",
-	"lCOXgmUXShagkIOm8YxlGiJaeLfuqUySUulLZtfNpMrNL5oyhCPkOdCI4qIAGlONiotrGtGvR9fyCL6i",
-	"YkfIru0mtyzjZgmNqYIvJVeQ0uUyosgxA/PA6D2W0foq/uhpW2/+aaWgvPoMCdJl1PGLLqTQMNAxrFo+",
-	"TRueKUuedpzSVtNbu1m/cy5uxmG2u1sjWqqsaZfio7GOzGYdrJyWTtI2L4xCKOPiZgw61brNOn1QvBiH",
-	"TAoauWDmaXOZc3EO4hrnND4d7dyci19OrRGQM57pS5SXXNxytP7iCLlu+MA+1XXC6gZTii3Cxaf8FiK3",
-	"p9VBpPtiC3knQF06UdsNCjZgrbsTIFi+a/JoZAr344ZWrPoB5ctdA9ETFg1Lm37dFvSjEhEVL8YkYrWu",
-	"T6c3Skm1VY0UdKJ44dKNvmIpUVXatlXMQWt23YN7W6f6wT6l3gIautI78JVu5Oz/FcxoTP83WZf4SVXf",
-	"J21hL23attO4j9t0kPJuv2EW8BCQN5b9wKrTNsnJ2FJM3gKaAK5qPge9W9Wv7Q0Eql/0uxJBhcHmiR1k",
-	"3VSIWsRekBzaHT4A/kOorsUMst5z8NOh7EHQQTmijuDDfNemfmapPCw0zgBNEdiBwAMd0BJkbr27+txL",
-	"7QP0rbfZW7c1uHNZRqE5wvVlIsWMqxxSL+6vpMyACTqiXejNlZBOoKHKA96/YAp5wgsmcGzIFN4WQ5Oo",
-	"T3wYTzakDjRwDFGENqOraBkRHXU/KsosY1eGO1GVEBQTVYNX6xQK/y40MRjsTYSxBWknq8+IqW1yPYTH",
-	"jWp7mzNahmzuu38v0u952NzfoPc9jU9dYMweXMxk5WJvwHijC0j4jCfs21/f/gFNUkZeXkxJwRQjklyx",
-	"5OYIRGpusyJzj/0pSZExIY5BkUQKjar89nfKSFoqJhCIJL+d/0F+laUSsDAr38vkBlADw+NVhxTTeg8a",
-	"0VtQ2unz4vjk+MS2aQUIVnAa05/trYgWDOfWTROfMif33tU0XU4qunCEjsncnggWoKzHzEhHL8xtn069",
-	"39Oz19V6I1CxHBCUpvHHe8qNfkaJmqVi2hBNfZwc3zneCJkiP5nFjk6sjT+dnJr/EikQhMuiwvrfWDH5",
-	"rF1+rPcHUeYmOgzjmgBoMq8NgCbwZzBjZYZkxZvLiJ6enAwS+hBVumm3R7A/0trMKfOcqQWNaeV5TRjx",
-	"HEukIIwY7rTBY1OlXTXNPpMVlV8DdkGv6wTtOPrxbO7UosPw+znXqAnLMoKVh2ovVxVrGdFC6h6nXkjt",
-	"edXu/Uqmi0czpnt22aJDG9wdRF/sRYGDwtQpThgRcGdh7UF1lTSTe3dstdyaPeaf6VkQN1YnYY9Lio+e",
-	"q+0h8zDQfQtYcSJJnQHH/Vlb9iVt+WRYPj5DdBvOIIb48Yqrc1RPJd3MBpPmmVJFDE2BH+ZcEyVLBHLH",
-	"s4wowFIJV0zmQIxMTa4A7wCEvWODdtW1EiZSUvWt7uGIwK19VGqzJc5liWStiNH8IWpaH2Y9I5LqOQI+",
-	"OJ5qQlgHn38SuL3LeFKI99XdtL91eJIOp/NhwYF1OX6ILTYGWA/FedNiQOMzZDbcC7X8sEPhCmOREg2m",
-	"aBzljGfEvh62qujAouZeKLsD3xC6mVbPHzbXbDxh3APdPIewc/4iWuYgBRCUq+Yl5BRiHW2rF+QB7GLf",
-	"ZT+TtqX5UcHBdSsWNh/p6iOE0B7lv4dyX+2J/0nfk7Qmja/pDrEtMaHTF0o9bNF+AxlAGv459jMaeXpf",
-	"5x4cjfh4PlQ3lst/AwAA//8vxohsEy0AAA==",
+	"H4sIAAAAAAAC/+xd3W7cOJZ+FUK7QN/IZTudDKYN9IWTGNMepDtex56exaBhsKRTVWxLpJqk7BQCP81e",
+	"zNVe7hP0iy34J1ESVSWVf+Jy6iZxVUnkIXn+z0fyS5SwvGAUqBTR0ZdIJAvIsf7zOJHkhkgC4mMpP87O",
+	"MZ3DOYiCUQHqd5ymRBJGcXbGWQFcPRkdzXAmII4K76svEa6aUp+IhFz/8Z8cZtFR9B/7NQ37loD9Vb0f",
+	"c46X0V0cyWUB0VGE3ecchMBzTZ39SUhO6Dy6u4sjDn+UhEMaHf2rejD2SfutapBNf4dEqhbXkzFuJkiq",
+	"/p0xnmMZHUVlSdIobhMbRyxJSi6usGw8nWIJe5LkEMVrxqdbrRsJjewdByyhHt9bLJPFOfxRgpCDV6nR",
+	"yNK9HViZnt7uw03L07TJTmtntUlUa8r8VtfO19KbqBGUj13WOPq8N2d78FlyvCfxXDdygzOiXomOavrV",
+	"YNzv1Xzk+PMHoHO5iI5eHcRRTqj7eBiYnBx/PjVvvmnN1DoqWK66LOQyzvHnH9/EKbmBOCf0x0P9xasD",
+	"Qx6RGXTIOth0yLZp1XZrIetJdp0OWc57cuIA/uvltxX0fSD0ejNWe7zpjqOSZ83xcrIx68aqsc4aGupN",
+	"T+tmZ6OVywi93mTV7Hv9NF1wUmy2YimIhJNCPd1dt9EL1xRK1YIeSwpCEooDnRy2dMTrzVmF0B9f624P",
+	"rexDjkkmriS7IvSGSOgMNzqWKGdCojcHyDwco5JmIASSC0AC+A1wJEAKhCmTC+Do7x8vz385+e+rn4//",
+	"eXX6yz9OL04+XZ2dnF+dn/zX5cmni0kUByyDbnutaRildoGmj6XRSY7ncBWWtgZ7vP7r5uxR8syyyOu/",
+	"6l4zluAssEQfMJ2XeA6IzfSimHVCAqhEeMpKqb+VnBQT9OsCKMqJEITOY0TkdwIVJLmGFM04y/WDx0kC",
+	"hdyrWl0AToHHaIazjNA5muLkGkmGCrn39lytJtAyVyKov9Cfo9/aw1au0y0FfmUWev3Sj1oP0zTFeVux",
+	"Ht5TsR5a6RQSc/k43NTSY74e8Put+TkgtY0ZaM70OoW4kZJOyyIjCZZwZUlJOSsKSLu8+RO7RTmmSwRU",
+	"cgJC8WibfHQLHJBtAmGBqub1406vMAqTeqIJlTAHroYjyqLgIASklpwuGScrepcLLFFJRTlVb0x9UYA9",
+	"q/LkApbfcUDmlRRNS4luGf1Ooql7CtKGZlvj48aRksdNDJ19L7Su7928bW7r2GwmQF6leBmYxfd4adR+",
+	"6tZGfUhYsUSYA8rZjZqaZYywNRqHB2gJmAsERC/hLV5OjIokudIZ3//lL8a2mY975nN7he8CQz3hnPG1",
+	"Y2uS/xaniNtZaY97dIAamv+/gXwgv3VdePc3kGqN67DN9XdKKfDjlXFUH+nKaRMb0p2wkkpv8jzpVI7Z",
+	"8OxCm46ejELA+RNRbMkYMr5NUgSJVpvpiOA/HppWqIKCzi9lkY7udEgYEExM+N597I+3QUfP/J5hLklC",
+	"Ckzle5BKb27ITEXd0AB26e/W++Xj9PfOiP1uRg+p1fa4AQ71gYbzDxFXCaMzwnNjhO0DU8YywNQ+kUKS",
+	"Edr3gPOeaJlleKq4UfISQrzKSXGvdVGaK7QgemjWhXET0hhYcxSWktFL57ofHQT68VlnVsZFG8NXtuna",
+	"dn4e65gG57zpczZcyZADum7GJaFzNcli4xRATnqMiXFPwr9JJnHW85OiZowN6h/LMINkOoztUCq6HZGj",
+	"Z3CjjPb9dYKnIq+GWrJh2kGNbqBa0C12aFmpGnrmN+wyPX7JpLfrj6WsvLVhWfC+csgQh/DZeDujiyiB",
+	"HPr6+OoBXapVvlIjq62oHO04reWNr8egfjjRnWKTzNhoCvWr8UCuvp87+UgaaTi9mzkcmCcLctOnmTeR",
+	"zlYeO/R7IwW9IuX8iA7PqtzqBn7vAyRNQx2P9rv0K7I0/r9NmGruV88mmCaQZQ3L9dD6akhisWVSKxas",
+	"iO/XbdVMr5CLk/weYlyn9saotGaXvZrsoV3O1gJY2sf6g30DeHxDDi69tqkUXxOa+qxu2epKCdSVjjCi",
+	"uPmlH47HkUm0XnHICU31w/qhWlDsF5YD3UciCQWO+TIoSF13tqkTLqkAiWaM+8lfVepQHzXRCNMUMZqA",
+	"/sprDxGBOOgMqNIXa+eHQ0IKAo2k2SpVofSSalcnmiM/5x0YaUj86w7t4oRlehUnfi4Yl49vhJv9VDY4",
+	"jm6Ai6bt6pM392S81lSHO3uuDtcTWP2ttfOPkWYeW7Rcn6/xVMZobvFSWusJ38BBeQw/o7cuOdAF6Xc3",
+	"GjPZiCQcM6wQ+9BUPk2W6skYYKhv0prH8R5KP1WPnBXvfm2cBmUrLRM37bsGPyjD7erOWKAMC6m9/hip",
+	"zDe6XZAMLDACaErovGHPfWFYmyl/gAwcEdZbCv6qiLdl7KvKZWuPeamHrMeJpcaWIKyHrAbnT4dxLuw8",
+	"kBkiEqUkpd/JyZDBDqwf3DfnX01IZ7lD07GCbT+VeY758t5ZwKsVtc9qGL53u/INrblWPTC0oafIwzoC",
+	"1gw07s5Wc6ArVukRtDOFz/JK5exC4nKGhTAgFPOE8v7nUCcDBMIzCSpAIAIVeA4xEiDRrdMs6isVC8yU",
+	"DKlgwXuRAxKMa/jIElXWD2GR1Iqm6088UcVjFSM8fLHjVAvpO0ZnGUk2jSnWq9d+VMcGtY3enQqrKxP9",
+	"w/cM52ZgnUfA0oUSF8EhnGGZLL5dTK3XYxdUOzpi+ipw0k1Kxx02uKSW0zdnBQ4Kcdb2i9vCHOxcxwM7",
+	"KH5YQs3s3FvJBFC1r968ud8cvXnTHY7up38cO/j+vTXNFkDcnxPOusuLel7ojAXwxaKAhMxIgv/895//",
+	"BwKlGB2fnaICc4yYhszvAU3V11hjdP/895//w1CRYUonwFHCqJC8/PN/U4zSkmMqATH0y4df0d9ZySks",
+	"1ZvnLLkGKQDrgMwqr8i1EXkJ0uhwcjA50O5jARQXJDqKvtdfxVGB5UJP076vcve/eJ9O0zv1wNz4nUqW",
+	"9DwptHILWiW8v0/f69Y5zkECF9HRv75ERBGjenRR3lHU6CfyF8XEi8YnXe+P2aU3X36JSF4wrh6eE7ko",
+	"p5OE5ftzxuYZ7Ddfv7w8fa+W8jfVtfE79XQoxa2RrFTatDwu9FKpoe//Loxg19RtDH8zfNRCVsMMl5lE",
+	"9TNx9PoBCTJw6UDHPib6TmPpdVRsFhrhRokjNeOYOKBDO3n0m/aqZbLoco32Fl8Q3+gJe8vS5YOtUK+1",
+	"bmkuNdi7Duu+HkWHKyqpVI1Sc82UzXawp5mvJoeu4My7eLW+27durKf3mpR8LIC6fJnKXSCxYLdC9z8H",
+	"hMW1/pEh206sEonJQj1O0dnHTxdVHVHgHNDl+YcYCYawbSvBlAJHM5DJQjWkUm8MhNpgYhtEmC7tDpjh",
+	"CvmdHdQ2yVfcnvkLm0EJlVzhcwGJyqhIhqaQMb0GE3TceM7bQ6Tb0aXawmRisECUqZpvSc3+HdXjHyXw",
+	"ZT0zprKcRsPnYIh1kfBZ7i9knjXFpN3QNsjisbj2WL9lNRhFWM97XO+r0kxvgTc6X6sJtRX3YfalSZtl",
+	"dCU6mCKcccDpElVJmDbbYETZHlMUZXg+Nwk5Rcs/947Nq3vvqlfN9seu3K02aTvJez6S99TGMY4Mz+ju",
+	"AyzVZd9PILV54CX4qeR6IlWlqsPVkygO0K0aCWFC7r6+nrAzIPo0xGrJZ0I+D8FnYmdxd3K/k/sHk3vP",
+	"M7CDNP6Acq3v49PbsrHZU7hBXPrevr/daY1vLza067aJnVnLUxwE0HSvPi4lbJfOYVaqQjKZdWS6I8/x",
+	"6tMHEOOuGc9XVppB0yIRoTXC4w3KCS0liFhXn2+JXKDXr36oMataH+vXJGP2aAbTz0hLd64n4tQdQLGT",
+	"kGcuIXH0+tUPj9/nRYurDK85TLXFkHh28hwkX+4dzyTwkH1MGE0FKqkkWc2+Caaq2bTqA88xoU2z2EG9",
+	"3TV1hGHfcASqbPKYxFIFtujLmGskS4+QtFwfB6KoR5Ia9omO3hx4R2e8OvBPzgiemhHuoEJnBHrYsMkW",
+	"YKs/mdFHUhMX2tUDPUDAvvYE4z0DbBV6jCCHgajNAlEQIr92XNxsXQgRgkXikWA+KeYf0TyhSVamcOVv",
+	"01k19V0n34NCuXRMweGGsNKBm7BARBwhjJIFpipgYhSMO/+7DgMm6CPNlghnGbsFa29UM3agGvBkNk1w",
+	"fYiUM0pqgxWmyDBjXxhgKFs5rEeu4jQhaNvh/XwgQqqoIUMOmOVUl/nsB9Nde+9U1WPUOLoH8Q0qbhw+",
+	"CgFbtaaGcJW+gFtnRNurWlmi/cI/R6C3onGCk4W1pywHUcuu76yevtci2wjLzL4hpzDm5Aao2T6pPGwi",
+	"gyUKzVaN8w2GmUOn+Qc4iyONxLM2tE9VnO4e1bFNOs7D1joOVGGRO6RNspVSIkAZzl7xeIcF7BEqgAqi",
+	"tuki8zzSeQkuHft7boIK1MwuQcUBsf5oQrNbxlMRekPJltetPmgOTUFIlKvMBAgtm2hGuJATpEy2ZjKU",
+	"l0KiBb4BhCXKAAuJXikTzXGixKlfBD+ZQQ+SvT9Wyp0HaXq1E7oX6ksYdrFCNl36vBv7jNtk/clKufti",
+	"TjC8M2uTQejU2XO9lbaWcIR1plmbKCKFb6JEjOrtBVqg9NaCCaoz+TX+niln1XSamozrjPFEe7SKv7ty",
+	"814/q5dO/TMQq2JG+HVT+iHp0IMN+dM1zHaXP3SLHkgW1g70ykh/mzjlCZTeJvi3Ro7o5ALPuzriHwbt",
+	"6MyqKSRIZjJDWKDT2d7PyogaOdd7Rw20ZrIyorx7LtA7rfcCmDsviguDIXRErqZkRiBLhQrp/TzxlKXm",
+	"vFa7ndY4HRyE9KdSq0Q1cckC9PnQSrMKrE54xQKdXV5ogFJcz7L63SpTk5dIezATW6tMDUfWhLmhb5Ly",
+	"GqqJHz4I72zc2QEMvRT5wcOlyFde0xPKnOuE3K09XDkD7d1XbSBWNuRTU3v46gkS+k4hqNDKZQEFUfUk",
+	"YgrUHHDa0mA6wMRZtrRKZqUtLcq+vdqVciG2juBrJ0ar5lO7XVlIkmVogYU51FvZjRhpHMItEVBXyW65",
+	"3v5NqJigX6op1++sm3etOV3209bXDn5wdyJosf5RqycLAL0GKFTTTDRa1SSC1sMKPKHXvRlJ1qygegRz",
+	"xLgDkQYBLsGrGVIilOSJYGLmrJQ7bfxVtXF3c9NOHe/U8UOr48t1Srgbne83TzUKpsguFkQgzkqtS7MM",
+	"cZAlp6b0UN0SMAV5CxbapAn3dr6rEq4puJmHYwQ3QK22rMpEFSH9aS2jwOol3uoo3Z6NGaDC/jJQq0k8",
+	"bzTTunbsK5TSAofvbtNmKGP/G+zoBMk/yHR9fW072fW3x6wKdq4q/BqVwc7tHVtWHfQZdNnLniuV/YQk",
+	"/Qr/5EYVH1wHBgdAU+AmKYCpVt8yRkWm6oMUzGckCkyrzVO3C5bVB6sOU+eniXgp2TS97SfBGdAU8+3f",
+	"+mPOT+zqRssP5J0dKZqRDDbjyP2pS3OFoZZdpnQbwFM0hRnjoPbOSbOvTiCL5DFZL59gDjbhZR5Q6TKs",
+	"PKu5ZldMhXqYUVOGc6uMsqoS6d7z2jx9L1QzhBalNAiYMMgyyPRvbTSzswzBm3C/poHo3JO7VXZCwzM7",
+	"8io1RnhDEf1SX5p6txYI2Wb0Y/fulmUgmmTVM/Byqzlb6h91vfdxzpHBV/ZboJ9I6teqKwC/bkdbCGV7",
+	"bG6ujdo0uU0TDAvJCoGwvoBTvaLyDzV1sals6ycNxkVM0LFuxu1/c226HKnd77be6tghbqmT9e2VqO2C",
+	"jUvpmNPT+/n4Z8yvPT7GAlXnrcfjeVP/7RD6oJ00H1KP3pm2NevWHY1mXdPMjnO3hXPNeo1k3DWHc5xa",
+	"jmuhVy0LGg5Uv1XXAzQO9a+gQpYVG7ghjxV1a+5YDrd7RFRXh1SbUFZ5PWP2B+/YNXo2e1cr/4GmZunt",
+	"TqF675AYyMvV5cb9etj46cLCvUva4sgKqK2PkmlA8kJIuBgx3bAux4oFmdkTaL2rfiforCE1HPQe7YQV",
+	"BNK1Gri6dvibDlaDly/f2Sh1t5+hhbBzkzXODtR3AK1KT9pDzisoWLWtr3mvU6ykC4Q06OrYOuYuTcmo",
+	"QwSYY9Grw5z1D+6y8ikkuLTIApymHIRobOZdZw5O3MVA21ut+tYh3a1LrbYnIva3mHvXno0TSJXf7xfI",
+	"n5jCH1rACoXEYFsKoNYxI7KuGmiYpgoT1DcuTI/dCWmMtzfze5AcUwZXwTBaAOZyClgZrzwHc2aJHp2J",
+	"QL4/QMJsKl4rmmZoL6nsoOd5T0gOON/+0sMnPQ6EGzygglaDfNpzq07lUNcMPpt57+Hmcw1v8AJk7Ygp",
+	"tMOwLQmmomAPaQUVSiRlrutloiRSOcZ6n7w6LLYs1vOnIXYHMg/eTraN1bMGO6VY4oFsa/Mt/fGEDo9N",
+	"cNs4XGjBkLtZpUrayAXkJpSNrXWwHlHuDiFxUXF9HhE61mdTIsgEVMebuF14On5O7E0Pa2MJG8l/05FE",
+	"7+0Qg0peB48bol+0NggvcFozkc7+1RswNRu1j8XS/tLep/r+wmGnYoW8685ROXHoxDJHzbSU6Jap3M3U",
+	"vbDJWVpj47cHP1ds2ydw24GkPXfXBIg4C5z6ZCdzdx5P6zweM6tIsByUKZGsan7I4Tu1MazugQy6cB9M",
+	"kYKDQWOYRJgGbqjIRJIcJuhXhztFOrS1gYNq1wCelBO4PrLXPb2wwP5Fx/KN+0C3r7BtNj17omLvwBwK",
+	"Rt0ifn1UtJF/m9BXgRgZAp47Gz6sSV2/28GZzwUWLiWkTQQR6iz9lXhY9XBIMPqMx/4X9Z8FMhVlSHTK",
+	"juSof7Ybv2QG/VIv1hgt1rsdVl9bihublsZIcfsquQFgRL/8+VKrMYcHfjnmzTaeH/kEecTgzeBb5w36",
+	"IjAuflp3KVjfiT3+vOnMYvcs/uoAHq5P99nwAJ773SL1TI3vM7oKYHdu0MYCaE6tamXZdYoNb5DJqNod",
+	"Wo7qPyMrrrfHuGoU0ldvr60y2VvSd2Wmnlvjt84y2C8HlpZKuhb8/lYtneiiz1UZc4IubQOm+qR/0KAa",
+	"Ym4B8UHwQwG/lxVNOxDltlzW55ZsHLqkQj72c98n8M4B1TynIgoPMtlAP3IQIKWDeDWQwv4Jt0LxbdK4",
+	"WagDwhzFsMnWoX4fIQ4P3RQexEd+i/KRNGDGffLh1e72v0h2DfRuDRjSIh9BVW0sbpgIE0wjd6UlkU92",
+	"maU3gFUXWl7Wj12oUQ4THPvkMwdhvcgrH4VkReH0qqsCSqa9AlN3bt/+SGgNzN3g3rdPejcS+DxuWdL2",
+	"V/d0i93hYWyCas7S1N6SBNaq8hfCjd+eWlVMMogjO7eOGbwpo7CXZCS59vXWqiDu7u7/BwC/IY3Wt7cA",
+	"AA==",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
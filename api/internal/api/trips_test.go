@@ -0,0 +1,386 @@
+package api_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"journey/internal/api"
+	"journey/internal/api/spec"
+	"journey/internal/memstore"
+	"journey/internal/pgstore"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// TestTripDates checks the rules on the dates of a trip are the same whether
+// it's created, replaced with PUT or patched.
+func TestTripDates(t *testing.T) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	maxDuration := api.DefaultMaxTripDays * 24 * time.Hour
+
+	// The trips being updated run from start to start+5 days, with an activity
+	// on their fourth day
+	start := today.AddDate(0, 1, 0)
+	end := start.AddDate(0, 0, 5)
+	activityAt := start.AddDate(0, 0, 4).Add(10 * time.Hour)
+
+	trip := func(startsAt, endsAt time.Time) map[string]any {
+		return map[string]any{
+			"destination":      "Lisbon",
+			"owner_email":      "owner@example.com",
+			"owner_name":       "Owner",
+			"emails_to_invite": []string{"ana@example.com"},
+			"starts_at":        startsAt,
+			"ends_at":          endsAt,
+		}
+	}
+	update := func(startsAt, endsAt time.Time) map[string]any {
+		return map[string]any{"destination": "Lisbon", "starts_at": startsAt, "ends_at": endsAt}
+	}
+
+	tests := []struct {
+		name    string
+		method  string
+		target  string
+		body    map[string]any
+		ifMatch string
+		want    int
+	}{
+		{"create ending before it starts", http.MethodPost, "/trips", trip(end, start), "", http.StatusBadRequest},
+		{"create ending when it starts", http.MethodPost, "/trips", trip(start, start), "", http.StatusBadRequest},
+		{"create lasting the maximum", http.MethodPost, "/trips", trip(start, start.Add(maxDuration)), "", http.StatusCreated},
+		{"create lasting over the maximum", http.MethodPost, "/trips", trip(start, start.Add(maxDuration+time.Second)), "", http.StatusBadRequest},
+		{"create starting today", http.MethodPost, "/trips", trip(today, end), "", http.StatusCreated},
+		{"create starting yesterday", http.MethodPost, "/trips", trip(today.AddDate(0, 0, -1), end), "", http.StatusBadRequest},
+
+		{"put ending before it starts", http.MethodPut, "/trips/{tripId}", update(end, start), "", http.StatusBadRequest},
+		{"put lasting over the maximum", http.MethodPut, "/trips/{tripId}", update(start, start.Add(maxDuration+time.Second)), "", http.StatusBadRequest},
+		{"put under way", http.MethodPut, "/trips/{tripId}", update(today.AddDate(0, 0, -1), end), "", http.StatusNoContent},
+		{"put leaving an activity out", http.MethodPut, "/trips/{tripId}", update(start, start.AddDate(0, 0, 2)), "", http.StatusConflict},
+		{"put leaving an activity out with force", http.MethodPut, "/trips/{tripId}?force=true", update(start, start.AddDate(0, 0, 2)), "", http.StatusNoContent},
+		{"put a stale version", http.MethodPut, "/trips/{tripId}", update(start, end), `"999"`, http.StatusPreconditionFailed},
+
+		{"patch ending before it starts", http.MethodPatch, "/trips/{tripId}", map[string]any{"ends_at": start.Add(-time.Hour)}, "", http.StatusBadRequest},
+		{"patch lasting over the maximum", http.MethodPatch, "/trips/{tripId}", map[string]any{"ends_at": start.Add(maxDuration + time.Second)}, "", http.StatusBadRequest},
+		{"patch under way", http.MethodPatch, "/trips/{tripId}", map[string]any{"starts_at": today.AddDate(0, 0, -1)}, "", http.StatusNoContent},
+		{"patch leaving an activity out", http.MethodPatch, "/trips/{tripId}", map[string]any{"ends_at": start.AddDate(0, 0, 2)}, "", http.StatusConflict},
+		{"patch leaving an activity out with force", http.MethodPatch, "/trips/{tripId}?force=true", map[string]any{"ends_at": start.AddDate(0, 0, 2)}, "", http.StatusNoContent},
+		{"patch a stale version", http.MethodPatch, "/trips/{tripId}", map[string]any{"destination": "Porto"}, `"999"`, http.StatusPreconditionFailed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			store := memstore.New()
+			tripID := createTrip(t, store)
+			if _, err := store.UpdateTrip(ctx, pgstore.UpdateTripParams{
+				ID:          tripID,
+				Destination: "Lisbon",
+				StartsAt:    pgtype.Timestamp{Valid: true, Time: start},
+				EndsAt:      pgtype.Timestamp{Valid: true, Time: end},
+			}); err != nil {
+				t.Fatalf("UpdateTrip: %v", err)
+			}
+			if _, err := store.CreateActivity(ctx, pgstore.CreateActivityParams{
+				TripID:   tripID,
+				Title:    "Museum",
+				OccursAt: pgtype.Timestamp{Valid: true, Time: activityAt},
+				Tags:     []string{},
+			}); err != nil {
+				t.Fatalf("CreateActivity: %v", err)
+			}
+
+			body, err := json.Marshal(tt.body)
+			if err != nil {
+				t.Fatalf("json.Marshal: %v", err)
+			}
+			r := httptest.NewRequest(tt.method, strings.Replace(tt.target, "{tripId}", tripID.String(), 1), bytes.NewReader(body))
+			r.Header.Set("Content-Type", "application/json")
+			if tt.ifMatch != "" {
+				r.Header.Set("If-Match", tt.ifMatch)
+			}
+
+			if rec := serve(newServer(store), r); rec.Code != tt.want {
+				t.Errorf("%s %s = %d %s, want %d", tt.method, tt.target, rec.Code, rec.Body, tt.want)
+			}
+		})
+	}
+}
+
+func TestDuplicateTripOffset(t *testing.T) {
+	tests := []struct {
+		offsetDays int
+		want       int
+	}{
+		{365, http.StatusCreated},
+		{-3660, http.StatusCreated},
+		{3660, http.StatusCreated},
+		{-3661, http.StatusBadRequest},
+		{3661, http.StatusBadRequest},
+		{1 << 40, http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(strconv.Itoa(tt.offsetDays), func(t *testing.T) {
+			store := memstore.New()
+			tripID := createTrip(t, store)
+
+			r := httptest.NewRequest(http.MethodPost, "/trips/"+tripID.String()+"/duplicate", strings.NewReader(`{"offset_days": `+strconv.Itoa(tt.offsetDays)+`}`))
+			r.Header.Set("Content-Type", "application/json")
+			if rec := serve(newServer(store), r); rec.Code != tt.want {
+				t.Errorf("POST /trips/{tripId}/duplicate with offset_days %d = %d %s, want %d", tt.offsetDays, rec.Code, rec.Body, tt.want)
+			}
+		})
+	}
+}
+
+// noTrips is a store without any trip. Only the methods GET /trips calls are
+// implemented, the others panic.
+type noTrips struct {
+	api.Store
+}
+
+func (noTrips) ListTrips(ctx context.Context, arg pgstore.ListTripsParams) ([]pgstore.Trip, error) {
+	return nil, nil
+}
+
+func (noTrips) CountTrips(ctx context.Context, arg pgstore.CountTripsParams) (int64, error) {
+	return 0, nil
+}
+
+// TestListNoTrips checks GET /trips answers an empty array, not null, when
+// there are no trips.
+func TestListNoTrips(t *testing.T) {
+	rec := serve(newServer(noTrips{}), httptest.NewRequest(http.MethodGet, "/trips", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET /trips = %d %s, want 200", rec.Code, rec.Body)
+	}
+
+	var body struct {
+		Trips json.RawMessage `json:"trips"`
+		Total int             `json:"total"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decoding %s: %v", rec.Body, err)
+	}
+	if string(body.Trips) != "[]" || body.Total != 0 {
+		t.Errorf("GET /trips = trips %s, total %d, want [], 0", body.Trips, body.Total)
+	}
+}
+
+// listTrips GETs target, a page of GET /trips, failing the test unless it's
+// answered with a 200.
+func listTrips(t *testing.T, server http.Handler, target string) spec.GetTripsResponse {
+	t.Helper()
+
+	rec := serve(server, httptest.NewRequest(http.MethodGet, target, nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET %s = %d %s, want 200", target, rec.Code, rec.Body)
+	}
+	var page spec.GetTripsResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &page); err != nil {
+		t.Fatalf("decoding %s: %v", rec.Body, err)
+	}
+	return page
+}
+
+// TestListTripsSort checks GET /trips sorts by the allowed columns only.
+func TestListTripsSort(t *testing.T) {
+	store := memstore.New()
+	server := newServer(store)
+
+	start := time.Now().UTC().Truncate(time.Second).AddDate(0, 1, 0)
+	for _, trip := range []struct {
+		destination string
+		startDay    int
+		endDay      int
+	}{
+		{"Porto", 3, 4},
+		{"Lisbon", 1, 10},
+		{"Faro", 2, 3},
+		{"Braga", 4, 5},
+	} {
+		_, err := store.CreateTrip(context.Background(), spec.CreateTripRequest{
+			Destination: trip.destination,
+			OwnerEmail:  "owner@example.com",
+			OwnerName:   "Owner",
+			StartsAt:    start.AddDate(0, 0, trip.startDay),
+			EndsAt:      start.AddDate(0, 0, trip.endDay),
+		})
+		if err != nil {
+			t.Fatalf("CreateTrip(%s): %v", trip.destination, err)
+		}
+	}
+
+	sorted := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"Lisbon", "Faro", "Porto", "Braga"}},
+		{"?sort=starts_at", []string{"Lisbon", "Faro", "Porto", "Braga"}},
+		{"?sort=starts_at&order=desc", []string{"Braga", "Porto", "Faro", "Lisbon"}},
+		{"?sort=ends_at", []string{"Faro", "Porto", "Braga", "Lisbon"}},
+		{"?sort=ends_at&order=desc", []string{"Lisbon", "Braga", "Porto", "Faro"}},
+		{"?sort=destination", []string{"Braga", "Faro", "Lisbon", "Porto"}},
+		{"?sort=destination&order=desc", []string{"Porto", "Lisbon", "Faro", "Braga"}},
+		{"?sort=created_at&order=asc", []string{"Porto", "Lisbon", "Faro", "Braga"}},
+	}
+	for _, tt := range sorted {
+		page := listTrips(t, server, "/trips"+tt.query)
+		var got []string
+		for _, trip := range page.Trips {
+			got = append(got, trip.Destination)
+		}
+		if !slices.Equal(got, tt.want) {
+			t.Errorf("GET /trips%s = %v, want %v", tt.query, got, tt.want)
+		}
+	}
+
+	rejected := []struct {
+		query       string
+		wantMessage string
+	}{
+		{"?sort=owner_email", "Invalid sort: must be one of starts_at, ends_at, destination, created_at"},
+		{"?sort=STARTS_AT", "Invalid sort: must be one of starts_at, ends_at, destination, created_at"},
+		{"?sort=destination%3B%20DROP%20TABLE%20trips", "Invalid sort: must be one of starts_at, ends_at, destination, created_at"},
+		{"?order=up", "Invalid order: must be one of asc, desc"},
+		{"?sort=destination&cursor=abc", "Invalid cursor: only allowed with sort starts_at and order asc"},
+		{"?order=desc&cursor=abc", "Invalid cursor: only allowed with sort starts_at and order asc"},
+		{"?offset=1&cursor=abc", "Invalid cursor: can't be combined with offset"},
+	}
+	for _, tt := range rejected {
+		target := "/trips" + tt.query
+		rec := serve(server, httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), tt.wantMessage) {
+			t.Errorf("GET %s = %d %s, want 400 %q", target, rec.Code, rec.Body, tt.wantMessage)
+		}
+	}
+}
+
+// TestListTripsKeyset checks following next_cursor pages through every trip
+// once, in the same (starts_at, id) order as a single page, trips starting
+// together included.
+func TestListTripsKeyset(t *testing.T) {
+	store := memstore.New()
+	server := newServer(store)
+
+	start := time.Now().UTC().Truncate(time.Second).AddDate(0, 1, 0)
+	for i, startDay := range []int{3, 1, 2, 1, 3, 1, 2} {
+		_, err := store.CreateTrip(context.Background(), spec.CreateTripRequest{
+			Destination: "Trip " + strconv.Itoa(i),
+			OwnerEmail:  "owner@example.com",
+			OwnerName:   "Owner",
+			StartsAt:    start.AddDate(0, 0, startDay),
+			EndsAt:      start.AddDate(0, 0, startDay+1),
+		})
+		if err != nil {
+			t.Fatalf("CreateTrip: %v", err)
+		}
+	}
+
+	all := listTrips(t, server, "/trips?limit=200")
+	if len(all.Trips) != 7 || all.NextCursor != nil {
+		t.Fatalf("GET /trips = %d trips, next_cursor %v, want 7 and no cursor", len(all.Trips), all.NextCursor)
+	}
+	for i := 1; i < len(all.Trips); i++ {
+		prev, trip := all.Trips[i-1], all.Trips[i]
+		if trip.StartsAt.Before(prev.StartsAt) || (trip.StartsAt.Equal(prev.StartsAt) && trip.ID <= prev.ID) {
+			t.Errorf("trip %d (%s, %s) is listed after trip %d (%s, %s)", i, trip.StartsAt, trip.ID, i-1, prev.StartsAt, prev.ID)
+		}
+	}
+
+	var paged []string
+	target := "/trips?limit=2"
+	for pages := 0; ; pages++ {
+		if pages > len(all.Trips) {
+			t.Fatalf("still paging after %d pages", pages)
+		}
+		page := listTrips(t, server, target)
+		for _, trip := range page.Trips {
+			paged = append(paged, trip.ID)
+		}
+		if page.NextCursor == nil {
+			break
+		}
+		target = "/trips?limit=2&cursor=" + url.QueryEscape(*page.NextCursor)
+	}
+
+	var want []string
+	for _, trip := range all.Trips {
+		want = append(want, trip.ID)
+	}
+	if !slices.Equal(paged, want) {
+		t.Errorf("paging through GET /trips = %v, want %v", paged, want)
+	}
+}
+
+// TestListTripsTamperedCursor checks a next_cursor changed by the client, or
+// one without its signature, is rejected instead of paging from where it says.
+func TestListTripsTamperedCursor(t *testing.T) {
+	store := memstore.New()
+	server := newServer(store)
+
+	start := time.Now().UTC().Truncate(time.Second).AddDate(0, 1, 0)
+	for i := range 3 {
+		_, err := store.CreateTrip(context.Background(), spec.CreateTripRequest{
+			Destination: "Trip " + strconv.Itoa(i),
+			OwnerEmail:  "owner@example.com",
+			OwnerName:   "Owner",
+			StartsAt:    start.AddDate(0, 0, i),
+			EndsAt:      start.AddDate(0, 0, i+1),
+		})
+		if err != nil {
+			t.Fatalf("CreateTrip: %v", err)
+		}
+	}
+
+	page := listTrips(t, server, "/trips?limit=1")
+	if page.NextCursor == nil {
+		t.Fatalf("GET /trips?limit=1 has no next_cursor")
+	}
+	payload, signature, ok := strings.Cut(*page.NextCursor, ".")
+	if !ok {
+		t.Fatalf("next_cursor %q isn't signed", *page.NextCursor)
+	}
+	decoded, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		t.Fatalf("decoding next_cursor %q: %v", *page.NextCursor, err)
+	}
+	var cursor map[string]any
+	if err := json.Unmarshal(decoded, &cursor); err != nil {
+		t.Fatalf("decoding next_cursor %s: %v", decoded, err)
+	}
+	cursor["starts_at"] = start.AddDate(0, 0, -1)
+	modified, err := json.Marshal(cursor)
+	if err != nil {
+		t.Fatalf("json.Marshal: %v", err)
+	}
+	tampered := base64.RawURLEncoding.EncodeToString(modified)
+
+	rejected := []struct {
+		name   string
+		cursor string
+	}{
+		{"modified", tampered + "." + signature},
+		{"unsigned", tampered},
+		{"bad signature", payload + "." + base64.RawURLEncoding.EncodeToString([]byte("not the signature"))},
+	}
+	for _, tt := range rejected {
+		target := "/trips?limit=1&cursor=" + url.QueryEscape(tt.cursor)
+		rec := serve(server, httptest.NewRequest(http.MethodGet, target, nil))
+		if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "Invalid cursor") {
+			t.Errorf("GET /trips with a %s cursor = %d %s, want 400", tt.name, rec.Code, rec.Body)
+		}
+	}
+
+	if next := listTrips(t, server, "/trips?limit=1&cursor="+url.QueryEscape(*page.NextCursor)); len(next.Trips) != 1 || next.Trips[0].Destination != "Trip 1" {
+		t.Errorf("GET /trips with the next_cursor = %v, want Trip 1", next.Trips)
+	}
+}
@@ -0,0 +1,235 @@
+package api
+
+import (
+	"errors"
+	"journey/internal/emails"
+	"net"
+	"net/url"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/discord-gophers/goapi-gen/types"
+	"github.com/go-playground/validator/v10"
+)
+
+// validateHTTPURL makes sure a URL can be safely rendered as a link or an
+// image by the frontend, which the validator's url tag alone doesn't.
+func validateHTTPURL(field, raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return errors.New(field + " is not a valid URL")
+	}
+
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.New(field + " must use the http or https scheme")
+	}
+
+	return nil
+}
+
+// maxLinkURLLength is the longest URL a trip link may point to.
+const maxLinkURLLength = 2048
+
+// normalizeLinkURL validates a trip link URL and returns the form it is stored
+// in, with a lowercased host and without the scheme's default port. URLs with
+// embedded credentials are rejected so they don't leak to every participant.
+func normalizeLinkURL(raw string) (string, error) {
+	if len(raw) > maxLinkURLLength {
+		return "", errors.New("url must be at most " + strconv.Itoa(maxLinkURLLength) + " characters long")
+	}
+
+	if err := validateHTTPURL("url", raw); err != nil {
+		return "", err
+	}
+
+	u, err := url.Parse(raw)
+	if err != nil {
+		return "", errors.New("url is not a valid URL")
+	}
+
+	if u.User != nil {
+		return "", errors.New("url must not contain credentials")
+	}
+
+	if u.Hostname() == "" {
+		return "", errors.New("url must have a host")
+	}
+
+	host := strings.ToLower(u.Hostname())
+	port := u.Port()
+	if (u.Scheme == "http" && port == "80") || (u.Scheme == "https" && port == "443") {
+		port = ""
+	}
+	if port != "" {
+		host = net.JoinHostPort(host, port)
+	} else if strings.Contains(host, ":") {
+		// IPv6 literals keep their brackets
+		host = "[" + host + "]"
+	}
+	u.Host = host
+
+	return u.String(), nil
+}
+
+// jsonFieldName names the validator errors after the JSON fields the client
+// sent rather than the Go struct fields.
+func jsonFieldName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+	if name == "-" {
+		return ""
+	}
+	return name
+}
+
+// validationMessage describes the validator errors one field at a time. The
+// length rules state their limit, so clients know how much to trim.
+func validationMessage(err error) string {
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return err.Error()
+	}
+
+	messages := make([]string, len(fieldErrs))
+	for i, fieldErr := range fieldErrs {
+		switch {
+		case fieldErr.Tag() == "max" && fieldErr.Kind() == reflect.String:
+			messages[i] = fieldErr.Field() + " must be at most " + fieldErr.Param() + " characters long"
+		case fieldErr.Tag() == "min" && fieldErr.Kind() == reflect.String:
+			messages[i] = fieldErr.Field() + " must be at least " + fieldErr.Param() + " characters long"
+		default:
+			messages[i] = fieldErr.Error()
+		}
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+// validateTripDates checks every rule on the dates of a trip, for the trips
+// being created (isNew) as well as the ones being updated. A trip must end after
+// it starts and last at most maxDays, a trip lasting exactly maxDays being
+// accepted. A new trip can't start before today, while an existing one may
+// already be under way.
+func validateTripDates(startsAt, endsAt time.Time, isNew bool, maxDays int) error {
+	if !endsAt.After(startsAt) {
+		return errors.New("ends_at must be after starts_at")
+	}
+
+	if endsAt.Sub(startsAt) > time.Duration(maxDays)*24*time.Hour {
+		return errors.New("ends_at must be at most " + strconv.Itoa(maxDays) + " days after starts_at")
+	}
+
+	if isNew {
+		year, month, day := time.Now().In(startsAt.Location()).Date()
+		if startsAt.Before(time.Date(year, month, day, 0, 0, 0, 0, startsAt.Location())) {
+			return errors.New("starts_at must not be before today")
+		}
+	}
+
+	return nil
+}
+
+// validateEmail returns the normalized form of an address, or an error quoting
+// it after the field it was sent in.
+func validateEmail(field string, address types.Email) (types.Email, error) {
+	normalized, err := emails.Validate(string(address))
+	if err != nil {
+		return "", errors.New(field + " " + strconv.Quote(string(address)) + " " + err.Error())
+	}
+
+	return types.Email(normalized), nil
+}
+
+// validateEmails normalizes a list of addresses, reporting every invalid one
+// along with its position in the list.
+func validateEmails(field string, addresses []types.Email) ([]types.Email, error) {
+	var failures []string
+	normalized := make([]types.Email, len(addresses))
+	for i, address := range addresses {
+		email, err := validateEmail(field+"["+strconv.Itoa(i)+"]", address)
+		if err != nil {
+			failures = append(failures, err.Error())
+			continue
+		}
+		normalized[i] = email
+	}
+
+	if len(failures) > 0 {
+		return nil, errors.New(strings.Join(failures, "; "))
+	}
+
+	return normalized, nil
+}
+
+// validateInvitesCount caps how many emails one request may invite, so a
+// pasted address book doesn't time out halfway through the inserts.
+func validateInvitesCount(count, maxInvites int) error {
+	if count > maxInvites {
+		return errors.New("emails_to_invite must have at most " + strconv.Itoa(maxInvites) + " emails, got " + strconv.Itoa(count))
+	}
+
+	return nil
+}
+
+// validateActivityDate checks that an activity happens within the trip dates.
+// The whole last day is allowed, so a trip ending at noon can still hold a
+// dinner that evening.
+func validateActivityDate(occursAt, startsAt, endsAt time.Time) error {
+	if occursAt.Before(startsAt) || !occursAt.Before(endOfDay(endsAt)) {
+		return errors.New("occurs_at must be between " + startsAt.Format(time.RFC3339) + " and the end of " + endsAt.Format(time.DateOnly))
+	}
+
+	return nil
+}
+
+// endOfDay returns the midnight that follows t.
+func endOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day+1, 0, 0, 0, 0, t.Location())
+}
+
+// collapseSpaces trims s and collapses its inner runs of whitespace into a
+// single space, so "  Rio   de Janeiro " is stored as "Rio de Janeiro" and a
+// blank value fails the validator's length rules.
+func collapseSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// escapeLikePattern escapes the LIKE wildcards in s so it is matched literally.
+func escapeLikePattern(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(s)
+}
+
+// dedupeEmails drops the emails already present earlier in the list, ignoring
+// case, and returns how many were dropped.
+func dedupeEmails(emails []types.Email) ([]types.Email, int) {
+	seen := make(map[string]struct{}, len(emails))
+	deduped := make([]types.Email, 0, len(emails))
+	for _, email := range emails {
+		key := strings.ToLower(string(email))
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		deduped = append(deduped, email)
+	}
+
+	return deduped, len(emails) - len(deduped)
+}
+
+// normalizeTags trims and lowercases activity tags, dropping the duplicates, so
+// filtering by tag doesn't depend on how a tag was typed.
+func normalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || slices.Contains(normalized, tag) {
+			continue
+		}
+		normalized = append(normalized, tag)
+	}
+
+	return normalized
+}
@@ -0,0 +1,107 @@
+package api
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/discord-gophers/goapi-gen/types"
+)
+
+func TestCollapseSpaces(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Rio de Janeiro", "Rio de Janeiro"},
+		{"  Rio   de Janeiro ", "Rio de Janeiro"},
+		{"Rio\tde\nJaneiro", "Rio de Janeiro"},
+		{"Rio de Janeiro", "Rio de Janeiro"},
+		{"    ", ""},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := collapseSpaces(tt.in); got != tt.want {
+			t.Errorf("collapseSpaces(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestDedupeEmails(t *testing.T) {
+	tests := []struct {
+		name        string
+		in          []types.Email
+		want        []types.Email
+		wantDropped int
+	}{
+		{"none", []types.Email{}, []types.Email{}, 0},
+		{"no duplicates", []types.Email{"ana@example.com", "bruno@example.com"}, []types.Email{"ana@example.com", "bruno@example.com"}, 0},
+		{"same address", []types.Email{"ana@example.com", "bruno@example.com", "ana@example.com"}, []types.Email{"ana@example.com", "bruno@example.com"}, 1},
+		{"first spelling kept", []types.Email{"Ana@Example.com", "ana@example.com", "ANA@EXAMPLE.COM"}, []types.Email{"Ana@Example.com"}, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, dropped := dedupeEmails(tt.in)
+			if !slices.Equal(got, tt.want) || dropped != tt.wantDropped {
+				t.Errorf("dedupeEmails(%v) = %v, %d, want %v, %d", tt.in, got, dropped, tt.want, tt.wantDropped)
+			}
+		})
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	tests := []struct {
+		name string
+		in   []string
+		want []string
+	}{
+		{"none", nil, []string{}},
+		{"trimmed and lowercased", []string{" Food ", "MUSEUM"}, []string{"food", "museum"}},
+		{"duplicates dropped", []string{"food", "Food", " food"}, []string{"food"}},
+		{"blank dropped", []string{"", "   ", "food"}, []string{"food"}},
+		{"order kept", []string{"museum", "food", "beach"}, []string{"museum", "food", "beach"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := normalizeTags(tt.in); !slices.Equal(got, tt.want) {
+				t.Errorf("normalizeTags(%q) = %q, want %q", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateTripDates(t *testing.T) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	start := today.AddDate(0, 1, 0)
+	const maxDays = 90
+
+	tests := []struct {
+		name     string
+		startsAt time.Time
+		endsAt   time.Time
+		isNew    bool
+		wantErr  bool
+	}{
+		{"new trip", start, start.AddDate(0, 0, 5), true, false},
+		{"ending before it starts", start.AddDate(0, 0, 5), start, false, true},
+		{"ending when it starts", start, start, false, true},
+		{"ending half a second before it starts", start, start.Add(-500 * time.Millisecond), false, true},
+		{"ending half a second after it starts", start, start.Add(500 * time.Millisecond), false, false},
+		{"ending a nanosecond after it starts", start, start.Add(time.Nanosecond), false, false},
+		{"new trip starting today", today, today.AddDate(0, 0, 5), true, false},
+		{"new trip starting yesterday", today.AddDate(0, 0, -1), today.AddDate(0, 0, 5), true, true},
+		{"trip under way", today.AddDate(0, 0, -1), today.AddDate(0, 0, 5), false, false},
+		{"lasting exactly the maximum", start, start.AddDate(0, 0, maxDays), false, false},
+		{"lasting a second over the maximum", start, start.AddDate(0, 0, maxDays).Add(time.Second), false, true},
+		{"lasting a day over the maximum", start, start.AddDate(0, 0, maxDays+1), false, true},
+		{"lasting longer than allowed", start, start.AddDate(0, 0, 2*maxDays), false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateTripDates(tt.startsAt, tt.endsAt, tt.isNew, maxDays)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("validateTripDates(%v, %v, %v) = %v, want error %v", tt.startsAt, tt.endsAt, tt.isNew, err, tt.wantErr)
+			}
+		})
+	}
+}
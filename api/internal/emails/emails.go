@@ -0,0 +1,109 @@
+// Package emails holds the rules shared by everything handling e-mail addresses.
+package emails
+
+import (
+	"errors"
+	"strings"
+
+	"golang.org/x/net/idna"
+)
+
+// maxLocalPartLength and maxAddressLength are the limits RFC 5321 puts on an
+// address that can actually be delivered.
+const (
+	maxLocalPartLength = 64
+	maxAddressLength   = 254
+)
+
+// localPartSpecials are the characters besides letters and digits allowed in
+// the unquoted local part of an address.
+const localPartSpecials = "!#$%&'*+-/=?^_`{|}~."
+
+// Normalize returns the form an address is stored and compared in. Addresses
+// are treated as case-insensitive, so "Bob@Gmail.com" and "bob@gmail.com" are
+// the same participant.
+func Normalize(address string) string {
+	return strings.ToLower(strings.TrimSpace(address))
+}
+
+// Validate checks that address looks deliverable and returns its normalized
+// form, with an internationalized domain converted to punycode so
+// "ana@münchen.de" is stored as "ana@xn--mnchen-3ya.de". It is stricter than
+// the validator's email tag, rejecting domains without a dot like "a@b" and
+// the stray dots typos leave behind.
+func Validate(address string) (string, error) {
+	address = Normalize(address)
+	if address == "" {
+		return "", errors.New("must not be empty")
+	}
+
+	local, domain, found := strings.Cut(address, "@")
+	if !found || strings.Contains(domain, "@") {
+		return "", errors.New("must contain a single @")
+	}
+
+	if err := validateLocalPart(local); err != nil {
+		return "", err
+	}
+
+	domain, err := validateDomain(domain)
+	if err != nil {
+		return "", err
+	}
+
+	address = local + "@" + domain
+	if len(address) > maxAddressLength {
+		return "", errors.New("must be at most 254 characters long")
+	}
+
+	return address, nil
+}
+
+func validateLocalPart(local string) error {
+	if local == "" {
+		return errors.New("must have a name before the @")
+	}
+
+	if len(local) > maxLocalPartLength {
+		return errors.New("must have at most 64 characters before the @")
+	}
+
+	if strings.HasPrefix(local, ".") || strings.HasSuffix(local, ".") || strings.Contains(local, "..") {
+		return errors.New("must not have a dot at the start or end of the name, or two dots in a row")
+	}
+
+	for _, c := range local {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && !strings.ContainsRune(localPartSpecials, c) {
+			return errors.New("must not contain " + string(c) + " before the @")
+		}
+	}
+
+	return nil
+}
+
+func validateDomain(domain string) (string, error) {
+	if domain == "" {
+		return "", errors.New("must have a domain after the @")
+	}
+
+	if strings.HasPrefix(domain, ".") || strings.HasSuffix(domain, ".") || strings.Contains(domain, "..") {
+		return "", errors.New("must not have a dot at the start or end of the domain, or two dots in a row")
+	}
+
+	if !strings.Contains(domain, ".") {
+		return "", errors.New("must have a domain with a dot, like example.com")
+	}
+
+	domain, err := idna.Lookup.ToASCII(domain)
+	if err != nil {
+		return "", errors.New("must have a valid domain")
+	}
+
+	// Top level domains are letters only, or punycode, which catches typos like "gmail.c0m"
+	tld := domain[strings.LastIndex(domain, ".")+1:]
+	if len(tld) < 2 || (!strings.HasPrefix(tld, "xn--") && strings.Trim(tld, "abcdefghijklmnopqrstuvwxyz") != "") {
+		return "", errors.New("must have a valid top level domain")
+	}
+
+	return domain, nil
+}
@@ -0,0 +1,132 @@
+// Package events is an in-process pub/sub of the changes made to trips, used to
+// stream them to the clients looking at a trip.
+package events
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Types of the events published when a trip child resource changes.
+const (
+	ActivityCreated      = "activity.created"
+	LinkCreated          = "link.created"
+	LinkUpdated          = "link.updated"
+	ParticipantInvited   = "participant.invited"
+	ParticipantUpdated   = "participant.updated"
+	ParticipantConfirmed = "participant.confirmed"
+	ParticipantDeclined  = "participant.declined"
+	ParticipantRemoved   = "participant.removed"
+)
+
+// subscriberBuffer is how many events a slow subscriber may lag behind before
+// new events are dropped for it.
+const subscriberBuffer = 16
+
+// ErrTooManySubscribers is returned by Subscribe when a trip already has the
+// maximum number of subscribers.
+var ErrTooManySubscribers = errors.New("events: too many subscribers for trip")
+
+// ErrClosed is returned by Subscribe once the broker is closed.
+var ErrClosed = errors.New("events: broker closed")
+
+// Event is a change made to a resource of a trip.
+type Event struct {
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// Broker fans the events of a trip out to its subscribers. The zero value is
+// not usable, use NewBroker.
+type Broker struct {
+	mu             sync.Mutex
+	subscribers    map[uuid.UUID]map[chan Event]struct{}
+	maxSubscribers int
+	closed         bool
+}
+
+// NewBroker returns a Broker accepting up to maxSubscribers subscribers per trip.
+func NewBroker(maxSubscribers int) *Broker {
+	return &Broker{
+		subscribers:    make(map[uuid.UUID]map[chan Event]struct{}),
+		maxSubscribers: maxSubscribers,
+	}
+}
+
+// Subscribe registers a subscriber to the events of a trip. The returned func
+// must be called once the subscriber is done, it closes the channel. The
+// channel is also closed when the broker is.
+func (b *Broker) Subscribe(tripID uuid.UUID) (<-chan Event, func(), error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil, nil, ErrClosed
+	}
+
+	subs := b.subscribers[tripID]
+	if len(subs) >= b.maxSubscribers {
+		return nil, nil, ErrTooManySubscribers
+	}
+
+	if subs == nil {
+		subs = make(map[chan Event]struct{})
+		b.subscribers[tripID] = subs
+	}
+
+	ch := make(chan Event, subscriberBuffer)
+	subs[ch] = struct{}{}
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+
+			// Close closed it already
+			if _, ok := subs[ch]; !ok {
+				return
+			}
+
+			delete(subs, ch)
+			if len(subs) == 0 {
+				delete(b.subscribers, tripID)
+			}
+			close(ch)
+		})
+	}
+
+	return ch, unsubscribe, nil
+}
+
+// Publish sends an event to every subscriber of a trip. It never blocks, a
+// subscriber whose buffer is full misses the event.
+func (b *Broker) Publish(tripID uuid.UUID, event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subscribers[tripID] {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// Close closes the channel of every subscriber, telling them to stop, and
+// refuses the new ones.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for tripID, subs := range b.subscribers {
+		for ch := range subs {
+			delete(subs, ch)
+			close(ch)
+		}
+		delete(b.subscribers, tripID)
+	}
+}
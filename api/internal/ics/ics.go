@@ -0,0 +1,159 @@
+// Package ics renders calendars in the iCalendar format (RFC 5545).
+package ics
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	prodID = "-//journey//trip calendar//PT"
+
+	// maxLineOctets is the longest a content line may be before it must be folded.
+	maxLineOctets = 75
+
+	dateTimeLayout = "20060102T150405Z"
+)
+
+// Event is a single VEVENT. An End left as the zero time is omitted, which
+// calendars render as an event without duration. Organizer and Attendees are
+// e-mail addresses, only needed by invites.
+type Event struct {
+	UID       string
+	Start     time.Time
+	End       time.Time
+	Summary   string
+	Organizer string
+	Attendees []string
+}
+
+// Calendar is a VCALENDAR holding events. Stamp is the DTSTAMP written on every
+// event, usually the time the calendar was generated. Method is the iTIP method
+// (RFC 5546) of a calendar sent by e-mail, such as "REQUEST" for an invite.
+type Calendar struct {
+	Name   string
+	Method string
+	Stamp  time.Time
+	Events []Event
+}
+
+// Encode writes the calendar to w using CRLF line endings, escaping text values
+// and folding lines longer than 75 octets.
+func (c Calendar) Encode(w io.Writer) error {
+	lines := []string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:" + prodID,
+		"CALSCALE:GREGORIAN",
+	}
+
+	if c.Method != "" {
+		lines = append(lines, "METHOD:"+c.Method)
+	}
+
+	if c.Name != "" {
+		lines = append(lines, "X-WR-CALNAME:"+escapeText(c.Name))
+	}
+
+	for _, event := range c.Events {
+		lines = append(lines,
+			"BEGIN:VEVENT",
+			"UID:"+escapeText(event.UID),
+			"DTSTAMP:"+formatDateTime(c.Stamp),
+			"DTSTART:"+formatDateTime(event.Start),
+		)
+		if !event.End.IsZero() {
+			lines = append(lines, "DTEND:"+formatDateTime(event.End))
+		}
+		lines = append(lines, "SUMMARY:"+escapeText(event.Summary))
+		if event.Organizer != "" {
+			lines = append(lines, "ORGANIZER:mailto:"+event.Organizer)
+		}
+		for _, attendee := range event.Attendees {
+			lines = append(lines, "ATTENDEE;RSVP=TRUE:mailto:"+attendee)
+		}
+		lines = append(lines, "END:VEVENT")
+	}
+
+	lines = append(lines, "END:VCALENDAR")
+
+	for _, line := range lines {
+		if _, err := io.WriteString(w, foldLine(line)+"\r\n"); err != nil {
+			return fmt.Errorf("ics: failed to write calendar: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Filename turns name into a file name safe to send anywhere, such as
+// "sao-paulo.ics" for "São Paulo", falling back to "calendar.ics".
+func Filename(name string) string {
+	var b strings.Builder
+	dash := false
+	for _, r := range norm.NFD.String(strings.ToLower(name)) {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+			// Accents are dropped, leaving the letter they were on
+		case (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9'):
+			if dash && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			dash = false
+			b.WriteRune(r)
+		default:
+			dash = true
+		}
+	}
+
+	if b.Len() == 0 {
+		return "calendar.ics"
+	}
+	return b.String() + ".ics"
+}
+
+func formatDateTime(t time.Time) string {
+	return t.UTC().Format(dateTimeLayout)
+}
+
+// escapeText escapes a TEXT value as required by RFC 5545 section 3.3.11.
+func escapeText(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\n", `\n`,
+		"\r", `\n`,
+	).Replace(s)
+}
+
+// foldLine splits a content line into chunks of at most 75 octets, every
+// continuation starting with a single space. It never splits a UTF-8 sequence.
+func foldLine(line string) string {
+	if len(line) <= maxLineOctets {
+		return line
+	}
+
+	var b strings.Builder
+	limit := maxLineOctets
+	size := 0
+	for _, r := range line {
+		n := len(string(r))
+		if size+n > limit {
+			b.WriteString("\r\n ")
+			// The leading space counts toward the continuation line length
+			limit = maxLineOctets - 1
+			size = 0
+		}
+		b.WriteRune(r)
+		size += n
+	}
+
+	return b.String()
+}
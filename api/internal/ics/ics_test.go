@@ -0,0 +1,111 @@
+package ics
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestEscapeText(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"Lisbon", "Lisbon"},
+		{"Lisbon, Portugal", `Lisbon\, Portugal`},
+		{"dinner; drinks", `dinner\; drinks`},
+		{`C:\trips`, `C:\\trips`},
+		{"first\nsecond", `first\nsecond`},
+		{"first\r\nsecond", `first\nsecond`},
+		{"first\rsecond", `first\nsecond`},
+		{`a\,b;`, `a\\\,b\;`},
+	}
+	for _, tt := range tests {
+		if got := escapeText(tt.in); got != tt.want {
+			t.Errorf("escapeText(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestFoldLine(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+	}{
+		{"short", "SUMMARY:Lisbon"},
+		{"exactly 75 octets", "SUMMARY:" + strings.Repeat("a", 75-len("SUMMARY:"))},
+		{"76 octets", "SUMMARY:" + strings.Repeat("a", 76-len("SUMMARY:"))},
+		{"several continuations", "SUMMARY:" + strings.Repeat("a", 300)},
+		{"multibyte", "SUMMARY:" + strings.Repeat("ção ", 60)},
+		{"multibyte across the limit", "SUMMARY:" + strings.Repeat("a", 66) + "ããã"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			folded := foldLine(tt.line)
+			lines := strings.Split(folded, "\r\n")
+			for i, line := range lines {
+				if len(line) > maxLineOctets {
+					t.Errorf("line %d is %d octets long, want at most %d: %q", i, len(line), maxLineOctets, line)
+				}
+				if i > 0 && !strings.HasPrefix(line, " ") {
+					t.Errorf("continuation line %d doesn't start with a space: %q", i, line)
+				}
+				if !utf8.ValidString(line) {
+					t.Errorf("line %d splits a UTF-8 sequence: %q", i, line)
+				}
+			}
+			if len(tt.line) <= maxLineOctets && len(lines) != 1 {
+				t.Errorf("a %d octets line was folded into %d lines", len(tt.line), len(lines))
+			}
+			if unfolded := strings.ReplaceAll(folded, "\r\n ", ""); unfolded != tt.line {
+				t.Errorf("unfolding gives %q, want %q", unfolded, tt.line)
+			}
+		})
+	}
+}
+
+func TestEncode(t *testing.T) {
+	start := time.Date(2030, 3, 10, 9, 0, 0, 0, time.UTC)
+	calendar := Calendar{
+		Name:  "Lisbon, Portugal",
+		Stamp: start.AddDate(0, -1, 0),
+		Events: []Event{{
+			UID:     "activity@journey",
+			Start:   start,
+			Summary: "Dinner; then drinks\n" + strings.Repeat("and more drinks, ", 10),
+		}},
+	}
+
+	var b strings.Builder
+	if err := calendar.Encode(&b); err != nil {
+		t.Fatalf("Encode: %v", err)
+	}
+	out := b.String()
+
+	if !strings.HasSuffix(out, "END:VCALENDAR\r\n") {
+		t.Errorf("the calendar doesn't end with a CRLF terminated END:VCALENDAR:\n%s", out)
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(out, "\r\n"), "\r\n") {
+		if len(line) > maxLineOctets {
+			t.Errorf("line is %d octets long: %q", len(line), line)
+		}
+		if strings.Contains(line, "\n") {
+			t.Errorf("line has a bare line feed: %q", line)
+		}
+	}
+
+	unfolded := strings.ReplaceAll(out, "\r\n ", "")
+	for _, want := range []string{
+		`X-WR-CALNAME:Lisbon\, Portugal` + "\r\n",
+		"DTSTART:20300310T090000Z\r\n",
+		`SUMMARY:Dinner\; then drinks\n` + strings.Repeat(`and more drinks\, `, 10) + "\r\n",
+	} {
+		if !strings.Contains(unfolded, want) {
+			t.Errorf("the calendar doesn't contain %q:\n%s", want, out)
+		}
+	}
+	if strings.Contains(out, "DTEND") {
+		t.Errorf("an event without End has a DTEND:\n%s", out)
+	}
+}
@@ -0,0 +1,34 @@
+// Package itinerary groups the activities of a trip by the day they happen on,
+// the way both the API and the emails show them.
+package itinerary
+
+import (
+	"journey/internal/pgstore"
+	"time"
+)
+
+// Day is the activities happening on Date, midnight UTC of that day.
+type Day struct {
+	Date       time.Time
+	Activities []pgstore.Activity
+}
+
+// GroupByDay groups activities by the UTC day they occur on. The days are in
+// the order their first activity appears in, and so are the activities of a day.
+func GroupByDay(activities []pgstore.Activity) []Day {
+	days := make([]Day, 0, len(activities))
+	index := make(map[time.Time]int)
+	for _, activity := range activities {
+		year, month, day := activity.OccursAt.Time.Date()
+		date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+
+		i, ok := index[date]
+		if !ok {
+			i = len(days)
+			index[date] = i
+			days = append(days, Day{Date: date})
+		}
+		days[i].Activities = append(days[i].Activities, activity)
+	}
+	return days
+}
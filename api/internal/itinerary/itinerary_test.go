@@ -0,0 +1,88 @@
+package itinerary
+
+import (
+	"journey/internal/pgstore"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func activity(title string, occursAt time.Time) pgstore.Activity {
+	return pgstore.Activity{Title: title, OccursAt: pgtype.Timestamp{Valid: true, Time: occursAt}}
+}
+
+// titles are the titles of the activities of every day, a day per element.
+func titles(days []Day) [][]string {
+	grouped := make([][]string, len(days))
+	for i, day := range days {
+		for _, activity := range day.Activities {
+			grouped[i] = append(grouped[i], activity.Title)
+		}
+	}
+	return grouped
+}
+
+func TestGroupByDayMidnight(t *testing.T) {
+	midnight := time.Date(2030, time.March, 11, 0, 0, 0, 0, time.UTC)
+	days := GroupByDay([]pgstore.Activity{
+		activity("Dinner", midnight.Add(-time.Hour)),
+		activity("Last drink", midnight.Add(-time.Nanosecond)),
+		activity("Midnight walk", midnight),
+		activity("Breakfast", midnight.Add(8*time.Hour)),
+		activity("Late check-in", midnight.Add(24*time.Hour-time.Nanosecond)),
+	})
+
+	wantDates := []time.Time{midnight.AddDate(0, 0, -1), midnight}
+	if len(days) != len(wantDates) {
+		t.Fatalf("GroupByDay = %v, want %d days", titles(days), len(wantDates))
+	}
+	for i, want := range wantDates {
+		if !days[i].Date.Equal(want) || days[i].Date.Location() != time.UTC {
+			t.Errorf("day %d is %s, want %s", i, days[i].Date, want)
+		}
+	}
+	want := [][]string{{"Dinner", "Last drink"}, {"Midnight walk", "Breakfast", "Late check-in"}}
+	if got := titles(days); !slices.EqualFunc(got, want, slices.Equal[[]string]) {
+		t.Errorf("GroupByDay = %v, want %v", got, want)
+	}
+}
+
+func TestGroupByDaySameTime(t *testing.T) {
+	at := time.Date(2030, time.March, 11, 10, 0, 0, 0, time.UTC)
+	days := GroupByDay([]pgstore.Activity{
+		activity("Boat", at),
+		activity("Bus", at),
+		activity("Train", at),
+		activity("Lunch", at.Add(2*time.Hour)),
+		activity("Museum", at.Add(2*time.Hour)),
+	})
+
+	// Activities at the same time keep the order the store listed them in
+	want := [][]string{{"Boat", "Bus", "Train", "Lunch", "Museum"}}
+	if got := titles(days); !slices.EqualFunc(got, want, slices.Equal[[]string]) {
+		t.Errorf("GroupByDay = %v, want %v", got, want)
+	}
+}
+
+func TestGroupByDayNone(t *testing.T) {
+	if days := GroupByDay(nil); days == nil || len(days) != 0 {
+		t.Errorf("GroupByDay(nil) = %#v, want no days, not nil", days)
+	}
+}
+
+// BenchmarkGroupByDay groups 5000 activities over a 90 days trip.
+func BenchmarkGroupByDay(b *testing.B) {
+	start := time.Date(2030, time.March, 10, 0, 0, 0, 0, time.UTC)
+	activities := make([]pgstore.Activity, 5000)
+	for i := range activities {
+		activities[i] = activity("Activity", start.Add(time.Duration(i)*90*24*time.Hour/time.Duration(len(activities))))
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for range b.N {
+		GroupByDay(activities)
+	}
+}
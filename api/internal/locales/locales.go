@@ -0,0 +1,43 @@
+// Package locales lists the languages the emails are written in and picks one
+// for a request.
+package locales
+
+import (
+	"slices"
+
+	"golang.org/x/text/language"
+)
+
+// Default is the locale of the trips created without one, and the one used
+// when a trip has a locale the emails aren't written in.
+const Default = "pt-BR"
+
+// Supported are the locales the emails are written in, Default first.
+var Supported = []string{Default, "en"}
+
+// matcher picks the closest supported locale, in the same order as Supported.
+var matcher = language.NewMatcher([]language.Tag{language.BrazilianPortuguese, language.English})
+
+// IsSupported reports whether locale is one of Supported, spelled the same.
+func IsSupported(locale string) bool {
+	return slices.Contains(Supported, locale)
+}
+
+// FromAcceptLanguage is the supported locale closest to an Accept-Language
+// header, Default when the header is empty, malformed or matches none of them.
+func FromAcceptLanguage(header string) string {
+	if header == "" {
+		return Default
+	}
+
+	tags, _, err := language.ParseAcceptLanguage(header)
+	if err != nil || len(tags) == 0 {
+		return Default
+	}
+
+	_, index, confidence := matcher.Match(tags...)
+	if confidence == language.No {
+		return Default
+	}
+	return Supported[index]
+}
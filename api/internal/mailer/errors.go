@@ -0,0 +1,80 @@
+package mailer
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RecipientError is the failure to email one participant of a batch.
+type RecipientError struct {
+	ParticipantID uuid.UUID
+	Email string
+	Err error
+}
+
+func (e RecipientError) Error() string {
+	return e.Email + ": " + e.Err.Error()
+}
+
+func (e RecipientError) Unwrap() error {
+	return e.Err
+}
+
+// DeliveryError is returned when some recipients of a batch couldn't be
+// emailed, the others were.
+type DeliveryError struct {
+	Failures []RecipientError
+}
+
+func (e *DeliveryError) Error() string {
+	failures := make([]string, len(e.Failures))
+	for i, f := range e.Failures {
+		failures[i] = f.Error()
+	}
+	return "failed to email " + strings.Join(failures, "; ")
+}
+
+func (e *DeliveryError) Unwrap() []error {
+	errs := make([]error, len(e.Failures))
+	for i, f := range e.Failures {
+		errs[i] = f
+	}
+	return errs
+}
+
+// AsDeliveryError reports whether err is a partial failure, and which
+// recipients failed.
+func AsDeliveryError(err error) (*DeliveryError, bool) {
+	var deliveryErr *DeliveryError
+	ok := errors.As(err, &deliveryErr)
+	return deliveryErr, ok
+}
+
+// ErrRateLimited is matched by every *RateLimitError.
+var ErrRateLimited = errors.New("mailer: too many emails for this trip")
+
+// RateLimitError is returned when a trip used up its emails for now, RetryAfter
+// being how long until enough of them are available again.
+type RateLimitError struct {
+	TripID uuid.UUID
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return ErrRateLimited.Error() + ", retry after " + e.RetryAfter.String()
+}
+
+func (e *RateLimitError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// AsRateLimitError reports whether err is a refusal of the rate limiter, and
+// how long to wait before retrying.
+func AsRateLimitError(err error) (*RateLimitError, bool) {
+	var rateLimitErr *RateLimitError
+	ok := errors.As(err, &rateLimitErr)
+	return rateLimitErr, ok
+}
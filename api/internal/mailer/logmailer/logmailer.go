@@ -0,0 +1,104 @@
+// Package logmailer delivers the emails nowhere, writing them to the logs or to
+// a directory instead, so the API runs in development without an SMTP server.
+package logmailer
+
+import (
+	"context"
+	"fmt"
+	"journey/internal/mailer"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+type LogMailer struct {
+	logger *zap.Logger
+	// dir is where the emails are written, they're only logged when empty.
+	dir string
+}
+
+// NewLogMailer logs every email, writing it to dir as well unless dir is empty.
+// dir is created if it doesn't exist.
+func NewLogMailer(logger *zap.Logger, dir string) (LogMailer, error) {
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return LogMailer{}, fmt.Errorf("logmailer: failed to create directory %s: %w", dir, err)
+		}
+	}
+
+	return LogMailer{logger, dir}, nil
+}
+
+func (lm LogMailer) Deliver(ctx context.Context, message mailer.Message) error {
+	attachments := make([]string, len(message.Attachments))
+	for i, a := range message.Attachments {
+		attachments[i] = a.Filename
+	}
+
+	if lm.dir == "" {
+		lm.logger.Info("Email not sent, logged instead",
+			zap.String("from", message.FromAddress),
+			zap.String("to", message.To),
+			zap.String("reply_to", message.ReplyToAddress),
+			zap.String("subject", message.Subject),
+			zap.String("body", message.Text),
+			zap.Strings("attachments", attachments),
+		)
+		return nil
+	}
+
+	path, err := lm.write(message)
+	if err != nil {
+		return err
+	}
+
+	lm.logger.Info("Email not sent, written to a file instead", zap.String("to", message.To), zap.String("subject", message.Subject), zap.String("path", path))
+	return nil
+}
+
+// write saves message to its own files in dir, the text body and its headers
+// in a .txt, the HTML body in a .html and every attachment next to them. It
+// returns the path of the .txt.
+func (lm LogMailer) write(message mailer.Message) (string, error) {
+	base := filepath.Join(lm.dir, time.Now().Format("20060102T150405.000000000")+"-"+fileSafe(message.To))
+
+	var text strings.Builder
+	fmt.Fprintf(&text, "From: %s\nTo: %s\n", message.FromAddress, message.To)
+	if message.ReplyToAddress != "" {
+		fmt.Fprintf(&text, "Reply-To: %s\n", message.ReplyToAddress)
+	}
+	fmt.Fprintf(&text, "Subject: %s\n", message.Subject)
+	for _, a := range message.Attachments {
+		fmt.Fprintf(&text, "Attachment: %s (%s)\n", a.Filename, a.ContentType)
+	}
+	text.WriteString("\n" + message.Text)
+
+	if err := os.WriteFile(base+".txt", []byte(text.String()), 0o644); err != nil {
+		return "", fmt.Errorf("logmailer: failed to write email: %w", err)
+	}
+
+	if err := os.WriteFile(base+".html", []byte(message.HTML), 0o644); err != nil {
+		return "", fmt.Errorf("logmailer: failed to write email: %w", err)
+	}
+
+	for _, a := range message.Attachments {
+		if err := os.WriteFile(base+"-"+fileSafe(a.Filename), a.Content, 0o644); err != nil {
+			return "", fmt.Errorf("logmailer: failed to write attachment %s: %w", a.Filename, err)
+		}
+	}
+
+	return base + ".txt", nil
+}
+
+// fileSafe replaces what a file name can't hold on every platform with "_".
+func fileSafe(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '.' || r == '-' || r == '@' || r == '_' || ('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || ('0' <= r && r <= '9') {
+			return r
+		}
+		return '_'
+	}, name)
+}
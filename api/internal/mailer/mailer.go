@@ -0,0 +1,678 @@
+// Package mailer renders the emails of the API and looks up who they go to,
+// leaving their delivery to a provider such as mailpit or sendgrid.
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"journey/internal/emails"
+	"journey/internal/ics"
+	"journey/internal/itinerary"
+	"journey/internal/pgstore"
+	netmail "net/mail"
+	"net/url"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+	"golang.org/x/sync/errgroup"
+)
+
+// Store is where the mailer looks up who the emails go to and logs them, the
+// pgstore queries or the in-memory memstore.Store.
+type Store interface {
+	GetTrip(context.Context, uuid.UUID) (pgstore.Trip, error)
+	GetParticipant(context.Context, uuid.UUID) (pgstore.Participant, error)
+	GetParticipants(context.Context, uuid.UUID) ([]pgstore.Participant, error)
+	GetTripActivities(context.Context, pgstore.GetTripActivitiesParams) ([]pgstore.Activity, error)
+	ListTripLinks(context.Context, pgstore.ListTripLinksParams) ([]pgstore.Link, error)
+	IsEmailSuppressed(context.Context, string) (bool, error)
+	InsertEmailLog(context.Context, pgstore.InsertEmailLogParams) error
+	RecordParticipantInvite(context.Context, pgstore.RecordParticipantInviteParams) error
+}
+
+// Message is a rendered email, ready to be delivered.
+type Message struct {
+	FromAddress string
+	FromName string
+	To string
+	// ReplyToAddress is where the replies go instead of FromAddress, unless
+	// it's empty.
+	ReplyToAddress string
+	ReplyToName string
+	Subject string
+	// Text and HTML are the two renderings of the body, sent as a
+	// multipart/alternative.
+	Text string
+	HTML string
+	Attachments []Attachment
+	// Template is the name of the templates the message was rendered from,
+	// telling apart the types of email in the metrics.
+	Template string
+}
+
+type Attachment struct {
+	Filename string
+	ContentType string
+	Content []byte
+}
+
+// Deliverer sends a rendered email through a provider.
+type Deliverer interface {
+	Deliver(ctx context.Context, message Message) error
+}
+
+// BatchDeliverer is a Deliverer able to send several emails in one go, over a
+// single connection to the provider.
+type BatchDeliverer interface {
+	Deliverer
+	// DeliverBatch returns the error of every message at its index, nil for
+	// the ones delivered.
+	DeliverBatch(ctx context.Context, messages []Message) []error
+}
+
+// Config is who the emails are sent from and where their links point to,
+// whatever the provider.
+type Config struct {
+	FromAddress string
+	FromName string
+	// PublicURL is where the API is reached from the emails, the confirmation
+	// links are built on it.
+	PublicURL string
+	// Concurrency is how many participants of a trip are emailed at once by
+	// the providers that can't send them all in one go.
+	Concurrency int
+}
+
+func DefaultConfig() Config {
+	return Config{
+		FromAddress: "mailpit@journey.com",
+		PublicURL: "http://localhost:8080",
+		Concurrency: 5,
+	}
+}
+
+// Validate reports the settings that would make every email fail to send.
+func (c Config) Validate() error {
+	if _, err := netmail.ParseAddress(c.FromAddress); err != nil {
+		return fmt.Errorf("mailer: invalid from address %q: %w", c.FromAddress, err)
+	}
+
+	if headerName(c.FromName) != c.FromName {
+		return fmt.Errorf("mailer: from name %q must not contain line breaks or other control characters", c.FromName)
+	}
+
+	if u, err := url.Parse(c.PublicURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("mailer: public URL %q must be an absolute http or https URL", c.PublicURL)
+	}
+
+	if c.Concurrency < 1 {
+		return fmt.Errorf("mailer: concurrency %d must be at least 1", c.Concurrency)
+	}
+
+	return nil
+}
+
+type Mailer struct {
+	// ctx is canceled on shutdown, which stops the pending deliveries.
+	ctx context.Context
+	store Store
+	logger *zap.Logger
+	config Config
+	templates emailTemplates
+	deliverer Deliverer
+}
+
+func NewMailer(ctx context.Context, store Store, logger *zap.Logger, config Config, deliverer Deliverer) (Mailer, error) {
+	templates, err := parseTemplates()
+	if err != nil {
+		return Mailer{}, err
+	}
+
+	return Mailer{ctx, store, logger, config, templates, deliverer}, nil
+}
+
+// newMessage renders the name templates of locale into an email to the given
+// address.
+func (m Mailer) newMessage(locale, to, name string, data any) (Message, error) {
+	message := Message{
+		FromAddress: m.config.FromAddress,
+		FromName: m.config.FromName,
+		To: emails.Normalize(to),
+		Template: name,
+	}
+
+	if err := m.templates.render(&message, locale, name, data); err != nil {
+		return Message{}, err
+	}
+
+	return message, nil
+}
+
+// replyToOwner makes the replies to message reach the owner of trip, for the
+// emails the participants get.
+func replyToOwner(message *Message, trip pgstore.Trip) {
+	message.ReplyToAddress = emails.Normalize(trip.OwnerEmail)
+	message.ReplyToName = headerName(trip.OwnerName)
+}
+
+// headerName makes a display name safe to put in a header. The line breaks that
+// would let it add headers of its own are dropped along with every other
+// control character, the spaces around them being collapsed.
+func headerName(name string) string {
+	return strings.Join(strings.Fields(strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+		return r
+	}, name)), " ")
+}
+
+// tripCalendar is the trip dates as a calendar invite for attendee, which mail
+// clients offer to add to the calendar in one click.
+func tripCalendar(trip pgstore.Trip, attendee string) (Attachment, error) {
+	calendar := ics.Calendar{
+		Method: "REQUEST",
+		Stamp: time.Now(),
+		Events: []ics.Event{{
+			UID: trip.ID.String(),
+			Start: trip.StartsAt.Time,
+			End: trip.EndsAt.Time,
+			Summary: trip.Destination,
+			Organizer: emails.Normalize(trip.OwnerEmail),
+			Attendees: []string{emails.Normalize(attendee)},
+		}},
+	}
+
+	var buf bytes.Buffer
+	if err := calendar.Encode(&buf); err != nil {
+		return Attachment{}, err
+	}
+
+	return Attachment{
+		Filename: ics.Filename(trip.Destination),
+		ContentType: "text/calendar; charset=utf-8; method=REQUEST",
+		Content: buf.Bytes(),
+	}, nil
+}
+
+// linkURL is a link an email points to, path being relative to PublicURL.
+func (m Mailer) linkURL(path string) string {
+	return strings.TrimSuffix(m.config.PublicURL, "/") + path
+}
+
+// footer is the footer of an email to the owner of token, whose unsubscribe
+// link stops every email to that address.
+func (m Mailer) footer(token uuid.UUID) footerData {
+	return footerData{UnsubscribeURL: m.linkURL("/unsubscribe/" + token.String())}
+}
+
+// deliver sends message unless its address unsubscribed, in which case it's
+// dropped without an error. Either way the outcome is recorded in email_log,
+// record telling which trip, participant and kind of email it's about. The
+// errors are reported for caller.
+func (m Mailer) deliver(message Message, caller string, record pgstore.InsertEmailLogParams) error {
+	send, err := m.unsuppressed(message, caller, record)
+	if err != nil || !send {
+		return err
+	}
+
+	return m.delivered(message, caller, record, m.deliverer.Deliver(m.ctx, message))
+}
+
+// unsuppressed reports whether message may be sent, recording it as suppressed
+// in email_log when its address unsubscribed.
+func (m Mailer) unsuppressed(message Message, caller string, record pgstore.InsertEmailLogParams) (bool, error) {
+	suppressed, err := m.store.IsEmailSuppressed(m.ctx, message.To)
+	if err != nil {
+		return false, fmt.Errorf("mailer: failed to check suppression list for %s: %w", caller, err)
+	}
+	if suppressed {
+		m.logger.Info("Email not sent, the address unsubscribed", zap.String("email", message.To), zap.String("caller", caller))
+		record.Recipient = message.To
+		record.Status = pgstore.DeliverySuppressed
+		m.logDelivery(record)
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// delivered records err, the outcome of sending message, in email_log and
+// reports it for caller.
+func (m Mailer) delivered(message Message, caller string, record pgstore.InsertEmailLogParams, err error) error {
+	record.Recipient = message.To
+	record.Status = pgstore.DeliverySent
+	if err != nil {
+		record.Status = pgstore.DeliveryFailed
+		record.Error = pgtype.Text{Valid: true, String: err.Error()}
+	}
+	m.logDelivery(record)
+	if record.ParticipantID.Valid && (record.Kind == pgstore.EmailConfirmTripParticipant || record.Kind == pgstore.EmailInviteReminder) {
+		m.recordInvite(record.ParticipantID.Bytes, record.Error)
+	}
+
+	if err != nil {
+		return fmt.Errorf("mailer: failed to send email for %s: %w", caller, err)
+	}
+
+	return nil
+}
+
+// deliverBatch sends messages over a single connection when the provider can,
+// Concurrency at a time otherwise, returning the error of each at its index.
+func deliverBatch(ctx context.Context, deliverer Deliverer, messages []Message, concurrency int) []error {
+	if batch, ok := deliverer.(BatchDeliverer); ok {
+		return batch.DeliverBatch(ctx, messages)
+	}
+
+	errs := make([]error, len(messages))
+	var g errgroup.Group
+	g.SetLimit(concurrency)
+	for i, message := range messages {
+		g.Go(func() error {
+			errs[i] = deliverer.Deliver(ctx, message)
+			return nil
+		})
+	}
+	g.Wait()
+
+	return errs
+}
+
+// logDelivery records an attempt to send an email. Failing to is only logged,
+// the email went out, or didn't, regardless.
+func (m Mailer) logDelivery(record pgstore.InsertEmailLogParams) {
+	if err := m.store.InsertEmailLog(m.ctx, record); err != nil {
+		m.logger.Error("Failed to record email delivery", zap.Error(err), zap.String("trip_id", record.TripID.String()), zap.String("email", record.Recipient), zap.String("status", record.Status))
+	}
+}
+
+// recordInvite records on a participant the outcome of sending their invite,
+// lastError being null once it's sent.
+func (m Mailer) recordInvite(participantID uuid.UUID, lastError pgtype.Text) {
+	if err := m.store.RecordParticipantInvite(m.ctx, pgstore.RecordParticipantInviteParams{Error: lastError, ID: participantID}); err != nil {
+		m.logger.Error("Failed to record participant invite", zap.Error(err), zap.String("participant_id", participantID.String()))
+	}
+}
+
+// participantRecord is what email_log tells about an email of kind to a
+// participant of a trip.
+func participantRecord(participant pgstore.Participant, kind string) pgstore.InsertEmailLogParams {
+	return pgstore.InsertEmailLogParams{
+		TripID: participant.TripID,
+		ParticipantID: pgtype.UUID{Valid: true, Bytes: participant.ID},
+		Kind: kind,
+	}
+}
+
+func (m Mailer) SendConfirmTripEmailToTripOwner(tripID uuid.UUID) error {
+	trip, err := m.store.GetTrip(m.ctx, tripID)
+	if err != nil {
+		return fmt.Errorf("mailer: failed to get trip for SendConfirmTripEmailToTripOwner: %w", err)
+	}
+
+	message, err := m.newMessage(trip.Locale, trip.OwnerEmail, confirmTripOwnerTemplate, confirmTripOwnerData{
+		footerData: m.footer(trip.OwnerUnsubscribeToken),
+		OwnerName: trip.OwnerName,
+		Destination: trip.Destination,
+		StartsAt: formatEmailDate(trip.Locale, trip.StartsAt.Time),
+		EndsAt: formatEmailDate(trip.Locale, trip.EndsAt.Time),
+		ConfirmURL: m.linkURL("/trips/" + trip.ID.String() + "/confirm"),
+	})
+	if err != nil {
+		return fmt.Errorf("mailer: failed to render email for SendConfirmTripEmailToTripOwner: %w", err)
+	}
+
+	if err := m.deliver(message, "SendConfirmTripEmailToTripOwner", pgstore.InsertEmailLogParams{TripID: trip.ID, Kind: pgstore.EmailConfirmTripOwner}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SendItineraryEmail sends the owner of a trip its activities day by day and
+// its links, once the trip is confirmed.
+func (m Mailer) SendItineraryEmail(tripID uuid.UUID) error {
+	trip, err := m.store.GetTrip(m.ctx, tripID)
+	if err != nil {
+		return fmt.Errorf("mailer: failed to get trip for SendItineraryEmail: %w", err)
+	}
+
+	activities, err := m.store.GetTripActivities(m.ctx, pgstore.GetTripActivitiesParams{TripID: tripID})
+	if err != nil {
+		return fmt.Errorf("mailer: failed to get activities for SendItineraryEmail: %w", err)
+	}
+
+	links, err := m.store.ListTripLinks(m.ctx, pgstore.ListTripLinksParams{TripID: tripID})
+	if err != nil {
+		return fmt.Errorf("mailer: failed to get links for SendItineraryEmail: %w", err)
+	}
+
+	data := tripItineraryData{
+		footerData: m.footer(trip.OwnerUnsubscribeToken),
+		OwnerName: trip.OwnerName,
+		Destination: trip.Destination,
+		StartsAt: formatEmailDate(trip.Locale, trip.StartsAt.Time),
+		EndsAt: formatEmailDate(trip.Locale, trip.EndsAt.Time),
+	}
+	for _, day := range itinerary.GroupByDay(activities) {
+		itineraryDay := itineraryDay{Date: formatEmailDate(trip.Locale, day.Date)}
+		for _, activity := range day.Activities {
+			itineraryDay.Activities = append(itineraryDay.Activities, itineraryActivity{
+				Time: activity.OccursAt.Time.Format(emailTimeFormat),
+				Title: activity.Title,
+			})
+		}
+		data.Days = append(data.Days, itineraryDay)
+	}
+	for _, link := range links {
+		data.Links = append(data.Links, itineraryLink{Title: link.Title, URL: link.Url})
+	}
+
+	message, err := m.newMessage(trip.Locale, trip.OwnerEmail, tripItineraryTemplate, data)
+	if err != nil {
+		return fmt.Errorf("mailer: failed to render email for SendItineraryEmail: %w", err)
+	}
+
+	if err := m.deliver(message, "SendItineraryEmail", pgstore.InsertEmailLogParams{TripID: trip.ID, Kind: pgstore.EmailTripItinerary}); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SendConfirmTripEmailToTripParticipants invites every participant of a trip
+// not invited yet since its invitations were queued. A participant that can't
+// be emailed doesn't stop the others, the ones that failed are listed in a
+// *DeliveryError.
+func (m Mailer) SendConfirmTripEmailToTripParticipants(tripID uuid.UUID) error {
+	trip, err := m.store.GetTrip(m.ctx, tripID)
+	if err != nil {
+		return fmt.Errorf("mailer: failed to get trip for SendConfirmTripEmailToTripParticipants: %w", err)
+	}
+
+	participants, err := m.store.GetParticipants(m.ctx, tripID)
+	if err != nil {
+		return fmt.Errorf("mailer: failed to get participants for SendConfirmTripEmailToTripParticipants: %w", err)
+	}
+
+	// Sending the invitations again, like the outbox does after a crash,
+	// leaves out the participants already invited
+	var invitees []pgstore.Participant
+	for _, participant := range participants {
+		if !inviteSent(trip, participant) {
+			invitees = append(invitees, participant)
+		}
+	}
+
+	return m.deliverToParticipants(invitees, pgstore.EmailConfirmTripParticipant, "SendConfirmTripEmailToTripParticipants", func(participant pgstore.Participant) (Message, error) {
+		return m.confirmTripMessage(trip, participant, "SendConfirmTripEmailToTripParticipants")
+	})
+}
+
+// deliverToParticipants sends every participant the message rendered for them,
+// all rendered first then sent together, over one SMTP session with mailpit.
+// kind is the email recorded in email_log. A participant that can't be emailed
+// doesn't stop the others, the ones that failed are listed in a *DeliveryError.
+func (m Mailer) deliverToParticipants(participants []pgstore.Participant, kind, caller string, render func(pgstore.Participant) (Message, error)) error {
+	var (
+		failures []RecipientError
+		messages []Message
+		recipients []pgstore.Participant
+	)
+	for _, participant := range participants {
+		message, err := render(participant)
+		if err != nil {
+			failures = append(failures, RecipientError{ParticipantID: participant.ID, Email: participant.Email, Err: err})
+			continue
+		}
+
+		send, err := m.unsuppressed(message, caller, participantRecord(participant, kind))
+		if err != nil {
+			failures = append(failures, RecipientError{ParticipantID: participant.ID, Email: participant.Email, Err: err})
+			continue
+		}
+		if send {
+			messages = append(messages, message)
+			recipients = append(recipients, participant)
+		}
+	}
+
+	for i, err := range deliverBatch(m.ctx, m.deliverer, messages, m.config.Concurrency) {
+		participant := recipients[i]
+		if err := m.delivered(messages[i], caller, participantRecord(participant, kind), err); err != nil {
+			failures = append(failures, RecipientError{ParticipantID: participant.ID, Email: participant.Email, Err: err})
+		}
+	}
+
+	if len(failures) > 0 {
+		return &DeliveryError{Failures: failures}
+	}
+
+	return nil
+}
+
+// inviteSent reports whether participant was sent their invite since the
+// invitations of trip were last queued, the ones sent before being stale. They
+// are only queued anew when the trip is first confirmed, or confirmed again
+// after its destination or dates changed.
+func inviteSent(trip pgstore.Trip, participant pgstore.Participant) bool {
+	return participant.InviteSentAt.Valid && !participant.InviteSentAt.Time.Before(trip.InvitationsQueuedAt.Time)
+}
+
+func (m Mailer) SendConfirmTripEmailToTripParticipant(participantID uuid.UUID) error {
+	participant, err := m.store.GetParticipant(m.ctx, participantID)
+	if err != nil {
+		return fmt.Errorf("mailer: failed to get participant for SendConfirmTripEmailToTripParticipant: %w", err)
+	}
+
+	trip, err := m.store.GetTrip(m.ctx, participant.TripID)
+	if err != nil {
+		return fmt.Errorf("mailer: failed to get trip for SendConfirmTripEmailToTripParticipant: %w", err)
+	}
+
+	return m.sendConfirmTripEmail(trip, participant, "SendConfirmTripEmailToTripParticipant")
+}
+
+// sendConfirmTripEmail invites a participant of trip, the errors being
+// reported for caller.
+func (m Mailer) sendConfirmTripEmail(trip pgstore.Trip, participant pgstore.Participant, caller string) error {
+	message, err := m.confirmTripMessage(trip, participant, caller)
+	if err != nil {
+		return err
+	}
+
+	if err := m.deliver(message, caller, participantRecord(participant, pgstore.EmailConfirmTripParticipant)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// confirmTripMessage is the invite of a participant of trip, with the trip in
+// a calendar attachment.
+func (m Mailer) confirmTripMessage(trip pgstore.Trip, participant pgstore.Participant, caller string) (Message, error) {
+	message, err := m.newMessage(trip.Locale, participant.Email, confirmTripParticipantTemplate, confirmTripParticipantData{
+		footerData: m.footer(participant.UnsubscribeToken),
+		OwnerName: trip.OwnerName,
+		Destination: trip.Destination,
+		StartsAt: formatEmailDate(trip.Locale, trip.StartsAt.Time),
+		EndsAt: formatEmailDate(trip.Locale, trip.EndsAt.Time),
+		ConfirmURL: m.linkURL("/participants/" + participant.ID.String() + "/confirm"),
+	})
+	if err != nil {
+		return Message{}, fmt.Errorf("mailer: failed to render email for %s: %w", caller, err)
+	}
+	replyToOwner(&message, trip)
+
+	calendar, err := tripCalendar(trip, participant.Email)
+	if err != nil {
+		return Message{}, fmt.Errorf("mailer: failed to attach calendar to email for %s: %w", caller, err)
+	}
+	message.Attachments = append(message.Attachments, calendar)
+
+	return message, nil
+}
+
+func (m Mailer) SendInviteReminderEmailToTripParticipant(participantID uuid.UUID) error {
+	participant, err := m.store.GetParticipant(m.ctx, participantID)
+	if err != nil {
+		return fmt.Errorf("mailer: failed to get participant for SendInviteReminderEmailToTripParticipant: %w", err)
+	}
+
+	trip, err := m.store.GetTrip(m.ctx, participant.TripID)
+	if err != nil {
+		return fmt.Errorf("mailer: failed to get trip for SendInviteReminderEmailToTripParticipant: %w", err)
+	}
+
+	message, err := m.newMessage(trip.Locale, participant.Email, confirmTripParticipantTemplate, confirmTripParticipantData{
+		footerData: m.footer(participant.UnsubscribeToken),
+		OwnerName: trip.OwnerName,
+		Destination: trip.Destination,
+		StartsAt: formatEmailDate(trip.Locale, trip.StartsAt.Time),
+		EndsAt: formatEmailDate(trip.Locale, trip.EndsAt.Time),
+		ConfirmURL: m.linkURL("/participants/" + participant.ID.String() + "/confirm"),
+		Reminder: true,
+	})
+	if err != nil {
+		return fmt.Errorf("mailer: failed to render email for SendInviteReminderEmailToTripParticipant: %w", err)
+	}
+	replyToOwner(&message, trip)
+
+	if err := m.deliver(message, "SendInviteReminderEmailToTripParticipant", participantRecord(participant, pgstore.EmailInviteReminder)); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// SendTripCanceledEmail tells every participant of a trip it was cancelled.
+// A participant that can't be emailed doesn't stop the others, the ones that
+// failed are listed in a *DeliveryError.
+func (m Mailer) SendTripCanceledEmail(tripID uuid.UUID) error {
+	trip, err := m.store.GetTrip(m.ctx, tripID)
+	if err != nil {
+		return fmt.Errorf("mailer: failed to get trip for SendTripCanceledEmail: %w", err)
+	}
+
+	participants, err := m.store.GetParticipants(m.ctx, tripID)
+	if err != nil {
+		return fmt.Errorf("mailer: failed to get participants for SendTripCanceledEmail: %w", err)
+	}
+
+	return m.deliverToParticipants(participants, pgstore.EmailTripCancelled, "SendTripCanceledEmail", func(participant pgstore.Participant) (Message, error) {
+		return m.tripCancelledMessage(trip, participant, "SendTripCanceledEmail")
+	})
+}
+
+// SendTripCanceledEmailToTripParticipant tells a single participant their trip
+// was cancelled.
+func (m Mailer) SendTripCanceledEmailToTripParticipant(participantID uuid.UUID) error {
+	participant, err := m.store.GetParticipant(m.ctx, participantID)
+	if err != nil {
+		return fmt.Errorf("mailer: failed to get participant for SendTripCanceledEmailToTripParticipant: %w", err)
+	}
+
+	trip, err := m.store.GetTrip(m.ctx, participant.TripID)
+	if err != nil {
+		return fmt.Errorf("mailer: failed to get trip for SendTripCanceledEmailToTripParticipant: %w", err)
+	}
+
+	message, err := m.tripCancelledMessage(trip, participant, "SendTripCanceledEmailToTripParticipant")
+	if err != nil {
+		return err
+	}
+
+	return m.deliver(message, "SendTripCanceledEmailToTripParticipant", participantRecord(participant, pgstore.EmailTripCancelled))
+}
+
+// tripCancelledMessage tells a participant of trip it was cancelled.
+func (m Mailer) tripCancelledMessage(trip pgstore.Trip, participant pgstore.Participant, caller string) (Message, error) {
+	message, err := m.newMessage(trip.Locale, participant.Email, tripCancelledTemplate, tripCancelledData{
+		footerData: m.footer(participant.UnsubscribeToken),
+		OwnerName: trip.OwnerName,
+		Destination: trip.Destination,
+		StartsAt: formatEmailDate(trip.Locale, trip.StartsAt.Time),
+	})
+	if err != nil {
+		return Message{}, fmt.Errorf("mailer: failed to render email for %s: %w", caller, err)
+	}
+	replyToOwner(&message, trip)
+
+	return message, nil
+}
+
+// SendTripUpdatedEmail tells the confirmed participants of a trip what changes
+// did to it, the ones still to confirm reading the new details in their invite.
+// A participant that can't be emailed doesn't stop the others, the ones that
+// failed are listed in a *DeliveryError.
+func (m Mailer) SendTripUpdatedEmail(tripID uuid.UUID, changes pgstore.TripChanges) error {
+	trip, err := m.store.GetTrip(m.ctx, tripID)
+	if err != nil {
+		return fmt.Errorf("mailer: failed to get trip for SendTripUpdatedEmail: %w", err)
+	}
+
+	participants, err := m.store.GetParticipants(m.ctx, tripID)
+	if err != nil {
+		return fmt.Errorf("mailer: failed to get participants for SendTripUpdatedEmail: %w", err)
+	}
+
+	var confirmed []pgstore.Participant
+	for _, participant := range participants {
+		if participant.IsConfirmed {
+			confirmed = append(confirmed, participant)
+		}
+	}
+
+	return m.deliverToParticipants(confirmed, pgstore.EmailTripUpdated, "SendTripUpdatedEmail", func(participant pgstore.Participant) (Message, error) {
+		return m.tripUpdatedMessage(trip, participant, changes, "SendTripUpdatedEmail")
+	})
+}
+
+// SendTripUpdatedEmailToTripParticipant tells a single participant what
+// changes did to their trip.
+func (m Mailer) SendTripUpdatedEmailToTripParticipant(participantID uuid.UUID, changes pgstore.TripChanges) error {
+	participant, err := m.store.GetParticipant(m.ctx, participantID)
+	if err != nil {
+		return fmt.Errorf("mailer: failed to get participant for SendTripUpdatedEmailToTripParticipant: %w", err)
+	}
+
+	trip, err := m.store.GetTrip(m.ctx, participant.TripID)
+	if err != nil {
+		return fmt.Errorf("mailer: failed to get trip for SendTripUpdatedEmailToTripParticipant: %w", err)
+	}
+
+	message, err := m.tripUpdatedMessage(trip, participant, changes, "SendTripUpdatedEmailToTripParticipant")
+	if err != nil {
+		return err
+	}
+
+	return m.deliver(message, "SendTripUpdatedEmailToTripParticipant", participantRecord(participant, pgstore.EmailTripUpdated))
+}
+
+// tripUpdatedMessage tells a participant of trip what changes did to it.
+func (m Mailer) tripUpdatedMessage(trip pgstore.Trip, participant pgstore.Participant, changes pgstore.TripChanges, caller string) (Message, error) {
+	message, err := m.newMessage(trip.Locale, participant.Email, tripUpdatedTemplate, tripUpdatedData{
+		footerData: m.footer(participant.UnsubscribeToken),
+		OwnerName: trip.OwnerName,
+		Destination: changes.NewDestination,
+		DestinationChanged: changes.DestinationChanged(),
+		OldDestination: changes.OldDestination,
+		DatesChanged: changes.DatesChanged(),
+		OldStartsAt: formatEmailDate(trip.Locale, changes.OldStartsAt),
+		OldEndsAt: formatEmailDate(trip.Locale, changes.OldEndsAt),
+		StartsAt: formatEmailDate(trip.Locale, changes.NewStartsAt),
+		EndsAt: formatEmailDate(trip.Locale, changes.NewEndsAt),
+	})
+	if err != nil {
+		return Message{}, fmt.Errorf("mailer: failed to render email for %s: %w", caller, err)
+	}
+	replyToOwner(&message, trip)
+
+	return message, nil
+}
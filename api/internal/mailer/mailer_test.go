@@ -0,0 +1,206 @@
+package mailer_test
+
+import (
+	"context"
+	"errors"
+	"journey/internal/api/spec"
+	"journey/internal/mailer"
+	"journey/internal/memstore"
+	"journey/internal/pgstore"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/discord-gophers/goapi-gen/types"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// outbox keeps the emails sent instead of sending them, failing the ones to
+// the addresses in fail.
+type outbox struct {
+	mu       sync.Mutex
+	fail     map[string]bool
+	messages []mailer.Message
+}
+
+func (o *outbox) Deliver(ctx context.Context, message mailer.Message) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if o.fail[message.To] {
+		return errors.New("mailbox unavailable")
+	}
+	o.messages = append(o.messages, message)
+	return nil
+}
+
+// newMailer is a mailer sending to sent the emails about the trips of a new
+// store.
+func newMailer(t *testing.T, sent *outbox) (mailer.Mailer, *memstore.Store) {
+	t.Helper()
+
+	store := memstore.New()
+	config := mailer.DefaultConfig()
+	config.PublicURL = "http://journey.test"
+	mail, err := mailer.NewMailer(context.Background(), store, zap.NewNop(), config, sent)
+	if err != nil {
+		t.Fatalf("NewMailer: %v", err)
+	}
+	return mail, store
+}
+
+// createTrip creates a trip on store inviting emails.
+func createTrip(t *testing.T, store *memstore.Store, locale string, emails ...string) uuid.UUID {
+	t.Helper()
+
+	invites := make([]types.Email, len(emails))
+	for i, email := range emails {
+		invites[i] = types.Email(email)
+	}
+	request := spec.CreateTripRequest{
+		Destination:    "Lisbon",
+		OwnerEmail:     "owner@example.com",
+		OwnerName:      "Owner",
+		EmailsToInvite: invites,
+		StartsAt:       time.Date(2030, time.March, 10, 9, 0, 0, 0, time.UTC),
+		EndsAt:         time.Date(2030, time.March, 15, 18, 0, 0, 0, time.UTC),
+	}
+	if locale != "" {
+		request.Locale = &spec.CreateTripRequestLocale{}
+		if err := request.Locale.FromValue(locale); err != nil {
+			t.Fatalf("CreateTripRequestLocale.FromValue(%s): %v", locale, err)
+		}
+	}
+	tripID, err := store.CreateTrip(context.Background(), request)
+	if err != nil {
+		t.Fatalf("CreateTrip: %v", err)
+	}
+	return tripID
+}
+
+// TestSendTripCanceledEmailPartialFailure checks a participant that can't be
+// emailed doesn't stop the others, and is the only one reported as failed.
+func TestSendTripCanceledEmailPartialFailure(t *testing.T) {
+	sent := &outbox{fail: map[string]bool{"bruno@example.com": true}}
+	mail, store := newMailer(t, sent)
+	tripID := createTrip(t, store, "", "ana@example.com", "bruno@example.com", "carla@example.com")
+
+	err := mail.SendTripCanceledEmail(tripID)
+	deliveryErr, ok := mailer.AsDeliveryError(err)
+	if !ok {
+		t.Fatalf("SendTripCanceledEmail = %v, want a *DeliveryError", err)
+	}
+	if len(deliveryErr.Failures) != 1 || deliveryErr.Failures[0].Email != "bruno@example.com" {
+		t.Errorf("failures = %v, want bruno@example.com alone", deliveryErr.Failures)
+	}
+	if len(sent.messages) != 2 {
+		t.Errorf("sent %d emails, want the 2 to the others", len(sent.messages))
+	}
+}
+
+// TestSendTripUpdatedEmailPartialFailure checks the confirmed participants are
+// emailed even when one of them can't be, the only one reported as failed.
+func TestSendTripUpdatedEmailPartialFailure(t *testing.T) {
+	ctx := context.Background()
+	sent := &outbox{fail: map[string]bool{"bruno@example.com": true}}
+	mail, store := newMailer(t, sent)
+	tripID := createTrip(t, store, "", "ana@example.com", "bruno@example.com", "carla@example.com")
+	participants, err := store.GetParticipants(ctx, tripID)
+	if err != nil {
+		t.Fatalf("GetParticipants: %v", err)
+	}
+	for _, participant := range participants {
+		if participant.Email == "carla@example.com" {
+			continue
+		}
+		if err := store.ConfirmParticipant(ctx, participant.ID); err != nil {
+			t.Fatalf("ConfirmParticipant: %v", err)
+		}
+	}
+
+	err = mail.SendTripUpdatedEmail(tripID, pgstore.TripChanges{OldDestination: "Lisbon", NewDestination: "Porto"})
+	deliveryErr, ok := mailer.AsDeliveryError(err)
+	if !ok {
+		t.Fatalf("SendTripUpdatedEmail = %v, want a *DeliveryError", err)
+	}
+	if len(deliveryErr.Failures) != 1 || deliveryErr.Failures[0].Email != "bruno@example.com" {
+		t.Errorf("failures = %v, want bruno@example.com alone", deliveryErr.Failures)
+	}
+	if len(sent.messages) != 1 || sent.messages[0].To != "ana@example.com" {
+		t.Errorf("sent %d emails, want the one to ana@example.com, the only other confirmed participant", len(sent.messages))
+	}
+}
+
+// TestConfirmTripAgain checks confirming a trip, unconfirming it and confirming
+// it again doesn't invite its participants twice.
+func TestConfirmTripAgain(t *testing.T) {
+	ctx := context.Background()
+	sent := &outbox{}
+	mail, store := newMailer(t, sent)
+	tripID := createTrip(t, store, "", "ana@example.com", "bruno@example.com")
+
+	confirm := func() {
+		t.Helper()
+		if _, err := store.ConfirmTripAndInvite(ctx, tripID); err != nil {
+			t.Fatalf("ConfirmTripAndInvite: %v", err)
+		}
+		if err := mail.SendConfirmTripEmailToTripParticipants(tripID); err != nil {
+			t.Fatalf("SendConfirmTripEmailToTripParticipants: %v", err)
+		}
+	}
+
+	confirm()
+	if len(sent.messages) != 2 {
+		t.Fatalf("confirming sent %d emails, want the 2 invites", len(sent.messages))
+	}
+
+	trip, err := store.GetTrip(ctx, tripID)
+	if err != nil {
+		t.Fatalf("GetTrip: %v", err)
+	}
+	if _, err := store.UpdateTrip(ctx, pgstore.UpdateTripParams{
+		ID:          tripID,
+		Destination: trip.Destination,
+		StartsAt:    trip.StartsAt,
+		EndsAt:      trip.EndsAt,
+	}); err != nil {
+		t.Fatalf("UpdateTrip: %v", err)
+	}
+	confirm()
+	if len(sent.messages) != 2 {
+		t.Errorf("confirming again sent %d emails in all, want the 2 first invites alone", len(sent.messages))
+	}
+}
+
+// TestEmailLocale checks the emails about a trip are written in its locale,
+// locales.Default when it has none.
+func TestEmailLocale(t *testing.T) {
+	tests := []struct {
+		locale      string
+		wantSubject string
+		wantDate    string
+	}{
+		{"", "Confirme sua viagem", "10/03/2030"},
+		{"pt-BR", "Confirme sua viagem", "10/03/2030"},
+		{"en", "Confirm your trip", "March 10, 2030"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.locale, func(t *testing.T) {
+			sent := &outbox{}
+			mail, store := newMailer(t, sent)
+			tripID := createTrip(t, store, tt.locale)
+
+			if err := mail.SendConfirmTripEmailToTripOwner(tripID); err != nil {
+				t.Fatalf("SendConfirmTripEmailToTripOwner: %v", err)
+			}
+			if len(sent.messages) != 1 {
+				t.Fatalf("%d emails sent, want 1", len(sent.messages))
+			}
+			message := sent.messages[0]
+			if message.Subject != tt.wantSubject || !strings.Contains(message.Text, tt.wantDate) {
+				t.Errorf("email = %q with the text\n%s\nwant %q with the date %s", message.Subject, message.Text, tt.wantSubject, tt.wantDate)
+			}
+		})
+	}
+}
@@ -1,108 +1,313 @@
 package mailpit
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
-	"journey/internal/pgstore"
+	"math/rand/v2"
+	"journey/internal/mailer"
+	"net"
+	netmail "net/mail"
+	"net/textproto"
+	"strings"
 	"time"
 
-	"github.com/google/uuid"
-	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/wneessen/go-mail"
 )
 
-type store interface {
-	GetTrip(context.Context, uuid.UUID) (pgstore.Trip, error)
-	GetParticipants(context.Context, uuid.UUID) ([]pgstore.Participant, error)
+// MailpitConfig is the SMTP server the emails are sent through.
+type MailpitConfig struct {
+	Host string
+	Port int
+	// Username and Password are only sent when Username is set, so mailpit
+	// works without credentials.
+	Username string
+	Password string
+	// AuthMechanism is PLAIN, LOGIN or CRAM-MD5, PLAIN when empty.
+	AuthMechanism string
+	// TLSPolicy is none, opportunistic or mandatory.
+	TLSPolicy string
+	// RetryAttempts is how many times an email is tried before giving up on
+	// a transient failure, RetryBaseDelay the wait before the second attempt,
+	// doubled before every following one.
+	RetryAttempts int
+	RetryBaseDelay time.Duration
 }
 
-type Mailpit struct {
-	store store
+// smtpAuthMechanisms are the AuthMechanism values the mailer supports.
+var smtpAuthMechanisms = map[string]mail.SMTPAuthType{
+	"PLAIN": mail.SMTPAuthPlain,
+	"LOGIN": mail.SMTPAuthLogin,
+	"CRAM-MD5": mail.SMTPAuthCramMD5,
 }
 
-func NewMailpit(pool *pgxpool.Pool) Mailpit {
-	return Mailpit{pgstore.New(pool)}
+// tlsPolicies are the TLSPolicy values the mailer supports.
+var tlsPolicies = map[string]mail.TLSPolicy{
+	"none": mail.NoTLS,
+	"opportunistic": mail.TLSOpportunistic,
+	"mandatory": mail.TLSMandatory,
 }
 
-func (mp Mailpit) SendConfirmTripEmailToTripOwner(tripID uuid.UUID) error {
-	ctx := context.Background()
-	trip, err := mp.store.GetTrip(ctx, tripID)
-	if err != nil {
-		return fmt.Errorf("mailpit: failed to get trip for SendConfirmTripEmailToTripOwner: %w", err)
+// DefaultMailpitConfig sends the emails through the mailpit container of the
+// docker-compose setup.
+func DefaultMailpitConfig() MailpitConfig {
+	return MailpitConfig{
+		Host: "mailpit",
+		Port: 1025,
+		TLSPolicy: "none",
+		RetryAttempts: 3,
+		RetryBaseDelay: time.Second,
 	}
+}
 
-	msg := mail.NewMsg()
-	if err := msg.From("mailpit@journey.com"); err != nil {
-		return fmt.Errorf("mailpit: failed to set From in email for SendConfirmTripEmailToTripOwner: %w", err)
+// Validate reports the settings that would make every email fail to send.
+func (c MailpitConfig) Validate() error {
+	if c.Host == "" {
+		return errors.New("mailpit: host must not be empty")
 	}
 
-	if err := msg.To(trip.OwnerEmail); err != nil {
-		return fmt.Errorf("mailpit: failed to set To in email for SendConfirmTripEmailToTripOwner: %w", err)
+	if c.Port < 1 || c.Port > 65535 {
+		return fmt.Errorf("mailpit: port %d must be between 1 and 65535", c.Port)
 	}
 
-	msg.Subject("Confirm your trip");
+	if _, ok := tlsPolicies[c.TLSPolicy]; !ok {
+		return fmt.Errorf("mailpit: TLS policy %q must be none, opportunistic or mandatory", c.TLSPolicy)
+	}
 
-	msg.SetBodyString(mail.TypeTextPlain, fmt.Sprintf(`
-		Olá, %s!
-		
-		A sua Viagem para %s que começa em %s precisa ser confirmada.
-		Clique no botão abaixo para confirmar.
-	`, trip.OwnerName, trip.Destination, trip.StartsAt.Time.Format(time.DateOnly),
-	))
+	if c.AuthMechanism != "" {
+		if _, ok := smtpAuthMechanisms[c.AuthMechanism]; !ok {
+			return fmt.Errorf("mailpit: auth mechanism %q must be PLAIN, LOGIN or CRAM-MD5", c.AuthMechanism)
+		}
+		if c.Username == "" {
+			return errors.New("mailpit: an auth mechanism needs a username")
+		}
+	}
 
-	client, err := mail.NewClient("mailpit", mail.WithTLSPortPolicy(mail.NoTLS), mail.WithPort(1025))
-	if err != nil {
-		return fmt.Errorf("mailpit: failed to create email client for SendConfirmTripEmailToTripOwner: %w", err)
+	if c.RetryAttempts < 1 {
+		return fmt.Errorf("mailpit: retry attempts %d must be at least 1", c.RetryAttempts)
 	}
 
-	if err := client.DialAndSend(msg); err != nil {
-		return fmt.Errorf("mailpit: failed to send email for SendConfirmTripEmailToTripOwner: %w", err)
+	if c.RetryBaseDelay < 0 {
+		return fmt.Errorf("mailpit: retry base delay %s must not be negative", c.RetryBaseDelay)
 	}
 
 	return nil
 }
 
-func (mp Mailpit) SendConfirmTripEmailToTripParticipants(tripID uuid.UUID) error {
-	ctx := context.Background()
-	trip, err := mp.store.GetTrip(ctx, tripID)
-	if err != nil {
-		return fmt.Errorf("mailpit: failed to get trip for SendConfirmTripEmailToTripParticipants: %w", err)
+// authMechanism is the mechanism used to authenticate as Username.
+func (c MailpitConfig) authMechanism() string {
+	if c.AuthMechanism == "" {
+		return "PLAIN"
+	}
+	return c.AuthMechanism
+}
+
+// Mailpit delivers the emails over SMTP, to mailpit in development.
+type Mailpit struct {
+	config MailpitConfig
+}
+
+func NewMailpit(config MailpitConfig) Mailpit {
+	return Mailpit{config}
+}
+
+// Deliver sends message as a multipart/alternative email, the plain text first
+// and the HTML the mail clients prefer last.
+func (mp Mailpit) Deliver(ctx context.Context, message mailer.Message) error {
+	return mp.DeliverBatch(ctx, []mailer.Message{message})[0]
+}
+
+// DeliverBatch sends messages over a single SMTP session, dialed once for all
+// of them. The error of each message is the server's answer to it, or the
+// failure to reach the server at all.
+func (mp Mailpit) DeliverBatch(ctx context.Context, messages []mailer.Message) []error {
+	errs := make([]error, len(messages))
+
+	var (
+		msgs []*mail.Msg
+		indexes []int
+	)
+	for i, message := range messages {
+		msg, err := newMsg(message)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+		msgs = append(msgs, msg)
+		indexes = append(indexes, i)
+	}
+	if len(msgs) == 0 {
+		return errs
 	}
 
-	participants, err := mp.store.GetParticipants(ctx, tripID)
+	client, err := mp.newClient()
 	if err != nil {
-		return fmt.Errorf("mailpit: failed to get participants for SendConfirmTripEmailToTripParticipants: %w", err)
+		for _, i := range indexes {
+			errs[i] = fmt.Errorf("mailpit: failed to create email client: %w", err)
+		}
+		return errs
 	}
 
-	for _, participant := range participants {
-		msg := mail.NewMsg()
-		if err := msg.From("mailpit@journey.com"); err != nil {
-			return fmt.Errorf("mailpit: failed to set From in email for SendConfirmTripEmailToTripParticipants: %w", err)
+	err = mp.dialAndSend(ctx, client, msgs)
+	for j, msg := range msgs {
+		switch {
+		case msg.IsDelivered():
+		case msg.HasSendError():
+			errs[indexes[j]] = msg.SendError()
+		default:
+			errs[indexes[j]] = err
 		}
+	}
 
-		if err := msg.To(participant.Email); err != nil {
-			return fmt.Errorf("mailpit: failed to set To in email for SendConfirmTripEmailToTripParticipants: %w", err)
+	return errs
+}
+
+// newMsg is message as go-mail builds it.
+func newMsg(message mailer.Message) (*mail.Msg, error) {
+	msg := mail.NewMsg()
+	if err := setFrom(msg, message); err != nil {
+		return nil, fmt.Errorf("mailpit: failed to set From: %w", err)
+	}
+
+	if message.ReplyToAddress != "" {
+		if err := msg.ReplyTo(formatAddress(message.ReplyToName, message.ReplyToAddress)); err != nil {
+			return nil, fmt.Errorf("mailpit: failed to set Reply-To: %w", err)
 		}
+	}
 
-		msg.Subject("Confirm your trip");
+	if err := msg.To(message.To); err != nil {
+		return nil, fmt.Errorf("mailpit: failed to set To: %w", err)
+	}
 
-		msg.SetBodyString(mail.TypeTextPlain, fmt.Sprintf(`
-			Olá!
-			
-			A sua Viagem com %s para %s que começa em %s precisa de sua confirmação.
-			Clique no botão abaixo e confirme sua presença.
-		`, trip.OwnerName, trip.Destination, trip.StartsAt.Time.Format(time.DateOnly),
-		))
+	msg.Subject(message.Subject)
+	msg.SetBodyString(mail.TypeTextPlain, message.Text)
+	msg.AddAlternativeString(mail.TypeTextHTML, message.HTML)
 
-		client, err := mail.NewClient("mailpit", mail.WithTLSPortPolicy(mail.NoTLS), mail.WithPort(1025))
-		if err != nil {
-			return fmt.Errorf("mailpit: failed to create email client for SendConfirmTripEmailToTripParticipants: %w", err)
+	for _, attachment := range message.Attachments {
+		if err := msg.AttachReader(attachment.Filename, bytes.NewReader(attachment.Content), mail.WithFileContentType(mail.ContentType(attachment.ContentType))); err != nil {
+			return nil, fmt.Errorf("mailpit: failed to attach %s: %w", attachment.Filename, err)
 		}
+	}
+
+	return msg, nil
+}
+
+// setFrom sets the sender of message on msg, with its display name if any.
+func setFrom(msg *mail.Msg, message mailer.Message) error {
+	return msg.From(formatAddress(message.FromName, message.FromAddress))
+}
 
-		if err := client.DialAndSend(msg); err != nil {
-			return fmt.Errorf("mailpit: failed to send email for SendConfirmTripEmailToTripParticipants: %w", err)
+// formatAddress is address with its display name, if any, quoted and encoded
+// as RFC 5322 requires. go-mail's *Format methods would put a name holding
+// quotes in the header as is.
+func formatAddress(name, address string) string {
+	if name == "" {
+		return address
+	}
+	return (&netmail.Address{Name: name, Address: address}).String()
+}
+
+func (mp Mailpit) newClient() (*mail.Client, error) {
+	// The port is set explicitly, WithTLSPortPolicy would replace it
+	opts := []mail.Option{mail.WithTLSPolicy(tlsPolicies[mp.config.TLSPolicy]), mail.WithPort(mp.config.Port)}
+	if mp.config.Username != "" {
+		opts = append(opts,
+			mail.WithSMTPAuth(smtpAuthMechanisms[mp.config.authMechanism()]),
+			mail.WithUsername(mp.config.Username),
+			mail.WithPassword(mp.config.Password),
+		)
+	}
+
+	return mail.NewClient(mp.config.Host, opts...)
+}
+
+// dialAndSend sends msgs through client in one session, naming the
+// credentials in use when the server rejects them. The messages that fail
+// transiently are sent again, in a new session, up to RetryAttempts times with
+// an exponential backoff until ctx is done. Whether each message went out is
+// left on it.
+func (mp Mailpit) dialAndSend(ctx context.Context, client *mail.Client, msgs []*mail.Msg) error {
+	var err error
+	pending := msgs
+	attempt := 1
+	for ; ; attempt++ {
+		err = client.DialAndSendWithContext(ctx, pending...)
+		if err == nil {
+			return nil
+		}
+		if mp.config.Username != "" && strings.Contains(err.Error(), "AUTH") {
+			err = fmt.Errorf("authenticating as %q with %s on %s:%d: %w", mp.config.Username, mp.config.authMechanism(), mp.config.Host, mp.config.Port, err)
+			break
+		}
+		pending = retryable(pending, err)
+		if attempt >= mp.config.RetryAttempts || len(pending) == 0 {
+			break
+		}
+
+		timer := time.NewTimer(mp.retryDelay(attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("gave up after %d attempts on shutdown: %w", attempt, err)
+		case <-timer.C:
 		}
 	}
 
-	return nil
-}
\ No newline at end of file
+	if attempt == 1 {
+		return err
+	}
+	return fmt.Errorf("gave up after %d attempts: %w", attempt, err)
+}
+
+// retryable are the msgs that weren't delivered but may be by sending them
+// again: the server answered them with a 4xx code, or err kept them from
+// being sent at all and is transient.
+func retryable(msgs []*mail.Msg, err error) []*mail.Msg {
+	var pending []*mail.Msg
+	for _, msg := range msgs {
+		switch {
+		case msg.IsDelivered():
+		case msg.HasSendError():
+			if msg.SendErrorIsTemp() {
+				pending = append(pending, msg)
+			}
+		case isTransient(err):
+			pending = append(pending, msg)
+		}
+	}
+	return pending
+}
+
+// retryDelay is the wait after the given failed attempt, RetryBaseDelay
+// doubled for every previous attempt, with up to half of it taken off at
+// random so the retries of a batch of emails don't hit the server together.
+func (mp Mailpit) retryDelay(attempt int) time.Duration {
+	delay := mp.config.RetryBaseDelay << (attempt - 1)
+	if delay <= 0 {
+		return 0
+	}
+	return delay - rand.N(delay/2+1)
+}
+
+// isTransient reports whether sending again may succeed: the server could not
+// be reached or answered with a 4xx code. A rejected recipient or message is
+// permanent.
+func isTransient(err error) bool {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var sendErr *mail.SendError
+	if errors.As(err, &sendErr) {
+		return sendErr.IsTemp()
+	}
+
+	var protoErr *textproto.Error
+	if errors.As(err, &protoErr) {
+		return protoErr.Code >= 400 && protoErr.Code < 500
+	}
+
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, mail.ErrNoActiveConnection)
+}
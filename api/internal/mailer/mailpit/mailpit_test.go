@@ -0,0 +1,351 @@
+package mailpit_test
+
+import (
+	"context"
+	"encoding/base64"
+	"io"
+	"journey/internal/mailer"
+	"journey/internal/mailer/mailpit"
+	"mime"
+	"mime/multipart"
+	"net"
+	netmail "net/mail"
+	"net/textproto"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// smtpServer is an SMTP server answering the RCPT of each recipient with the
+// replies queued for it, then accepting it. The messages it accepts are kept.
+type smtpServer struct {
+	listener net.Listener
+
+	mu       sync.Mutex
+	replies  map[string][]string
+	rcpts    map[string]int
+	messages []string
+}
+
+// newSMTPServer starts an smtpServer, which is stopped at the end of the test.
+func newSMTPServer(t *testing.T, replies map[string][]string) *smtpServer {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("net.Listen: %v", err)
+	}
+	s := &smtpServer{listener: listener, replies: replies, rcpts: make(map[string]int)}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.serve(conn)
+		}
+	}()
+
+	return s
+}
+
+// config sends the emails to s, retrying them without waiting.
+func (s *smtpServer) config() mailpit.MailpitConfig {
+	config := mailpit.DefaultMailpitConfig()
+	config.Host = "127.0.0.1"
+	config.Port = s.listener.Addr().(*net.TCPAddr).Port
+	config.RetryBaseDelay = 0
+	return config
+}
+
+func (s *smtpServer) serve(conn net.Conn) {
+	defer conn.Close()
+	tp := textproto.NewConn(conn)
+	tp.PrintfLine("220 localhost ESMTP")
+
+	accepted := 0
+	for {
+		line, err := tp.ReadLine()
+		if err != nil {
+			return
+		}
+		verb, arg, _ := strings.Cut(line, " ")
+		switch strings.ToUpper(verb) {
+		case "EHLO", "HELO":
+			tp.PrintfLine("250 localhost")
+		case "MAIL":
+			accepted = 0
+			tp.PrintfLine("250 2.1.0 OK")
+		case "RCPT":
+			if reply := s.rcpt(arg); reply != "" {
+				tp.PrintfLine("%s", reply)
+				continue
+			}
+			accepted++
+			tp.PrintfLine("250 2.1.5 OK")
+		case "DATA":
+			if accepted == 0 {
+				tp.PrintfLine("554 5.5.1 no valid recipients")
+				continue
+			}
+			tp.PrintfLine("354 end data with <CR><LF>.<CR><LF>")
+			data, err := tp.ReadDotBytes()
+			if err != nil {
+				return
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, string(data))
+			s.mu.Unlock()
+			tp.PrintfLine("250 2.0.0 OK")
+		case "RSET", "NOOP":
+			tp.PrintfLine("250 2.0.0 OK")
+		case "QUIT":
+			tp.PrintfLine("221 2.0.0 bye")
+			return
+		default:
+			tp.PrintfLine("502 5.5.2 command not implemented")
+		}
+	}
+}
+
+// rcpt counts an RCPT TO:<address> and returns the reply queued for address,
+// or "" to accept it.
+func (s *smtpServer) rcpt(arg string) string {
+	_, address, _ := strings.Cut(arg, "<")
+	address, _, _ = strings.Cut(address, ">")
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.rcpts[address]++
+	replies := s.replies[address]
+	if len(replies) == 0 {
+		return ""
+	}
+	s.replies[address] = replies[1:]
+	return replies[0]
+}
+
+// attempts is how many times address was sent to.
+func (s *smtpServer) attempts(address string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rcpts[address]
+}
+
+// delivered is how many messages were accepted.
+func (s *smtpServer) delivered() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.messages)
+}
+
+// received is the raw content of the messages accepted.
+func (s *smtpServer) received() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return slices.Clone(s.messages)
+}
+
+func message(to string) mailer.Message {
+	return mailer.Message{
+		FromAddress: "journey@example.com",
+		FromName:    "Journey",
+		To:          to,
+		Subject:     "Confirm your trip",
+		Text:        "Confirm your trip to Lisbon.",
+		HTML:        "<p>Confirm your trip to Lisbon.</p>",
+	}
+}
+
+// TestDeliverRetries checks a message the server answers with a 4xx code is
+// sent again, up to RetryAttempts times, and one answered with a 5xx code
+// isn't.
+func TestDeliverRetries(t *testing.T) {
+	const busy = "451 4.3.0 mailbox busy, try again later"
+	const unknown = "550 5.1.1 no such user"
+
+	tests := []struct {
+		name         string
+		replies      []string
+		wantErr      bool
+		wantAttempts int
+	}{
+		{"accepted", nil, false, 1},
+		{"temporary failure", []string{busy}, false, 2},
+		{"temporary failures up to the last attempt", []string{busy, busy}, false, 3},
+		{"temporary failures on every attempt", []string{busy, busy, busy}, true, 3},
+		{"permanent failure", []string{unknown}, true, 1},
+		{"temporary then permanent failure", []string{busy, unknown}, true, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			const to = "ana@example.com"
+			server := newSMTPServer(t, map[string][]string{to: tt.replies})
+
+			err := mailpit.NewMailpit(server.config()).Deliver(context.Background(), message(to))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Deliver = %v, want error %v", err, tt.wantErr)
+			}
+			if attempts := server.attempts(to); attempts != tt.wantAttempts {
+				t.Errorf("sent %d times, want %d", attempts, tt.wantAttempts)
+			}
+			wantDelivered := 1
+			if tt.wantErr {
+				wantDelivered = 0
+			}
+			if server.delivered() != wantDelivered {
+				t.Errorf("%d messages delivered, want %d", server.delivered(), wantDelivered)
+			}
+		})
+	}
+}
+
+// TestDeliverBatchRetries checks only the messages of a batch that failed
+// temporarily are sent again.
+func TestDeliverBatchRetries(t *testing.T) {
+	server := newSMTPServer(t, map[string][]string{
+		"busy@example.com":    {"451 4.3.0 mailbox busy, try again later"},
+		"unknown@example.com": {"550 5.1.1 no such user"},
+	})
+
+	recipients := []string{"ana@example.com", "busy@example.com", "unknown@example.com"}
+	messages := make([]mailer.Message, len(recipients))
+	for i, to := range recipients {
+		messages[i] = message(to)
+	}
+
+	errs := mailpit.NewMailpit(server.config()).DeliverBatch(context.Background(), messages)
+	for i, wantErr := range []bool{false, false, true} {
+		if (errs[i] != nil) != wantErr {
+			t.Errorf("error of the message to %s = %v, want error %v", recipients[i], errs[i], wantErr)
+		}
+	}
+	for i, want := range []int{1, 2, 1} {
+		if attempts := server.attempts(recipients[i]); attempts != want {
+			t.Errorf("sent to %s %d times, want %d", recipients[i], attempts, want)
+		}
+	}
+	if server.delivered() != 2 {
+		t.Errorf("%d messages delivered, want 2", server.delivered())
+	}
+}
+
+// part is a leaf of a MIME message, its body decoded.
+type part struct {
+	mediaType string
+	filename  string
+	body      string
+}
+
+// parts walks the MIME tree of an entity with the given header and body,
+// returning the media type of every multipart met, depth first, and its leaves.
+func parts(t *testing.T, header textproto.MIMEHeader, body io.Reader) ([]string, []part) {
+	t.Helper()
+
+	mediaType, params, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		t.Fatalf("parsing Content-Type %q: %v", header.Get("Content-Type"), err)
+	}
+
+	if !strings.HasPrefix(mediaType, "multipart/") {
+		if header.Get("Content-Transfer-Encoding") == "base64" {
+			body = base64.NewDecoder(base64.StdEncoding, body)
+		}
+		content, err := io.ReadAll(body)
+		if err != nil {
+			t.Fatalf("reading a %s part: %v", mediaType, err)
+		}
+		_, disposition, _ := mime.ParseMediaType(header.Get("Content-Disposition"))
+		return nil, []part{{mediaType, disposition["filename"], string(content)}}
+	}
+
+	multiparts := []string{mediaType}
+	var leaves []part
+	reader := multipart.NewReader(body, params["boundary"])
+	for {
+		p, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("reading a part of a %s: %v", mediaType, err)
+		}
+		m, l := parts(t, p.Header, p)
+		multiparts = append(multiparts, m...)
+		leaves = append(leaves, l...)
+	}
+	return multiparts, leaves
+}
+
+// TestDeliverMultipart checks an email is sent as a multipart/alternative with
+// the plain text first and the HTML last, inside a multipart/mixed when it has
+// attachments.
+func TestDeliverMultipart(t *testing.T) {
+	calendar := mailer.Attachment{Filename: "lisbon.ics", ContentType: "text/calendar", Content: []byte("BEGIN:VCALENDAR\r\nEND:VCALENDAR\r\n")}
+
+	tests := []struct {
+		name           string
+		attachments    []mailer.Attachment
+		wantMultiparts []string
+		wantParts      []part
+	}{
+		{
+			"text and HTML",
+			nil,
+			[]string{"multipart/alternative"},
+			[]part{
+				{mediaType: "text/plain", body: "Confirm your trip to Lisbon."},
+				{mediaType: "text/html", body: "<p>Confirm your trip to Lisbon.</p>"},
+			},
+		},
+		{
+			"with an attachment",
+			[]mailer.Attachment{calendar},
+			[]string{"multipart/mixed", "multipart/alternative"},
+			[]part{
+				{mediaType: "text/plain", body: "Confirm your trip to Lisbon."},
+				{mediaType: "text/html", body: "<p>Confirm your trip to Lisbon.</p>"},
+				{mediaType: "text/calendar", filename: "lisbon.ics", body: string(calendar.Content)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newSMTPServer(t, nil)
+			sent := message("ana@example.com")
+			sent.Attachments = tt.attachments
+			if err := mailpit.NewMailpit(server.config()).Deliver(context.Background(), sent); err != nil {
+				t.Fatalf("Deliver: %v", err)
+			}
+
+			received := server.received()
+			if len(received) != 1 {
+				t.Fatalf("%d messages delivered, want 1", len(received))
+			}
+			msg, err := netmail.ReadMessage(strings.NewReader(received[0]))
+			if err != nil {
+				t.Fatalf("netmail.ReadMessage: %v", err)
+			}
+
+			multiparts, leaves := parts(t, textproto.MIMEHeader(msg.Header), msg.Body)
+			if !slices.Equal(multiparts, tt.wantMultiparts) {
+				t.Errorf("multiparts = %v, want %v", multiparts, tt.wantMultiparts)
+			}
+			if len(leaves) != len(tt.wantParts) {
+				t.Fatalf("parts = %+v, want %+v", leaves, tt.wantParts)
+			}
+			for i, want := range tt.wantParts {
+				got := leaves[i]
+				got.body = strings.TrimRight(got.body, "\r\n")
+				want.body = strings.TrimRight(want.body, "\r\n")
+				if got != want {
+					t.Errorf("part %d = %+v, want %+v", i, got, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package mailer
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"sync"
+	"time"
+)
+
+// The delivery metrics are published through expvar under "mailer", keyed by
+// the type of email and the provider it went through:
+//
+//	emails_sent_total      successful deliveries
+//	emails_failed_total    deliveries the provider refused or never got
+//	send_duration_seconds  histogram of how long each delivery took
+var (
+	metrics = expvar.NewMap("mailer")
+	emailsSent = new(expvar.Map)
+	emailsFailed = new(expvar.Map)
+	sendDuration = new(expvar.Map)
+
+	// sendDurationMu keeps two first deliveries of a kind from both creating
+	// its histogram.
+	sendDurationMu sync.Mutex
+)
+
+// sendDurationBuckets are the upper bounds, in seconds, of the send duration
+// histogram.
+var sendDurationBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+func init() {
+	metrics.Set("emails_sent_total", emailsSent)
+	metrics.Set("emails_failed_total", emailsFailed)
+	metrics.Set("send_duration_seconds", sendDuration)
+}
+
+// instrumentedDeliverer counts every email its deliverer is handed, whichever
+// provider it is.
+type instrumentedDeliverer struct {
+	provider string
+	deliverer Deliverer
+}
+
+// NewInstrumentedDeliverer wraps deliverer so its deliveries are counted and
+// timed under provider. A BatchDeliverer stays one.
+func NewInstrumentedDeliverer(provider string, deliverer Deliverer) Deliverer {
+	if batch, ok := deliverer.(BatchDeliverer); ok {
+		return instrumentedBatchDeliverer{instrumentedDeliverer{provider, deliverer}, batch}
+	}
+	return instrumentedDeliverer{provider, deliverer}
+}
+
+func (d instrumentedDeliverer) Deliver(ctx context.Context, message Message) error {
+	start := time.Now()
+	err := d.deliverer.Deliver(ctx, message)
+	observeDelivery(message.Template, d.provider, time.Since(start), err)
+
+	return err
+}
+
+type instrumentedBatchDeliverer struct {
+	instrumentedDeliverer
+	batch BatchDeliverer
+}
+
+// DeliverBatch times the batch as a whole, each of its messages being counted
+// as taking an even share of it.
+func (d instrumentedBatchDeliverer) DeliverBatch(ctx context.Context, messages []Message) []error {
+	start := time.Now()
+	errs := d.batch.DeliverBatch(ctx, messages)
+	if len(messages) == 0 {
+		return errs
+	}
+
+	elapsed := time.Since(start) / time.Duration(len(messages))
+	for i, message := range messages {
+		observeDelivery(message.Template, d.provider, elapsed, errs[i])
+	}
+
+	return errs
+}
+
+func observeDelivery(template, provider string, elapsed time.Duration, err error) {
+	if template == "" {
+		template = "unknown"
+	}
+	key := "type=" + template + ",provider=" + provider
+
+	if err != nil {
+		emailsFailed.Add(key, 1)
+	} else {
+		emailsSent.Add(key, 1)
+	}
+
+	sendDurationMu.Lock()
+	h, ok := sendDuration.Get(key).(*histogram)
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(sendDurationBuckets))}
+		sendDuration.Set(key, h)
+	}
+	sendDurationMu.Unlock()
+
+	h.observe(elapsed.Seconds())
+}
+
+// histogram is an expvar.Var counting the observations under each of
+// sendDurationBuckets, cumulatively like Prometheus does.
+type histogram struct {
+	mu sync.Mutex
+	counts []uint64
+	count uint64
+	sum float64
+}
+
+func (h *histogram) observe(value float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for i, bound := range sendDurationBuckets {
+		if value <= bound {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += value
+}
+
+func (h *histogram) String() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	type bucket struct {
+		LE float64 `json:"le"`
+		Count uint64 `json:"count"`
+	}
+	buckets := make([]bucket, len(sendDurationBuckets))
+	for i, bound := range sendDurationBuckets {
+		buckets[i] = bucket{bound, h.counts[i]}
+	}
+
+	b, _ := json.Marshal(struct {
+		Buckets []bucket `json:"buckets"`
+		Count uint64 `json:"count"`
+		Sum float64 `json:"sum"`
+	}{buckets, h.count, h.sum})
+
+	return string(b)
+}
@@ -0,0 +1,336 @@
+// Package outbox sends the emails enqueued in the email_outbox table, which
+// the API writes in the same transaction as the change the email is about, so
+// an email is neither lost when the process stops right after the change nor
+// sent for a change that was rolled back.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"journey/internal/mailer"
+	"journey/internal/pgstore"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"go.uber.org/zap"
+)
+
+// errUnknownKind is returned for a row of a kind this version doesn't send,
+// which retrying won't fix.
+var errUnknownKind = errors.New("outbox: unknown email kind")
+
+// errInvalidPayload is returned for a row whose payload can't be decoded, which
+// retrying won't fix either.
+var errInvalidPayload = errors.New("outbox: invalid email payload")
+
+type sender interface {
+	SendConfirmTripEmailToTripOwner(tripID uuid.UUID) error
+	SendConfirmTripEmailToTripParticipants(tripID uuid.UUID) error
+	SendConfirmTripEmailToTripParticipant(participantID uuid.UUID) error
+	SendInviteReminderEmailToTripParticipant(participantID uuid.UUID) error
+	SendTripCanceledEmail(tripID uuid.UUID) error
+	SendTripCanceledEmailToTripParticipant(participantID uuid.UUID) error
+	SendItineraryEmail(tripID uuid.UUID) error
+	SendTripUpdatedEmail(tripID uuid.UUID, changes pgstore.TripChanges) error
+	SendTripUpdatedEmailToTripParticipant(participantID uuid.UUID, changes pgstore.TripChanges) error
+}
+
+// Store is the outbox the emails are claimed from, the pgstore queries or the
+// in-memory memstore.Store.
+type Store interface {
+	ClaimPendingEmails(ctx context.Context, arg pgstore.ClaimPendingEmailsParams) ([]pgstore.EmailOutbox, error)
+	MarkEmailSent(ctx context.Context, id uuid.UUID) error
+	MarkEmailFailed(ctx context.Context, arg pgstore.MarkEmailFailedParams) error
+	MarkTripInvitationsSent(ctx context.Context, id uuid.UUID) error
+	EnqueueEmail(ctx context.Context, arg pgstore.EnqueueEmailParams) error
+}
+
+// Config is how often the outbox is polled, how many emails are sent at once
+// and how failed emails are retried.
+type Config struct {
+	PollInterval time.Duration
+	// Workers is how many emails are sent concurrently, QueueSize how many
+	// claimed emails may wait for a worker.
+	Workers int
+	QueueSize int
+	// MaxAttempts is how many times an email is tried before it's marked as
+	// failed, RetryDelay the wait before the second attempt, doubled before
+	// every following one.
+	MaxAttempts int
+	RetryDelay time.Duration
+	// Lease is how long a claimed email is hidden from the other dispatchers,
+	// it's picked up again after it if the process stopped while sending it.
+	Lease time.Duration
+}
+
+// DefaultConfig polls every few seconds, holds a few SMTP connections at most
+// and gives up on an email after about half an hour of retries.
+func DefaultConfig() Config {
+	return Config{
+		PollInterval: 5 * time.Second,
+		Workers: 4,
+		QueueSize: 50,
+		MaxAttempts: 5,
+		RetryDelay: time.Minute,
+		Lease: 10 * time.Minute,
+	}
+}
+
+// Validate reports the settings the dispatcher can't work with.
+func (c Config) Validate() error {
+	if c.PollInterval <= 0 {
+		return fmt.Errorf("outbox: poll interval %s must be positive", c.PollInterval)
+	}
+
+	if c.Workers < 1 {
+		return fmt.Errorf("outbox: workers %d must be at least 1", c.Workers)
+	}
+
+	if c.QueueSize < 1 {
+		return fmt.Errorf("outbox: queue size %d must be at least 1", c.QueueSize)
+	}
+
+	if c.MaxAttempts < 1 {
+		return fmt.Errorf("outbox: max attempts %d must be at least 1", c.MaxAttempts)
+	}
+
+	if c.RetryDelay < 0 || c.Lease <= 0 {
+		return errors.New("outbox: retry delay must not be negative and lease must be positive")
+	}
+
+	return nil
+}
+
+// Dispatcher claims the pending emails into a queue worked by a fixed number
+// of workers, so no more than Workers emails of the outbox are sent at once.
+// An email to every participant of a trip may itself be sent to several of
+// them at once, up to the mailer's Concurrency.
+type Dispatcher struct {
+	store Store
+	mailer sender
+	logger *zap.Logger
+	config Config
+	queue chan pgstore.EmailOutbox
+	// stop ends the polling, polled is closed once the poller closed the queue.
+	stop chan struct{}
+	polled chan struct{}
+	workers *sync.WaitGroup
+	inFlight *atomic.Int64
+}
+
+func NewDispatcher(store Store, mailer sender, logger *zap.Logger, config Config) Dispatcher {
+	return Dispatcher{
+		store: store,
+		mailer: mailer,
+		logger: logger,
+		config: config,
+		queue: make(chan pgstore.EmailOutbox, config.QueueSize),
+		stop: make(chan struct{}),
+		polled: make(chan struct{}),
+		workers: &sync.WaitGroup{},
+		inFlight: &atomic.Int64{},
+	}
+}
+
+// Start polls the outbox every PollInterval and sends the claimed emails with
+// ctx, which must outlive the polling so Stop can drain the queue.
+func (d Dispatcher) Start(ctx context.Context) {
+	for range d.config.Workers {
+		d.workers.Add(1)
+		go func() {
+			defer d.workers.Done()
+			for email := range d.queue {
+				d.inFlight.Add(1)
+				d.deliver(ctx, email)
+				d.inFlight.Add(-1)
+			}
+		}()
+	}
+
+	go d.poll(ctx)
+}
+
+// Stop stops claiming emails and waits for the queued ones to be sent until ctx
+// is done. The emails left behind are logged, they stay claimed until their
+// lease expires and are sent again after that.
+func (d Dispatcher) Stop(ctx context.Context) {
+	close(d.stop)
+	<-d.polled
+
+	drained := make(chan struct{})
+	go func() {
+		d.workers.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+		return
+	case <-ctx.Done():
+	}
+
+	inFlight := d.inFlight.Load()
+	// The poller closed the queue, so this takes what the workers didn't get to
+	var queued []string
+	for email := range d.queue {
+		queued = append(queued, email.ID.String())
+	}
+	d.logger.Error("Stopped before sending every email, they're sent again once their lease expires", zap.Int64("in_flight", inFlight), zap.Strings("queued_email_ids", queued))
+}
+
+func (d Dispatcher) poll(ctx context.Context) {
+	defer close(d.polled)
+	defer close(d.queue)
+
+	ticker := time.NewTicker(d.config.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		d.claim(ctx)
+
+		select {
+		case <-d.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// claim queues pending emails until there are none left or the queue is full.
+// Only the poller sends to the queue, so claiming no more than the free room
+// never blocks it.
+func (d Dispatcher) claim(ctx context.Context) {
+	for {
+		room := cap(d.queue) - len(d.queue)
+		if room == 0 {
+			return
+		}
+
+		emails, err := d.store.ClaimPendingEmails(ctx, pgstore.ClaimPendingEmailsParams{
+			Lease: pgtype.Interval{Valid: true, Microseconds: d.config.Lease.Microseconds()},
+			Limit: int32(room),
+		})
+		if err != nil {
+			if ctx.Err() == nil {
+				d.logger.Error("Failed to claim pending emails", zap.Error(err))
+			}
+			return
+		}
+
+		for _, email := range emails {
+			d.queue <- email
+		}
+
+		if len(emails) < room {
+			return
+		}
+	}
+}
+
+// deliver sends a claimed email and records the outcome.
+func (d Dispatcher) deliver(ctx context.Context, email pgstore.EmailOutbox) {
+	err := d.send(ctx, email)
+	if err == nil {
+		if err := d.store.MarkEmailSent(ctx, email.ID); err != nil {
+			d.logger.Error("Failed to mark email as sent", zap.Error(err), zap.String("email_id", email.ID.String()))
+		}
+		return
+	}
+
+	// Stopping mid-send leaves the email claimed, it's retried once the lease expires
+	if ctx.Err() != nil {
+		return
+	}
+
+	fields := []zap.Field{zap.Error(err), zap.String("email_id", email.ID.String()), zap.String("kind", email.Kind), zap.String("subject_id", email.SubjectID.String()), zap.Int32("attempts", email.Attempts)}
+
+	params := pgstore.MarkEmailFailedParams{
+		ID: email.ID,
+		Status: pgstore.EmailPending,
+		LastError: pgtype.Text{Valid: true, String: err.Error()},
+		RetryAfter: pgtype.Interval{Valid: true, Microseconds: d.retryDelay(email.Attempts).Microseconds()},
+	}
+	// A trip or participant deleted since the email was enqueued won't come back
+	if int(email.Attempts) >= d.config.MaxAttempts || errors.Is(err, pgx.ErrNoRows) || errors.Is(err, errUnknownKind) || errors.Is(err, errInvalidPayload) {
+		params.Status = pgstore.EmailFailed
+		d.logger.Error("Failed to send email, giving up", fields...)
+	} else {
+		d.logger.Warn("Failed to send email, retrying later", fields...)
+	}
+
+	if err := d.store.MarkEmailFailed(ctx, params); err != nil {
+		d.logger.Error("Failed to mark email as failed", zap.Error(err), zap.String("email_id", email.ID.String()))
+	}
+}
+
+// retryDelay is the wait after the given failed attempt, RetryDelay doubled
+// for every previous attempt.
+func (d Dispatcher) retryDelay(attempts int32) time.Duration {
+	return d.config.RetryDelay << (attempts - 1)
+}
+
+func (d Dispatcher) send(ctx context.Context, email pgstore.EmailOutbox) error {
+	switch email.Kind {
+	case pgstore.EmailConfirmTripOwner:
+		return d.mailer.SendConfirmTripEmailToTripOwner(email.SubjectID)
+	case pgstore.EmailConfirmTripParticipants:
+		if err := d.retryFailed(ctx, email, pgstore.EmailConfirmTripParticipant, d.mailer.SendConfirmTripEmailToTripParticipants(email.SubjectID)); err != nil {
+			return err
+		}
+		// The invitations went out, failing to mark them must not send them again
+		if err := d.store.MarkTripInvitationsSent(ctx, email.SubjectID); err != nil {
+			d.logger.Error("Failed to mark trip invitations as sent", zap.Error(err), zap.String("trip_id", email.SubjectID.String()))
+		}
+		return nil
+	case pgstore.EmailConfirmTripParticipant:
+		return d.mailer.SendConfirmTripEmailToTripParticipant(email.SubjectID)
+	case pgstore.EmailInviteReminder:
+		return d.mailer.SendInviteReminderEmailToTripParticipant(email.SubjectID)
+	case pgstore.EmailTripCancelled:
+		return d.retryFailed(ctx, email, pgstore.EmailTripCancelledParticipant, d.mailer.SendTripCanceledEmail(email.SubjectID))
+	case pgstore.EmailTripCancelledParticipant:
+		return d.mailer.SendTripCanceledEmailToTripParticipant(email.SubjectID)
+	case pgstore.EmailTripItinerary:
+		return d.mailer.SendItineraryEmail(email.SubjectID)
+	case pgstore.EmailTripUpdated:
+		var changes pgstore.TripChanges
+		if err := json.Unmarshal(email.Payload, &changes); err != nil {
+			return fmt.Errorf("%w: %w", errInvalidPayload, err)
+		}
+		return d.retryFailed(ctx, email, pgstore.EmailTripUpdatedParticipant, d.mailer.SendTripUpdatedEmail(email.SubjectID, changes))
+	case pgstore.EmailTripUpdatedParticipant:
+		var changes pgstore.TripChanges
+		if err := json.Unmarshal(email.Payload, &changes); err != nil {
+			return fmt.Errorf("%w: %w", errInvalidPayload, err)
+		}
+		return d.mailer.SendTripUpdatedEmailToTripParticipant(email.SubjectID, changes)
+	default:
+		return fmt.Errorf("%w %q", errUnknownKind, email.Kind)
+	}
+}
+
+// retryFailed enqueues an email of kind for each participant err, the outcome
+// of sending email to every participant of a trip, lists as failed. Retrying the
+// whole trip would email the others again. Errors that aren't about some of
+// the participants are returned, for email to be retried as a whole.
+func (d Dispatcher) retryFailed(ctx context.Context, email pgstore.EmailOutbox, kind string, err error) error {
+	deliveryErr, ok := mailer.AsDeliveryError(err)
+	if !ok {
+		return err
+	}
+
+	for _, failure := range deliveryErr.Failures {
+		d.logger.Warn("Failed to email participant, retrying later", zap.Error(failure.Err), zap.String("kind", email.Kind), zap.String("trip_id", email.SubjectID.String()), zap.String("participant_id", failure.ParticipantID.String()), zap.String("email", failure.Email))
+		if err := d.store.EnqueueEmail(ctx, pgstore.EnqueueEmailParams{Kind: kind, SubjectID: failure.ParticipantID, Payload: email.Payload}); err != nil {
+			d.logger.Error("Failed to enqueue participant email", zap.Error(err), zap.String("kind", kind), zap.String("participant_id", failure.ParticipantID.String()))
+		}
+	}
+
+	return nil
+}
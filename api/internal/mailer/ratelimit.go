@@ -0,0 +1,109 @@
+package mailer
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RateLimitConfig is how many emails a trip may send in Window, at most Limit
+// of them at once.
+type RateLimitConfig struct {
+	Limit int
+	Window time.Duration
+}
+
+func DefaultRateLimitConfig() RateLimitConfig {
+	return RateLimitConfig{
+		Limit: 60,
+		Window: time.Hour,
+	}
+}
+
+// Validate reports the settings that would refuse every email.
+func (c RateLimitConfig) Validate() error {
+	if c.Limit < 1 {
+		return fmt.Errorf("mailer: rate limit %d must be at least 1", c.Limit)
+	}
+
+	if c.Window <= 0 {
+		return fmt.Errorf("mailer: rate limit window %s must be positive", c.Window)
+	}
+
+	return nil
+}
+
+// RateLimiter keeps a token bucket per trip, holding Limit emails and refilled
+// over Window, so a client hammering the invite endpoints can't get the sender
+// flagged as spam. The buckets only live as long as the process.
+type RateLimiter struct {
+	config RateLimitConfig
+	// perSecond is how many emails a bucket gets back every second.
+	perSecond float64
+
+	mu sync.Mutex
+	buckets map[uuid.UUID]*bucket
+	pruned time.Time
+}
+
+type bucket struct {
+	tokens float64
+	updated time.Time
+}
+
+func NewRateLimiter(config RateLimitConfig) *RateLimiter {
+	return &RateLimiter{
+		config: config,
+		perSecond: float64(config.Limit) / config.Window.Seconds(),
+		buckets: make(map[uuid.UUID]*bucket),
+		pruned: time.Now(),
+	}
+}
+
+// Reserve takes n emails from the bucket of tripID. When it doesn't hold that
+// many it takes none and returns a *RateLimitError, n larger than Limit never
+// being allowed.
+func (l *RateLimiter) Reserve(tripID uuid.UUID, n int) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	l.prune(now)
+
+	b, ok := l.buckets[tripID]
+	if !ok {
+		b = &bucket{tokens: float64(l.config.Limit), updated: now}
+		l.buckets[tripID] = b
+	}
+	b.tokens = min(float64(l.config.Limit), b.tokens+now.Sub(b.updated).Seconds()*l.perSecond)
+	b.updated = now
+
+	if b.tokens < float64(n) {
+		retryAfter := l.config.Window
+		if n <= l.config.Limit {
+			retryAfter = time.Duration(math.Ceil((float64(n)-b.tokens)/l.perSecond)) * time.Second
+		}
+		return &RateLimitError{TripID: tripID, RetryAfter: retryAfter}
+	}
+
+	b.tokens -= float64(n)
+	return nil
+}
+
+// prune forgets, once per Window, the buckets that refilled since, which are
+// the same as a new one.
+func (l *RateLimiter) prune(now time.Time) {
+	if now.Sub(l.pruned) < l.config.Window {
+		return
+	}
+
+	for tripID, b := range l.buckets {
+		if now.Sub(b.updated) >= l.config.Window {
+			delete(l.buckets, tripID)
+		}
+	}
+	l.pruned = now
+}
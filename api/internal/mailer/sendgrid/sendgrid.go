@@ -0,0 +1,143 @@
+// Package sendgrid delivers the emails through the SendGrid v3 Mail Send API.
+package sendgrid
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"journey/internal/mailer"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// maxErrorBody is how much of a failed response is kept in the error, SendGrid
+// explaining what went wrong in it.
+const maxErrorBody = 1 << 10
+
+// SendGridConfig is the SendGrid account the emails are sent with.
+type SendGridConfig struct {
+	APIKey string
+	// BaseURL is where the API is reached, only changed to point at a mock.
+	BaseURL string
+	Timeout time.Duration
+}
+
+func DefaultSendGridConfig() SendGridConfig {
+	return SendGridConfig{
+		BaseURL: "https://api.sendgrid.com",
+		Timeout: 10 * time.Second,
+	}
+}
+
+// Validate reports the settings that would make every email fail to send.
+func (c SendGridConfig) Validate() error {
+	if c.APIKey == "" {
+		return errors.New("sendgrid: API key must not be empty")
+	}
+
+	if u, err := url.Parse(c.BaseURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("sendgrid: base URL %q must be an absolute http or https URL", c.BaseURL)
+	}
+
+	if c.Timeout <= 0 {
+		return fmt.Errorf("sendgrid: timeout %s must be positive", c.Timeout)
+	}
+
+	return nil
+}
+
+type SendGrid struct {
+	config SendGridConfig
+	client *http.Client
+}
+
+func NewSendGrid(config SendGridConfig) SendGrid {
+	return SendGrid{config, &http.Client{Timeout: config.Timeout}}
+}
+
+type address struct {
+	Email string `json:"email"`
+	Name string `json:"name,omitempty"`
+}
+
+type personalization struct {
+	To []address `json:"to"`
+}
+
+type content struct {
+	Type string `json:"type"`
+	Value string `json:"value"`
+}
+
+type attachment struct {
+	Content string `json:"content"`
+	Type string `json:"type"`
+	Filename string `json:"filename"`
+	Disposition string `json:"disposition"`
+}
+
+type sendRequest struct {
+	Personalizations []personalization `json:"personalizations"`
+	From address `json:"from"`
+	ReplyTo *address `json:"reply_to,omitempty"`
+	Subject string `json:"subject"`
+	Content []content `json:"content"`
+	Attachments []attachment `json:"attachments,omitempty"`
+}
+
+// Deliver posts message to the Mail Send API, which answers 202 once it
+// accepted it.
+func (sg SendGrid) Deliver(ctx context.Context, message mailer.Message) error {
+	// The plain text must come before the HTML
+	req := sendRequest{
+		Personalizations: []personalization{{To: []address{{Email: message.To}}}},
+		From: address{Email: message.FromAddress, Name: message.FromName},
+		Subject: message.Subject,
+		Content: []content{
+			{Type: "text/plain", Value: message.Text},
+			{Type: "text/html", Value: message.HTML},
+		},
+	}
+	if message.ReplyToAddress != "" {
+		req.ReplyTo = &address{Email: message.ReplyToAddress, Name: message.ReplyToName}
+	}
+	for _, a := range message.Attachments {
+		req.Attachments = append(req.Attachments, attachment{
+			Content: base64.StdEncoding.EncodeToString(a.Content),
+			Type: a.ContentType,
+			Filename: a.Filename,
+			Disposition: "attachment",
+		})
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return fmt.Errorf("sendgrid: failed to encode request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(sg.config.BaseURL, "/")+"/v3/mail/send", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("sendgrid: failed to create request: %w", err)
+	}
+	httpReq.Header.Set("Authorization", "Bearer "+sg.config.APIKey)
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := sg.client.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("sendgrid: failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return fmt.Errorf("sendgrid: mail send answered %s: %s", resp.Status, strings.TrimSpace(string(detail)))
+	}
+
+	return nil
+}
@@ -0,0 +1,203 @@
+package mailer
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	htmltemplate "html/template"
+	"journey/internal/locales"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+//go:embed templates/*/*.html templates/*/*.txt
+var templatesFS embed.FS
+
+// Names of the templates in templates/<locale>/, each one having an .html and
+// a .txt variant, the .txt also defining <name>.subject.
+const (
+	confirmTripOwnerTemplate = "confirm_trip_owner"
+	confirmTripParticipantTemplate = "confirm_trip_participant"
+	tripCancelledTemplate = "trip_cancelled"
+	tripUpdatedTemplate = "trip_updated"
+	tripItineraryTemplate = "trip_itinerary"
+)
+
+// emailTimeFormat is how the emails show the time of an activity, the same in
+// every locale.
+const emailTimeFormat = "15:04"
+
+// emailDateFormats is how the emails of every locale show a date.
+var emailDateFormats = map[string]string{
+	"pt-BR": "02/01/2006",
+	"en": "January 2, 2006",
+}
+
+// footerData fills the footer every email ends with, embedded in the data of
+// every template.
+type footerData struct {
+	UnsubscribeURL string
+}
+
+// confirmTripOwnerData fills the confirm_trip_owner templates.
+type confirmTripOwnerData struct {
+	footerData
+	OwnerName string
+	Destination string
+	StartsAt string
+	EndsAt string
+	ConfirmURL string
+}
+
+// confirmTripParticipantData fills the confirm_trip_participant templates,
+// Reminder switching them to the wording of a resent invite.
+type confirmTripParticipantData struct {
+	footerData
+	OwnerName string
+	Destination string
+	StartsAt string
+	EndsAt string
+	ConfirmURL string
+	Reminder bool
+}
+
+// tripCancelledData fills the trip_cancelled templates.
+type tripCancelledData struct {
+	footerData
+	OwnerName string
+	Destination string
+	StartsAt string
+}
+
+// tripUpdatedData fills the trip_updated templates, the old values only being
+// shown for what changed.
+type tripUpdatedData struct {
+	footerData
+	OwnerName string
+	Destination string
+	DestinationChanged bool
+	OldDestination string
+	DatesChanged bool
+	OldStartsAt string
+	OldEndsAt string
+	StartsAt string
+	EndsAt string
+}
+
+// tripItineraryData fills the trip_itinerary templates, which are shorter when
+// Days is empty.
+type tripItineraryData struct {
+	footerData
+	OwnerName string
+	Destination string
+	StartsAt string
+	EndsAt string
+	Days []itineraryDay
+	Links []itineraryLink
+}
+
+type itineraryDay struct {
+	Date string
+	Activities []itineraryActivity
+}
+
+type itineraryActivity struct {
+	Time string
+	Title string
+}
+
+type itineraryLink struct {
+	Title string
+	URL string
+}
+
+// emailTemplates holds both renderings of every email, for every locale.
+type emailTemplates map[string]localeTemplates
+
+type localeTemplates struct {
+	html *htmltemplate.Template
+	text *texttemplate.Template
+}
+
+// parseTemplates parses the embedded templates once, so a broken or missing one
+// stops the server at startup instead of failing every send.
+func parseTemplates() (emailTemplates, error) {
+	templates := emailTemplates{}
+	for _, locale := range locales.Supported {
+		html, err := htmltemplate.ParseFS(templatesFS, "templates/"+locale+"/*.html")
+		if err != nil {
+			return nil, fmt.Errorf("mailer: failed to parse %s HTML email templates: %w", locale, err)
+		}
+
+		text, err := texttemplate.ParseFS(templatesFS, "templates/"+locale+"/*.txt")
+		if err != nil {
+			return nil, fmt.Errorf("mailer: failed to parse %s text email templates: %w", locale, err)
+		}
+
+		if html.Lookup("footer.html") == nil || text.Lookup("footer.txt") == nil {
+			return nil, fmt.Errorf("mailer: missing email footer templates for %s", locale)
+		}
+
+		for _, name := range []string{confirmTripOwnerTemplate, confirmTripParticipantTemplate, tripCancelledTemplate, tripUpdatedTemplate, tripItineraryTemplate} {
+			if html.Lookup(name+".html") == nil {
+				return nil, fmt.Errorf("mailer: missing email template %s/%s.html", locale, name)
+			}
+			if text.Lookup(name+".txt") == nil {
+				return nil, fmt.Errorf("mailer: missing email template %s/%s.txt", locale, name)
+			}
+			if text.Lookup(name+".subject") == nil {
+				return nil, fmt.Errorf("mailer: missing email template %s/%s.subject", locale, name)
+			}
+		}
+
+		if _, ok := emailDateFormats[locale]; !ok {
+			return nil, fmt.Errorf("mailer: missing date format for %s", locale)
+		}
+
+		templates[locale] = localeTemplates{html, text}
+	}
+
+	return templates, nil
+}
+
+// render renders the subject and both variants of the body of the name
+// templates into message, the providers sending the body as a
+// multipart/alternative. A locale the emails aren't written in falls back to
+// locales.Default.
+func (t emailTemplates) render(message *Message, locale, name string, data any) error {
+	lt, ok := t[locale]
+	if !ok {
+		lt = t[locales.Default]
+	}
+
+	var subject strings.Builder
+	if err := lt.text.ExecuteTemplate(&subject, name+".subject", data); err != nil {
+		return err
+	}
+
+	var text strings.Builder
+	if err := lt.text.ExecuteTemplate(&text, name+".txt", data); err != nil {
+		return err
+	}
+
+	var html bytes.Buffer
+	if err := lt.html.ExecuteTemplate(&html, name+".html", data); err != nil {
+		return err
+	}
+
+	message.Subject = subject.String()
+	message.Text = text.String()
+	message.HTML = html.String()
+	return nil
+}
+
+// formatEmailDate shows t the way the emails of locale do, falling back to
+// locales.Default like render.
+func formatEmailDate(locale string, t time.Time) string {
+	format, ok := emailDateFormats[locale]
+	if !ok {
+		format = emailDateFormats[locales.Default]
+	}
+	return t.Format(format)
+}
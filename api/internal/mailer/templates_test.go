@@ -0,0 +1,208 @@
+package mailer
+
+import (
+	"journey/internal/locales"
+	"strings"
+	"testing"
+	"time"
+)
+
+// templateData fills every template, with the values the tests look for in
+// the emails.
+var templateData = []struct {
+	name string
+	data any
+	want []string
+}{
+	{confirmTripOwnerTemplate, confirmTripOwnerData{
+		footerData:  footerData{UnsubscribeURL: "http://journey.test/unsubscribe/token"},
+		OwnerName:   "Owner",
+		Destination: "Lisbon",
+		StartsAt:    "March 10, 2030",
+		EndsAt:      "March 15, 2030",
+		ConfirmURL:  "http://journey.test/trips/trip-id/confirm",
+	}, []string{"Owner", "Lisbon", "March 10, 2030", "March 15, 2030", "http://journey.test/trips/trip-id/confirm"}},
+	{confirmTripParticipantTemplate, confirmTripParticipantData{
+		footerData:  footerData{UnsubscribeURL: "http://journey.test/unsubscribe/token"},
+		OwnerName:   "Owner",
+		Destination: "Lisbon",
+		StartsAt:    "March 10, 2030",
+		EndsAt:      "March 15, 2030",
+		ConfirmURL:  "http://journey.test/participants/participant-id/confirm",
+	}, []string{"Owner", "Lisbon", "March 10, 2030", "March 15, 2030", "http://journey.test/participants/participant-id/confirm"}},
+	{tripCancelledTemplate, tripCancelledData{
+		footerData:  footerData{UnsubscribeURL: "http://journey.test/unsubscribe/token"},
+		OwnerName:   "Owner",
+		Destination: "Lisbon",
+		StartsAt:    "March 10, 2030",
+	}, []string{"Owner", "Lisbon", "March 10, 2030"}},
+	{tripUpdatedTemplate, tripUpdatedData{
+		footerData:         footerData{UnsubscribeURL: "http://journey.test/unsubscribe/token"},
+		OwnerName:          "Owner",
+		Destination:        "Porto",
+		DestinationChanged: true,
+		OldDestination:     "Lisbon",
+		DatesChanged:       true,
+		OldStartsAt:        "March 10, 2030",
+		OldEndsAt:          "March 15, 2030",
+		StartsAt:           "April 10, 2030",
+		EndsAt:             "April 15, 2030",
+	}, []string{"Owner", "Porto", "Lisbon", "March 10, 2030", "March 15, 2030", "April 10, 2030", "April 15, 2030"}},
+	{tripItineraryTemplate, tripItineraryData{
+		footerData:  footerData{UnsubscribeURL: "http://journey.test/unsubscribe/token"},
+		OwnerName:   "Owner",
+		Destination: "Lisbon",
+		StartsAt:    "March 10, 2030",
+		EndsAt:      "March 15, 2030",
+		Days: []itineraryDay{{
+			Date:       "March 11, 2030",
+			Activities: []itineraryActivity{{Time: "10:00", Title: "Belém Tower"}},
+		}},
+		Links: []itineraryLink{{Title: "Hotel", URL: "https://example.com/hotel"}},
+	}, []string{"Owner", "Lisbon", "March 11, 2030", "10:00", "Belém Tower", "Hotel", "https://example.com/hotel"}},
+}
+
+// TestRenderTemplates renders every email, checking both bodies show its data
+// and end with the footer.
+func TestRenderTemplates(t *testing.T) {
+	templates, err := parseTemplates()
+	if err != nil {
+		t.Fatalf("parseTemplates: %v", err)
+	}
+
+	for _, tt := range templateData {
+		t.Run(tt.name, func(t *testing.T) {
+			var message Message
+			if err := templates.render(&message, "en", tt.name, tt.data); err != nil {
+				t.Fatalf("render: %v", err)
+			}
+
+			if message.Subject == "" || strings.ContainsAny(message.Subject, "\r\n") {
+				t.Errorf("subject = %q, want a single line", message.Subject)
+			}
+			for body, content := range map[string]string{"text": message.Text, "HTML": message.HTML} {
+				for _, want := range tt.want {
+					if !strings.Contains(content, want) {
+						t.Errorf("the %s body doesn't show %q:\n%s", body, want, content)
+					}
+				}
+				if !strings.Contains(content, "http://journey.test/unsubscribe/token") {
+					t.Errorf("the %s body doesn't end with the footer:\n%s", body, content)
+				}
+				if strings.Contains(content, "<no value>") {
+					t.Errorf("the %s body has a missing value:\n%s", body, content)
+				}
+			}
+		})
+	}
+}
+
+// TestRenderReminder checks a resent invite reads as a reminder.
+func TestRenderReminder(t *testing.T) {
+	templates, err := parseTemplates()
+	if err != nil {
+		t.Fatalf("parseTemplates: %v", err)
+	}
+
+	var invite, reminder Message
+	if err := templates.render(&invite, "en", confirmTripParticipantTemplate, confirmTripParticipantData{}); err != nil {
+		t.Fatalf("render: %v", err)
+	}
+	if err := templates.render(&reminder, "en", confirmTripParticipantTemplate, confirmTripParticipantData{Reminder: true}); err != nil {
+		t.Fatalf("render: %v", err)
+	}
+
+	if strings.Contains(invite.Subject, "Reminder") || !strings.Contains(reminder.Subject, "Reminder") {
+		t.Errorf("subjects = %q and %q, want only the second to be a reminder", invite.Subject, reminder.Subject)
+	}
+}
+
+// TestRenderEscaping checks the values of a trip are escaped in the HTML body
+// and left as they are in the text one.
+func TestRenderEscaping(t *testing.T) {
+	templates, err := parseTemplates()
+	if err != nil {
+		t.Fatalf("parseTemplates: %v", err)
+	}
+
+	const destination = `<script>alert("Lisbon")</script>`
+	var message Message
+	if err := templates.render(&message, "en", tripCancelledTemplate, tripCancelledData{OwnerName: "Owner", Destination: destination}); err != nil {
+		t.Fatalf("render: %v", err)
+	}
+
+	if strings.Contains(message.HTML, "<script>") {
+		t.Errorf("the HTML body doesn't escape the destination:\n%s", message.HTML)
+	}
+	if !strings.Contains(message.HTML, "&lt;script&gt;") {
+		t.Errorf("the HTML body doesn't show the escaped destination:\n%s", message.HTML)
+	}
+	if !strings.Contains(message.Text, destination) {
+		t.Errorf("the text body doesn't show the destination as is:\n%s", message.Text)
+	}
+}
+
+// TestRenderLocales renders every email in every locale, each in its own
+// words, and checks the other locales fall back to locales.Default.
+func TestRenderLocales(t *testing.T) {
+	templates, err := parseTemplates()
+	if err != nil {
+		t.Fatalf("parseTemplates: %v", err)
+	}
+
+	for _, tt := range templateData {
+		t.Run(tt.name, func(t *testing.T) {
+			subjects := map[string]string{}
+			for _, locale := range locales.Supported {
+				var message Message
+				if err := templates.render(&message, locale, tt.name, tt.data); err != nil {
+					t.Fatalf("render %s: %v", locale, err)
+				}
+				if message.Subject == "" || message.Text == "" || message.HTML == "" {
+					t.Errorf("%s email = %+v, want a subject and both bodies", locale, message)
+				}
+				if strings.Contains(message.Text+message.HTML, "<no value>") {
+					t.Errorf("%s email has a missing value:\n%s\n%s", locale, message.Text, message.HTML)
+				}
+				for other, subject := range subjects {
+					if subject == message.Subject {
+						t.Errorf("%s and %s emails have the same subject %q", locale, other, subject)
+					}
+				}
+				subjects[locale] = message.Subject
+			}
+
+			var want Message
+			if err := templates.render(&want, locales.Default, tt.name, tt.data); err != nil {
+				t.Fatalf("render %s: %v", locales.Default, err)
+			}
+			for _, locale := range []string{"", "fr", "pt", "EN"} {
+				var got Message
+				if err := templates.render(&got, locale, tt.name, tt.data); err != nil {
+					t.Fatalf("render %q: %v", locale, err)
+				}
+				if got.Subject != want.Subject || got.Text != want.Text || got.HTML != want.HTML {
+					t.Errorf("%q email = %+v, want the %s one %+v", locale, got, locales.Default, want)
+				}
+			}
+		})
+	}
+}
+
+func TestFormatEmailDate(t *testing.T) {
+	date := time.Date(2030, time.March, 10, 9, 0, 0, 0, time.UTC)
+	tests := []struct {
+		locale string
+		want   string
+	}{
+		{"pt-BR", "10/03/2030"},
+		{"en", "March 10, 2030"},
+		{"fr", "10/03/2030"},
+		{"", "10/03/2030"},
+	}
+	for _, tt := range tests {
+		if got := formatEmailDate(tt.locale, date); got != tt.want {
+			t.Errorf("formatEmailDate(%q) = %q, want %q", tt.locale, got, tt.want)
+		}
+	}
+}
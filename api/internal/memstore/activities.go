@@ -0,0 +1,217 @@
+package memstore
+
+import (
+	"context"
+	"journey/internal/pgstore"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// insertActivity is the CreateActivity query, the caller holding mu and having
+// checked the trip exists.
+func (s *Store) insertActivity(arg pgstore.CreateActivityParams) uuid.UUID {
+	tags := slices.Clone(arg.Tags)
+	if tags == nil {
+		tags = []string{}
+	}
+
+	ts := now()
+	activity := pgstore.Activity{
+		ID:        newID(),
+		TripID:    arg.TripID,
+		Title:     arg.Title,
+		OccursAt:  arg.OccursAt,
+		Tags:      tags,
+		CreatedAt: ts,
+		UpdatedAt: ts,
+	}
+	s.activities[activity.ID] = activity
+	return activity.ID
+}
+
+// tripActivities is the activities of the trip tripID by ("occurs_at", "id"),
+// the caller holding mu.
+func (s *Store) tripActivities(tripID uuid.UUID) []pgstore.Activity {
+	activities := values(s.activities, func(activity pgstore.Activity) bool {
+		return activity.TripID == tripID
+	})
+	slices.SortFunc(activities, compareActivities)
+	return activities
+}
+
+func compareActivities(a, b pgstore.Activity) int {
+	if c := compareTimestamps(a.OccursAt, b.OccursAt); c != 0 {
+		return c
+	}
+	return compareIDs(a.ID, b.ID)
+}
+
+func (s *Store) CreateActivity(ctx context.Context, arg pgstore.CreateActivityParams) (uuid.UUID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.trips[arg.TripID]; !ok {
+		return uuid.UUID{}, pgstore.ErrTripNotFound
+	}
+	return s.insertActivity(arg), nil
+}
+
+// CreateActivities creates every activity or, if one of their trips doesn't
+// exist, none of them, returning their IDs in the same order as params.
+func (s *Store) CreateActivities(ctx context.Context, params []pgstore.CreateActivityParams) ([]uuid.UUID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, p := range params {
+		if _, ok := s.trips[p.TripID]; !ok {
+			return nil, pgstore.ErrTripNotFound
+		}
+	}
+
+	ids := make([]uuid.UUID, len(params))
+	for i, p := range params {
+		ids[i] = s.insertActivity(p)
+	}
+	return ids, nil
+}
+
+func (s *Store) GetTripActivities(ctx context.Context, arg pgstore.GetTripActivitiesParams) ([]pgstore.Activity, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.DeleteFunc(s.tripActivities(arg.TripID), func(activity pgstore.Activity) bool {
+		return (arg.OccursFrom.Valid && activity.OccursAt.Time.Before(arg.OccursFrom.Time)) ||
+			(arg.OccursUntil.Valid && !activity.OccursAt.Time.Before(arg.OccursUntil.Time)) ||
+			(arg.Tag.Valid && !slices.Contains(activity.Tags, arg.Tag.String))
+	}), nil
+}
+
+func (s *Store) ListActivitiesOutsideRange(ctx context.Context, arg pgstore.ListActivitiesOutsideRangeParams) ([]pgstore.ListActivitiesOutsideRangeRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var rows []pgstore.ListActivitiesOutsideRangeRow
+	for _, activity := range s.tripActivities(arg.TripID) {
+		if activity.OccursAt.Time.Before(arg.OccursFrom.Time) || !activity.OccursAt.Time.Before(arg.OccursUntil.Time) {
+			rows = append(rows, pgstore.ListActivitiesOutsideRangeRow{ID: activity.ID, OccursAt: activity.OccursAt})
+		}
+	}
+	return rows, nil
+}
+
+func (s *Store) GetActivity(ctx context.Context, arg pgstore.GetActivityParams) (pgstore.Activity, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	activity, ok := s.activities[arg.ID]
+	if !ok || activity.TripID != arg.TripID {
+		return pgstore.Activity{}, pgx.ErrNoRows
+	}
+	return activity, nil
+}
+
+// insertLink is the CreateTripLink query, the caller holding mu and having
+// checked the trip exists and has no link to the URL yet.
+func (s *Store) insertLink(arg pgstore.CreateTripLinkParams) uuid.UUID {
+	ts := now()
+	link := pgstore.Link{
+		ID:        newID(),
+		TripID:    arg.TripID,
+		Title:     arg.Title,
+		Url:       arg.Url,
+		CreatedAt: ts,
+		UpdatedAt: ts,
+	}
+	s.links[link.ID] = link
+	return link.ID
+}
+
+// tripLinks is the links of the trip tripID by ("created_at", "id"), the caller
+// holding mu.
+func (s *Store) tripLinks(tripID uuid.UUID) []pgstore.Link {
+	links := values(s.links, func(link pgstore.Link) bool {
+		return link.TripID == tripID
+	})
+	slices.SortFunc(links, func(a, b pgstore.Link) int {
+		if c := compareTimestamps(a.CreatedAt, b.CreatedAt); c != 0 {
+			return c
+		}
+		return compareIDs(a.ID, b.ID)
+	})
+	return links
+}
+
+// hasLink is whether the trip tripID has a link to url other than the link
+// except, the caller holding mu.
+func (s *Store) hasLink(tripID uuid.UUID, url string, except uuid.UUID) bool {
+	for _, link := range s.links {
+		if link.TripID == tripID && link.Url == url && link.ID != except {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Store) CreateTripLink(ctx context.Context, arg pgstore.CreateTripLinkParams) (uuid.UUID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.trips[arg.TripID]; !ok {
+		return uuid.UUID{}, pgstore.ErrTripNotFound
+	}
+	if s.hasLink(arg.TripID, arg.Url, uuid.Nil) {
+		return uuid.UUID{}, pgstore.ErrDuplicateLink
+	}
+	return s.insertLink(arg), nil
+}
+
+func (s *Store) UpdateLink(ctx context.Context, arg pgstore.UpdateLinkParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	link, ok := s.links[arg.ID]
+	if !ok {
+		return nil
+	}
+	if s.hasLink(link.TripID, arg.Url, link.ID) {
+		return pgstore.ErrDuplicateLink
+	}
+
+	link.Title = arg.Title
+	link.Url = arg.Url
+	link.UpdatedAt = now()
+	s.links[link.ID] = link
+	return nil
+}
+
+func (s *Store) ListTripLinks(ctx context.Context, arg pgstore.ListTripLinksParams) ([]pgstore.Link, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	links := s.tripLinks(arg.TripID)
+	limit := int32(len(links))
+	if arg.Limit.Valid {
+		limit = arg.Limit.Int32
+	}
+	return page(links, limit, arg.Offset), nil
+}
+
+func (s *Store) CountTripLinks(ctx context.Context, tripID uuid.UUID) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return int64(len(s.tripLinks(tripID))), nil
+}
+
+func (s *Store) GetLink(ctx context.Context, linkID uuid.UUID) (pgstore.Link, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	link, ok := s.links[linkID]
+	if !ok {
+		return pgstore.Link{}, pgx.ErrNoRows
+	}
+	return link, nil
+}
@@ -0,0 +1,167 @@
+package memstore
+
+import (
+	"context"
+	"journey/internal/pgstore"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func (s *Store) SuppressEmail(ctx context.Context, email string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.suppressions[email]; !ok {
+		s.suppressions[email] = pgstore.EmailSuppression{Email: email, CreatedAt: now()}
+	}
+	return nil
+}
+
+func (s *Store) IsEmailSuppressed(ctx context.Context, email string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.suppressions[email]
+	return ok, nil
+}
+
+// ListSuppressedEmails is the emails that are suppressed, each once.
+func (s *Store) ListSuppressedEmails(ctx context.Context, emails []string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var suppressed []string
+	for _, email := range emails {
+		if _, ok := s.suppressions[email]; ok && !slices.Contains(suppressed, email) {
+			suppressed = append(suppressed, email)
+		}
+	}
+	return suppressed, nil
+}
+
+func (s *Store) InsertEmailLog(ctx context.Context, arg pgstore.InsertEmailLogParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.trips[arg.TripID]; !ok {
+		return pgstore.ErrTripNotFound
+	}
+
+	entry := pgstore.EmailLog{
+		ID:            uuid.New(),
+		TripID:        arg.TripID,
+		ParticipantID: arg.ParticipantID,
+		Recipient:     arg.Recipient,
+		Kind:          arg.Kind,
+		Status:        arg.Status,
+		Error:         arg.Error,
+		CreatedAt:     now(),
+	}
+	s.emailLog[entry.ID] = entry
+	return nil
+}
+
+// tripEmailLog is the email log of the trip tripID, newest first, the caller
+// holding mu.
+func (s *Store) tripEmailLog(tripID uuid.UUID) []pgstore.EmailLog {
+	entries := values(s.emailLog, func(entry pgstore.EmailLog) bool {
+		return entry.TripID == tripID
+	})
+	slices.SortFunc(entries, func(a, b pgstore.EmailLog) int {
+		if c := compareTimestamps(b.CreatedAt, a.CreatedAt); c != 0 {
+			return c
+		}
+		return compareIDs(a.ID, b.ID)
+	})
+	return entries
+}
+
+func (s *Store) ListTripEmailLog(ctx context.Context, arg pgstore.ListTripEmailLogParams) ([]pgstore.EmailLog, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return page(s.tripEmailLog(arg.TripID), arg.Limit, arg.Offset), nil
+}
+
+func (s *Store) CountTripEmailLog(ctx context.Context, tripID uuid.UUID) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return int64(len(s.tripEmailLog(tripID))), nil
+}
+
+// enqueueEmail is the EnqueueEmail query, the caller holding mu.
+func (s *Store) enqueueEmail(kind string, subjectID uuid.UUID, payload []byte) {
+	ts := now()
+	email := pgstore.EmailOutbox{
+		ID:          uuid.New(),
+		Kind:        kind,
+		SubjectID:   subjectID,
+		Status:      pgstore.EmailPending,
+		AvailableAt: ts,
+		CreatedAt:   ts,
+		Payload:     slices.Clone(payload),
+	}
+	s.outbox[email.ID] = email
+}
+
+func (s *Store) EnqueueEmail(ctx context.Context, arg pgstore.EnqueueEmailParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.enqueueEmail(arg.Kind, arg.SubjectID, arg.Payload)
+	return nil
+}
+
+// ClaimPendingEmails leases the pending emails that are due, the ones
+// available the longest first, hiding them from the next claims until the
+// lease is over.
+func (s *Store) ClaimPendingEmails(ctx context.Context, arg pgstore.ClaimPendingEmailsParams) ([]pgstore.EmailOutbox, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ts := now()
+	due := values(s.outbox, func(email pgstore.EmailOutbox) bool {
+		return email.Status == pgstore.EmailPending && !email.AvailableAt.Time.After(ts.Time)
+	})
+	slices.SortFunc(due, func(a, b pgstore.EmailOutbox) int {
+		return compareTimestamps(a.AvailableAt, b.AvailableAt)
+	})
+
+	claimed := page(due, arg.Limit, 0)
+	for i, email := range claimed {
+		email.Attempts++
+		email.AvailableAt = pgtype.Timestamp{Valid: true, Time: ts.Time.Add(interval(arg.Lease))}
+		s.outbox[email.ID] = email
+		claimed[i] = email
+	}
+	return claimed, nil
+}
+
+func (s *Store) MarkEmailSent(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if email, ok := s.outbox[id]; ok {
+		email.Status = pgstore.EmailSent
+		email.LastError = pgtype.Text{}
+		email.SentAt = now()
+		s.outbox[id] = email
+	}
+	return nil
+}
+
+func (s *Store) MarkEmailFailed(ctx context.Context, arg pgstore.MarkEmailFailedParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if email, ok := s.outbox[arg.ID]; ok {
+		email.Status = arg.Status
+		email.LastError = arg.LastError
+		email.AvailableAt = pgtype.Timestamp{Valid: true, Time: now().Time.Add(interval(arg.RetryAfter))}
+		s.outbox[arg.ID] = email
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+// Package memstore keeps the trips and everything that belongs to them in
+// memory, behaving like pgstore does on Postgres: a missing row is
+// pgx.ErrNoRows and a violated constraint is the pgstore error it's mapped to.
+// It serves the API with JOURNEY_STORE=memory, nothing being kept once the
+// process stops.
+package memstore
+
+import (
+	"bytes"
+	"journey/internal/pgstore"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Store is every table of the schema as a map by primary key. The functions
+// that change several of them hold mu throughout, which is what the
+// transactions of pgstore.Store are for.
+type Store struct {
+	mu           sync.Mutex
+	trips        map[uuid.UUID]pgstore.Trip
+	participants map[uuid.UUID]pgstore.Participant
+	activities   map[uuid.UUID]pgstore.Activity
+	links        map[uuid.UUID]pgstore.Link
+	suppressions map[string]pgstore.EmailSuppression
+	emailLog     map[uuid.UUID]pgstore.EmailLog
+	outbox       map[uuid.UUID]pgstore.EmailOutbox
+}
+
+// New is an empty Store.
+func New() *Store {
+	return &Store{
+		trips:        make(map[uuid.UUID]pgstore.Trip),
+		participants: make(map[uuid.UUID]pgstore.Participant),
+		activities:   make(map[uuid.UUID]pgstore.Activity),
+		links:        make(map[uuid.UUID]pgstore.Link),
+		suppressions: make(map[string]pgstore.EmailSuppression),
+		emailLog:     make(map[uuid.UUID]pgstore.EmailLog),
+		outbox:       make(map[uuid.UUID]pgstore.EmailOutbox),
+	}
+}
+
+// newID is the ID of a new trip, participant, activity or link, time ordered
+// like the uuidv7() Postgres gives them.
+func newID() uuid.UUID {
+	return uuid.Must(uuid.NewV7())
+}
+
+// now is the value of now() in a TIMESTAMP column.
+func now() pgtype.Timestamp {
+	return pgtype.Timestamp{Valid: true, Time: time.Now().UTC()}
+}
+
+// interval is the duration of an INTERVAL, a month counting as 30 days like
+// Postgres does when adding one to a date isn't possible.
+func interval(i pgtype.Interval) time.Duration {
+	days := time.Duration(i.Months)*30 + time.Duration(i.Days)
+	return days*24*time.Hour + time.Duration(i.Microseconds)*time.Microsecond
+}
+
+// compareIDs orders UUIDs byte by byte, as Postgres does.
+func compareIDs(a, b uuid.UUID) int {
+	return bytes.Compare(a[:], b[:])
+}
+
+func compareTimestamps(a, b pgtype.Timestamp) int {
+	return a.Time.Compare(b.Time)
+}
+
+// page is the LIMIT and OFFSET of rows.
+func page[T any](rows []T, limit, offset int32) []T {
+	if int(offset) >= len(rows) {
+		return nil
+	}
+	rows = rows[offset:]
+	if int(limit) < len(rows) {
+		rows = rows[:limit]
+	}
+	return rows
+}
+
+// values is the rows of table the keep function holds for, in no particular
+// order until sorted.
+func values[K comparable, V any](table map[K]V, keep func(V) bool) []V {
+	var rows []V
+	for _, row := range table {
+		if keep(row) {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// ilike is whether s matches the ILIKE pattern, where % is any run of
+// characters, _ any single one and a backslash escapes the next one.
+func ilike(pattern string) func(s string) bool {
+	var expr strings.Builder
+	expr.WriteString(`(?is)^`)
+
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '%':
+			expr.WriteString(`.*`)
+		case r == '_':
+			expr.WriteString(`.`)
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	expr.WriteString(`$`)
+
+	re := regexp.MustCompile(expr.String())
+	return re.MatchString
+}
@@ -0,0 +1,17 @@
+package memstore_test
+
+import (
+	"journey/internal/memstore"
+	"journey/internal/storetest"
+	"testing"
+)
+
+func TestStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) storetest.Store {
+		return memstore.New()
+	})
+}
+
+func BenchmarkCreateTrip(b *testing.B) {
+	storetest.BenchmarkCreateTrip(b, memstore.New())
+}
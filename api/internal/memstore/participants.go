@@ -0,0 +1,296 @@
+package memstore
+
+import (
+	"context"
+	"journey/internal/pgstore"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// insertParticipant invites email to the trip tripID, the caller holding mu and
+// having checked the email isn't invited yet.
+func (s *Store) insertParticipant(tripID uuid.UUID, email string) uuid.UUID {
+	participant := pgstore.Participant{
+		ID:               newID(),
+		TripID:           tripID,
+		Email:            email,
+		UnsubscribeToken: uuid.New(),
+	}
+	s.participants[participant.ID] = participant
+	return participant.ID
+}
+
+// participantByEmail is the participant of the trip tripID invited as email,
+// the caller holding mu.
+func (s *Store) participantByEmail(tripID uuid.UUID, email string) (pgstore.Participant, bool) {
+	for _, participant := range s.participants {
+		if participant.TripID == tripID && participant.Email == email {
+			return participant, true
+		}
+	}
+	return pgstore.Participant{}, false
+}
+
+// invitedParticipant is the participant of the trip tripID invited as email in
+// any case, matched like the unique index on the addresses, the caller holding
+// mu.
+func (s *Store) invitedParticipant(tripID uuid.UUID, email string) (pgstore.Participant, bool) {
+	for _, participant := range s.participants {
+		if participant.TripID == tripID && strings.EqualFold(participant.Email, email) {
+			return participant, true
+		}
+	}
+	return pgstore.Participant{}, false
+}
+
+// tripParticipants is the participants of the trip tripID by ("email", "id"),
+// the caller holding mu.
+func (s *Store) tripParticipants(tripID uuid.UUID) []pgstore.Participant {
+	participants := values(s.participants, func(participant pgstore.Participant) bool {
+		return participant.TripID == tripID
+	})
+	slices.SortFunc(participants, func(a, b pgstore.Participant) int {
+		if c := strings.Compare(a.Email, b.Email); c != 0 {
+			return c
+		}
+		return compareIDs(a.ID, b.ID)
+	})
+	return participants
+}
+
+func (s *Store) GetParticipant(ctx context.Context, participantID uuid.UUID) (pgstore.Participant, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	participant, ok := s.participants[participantID]
+	if !ok {
+		return pgstore.Participant{}, pgx.ErrNoRows
+	}
+	return participant, nil
+}
+
+func (s *Store) GetParticipantWithTrip(ctx context.Context, participantID uuid.UUID) (pgstore.GetParticipantWithTripRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	participant, ok := s.participants[participantID]
+	if !ok {
+		return pgstore.GetParticipantWithTripRow{}, pgx.ErrNoRows
+	}
+
+	trip := s.trips[participant.TripID]
+	return pgstore.GetParticipantWithTripRow{
+		ID:          participant.ID,
+		TripID:      participant.TripID,
+		Email:       participant.Email,
+		IsConfirmed: participant.IsConfirmed,
+		Name:        participant.Name,
+		IsDeclined:  participant.IsDeclined,
+		Destination: trip.Destination,
+		OwnerName:   trip.OwnerName,
+		StartsAt:    trip.StartsAt,
+		EndsAt:      trip.EndsAt,
+	}, nil
+}
+
+func (s *Store) GetParticipantByEmail(ctx context.Context, arg pgstore.GetParticipantByEmailParams) (pgstore.Participant, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	participant, ok := s.participantByEmail(arg.TripID, arg.Email)
+	if !ok {
+		return pgstore.Participant{}, pgx.ErrNoRows
+	}
+	return participant, nil
+}
+
+func (s *Store) GetParticipants(ctx context.Context, tripID uuid.UUID) ([]pgstore.Participant, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.tripParticipants(tripID), nil
+}
+
+// filterParticipants is the participants of a trip, like tripParticipants, that
+// are confirmed or not as isConfirmed asks, if it does.
+func (s *Store) filterParticipants(tripID uuid.UUID, isConfirmed pgtype.Bool) []pgstore.Participant {
+	return slices.DeleteFunc(s.tripParticipants(tripID), func(participant pgstore.Participant) bool {
+		return isConfirmed.Valid && participant.IsConfirmed != isConfirmed.Bool
+	})
+}
+
+func (s *Store) ListParticipants(ctx context.Context, arg pgstore.ListParticipantsParams) ([]pgstore.Participant, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return page(s.filterParticipants(arg.TripID, arg.IsConfirmed), arg.Limit, arg.Offset), nil
+}
+
+func (s *Store) GetUnconfirmedParticipants(ctx context.Context, arg pgstore.GetUnconfirmedParticipantsParams) ([]pgstore.Participant, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return page(s.filterParticipants(arg.TripID, pgtype.Bool{Valid: true, Bool: false}), arg.Limit, arg.Offset), nil
+}
+
+func (s *Store) CountParticipants(ctx context.Context, arg pgstore.CountParticipantsParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return int64(len(s.filterParticipants(arg.TripID, arg.IsConfirmed))), nil
+}
+
+// updateParticipant applies update to the participant participantID, if it
+// exists.
+func (s *Store) updateParticipant(participantID uuid.UUID, update func(*pgstore.Participant)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if participant, ok := s.participants[participantID]; ok {
+		update(&participant)
+		s.participants[participantID] = participant
+	}
+}
+
+func (s *Store) ConfirmParticipant(ctx context.Context, participantID uuid.UUID) error {
+	s.updateParticipant(participantID, func(participant *pgstore.Participant) {
+		participant.IsConfirmed = true
+		participant.IsDeclined = false
+	})
+	return nil
+}
+
+func (s *Store) DeclineParticipant(ctx context.Context, participantID uuid.UUID) error {
+	s.updateParticipant(participantID, func(participant *pgstore.Participant) {
+		participant.IsDeclined = true
+	})
+	return nil
+}
+
+func (s *Store) UpdateParticipantName(ctx context.Context, arg pgstore.UpdateParticipantNameParams) error {
+	s.updateParticipant(arg.ID, func(participant *pgstore.Participant) {
+		participant.Name = arg.Name
+	})
+	return nil
+}
+
+// RecordParticipantInvite records an attempt at sending the invite of a
+// participant, a failed one keeping when it was last sent, if ever.
+func (s *Store) RecordParticipantInvite(ctx context.Context, arg pgstore.RecordParticipantInviteParams) error {
+	s.updateParticipant(arg.ID, func(participant *pgstore.Participant) {
+		if !arg.Error.Valid {
+			participant.InviteSentAt = now()
+		}
+		participant.LastInviteError = arg.Error
+	})
+	return nil
+}
+
+func (s *Store) ResetParticipantsConfirmation(ctx context.Context, tripID uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, participant := range s.participants {
+		if participant.TripID == tripID {
+			participant.IsConfirmed = false
+			s.participants[id] = participant
+		}
+	}
+	return nil
+}
+
+// ResendParticipantInvite stamps the invite of a participant as resent and enqueues
+// the reminder. It returns 0, enqueuing nothing, if the cooldown hasn't passed yet.
+func (s *Store) ResendParticipantInvite(ctx context.Context, params pgstore.MarkParticipantInviteResentParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	participant, ok := s.participants[params.ID]
+	if !ok {
+		return 0, nil
+	}
+
+	ts := now()
+	if participant.InviteResentAt.Valid && !participant.InviteResentAt.Time.Before(ts.Time.Add(-interval(params.Cooldown))) {
+		return 0, nil
+	}
+
+	participant.InviteResentAt = ts
+	s.participants[participant.ID] = participant
+	s.enqueueEmail(pgstore.EmailInviteReminder, participant.ID, nil)
+
+	return 1, nil
+}
+
+// InviteParticipant invites a participant to a trip, enqueuing their invitation
+// only if notify is set. A participant who declined is invited again, created
+// being false then, anyone else already invited is ErrDuplicateParticipant.
+func (s *Store) InviteParticipant(ctx context.Context, params pgstore.InviteParticipantToTripParams, notify bool) (uuid.UUID, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.trips[params.TripID]; !ok {
+		return uuid.UUID{}, false, pgstore.ErrTripNotFound
+	}
+
+	var participantID uuid.UUID
+	participant, invited := s.invitedParticipant(params.TripID, params.Email)
+	switch {
+	case invited && !participant.IsDeclined:
+		return uuid.UUID{}, false, pgstore.ErrDuplicateParticipant
+	case invited:
+		participant.IsDeclined = false
+		participant.IsConfirmed = false
+		participant.InviteSentAt = pgtype.Timestamp{}
+		participant.LastInviteError = pgtype.Text{}
+		s.participants[participant.ID] = participant
+		participantID = participant.ID
+	default:
+		participantID = s.insertParticipant(params.TripID, params.Email)
+	}
+
+	if notify {
+		s.enqueueEmail(pgstore.EmailConfirmTripParticipant, participantID, nil)
+	}
+
+	return participantID, !invited, nil
+}
+
+// DeleteParticipant deletes a participant, the email log keeping the emails
+// they were sent without them.
+func (s *Store) DeleteParticipant(ctx context.Context, participantID uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.participants, participantID)
+	for id, entry := range s.emailLog {
+		if entry.ParticipantID.Valid && uuid.UUID(entry.ParticipantID.Bytes) == participantID {
+			entry.ParticipantID = pgtype.UUID{}
+			s.emailLog[id] = entry
+		}
+	}
+	return nil
+}
+
+// GetEmailByUnsubscribeToken is the address of the participant or trip owner
+// the unsubscribe token was made for, pgx.ErrNoRows if none was.
+func (s *Store) GetEmailByUnsubscribeToken(ctx context.Context, unsubscribeToken uuid.UUID) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, participant := range s.participants {
+		if participant.UnsubscribeToken == unsubscribeToken {
+			return participant.Email, nil
+		}
+	}
+	for _, trip := range s.trips {
+		if trip.OwnerUnsubscribeToken == unsubscribeToken {
+			return trip.OwnerEmail, nil
+		}
+	}
+	return "", pgx.ErrNoRows
+}
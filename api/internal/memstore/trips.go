@@ -0,0 +1,524 @@
+package memstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"journey/internal/api/spec"
+	"journey/internal/pgstore"
+	"journey/internal/textsearch"
+	"journey/internal/trigram"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// The statuses of trips, see migration 020.
+const (
+	tripActive    = "active"
+	tripCancelled = "cancelled"
+)
+
+// insertTrip is the InsertTrip query, the caller holding mu.
+func (s *Store) insertTrip(params pgstore.InsertTripParams) uuid.UUID {
+	ts := now()
+	trip := pgstore.Trip{
+		ID:                    newID(),
+		Destination:           params.Destination,
+		OwnerEmail:            params.OwnerEmail,
+		OwnerName:             params.OwnerName,
+		StartsAt:              params.StartsAt,
+		EndsAt:                params.EndsAt,
+		CreatedAt:             ts,
+		Description:           params.Description,
+		ImageUrl:              params.ImageUrl,
+		UpdatedAt:             ts,
+		Status:                tripActive,
+		Version:               1,
+		Locale:                params.Locale,
+		OwnerUnsubscribeToken: uuid.New(),
+	}
+	s.trips[trip.ID] = trip
+	return trip.ID
+}
+
+// CreateTrip inserts a trip and invites its participants, enqueuing the email
+// asking the owner to confirm it. Nothing is kept if an email is invited twice.
+func (s *Store) CreateTrip(ctx context.Context, params spec.CreateTripRequest) (uuid.UUID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seen := make(map[string]bool, len(params.EmailsToInvite))
+	for _, email := range params.EmailsToInvite {
+		address := strings.ToLower(string(email))
+		if seen[address] {
+			return uuid.UUID{}, fmt.Errorf("%w: %s", pgstore.ErrDuplicateParticipant, email)
+		}
+		seen[address] = true
+	}
+
+	tripID := s.insertTrip(pgstore.NewInsertTripParams(params))
+	for _, email := range params.EmailsToInvite {
+		s.insertParticipant(tripID, string(email))
+	}
+	s.enqueueEmail(pgstore.EmailConfirmTripOwner, tripID, nil)
+
+	return tripID, nil
+}
+
+// DuplicateTrip clones a trip, its activities and its links into a new unconfirmed trip
+// with the same owner, shifting every date by offsetDays. Participants are not copied.
+func (s *Store) DuplicateTrip(ctx context.Context, tripID uuid.UUID, offsetDays int) (uuid.UUID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	trip, ok := s.trips[tripID]
+	if !ok {
+		return uuid.UUID{}, pgx.ErrNoRows
+	}
+
+	shift := func(ts pgtype.Timestamp) pgtype.Timestamp {
+		return pgtype.Timestamp{Valid: ts.Valid, Time: ts.Time.AddDate(0, 0, offsetDays)}
+	}
+
+	newTripID := s.insertTrip(pgstore.InsertTripParams{
+		Destination: trip.Destination,
+		OwnerEmail:  trip.OwnerEmail,
+		OwnerName:   trip.OwnerName,
+		StartsAt:    shift(trip.StartsAt),
+		EndsAt:      shift(trip.EndsAt),
+		Description: trip.Description,
+		ImageUrl:    trip.ImageUrl,
+		Locale:      trip.Locale,
+	})
+
+	for _, activity := range s.tripActivities(tripID) {
+		s.insertActivity(pgstore.CreateActivityParams{
+			TripID:   newTripID,
+			Title:    activity.Title,
+			OccursAt: shift(activity.OccursAt),
+			Tags:     activity.Tags,
+		})
+	}
+
+	for _, link := range s.tripLinks(tripID) {
+		s.insertLink(pgstore.CreateTripLinkParams{
+			TripID: newTripID,
+			Title:  link.Title,
+			Url:    link.Url,
+		})
+	}
+
+	s.enqueueEmail(pgstore.EmailConfirmTripOwner, newTripID, nil)
+
+	return newTripID, nil
+}
+
+// ExportTrip is a trip along with everything that belongs to it, pgx.ErrNoRows
+// if it doesn't exist.
+func (s *Store) ExportTrip(ctx context.Context, tripID uuid.UUID) (pgstore.TripExport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	trip, ok := s.trips[tripID]
+	if !ok {
+		return pgstore.TripExport{}, pgx.ErrNoRows
+	}
+
+	return pgstore.TripExport{
+		Trip:         trip,
+		Participants: s.tripParticipants(tripID),
+		Activities:   s.tripActivities(tripID),
+		Links:        s.tripLinks(tripID),
+	}, nil
+}
+
+func (s *Store) GetTrip(ctx context.Context, id uuid.UUID) (pgstore.Trip, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	trip, ok := s.trips[id]
+	if !ok {
+		return pgstore.Trip{}, pgx.ErrNoRows
+	}
+	return trip, nil
+}
+
+// GetTripDetails is GetTrip, the Postgres store reading it from its replica.
+func (s *Store) GetTripDetails(ctx context.Context, id uuid.UUID) (pgstore.Trip, error) {
+	return s.GetTrip(ctx, id)
+}
+
+func (s *Store) GetTripSummary(ctx context.Context, id uuid.UUID) (pgstore.GetTripSummaryRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	trip, ok := s.trips[id]
+	if !ok {
+		return pgstore.GetTripSummaryRow{}, pgx.ErrNoRows
+	}
+
+	participants := s.tripParticipants(id)
+	var confirmed int64
+	for _, participant := range participants {
+		if participant.IsConfirmed {
+			confirmed++
+		}
+	}
+
+	return pgstore.GetTripSummaryRow{
+		ID:                         trip.ID,
+		Destination:                trip.Destination,
+		OwnerEmail:                 trip.OwnerEmail,
+		OwnerName:                  trip.OwnerName,
+		IsConfirmed:                trip.IsConfirmed,
+		StartsAt:                   trip.StartsAt,
+		EndsAt:                     trip.EndsAt,
+		CreatedAt:                  trip.CreatedAt,
+		Description:                trip.Description,
+		ImageUrl:                   trip.ImageUrl,
+		Archived:                   trip.Archived,
+		UpdatedAt:                  trip.UpdatedAt,
+		Status:                     trip.Status,
+		InvitationsQueuedAt:        trip.InvitationsQueuedAt,
+		InvitationsSentAt:          trip.InvitationsSentAt,
+		Version:                    trip.Version,
+		Locale:                     trip.Locale,
+		OwnerUnsubscribeToken:      trip.OwnerUnsubscribeToken,
+		ParticipantsCount:          int64(len(participants)),
+		ConfirmedParticipantsCount: confirmed,
+		ActivitiesCount:            int64(len(s.tripActivities(id))),
+		LinksCount:                 int64(len(s.tripLinks(id))),
+	}, nil
+}
+
+// filterTrips is the trips the filters of GET /trips keep, the caller holding mu.
+func (s *Store) filterTrips(isConfirmed pgtype.Bool, ownerEmail pgtype.Text, includeArchived bool) []pgstore.Trip {
+	return values(s.trips, func(trip pgstore.Trip) bool {
+		return (!isConfirmed.Valid || trip.IsConfirmed == isConfirmed.Bool) &&
+			(!ownerEmail.Valid || trip.OwnerEmail == ownerEmail.String) &&
+			(includeArchived || !trip.Archived)
+	})
+}
+
+func (s *Store) ListTrips(ctx context.Context, arg pgstore.ListTripsParams) ([]pgstore.Trip, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	trips := s.filterTrips(arg.IsConfirmed, arg.OwnerEmail, arg.IncludeArchived)
+	slices.SortFunc(trips, func(a, b pgstore.Trip) int {
+		var c int
+		switch arg.SortBy {
+		case "starts_at":
+			c = compareTimestamps(a.StartsAt, b.StartsAt)
+		case "ends_at":
+			c = compareTimestamps(a.EndsAt, b.EndsAt)
+		case "created_at":
+			c = compareTimestamps(a.CreatedAt, b.CreatedAt)
+		case "destination":
+			c = strings.Compare(a.Destination, b.Destination)
+		}
+		if arg.SortDesc {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+		return compareIDs(a.ID, b.ID)
+	})
+
+	return page(trips, arg.Limit, arg.Offset), nil
+}
+
+func (s *Store) ListTripsAfter(ctx context.Context, arg pgstore.ListTripsAfterParams) ([]pgstore.Trip, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	trips := slices.DeleteFunc(s.filterTrips(arg.IsConfirmed, arg.OwnerEmail, arg.IncludeArchived), func(trip pgstore.Trip) bool {
+		if c := compareTimestamps(trip.StartsAt, arg.AfterStartsAt); c != 0 {
+			return c < 0
+		}
+		return compareIDs(trip.ID, arg.AfterID) <= 0
+	})
+	slices.SortFunc(trips, compareTripsByStart)
+
+	return page(trips, arg.Limit, 0), nil
+}
+
+// compareTripsByStart orders trips by ("starts_at", "id").
+func compareTripsByStart(a, b pgstore.Trip) int {
+	if c := compareTimestamps(a.StartsAt, b.StartsAt); c != 0 {
+		return c
+	}
+	return compareIDs(a.ID, b.ID)
+}
+
+func (s *Store) CountTrips(ctx context.Context, arg pgstore.CountTripsParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return int64(len(s.filterTrips(arg.IsConfirmed, arg.OwnerEmail, arg.IncludeArchived))), nil
+}
+
+// searchTrips is the trips whose destination or owner name match the ILIKE
+// pattern, or whose destination and description have every word of query,
+// the caller holding mu.
+func (s *Store) searchTrips(query, pattern string) []pgstore.Trip {
+	match := ilike(pattern)
+	return values(s.trips, func(trip pgstore.Trip) bool {
+		return match(trip.Destination) || match(trip.OwnerName) || textsearch.Match(query, searchDocument(trip))
+	})
+}
+
+// searchDocument is the text of a trip trip_search_vector has the words of.
+func searchDocument(trip pgstore.Trip) string {
+	return trip.Destination + " " + trip.Description.String
+}
+
+// SearchTrips ranks the trips matching every word of the query first, then
+// by how similar they are to it. Unlike ts_rank on Postgres, the first ones
+// aren't ranked among themselves by how often the words appear.
+func (s *Store) SearchTrips(ctx context.Context, arg pgstore.SearchTripsParams) ([]pgstore.Trip, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	trips := s.searchTrips(arg.Query, arg.Pattern)
+	score := func(trip pgstore.Trip) float64 {
+		return max(trigram.Similarity(trip.Destination, arg.Query), trigram.Similarity(trip.OwnerName, arg.Query))
+	}
+	slices.SortFunc(trips, func(a, b pgstore.Trip) int {
+		if ma, mb := textsearch.Match(arg.Query, searchDocument(a)), textsearch.Match(arg.Query, searchDocument(b)); ma != mb {
+			if ma {
+				return -1
+			}
+			return 1
+		}
+		if sa, sb := score(a), score(b); sa != sb {
+			if sa > sb {
+				return -1
+			}
+			return 1
+		}
+		return compareIDs(a.ID, b.ID)
+	})
+
+	return page(trips, arg.Limit, arg.Offset), nil
+}
+
+func (s *Store) CountSearchTrips(ctx context.Context, arg pgstore.CountSearchTripsParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return int64(len(s.searchTrips(arg.Query, arg.Pattern))), nil
+}
+
+func (s *Store) ListTripsByParticipantEmail(ctx context.Context, arg pgstore.ListTripsByParticipantEmailParams) ([]pgstore.ListTripsByParticipantEmailRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	participants := values(s.participants, func(participant pgstore.Participant) bool {
+		return participant.Email == arg.Email
+	})
+	slices.SortFunc(participants, func(a, b pgstore.Participant) int {
+		return compareTripsByStart(s.trips[a.TripID], s.trips[b.TripID])
+	})
+
+	participants = page(participants, arg.Limit, arg.Offset)
+	rows := make([]pgstore.ListTripsByParticipantEmailRow, len(participants))
+	for i, participant := range participants {
+		trip := s.trips[participant.TripID]
+		rows[i] = pgstore.ListTripsByParticipantEmailRow{
+			ID:                     trip.ID,
+			Destination:            trip.Destination,
+			OwnerEmail:             trip.OwnerEmail,
+			OwnerName:              trip.OwnerName,
+			IsConfirmed:            trip.IsConfirmed,
+			StartsAt:               trip.StartsAt,
+			EndsAt:                 trip.EndsAt,
+			CreatedAt:              trip.CreatedAt,
+			Description:            trip.Description,
+			ImageUrl:               trip.ImageUrl,
+			Archived:               trip.Archived,
+			UpdatedAt:              trip.UpdatedAt,
+			Status:                 trip.Status,
+			InvitationsQueuedAt:    trip.InvitationsQueuedAt,
+			InvitationsSentAt:      trip.InvitationsSentAt,
+			Version:                trip.Version,
+			Locale:                 trip.Locale,
+			OwnerUnsubscribeToken:  trip.OwnerUnsubscribeToken,
+			ParticipantID:          participant.ID,
+			ParticipantIsConfirmed: participant.IsConfirmed,
+			ParticipantIsDeclined:  participant.IsDeclined,
+		}
+	}
+
+	return rows, nil
+}
+
+func (s *Store) CountTripsByParticipantEmail(ctx context.Context, email string) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	participants := values(s.participants, func(participant pgstore.Participant) bool {
+		return participant.Email == email
+	})
+	return int64(len(participants)), nil
+}
+
+// updateTrip is the UpdateTrip query, the caller holding mu.
+func (s *Store) updateTrip(arg pgstore.UpdateTripParams) int64 {
+	trip, ok := s.trips[arg.ID]
+	if !ok || (arg.ExpectedVersion.Valid && trip.Version != arg.ExpectedVersion.Int32) {
+		return 0
+	}
+
+	// Invites sent before the destination or the dates changed are stale
+	if trip.Destination != arg.Destination || !trip.StartsAt.Time.Equal(arg.StartsAt.Time) || !trip.EndsAt.Time.Equal(arg.EndsAt.Time) {
+		trip.InvitationsQueuedAt = pgtype.Timestamp{}
+	}
+
+	trip.Destination = arg.Destination
+	trip.EndsAt = arg.EndsAt
+	trip.StartsAt = arg.StartsAt
+	trip.IsConfirmed = arg.IsConfirmed
+	trip.Description = arg.Description
+	trip.ImageUrl = arg.ImageUrl
+	trip.UpdatedAt = now()
+	trip.Version++
+	s.trips[trip.ID] = trip
+
+	return 1
+}
+
+func (s *Store) UpdateTrip(ctx context.Context, arg pgstore.UpdateTripParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.updateTrip(arg), nil
+}
+
+// UpdateTripAndNotify updates a trip and, unless changes is nil, enqueues the
+// email telling its participants about them. It returns 0, enqueuing nothing,
+// if the trip wasn't updated.
+func (s *Store) UpdateTripAndNotify(ctx context.Context, params pgstore.UpdateTripParams, changes *pgstore.TripChanges) (int64, error) {
+	var payload []byte
+	if changes != nil {
+		var err error
+		payload, err = json.Marshal(changes)
+		if err != nil {
+			return 0, fmt.Errorf("memstore: failed to encode changes for UpdateTripAndNotify: %w", err)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	updated := s.updateTrip(params)
+	if updated == 0 || changes == nil {
+		return updated, nil
+	}
+
+	s.enqueueEmail(pgstore.EmailTripUpdated, params.ID, payload)
+	return updated, nil
+}
+
+func (s *Store) SetTripArchived(ctx context.Context, arg pgstore.SetTripArchivedParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if trip, ok := s.trips[arg.ID]; ok {
+		trip.Archived = arg.Archived
+		trip.UpdatedAt = now()
+		trip.Version++
+		s.trips[trip.ID] = trip
+	}
+	return nil
+}
+
+// ConfirmTripAndInvite confirms a trip and enqueues the invitations of its
+// participants and the itinerary for its owner. It returns 0, enqueuing nothing,
+// if the trip was already confirmed.
+func (s *Store) ConfirmTripAndInvite(ctx context.Context, tripID uuid.UUID) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	trip, ok := s.trips[tripID]
+	if !ok || trip.IsConfirmed {
+		return 0, nil
+	}
+
+	ts := now()
+	trip.IsConfirmed = true
+	// Only queued anew the first time, or after the trip changed, so confirming
+	// it again doesn't invite anyone twice
+	if !trip.InvitationsQueuedAt.Valid {
+		trip.InvitationsQueuedAt = ts
+	}
+	trip.InvitationsSentAt = pgtype.Timestamp{}
+	trip.UpdatedAt = ts
+	trip.Version++
+	s.trips[trip.ID] = trip
+
+	s.enqueueEmail(pgstore.EmailConfirmTripParticipants, tripID, nil)
+	s.enqueueEmail(pgstore.EmailTripItinerary, tripID, nil)
+
+	return 1, nil
+}
+
+// CancelTripAndNotify cancels a trip and enqueues the notice to its participants.
+// It returns 0, enqueuing nothing, if the trip was already cancelled.
+func (s *Store) CancelTripAndNotify(ctx context.Context, tripID uuid.UUID) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	trip, ok := s.trips[tripID]
+	if !ok || trip.Status == tripCancelled {
+		return 0, nil
+	}
+
+	trip.Status = tripCancelled
+	trip.UpdatedAt = now()
+	trip.Version++
+	s.trips[trip.ID] = trip
+
+	s.enqueueEmail(pgstore.EmailTripCancelled, tripID, nil)
+
+	return 1, nil
+}
+
+func (s *Store) MarkTripInvitationsSent(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if trip, ok := s.trips[id]; ok {
+		trip.InvitationsSentAt = now()
+		s.trips[trip.ID] = trip
+	}
+	return nil
+}
+
+// DeleteTrip deletes a trip along with its participants, activities, links and
+// email log, as the foreign keys cascade on Postgres.
+func (s *Store) DeleteTrip(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.trips, id)
+	deleteTripRows(s.participants, id, func(p pgstore.Participant) uuid.UUID { return p.TripID })
+	deleteTripRows(s.activities, id, func(a pgstore.Activity) uuid.UUID { return a.TripID })
+	deleteTripRows(s.links, id, func(l pgstore.Link) uuid.UUID { return l.TripID })
+	deleteTripRows(s.emailLog, id, func(e pgstore.EmailLog) uuid.UUID { return e.TripID })
+	return nil
+}
+
+// deleteTripRows deletes the rows of table belonging to the trip tripID.
+func deleteTripRows[V any](table map[uuid.UUID]V, tripID uuid.UUID, tripOf func(V) uuid.UUID) {
+	for id, row := range table {
+		if tripOf(row) == tripID {
+			delete(table, id)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package pgstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// The errors the constraints of the schema are reported as, the violation
+// itself staying wrapped along with them.
+var (
+	ErrDuplicateParticipant = errors.New("pgstore: participant already invited to this trip")
+	ErrDuplicateLink        = errors.New("pgstore: trip already has a link to this url")
+	// ErrTripNotFound is a row referencing a trip that doesn't exist, or was
+	// deleted meanwhile.
+	ErrTripNotFound = errors.New("pgstore: trip not found")
+)
+
+// The SQLSTATE codes of the constraint violations that are mapped.
+const (
+	uniqueViolation     = "23505"
+	foreignKeyViolation = "23503"
+)
+
+// constraintErrors are the errors each constraint is reported as when violated.
+var constraintErrors = map[string]error{
+	"participants_trip_id_lower_email_idx": ErrDuplicateParticipant,
+	"links_trip_id_url_idx":                ErrDuplicateLink,
+	"participants_trip_id_fkey":            ErrTripNotFound,
+	"activities_trip_id_fkey":              ErrTripNotFound,
+	"links_trip_id_fkey":                   ErrTripNotFound,
+}
+
+// mapConstraintError turns the violation of a known unique or foreign key
+// constraint into its error, leaving any other error as is.
+func mapConstraintError(err error) error {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return err
+	}
+
+	if pgErr.Code != uniqueViolation && pgErr.Code != foreignKeyViolation {
+		return err
+	}
+
+	if mapped, ok := constraintErrors[pgErr.ConstraintName]; ok {
+		return fmt.Errorf("%w: %w", mapped, err)
+	}
+
+	return err
+}
+
+// copyLine is the row, counting from 1, a COPY failed at, from the context
+// Postgres reports it in: "COPY participants, line 3" with the column at
+// fault appended, if any. It's 0 if err isn't a COPY that failed at a row.
+func copyLine(err error) int {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return 0
+	}
+
+	_, line, ok := strings.Cut(pgErr.Where, ", line ")
+	if !ok {
+		return 0
+	}
+	line, _, _ = strings.Cut(line, ",")
+	n, err := strconv.Atoi(line)
+	if err != nil {
+		return 0
+	}
+
+	return n
+}
+
+// The queries below are the generated ones with their constraint violations
+// mapped, the API calling them directly.
+
+func (s *Store) CreateActivity(ctx context.Context, arg CreateActivityParams) (uuid.UUID, error) {
+	id, err := s.Queries.CreateActivity(ctx, arg)
+	return id, mapConstraintError(err)
+}
+
+func (s *Store) CreateTripLink(ctx context.Context, arg CreateTripLinkParams) (uuid.UUID, error) {
+	id, err := s.Queries.CreateTripLink(ctx, arg)
+	return id, mapConstraintError(err)
+}
+
+func (s *Store) UpdateLink(ctx context.Context, arg UpdateLinkParams) error {
+	return mapConstraintError(s.Queries.UpdateLink(ctx, arg))
+}
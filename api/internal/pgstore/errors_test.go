@@ -0,0 +1,78 @@
+package pgstore
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+func TestMapConstraintError(t *testing.T) {
+	other := errors.New("connection reset")
+	tests := []struct {
+		name string
+		err  error
+		want error
+	}{
+		{"duplicate participant", &pgconn.PgError{Code: uniqueViolation, ConstraintName: "participants_trip_id_lower_email_idx"}, ErrDuplicateParticipant},
+		{"duplicate link", &pgconn.PgError{Code: uniqueViolation, ConstraintName: "links_trip_id_url_idx"}, ErrDuplicateLink},
+		{"participant of a missing trip", &pgconn.PgError{Code: foreignKeyViolation, ConstraintName: "participants_trip_id_fkey"}, ErrTripNotFound},
+		{"activity of a missing trip", &pgconn.PgError{Code: foreignKeyViolation, ConstraintName: "activities_trip_id_fkey"}, ErrTripNotFound},
+		{"link of a missing trip", &pgconn.PgError{Code: foreignKeyViolation, ConstraintName: "links_trip_id_fkey"}, ErrTripNotFound},
+		{"wrapped violation", fmt.Errorf("insert: %w", &pgconn.PgError{Code: uniqueViolation, ConstraintName: "links_trip_id_url_idx"}), ErrDuplicateLink},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := mapConstraintError(tt.err)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("mapConstraintError(%v) = %v, want %v", tt.err, err, tt.want)
+			}
+			// The violation stays available to whoever needs its details
+			var pgErr *pgconn.PgError
+			if !errors.As(err, &pgErr) {
+				t.Errorf("mapConstraintError(%v) = %v, which doesn't wrap the violation", tt.err, err)
+			}
+		})
+	}
+
+	unmapped := []struct {
+		name string
+		err  error
+	}{
+		{"no error", nil},
+		{"not a Postgres error", other},
+		{"unknown constraint", &pgconn.PgError{Code: uniqueViolation, ConstraintName: "emails_pkey"}},
+		{"other violation of a known constraint", &pgconn.PgError{Code: "23502", ConstraintName: "links_trip_id_url_idx"}},
+	}
+	for _, tt := range unmapped {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := mapConstraintError(tt.err); err != tt.err {
+				t.Errorf("mapConstraintError(%v) = %v, want it unchanged", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestCopyLine(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"failed row", &pgconn.PgError{Code: uniqueViolation, Where: "COPY participants, line 3"}, 3},
+		{"failed column of a row", &pgconn.PgError{Code: "22001", Where: `COPY participants, line 12, column email: "ana@example.com"`}, 12},
+		{"wrapped", fmt.Errorf("copy: %w", &pgconn.PgError{Where: "COPY participants, line 1"}), 1},
+		{"not at a row", &pgconn.PgError{Code: uniqueViolation}, 0},
+		{"not a number", &pgconn.PgError{Where: "COPY participants, line x"}, 0},
+		{"not a Postgres error", errors.New("connection reset"), 0},
+		{"no error", nil, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := copyLine(tt.err); got != tt.want {
+				t.Errorf("copyLine(%v) = %d, want %d", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,144 @@
+package pgstore_test
+
+import (
+	"context"
+	"journey/internal/api/spec"
+	"journey/internal/pgstore"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/discord-gophers/goapi-gen/types"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// latencyConn is a connection to a database latency away, counting the
+// roundtrips made on it: each time the client writes after having read, or
+// for the first time, it waits latency for its request to get there and the
+// answer to come back.
+type latencyConn struct {
+	net.Conn
+	latency time.Duration
+
+	mu         sync.Mutex
+	reading    bool
+	roundtrips int
+}
+
+func (c *latencyConn) Write(b []byte) (int, error) {
+	c.mu.Lock()
+	if c.reading || c.roundtrips == 0 {
+		c.roundtrips++
+		c.reading = false
+		c.mu.Unlock()
+		time.Sleep(c.latency)
+	} else {
+		c.mu.Unlock()
+	}
+	return c.Conn.Write(b)
+}
+
+func (c *latencyConn) Read(b []byte) (int, error) {
+	c.mu.Lock()
+	c.reading = true
+	c.mu.Unlock()
+	return c.Conn.Read(b)
+}
+
+// count is how many roundtrips fn made on c, and how long it took.
+func (c *latencyConn) count(fn func()) (int, time.Duration) {
+	c.mu.Lock()
+	before := c.roundtrips
+	c.reading = true
+	c.mu.Unlock()
+
+	start := time.Now()
+	fn()
+	elapsed := time.Since(start)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.roundtrips - before, elapsed
+}
+
+// TestExportTripRoundtrips checks ExportTrip reads a trip, its participants,
+// activities and links in one roundtrip to a remote database, where reading
+// them one after the other takes four.
+func TestExportTripRoundtrips(t *testing.T) {
+	const latency = 20 * time.Millisecond
+
+	var conn *latencyConn
+	pool := newPool(t, func(config *pgxpool.Config) {
+		// A single connection, so every query goes through conn
+		config.MaxConns = 1
+		config.ConnConfig.DialFunc = func(ctx context.Context, network, addr string) (net.Conn, error) {
+			c, err := (&net.Dialer{}).DialContext(ctx, network, addr)
+			if err != nil {
+				return nil, err
+			}
+			conn = &latencyConn{Conn: c, latency: latency}
+			return conn, nil
+		}
+	})
+	store := pgstore.NewStore(pool, pool, 5*time.Second)
+
+	ctx := context.Background()
+	startsAt := time.Date(2030, time.March, 10, 9, 0, 0, 0, time.UTC)
+	tripID, err := store.CreateTrip(ctx, spec.CreateTripRequest{
+		Destination:    "Lisbon",
+		OwnerEmail:     "owner@example.com",
+		OwnerName:      "Owner",
+		EmailsToInvite: []types.Email{"ana@example.com", "bruno@example.com"},
+		StartsAt:       startsAt,
+		EndsAt:         startsAt.Add(5 * 24 * time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("CreateTrip: %v", err)
+	}
+	if _, err := store.CreateActivity(ctx, pgstore.CreateActivityParams{TripID: tripID, Title: "Museum", OccursAt: pgtype.Timestamp{Valid: true, Time: startsAt.Add(time.Hour)}, Tags: []string{}}); err != nil {
+		t.Fatalf("CreateActivity: %v", err)
+	}
+	if _, err := store.CreateTripLink(ctx, pgstore.CreateTripLinkParams{TripID: tripID, Title: "Hotel", Url: "https://example.com/hotel"}); err != nil {
+		t.Fatalf("CreateTripLink: %v", err)
+	}
+
+	export := func() {
+		if _, err := store.ExportTrip(ctx, tripID); err != nil {
+			t.Fatalf("ExportTrip: %v", err)
+		}
+	}
+	sequential := func() {
+		if _, err := store.GetTrip(ctx, tripID); err != nil {
+			t.Fatalf("GetTrip: %v", err)
+		}
+		if _, err := store.GetParticipants(ctx, tripID); err != nil {
+			t.Fatalf("GetParticipants: %v", err)
+		}
+		if _, err := store.GetTripActivities(ctx, pgstore.GetTripActivitiesParams{TripID: tripID}); err != nil {
+			t.Fatalf("GetTripActivities: %v", err)
+		}
+		if _, err := store.ListTripLinks(ctx, pgstore.ListTripLinksParams{TripID: tripID}); err != nil {
+			t.Fatalf("ListTripLinks: %v", err)
+		}
+	}
+
+	// The first run prepares the statements, which takes roundtrips of its own
+	export()
+	sequential()
+
+	batched, batchedTook := conn.count(export)
+	oneByOne, oneByOneTook := conn.count(sequential)
+	t.Logf("ExportTrip: %d roundtrips in %s, one query at a time: %d roundtrips in %s", batched, batchedTook, oneByOne, oneByOneTook)
+
+	if batched != 1 {
+		t.Errorf("ExportTrip made %d roundtrips, want 1", batched)
+	}
+	if oneByOne != 4 {
+		t.Errorf("reading the trip one query at a time made %d roundtrips, want 4", oneByOne)
+	}
+	if batchedTook >= oneByOneTook {
+		t.Errorf("ExportTrip took %s, not less than the %s of reading the trip one query at a time", batchedTook, oneByOneTook)
+	}
+}
@@ -0,0 +1,151 @@
+package pgstore
+
+import (
+	"context"
+	"encoding/json"
+	"expvar"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// The query metrics are published through expvar under "pgstore", keyed by
+// the name sqlc gives the query, "other" for the statements it didn't
+// generate:
+//
+//	queries_failed_total    queries that returned an error
+//	query_duration_seconds  histogram of how long each query took
+//
+// along with the connections of each pool, keyed by the pool and sampled
+// every PoolStatsInterval:
+//
+//	pool_acquired_conns  connections in use
+//	pool_idle_conns      connections waiting to be used
+//	pool_total_conns     connections open or being opened
+var (
+	metrics           = expvar.NewMap("pgstore")
+	queriesFailed     = new(expvar.Map)
+	queryDuration     = new(expvar.Map)
+	poolAcquiredConns = new(expvar.Map)
+	poolIdleConns     = new(expvar.Map)
+	poolTotalConns    = new(expvar.Map)
+
+	// queryDurationMu keeps two first runs of a query from both creating its
+	// histogram.
+	queryDurationMu sync.Mutex
+)
+
+// queryDurationBuckets are the upper bounds, in seconds, of the query duration
+// histogram.
+var queryDurationBuckets = []float64{0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5}
+
+// PoolStatsInterval is how often SamplePoolStats samples the connections of a
+// pool.
+const PoolStatsInterval = 10 * time.Second
+
+func init() {
+	metrics.Set("queries_failed_total", queriesFailed)
+	metrics.Set("query_duration_seconds", queryDuration)
+	metrics.Set("pool_acquired_conns", poolAcquiredConns)
+	metrics.Set("pool_idle_conns", poolIdleConns)
+	metrics.Set("pool_total_conns", poolTotalConns)
+}
+
+func observeQuery(name string, elapsed time.Duration, err error) {
+	if name == "" {
+		name = "other"
+	}
+	key := "query=" + name
+
+	if err != nil {
+		queriesFailed.Add(key, 1)
+	}
+
+	queryDurationMu.Lock()
+	h, ok := queryDuration.Get(key).(*histogram)
+	if !ok {
+		h = &histogram{counts: make([]uint64, len(queryDurationBuckets))}
+		queryDuration.Set(key, h)
+	}
+	queryDurationMu.Unlock()
+
+	h.observe(elapsed.Seconds())
+}
+
+// SamplePoolStats publishes the connections of pool under name, sampling them
+// every PoolStatsInterval until ctx is done.
+func SamplePoolStats(ctx context.Context, name string, pool *pgxpool.Pool) {
+	key := "pool=" + name
+	sample := func() {
+		stat := pool.Stat()
+		setGauge(poolAcquiredConns, key, int64(stat.AcquiredConns()))
+		setGauge(poolIdleConns, key, int64(stat.IdleConns()))
+		setGauge(poolTotalConns, key, int64(stat.TotalConns()))
+	}
+
+	sample()
+	ticker := time.NewTicker(PoolStatsInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sample()
+		}
+	}
+}
+
+func setGauge(gauges *expvar.Map, key string, value int64) {
+	gauge, ok := gauges.Get(key).(*expvar.Int)
+	if !ok {
+		gauge = new(expvar.Int)
+		gauges.Set(key, gauge)
+	}
+	gauge.Set(value)
+}
+
+// histogram is an expvar.Var counting the observations under each of
+// queryDurationBuckets, cumulatively like Prometheus does.
+type histogram struct {
+	mu     sync.Mutex
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+func (h *histogram) observe(value float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for i, bound := range queryDurationBuckets {
+		if value <= bound {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += value
+}
+
+func (h *histogram) String() string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	type bucket struct {
+		LE    float64 `json:"le"`
+		Count uint64  `json:"count"`
+	}
+	buckets := make([]bucket, len(queryDurationBuckets))
+	for i, bound := range queryDurationBuckets {
+		buckets[i] = bucket{bound, h.counts[i]}
+	}
+
+	b, _ := json.Marshal(struct {
+		Buckets []bucket `json:"buckets"`
+		Count   uint64   `json:"count"`
+		Sum     float64  `json:"sum"`
+	}{buckets, h.count, h.sum})
+
+	return string(b)
+}
@@ -0,0 +1,57 @@
+package pgstore
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/jackc/tern/v2/migrate"
+	"go.uber.org/zap"
+)
+
+//go:embed migrations/*.sql
+var migrations embed.FS
+
+// migrationsVersionTable is the table tern keeps the version of the schema in,
+// the one `tern migrate` uses too so both can migrate the same database.
+const migrationsVersionTable = "public.schema_version"
+
+// Migrate brings the schema up to date with the migrations embedded in the
+// binary, logging each one it runs. When one fails the error names it.
+func Migrate(ctx context.Context, pool *pgxpool.Pool, logger *zap.Logger) error {
+	conn, err := pool.Acquire(ctx)
+	if err != nil {
+		return fmt.Errorf("pgstore: failed to connect to migrate: %w", err)
+	}
+	defer conn.Release()
+
+	migrator, err := migrate.NewMigrator(ctx, conn.Conn(), migrationsVersionTable)
+	if err != nil {
+		return fmt.Errorf("pgstore: failed to create the migrator: %w", err)
+	}
+
+	fsys, err := fs.Sub(migrations, "migrations")
+	if err != nil {
+		return fmt.Errorf("pgstore: failed to load the migrations: %w", err)
+	}
+	if err := migrator.LoadMigrations(fsys); err != nil {
+		return fmt.Errorf("pgstore: failed to load the migrations: %w", err)
+	}
+
+	var running string
+	migrator.OnStart = func(_ int32, name, direction, _ string) {
+		running = name
+		logger.Info("running migration", zap.String("migration", name), zap.String("direction", direction))
+	}
+
+	if err := migrator.Migrate(ctx); err != nil {
+		if running == "" {
+			return fmt.Errorf("pgstore: failed to migrate: %w", err)
+		}
+		return fmt.Errorf("pgstore: failed to run migration %s: %w", running, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,5 @@
+CREATE INDEX IF NOT EXISTS trips_owner_email_idx ON trips ("owner_email");
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS trips_owner_email_idx;
@@ -0,0 +1,11 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX IF NOT EXISTS trips_destination_trgm_idx ON trips USING GIN ("destination" gin_trgm_ops);
+CREATE INDEX IF NOT EXISTS trips_owner_name_trgm_idx ON trips USING GIN ("owner_name" gin_trgm_ops);
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS trips_owner_name_trgm_idx;
+DROP INDEX IF EXISTS trips_destination_trgm_idx;
+
+DROP EXTENSION IF EXISTS pg_trgm;
@@ -0,0 +1,7 @@
+ALTER TABLE links
+    ADD COLUMN IF NOT EXISTS "created_at"   TIMESTAMP   NOT NULL    DEFAULT now();
+
+---- create above / drop below ----
+
+ALTER TABLE links
+    DROP COLUMN IF EXISTS "created_at";
@@ -0,0 +1,11 @@
+ALTER TABLE activities
+    ADD COLUMN IF NOT EXISTS "tags"     TEXT[]      NOT NULL    DEFAULT '{}';
+
+CREATE INDEX IF NOT EXISTS activities_tags_idx ON activities USING GIN ("tags");
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS activities_tags_idx;
+
+ALTER TABLE activities
+    DROP COLUMN IF EXISTS "tags";
@@ -0,0 +1,5 @@
+CREATE INDEX IF NOT EXISTS participants_email_idx ON participants ("email");
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS participants_email_idx;
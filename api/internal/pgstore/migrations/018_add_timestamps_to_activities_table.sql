@@ -0,0 +1,9 @@
+ALTER TABLE activities
+    ADD COLUMN IF NOT EXISTS "created_at"   TIMESTAMP   NOT NULL    DEFAULT now(),
+    ADD COLUMN IF NOT EXISTS "updated_at"   TIMESTAMP   NOT NULL    DEFAULT now();
+
+---- create above / drop below ----
+
+ALTER TABLE activities
+    DROP COLUMN IF EXISTS "updated_at",
+    DROP COLUMN IF EXISTS "created_at";
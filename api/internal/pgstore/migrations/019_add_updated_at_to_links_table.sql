@@ -0,0 +1,7 @@
+ALTER TABLE links
+    ADD COLUMN IF NOT EXISTS "updated_at"   TIMESTAMP   NOT NULL    DEFAULT now();
+
+---- create above / drop below ----
+
+ALTER TABLE links
+    DROP COLUMN IF EXISTS "updated_at";
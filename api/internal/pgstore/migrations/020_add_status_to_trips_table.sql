@@ -0,0 +1,8 @@
+ALTER TABLE trips
+    ADD COLUMN IF NOT EXISTS "status"       TEXT        NOT NULL    DEFAULT 'active'
+        CHECK ("status" IN ('active', 'cancelled'));
+
+---- create above / drop below ----
+
+ALTER TABLE trips
+    DROP COLUMN IF EXISTS "status";
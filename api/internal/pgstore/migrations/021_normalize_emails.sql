@@ -0,0 +1,32 @@
+-- Keep a single participant per trip and address, preferring the one that confirmed
+DELETE FROM participants p
+USING (
+    SELECT
+        "id",
+        ROW_NUMBER() OVER (
+            PARTITION BY "trip_id", LOWER(TRIM("email"))
+            ORDER BY "is_confirmed" DESC, "is_declined", "id"
+        ) AS "position"
+    FROM participants
+) ranked
+WHERE
+    p.id = ranked.id AND ranked.position > 1;
+
+UPDATE participants
+SET
+    "email" = LOWER(TRIM("email"))
+WHERE
+    "email" <> LOWER(TRIM("email"));
+
+UPDATE trips
+SET
+    "owner_email" = LOWER(TRIM("owner_email"))
+WHERE
+    "owner_email" <> LOWER(TRIM("owner_email"));
+
+CREATE UNIQUE INDEX IF NOT EXISTS participants_trip_id_email_idx ON participants ("trip_id", "email");
+
+---- create above / drop below ----
+
+-- The original casing of the addresses and the merged participants are not restored
+DROP INDEX IF EXISTS participants_trip_id_email_idx;
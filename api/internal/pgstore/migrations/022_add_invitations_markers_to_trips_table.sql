@@ -0,0 +1,17 @@
+ALTER TABLE trips
+    ADD COLUMN IF NOT EXISTS "invitations_queued_at"    TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS "invitations_sent_at"      TIMESTAMP;
+
+-- Trips confirmed before the markers existed already had their invitations sent
+UPDATE trips
+SET
+    "invitations_queued_at" = "updated_at",
+    "invitations_sent_at" = "updated_at"
+WHERE
+    "is_confirmed";
+
+---- create above / drop below ----
+
+ALTER TABLE trips
+    DROP COLUMN IF EXISTS "invitations_sent_at",
+    DROP COLUMN IF EXISTS "invitations_queued_at";
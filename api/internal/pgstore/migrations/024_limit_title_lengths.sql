@@ -0,0 +1,46 @@
+-- Values stored before the limits existed are cut down to fit them
+UPDATE trips
+SET
+    "destination" = LEFT("destination", 120)
+WHERE
+    LENGTH("destination") > 120;
+
+UPDATE trips
+SET
+    "owner_name" = LEFT("owner_name", 100)
+WHERE
+    LENGTH("owner_name") > 100;
+
+UPDATE activities
+SET
+    "title" = LEFT("title", 200)
+WHERE
+    LENGTH("title") > 200;
+
+UPDATE links
+SET
+    "title" = LEFT("title", 200)
+WHERE
+    LENGTH("title") > 200;
+
+ALTER TABLE trips
+    ALTER COLUMN "destination"  TYPE VARCHAR(120),
+    ALTER COLUMN "owner_name"   TYPE VARCHAR(100);
+
+ALTER TABLE activities
+    ALTER COLUMN "title"        TYPE VARCHAR(200);
+
+ALTER TABLE links
+    ALTER COLUMN "title"        TYPE VARCHAR(200);
+
+---- create above / drop below ----
+
+ALTER TABLE trips
+    ALTER COLUMN "destination"  TYPE VARCHAR(255),
+    ALTER COLUMN "owner_name"   TYPE VARCHAR(255);
+
+ALTER TABLE activities
+    ALTER COLUMN "title"        TYPE VARCHAR(255);
+
+ALTER TABLE links
+    ALTER COLUMN "title"        TYPE VARCHAR(255);
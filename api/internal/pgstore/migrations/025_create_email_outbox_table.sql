@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS email_outbox (
+    "id"            uuid            PRIMARY KEY NOT NULL    DEFAULT gen_random_uuid(),
+    "kind"          VARCHAR(50)                 NOT NULL,
+    "subject_id"    uuid                        NOT NULL,
+    "status"        VARCHAR(20)                 NOT NULL    DEFAULT 'pending',
+    "attempts"      INTEGER                     NOT NULL    DEFAULT 0,
+    "last_error"    TEXT,
+    "available_at"  TIMESTAMP                   NOT NULL    DEFAULT now(),
+    "created_at"    TIMESTAMP                   NOT NULL    DEFAULT now(),
+    "sent_at"       TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS email_outbox_pending_idx ON email_outbox ("available_at") WHERE "status" = 'pending';
+
+-- Invitations of confirmed trips that were never sent are handed over to the outbox
+INSERT INTO email_outbox
+    ( "kind", "subject_id" )
+SELECT 'confirm_trip_participants', "id"
+FROM trips
+WHERE
+    "is_confirmed" AND "invitations_sent_at" IS NULL;
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS email_outbox;
@@ -0,0 +1,8 @@
+-- The emails were only written in Portuguese before
+ALTER TABLE trips
+    ADD COLUMN IF NOT EXISTS "locale"   VARCHAR(10) NOT NULL    DEFAULT 'pt-BR';
+
+---- create above / drop below ----
+
+ALTER TABLE trips
+    DROP COLUMN IF EXISTS "locale";
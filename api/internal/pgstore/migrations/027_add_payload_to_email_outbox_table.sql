@@ -0,0 +1,8 @@
+-- What an email needs beyond its subject, such as what changed in a trip
+ALTER TABLE email_outbox
+    ADD COLUMN IF NOT EXISTS "payload"  JSONB;
+
+---- create above / drop below ----
+
+ALTER TABLE email_outbox
+    DROP COLUMN IF EXISTS "payload";
@@ -0,0 +1,27 @@
+-- The addresses that unsubscribed, which are never emailed again
+CREATE TABLE IF NOT EXISTS email_suppressions (
+    "email"         VARCHAR(255)    PRIMARY KEY NOT NULL,
+    "created_at"    TIMESTAMP                   NOT NULL    DEFAULT now()
+);
+
+-- The tokens of the unsubscribe links, one per participant and one for the owner
+-- of every trip
+ALTER TABLE participants
+    ADD COLUMN IF NOT EXISTS "unsubscribe_token"        uuid    NOT NULL    DEFAULT gen_random_uuid();
+
+CREATE UNIQUE INDEX IF NOT EXISTS participants_unsubscribe_token_idx ON participants ("unsubscribe_token");
+
+ALTER TABLE trips
+    ADD COLUMN IF NOT EXISTS "owner_unsubscribe_token"  uuid    NOT NULL    DEFAULT gen_random_uuid();
+
+CREATE UNIQUE INDEX IF NOT EXISTS trips_owner_unsubscribe_token_idx ON trips ("owner_unsubscribe_token");
+
+---- create above / drop below ----
+
+ALTER TABLE trips
+    DROP COLUMN IF EXISTS "owner_unsubscribe_token";
+
+ALTER TABLE participants
+    DROP COLUMN IF EXISTS "unsubscribe_token";
+
+DROP TABLE IF EXISTS email_suppressions;
@@ -0,0 +1,19 @@
+-- Every attempt to send an email, kept with the trip so support can tell who got what
+CREATE TABLE IF NOT EXISTS email_log (
+    "id"                uuid            PRIMARY KEY NOT NULL    DEFAULT gen_random_uuid(),
+    "trip_id"           uuid                        NOT NULL,
+    "participant_id"    uuid,
+    "recipient"         VARCHAR(255)                NOT NULL,
+    "kind"              VARCHAR(50)                 NOT NULL,
+    "status"            VARCHAR(20)                 NOT NULL,
+    "error"             TEXT,
+    "created_at"        TIMESTAMP                   NOT NULL    DEFAULT now(),
+    FOREIGN KEY (trip_id) REFERENCES trips(id) ON DELETE CASCADE,
+    FOREIGN KEY (participant_id) REFERENCES participants(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS email_log_trip_id_idx ON email_log ("trip_id", "created_at");
+
+---- create above / drop below ----
+
+DROP TABLE IF EXISTS email_log;
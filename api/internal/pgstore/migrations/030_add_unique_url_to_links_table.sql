@@ -0,0 +1,20 @@
+-- Keep a single link per trip and URL
+DELETE FROM links l
+USING (
+    SELECT
+        "id",
+        ROW_NUMBER() OVER (
+            PARTITION BY "trip_id", "url"
+            ORDER BY "id"
+        ) AS "position"
+    FROM links
+) ranked
+WHERE
+    l.id = ranked.id AND ranked.position > 1;
+
+CREATE UNIQUE INDEX IF NOT EXISTS links_trip_id_url_idx ON links ("trip_id", "url");
+
+---- create above / drop below ----
+
+-- The merged links are not restored
+DROP INDEX IF EXISTS links_trip_id_url_idx;
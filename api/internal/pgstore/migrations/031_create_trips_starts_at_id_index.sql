@@ -0,0 +1,6 @@
+-- Lets the trips listing seek to a cursor instead of scanning the trips before it
+CREATE INDEX IF NOT EXISTS trips_starts_at_id_idx ON trips ("starts_at", "id");
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS trips_starts_at_id_idx;
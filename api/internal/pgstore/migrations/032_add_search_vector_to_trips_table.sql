@@ -0,0 +1,18 @@
+-- The words of the destination and the description, the destination's ranking
+-- higher. The simple configuration doesn't stem them, the trips being in
+-- every locale.
+ALTER TABLE trips
+    ADD COLUMN IF NOT EXISTS "search_vector"    TSVECTOR    NOT NULL
+        GENERATED ALWAYS AS (
+            setweight(to_tsvector('simple', "destination"), 'A') ||
+            setweight(to_tsvector('simple', COALESCE("description", '')), 'B')
+        ) STORED;
+
+CREATE INDEX IF NOT EXISTS trips_search_vector_idx ON trips USING GIN ("search_vector");
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS trips_search_vector_idx;
+
+ALTER TABLE trips
+    DROP COLUMN IF EXISTS "search_vector";
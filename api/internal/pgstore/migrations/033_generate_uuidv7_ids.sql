@@ -0,0 +1,35 @@
+-- Time ordered IDs for the new trips, participants, activities and links, so they
+-- append to the end of the primary key indexes and the "id" tiebreakers follow the
+-- order they were created in. The existing random ones keep working as they are.
+-- Postgres 18 ships its own uuidv7(), which pg_catalog resolves first, so this
+-- one is kept in public, out of its way.
+CREATE OR REPLACE FUNCTION public.uuidv7() RETURNS uuid AS $$
+    SELECT encode(
+        set_bit(
+            set_bit(
+                overlay(
+                    uuid_send(gen_random_uuid())
+                    PLACING substring(int8send(floor(extract(epoch FROM clock_timestamp()) * 1000)::bigint) FROM 3)
+                    FROM 1 FOR 6
+                ),
+                52, 1
+            ),
+            53, 1
+        ),
+        'hex'
+    )::uuid;
+$$ LANGUAGE SQL VOLATILE;
+
+ALTER TABLE trips ALTER COLUMN "id" SET DEFAULT uuidv7();
+ALTER TABLE participants ALTER COLUMN "id" SET DEFAULT uuidv7();
+ALTER TABLE activities ALTER COLUMN "id" SET DEFAULT uuidv7();
+ALTER TABLE links ALTER COLUMN "id" SET DEFAULT uuidv7();
+
+---- create above / drop below ----
+
+ALTER TABLE links ALTER COLUMN "id" SET DEFAULT gen_random_uuid();
+ALTER TABLE activities ALTER COLUMN "id" SET DEFAULT gen_random_uuid();
+ALTER TABLE participants ALTER COLUMN "id" SET DEFAULT gen_random_uuid();
+ALTER TABLE trips ALTER COLUMN "id" SET DEFAULT gen_random_uuid();
+
+DROP FUNCTION IF EXISTS public.uuidv7();
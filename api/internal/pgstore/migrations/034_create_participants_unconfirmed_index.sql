@@ -0,0 +1,6 @@
+-- Keeps looking up the participants yet to confirm cheap on the trips with many of them
+CREATE INDEX IF NOT EXISTS participants_unconfirmed_trip_id_idx ON participants ("trip_id") WHERE NOT "is_confirmed";
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS participants_unconfirmed_trip_id_idx;
@@ -0,0 +1,9 @@
+ALTER TABLE participants
+    ADD COLUMN IF NOT EXISTS "invite_sent_at"     TIMESTAMP,
+    ADD COLUMN IF NOT EXISTS "last_invite_error"  TEXT;
+
+---- create above / drop below ----
+
+ALTER TABLE participants
+    DROP COLUMN IF EXISTS "last_invite_error",
+    DROP COLUMN IF EXISTS "invite_sent_at";
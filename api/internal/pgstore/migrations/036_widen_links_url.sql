@@ -0,0 +1,21 @@
+-- The API accepts link URLs up to 2048 characters
+ALTER TABLE links
+    ALTER COLUMN "url"          TYPE VARCHAR(2048);
+
+---- create above / drop below ----
+
+-- A URL cut down to 255 characters would be a broken link, so the migration
+-- fails while any link doesn't fit instead of dropping it
+DO $$
+DECLARE
+    too_long BIGINT;
+BEGIN
+    SELECT COUNT(*) INTO too_long FROM links WHERE LENGTH("url") > 255;
+    IF too_long > 0 THEN
+        RAISE EXCEPTION '% links have a url longer than 255 characters, shorten or delete them first', too_long;
+    END IF;
+END
+$$;
+
+ALTER TABLE links
+    ALTER COLUMN "url"          TYPE VARCHAR(255);
@@ -0,0 +1,11 @@
+-- The addresses of the participants are unique whatever their case, not only
+-- as the API normalizes them
+DROP INDEX IF EXISTS participants_trip_id_email_idx;
+
+CREATE UNIQUE INDEX IF NOT EXISTS participants_trip_id_lower_email_idx ON participants ("trip_id", LOWER("email"));
+
+---- create above / drop below ----
+
+DROP INDEX IF EXISTS participants_trip_id_lower_email_idx;
+
+CREATE UNIQUE INDEX IF NOT EXISTS participants_trip_id_email_idx ON participants ("trip_id", "email");
@@ -0,0 +1,30 @@
+-- The words of the trips are indexed on an expression instead of the
+-- search_vector column, so the trips table keeps the columns pgstore.Trip has.
+-- The queries call trip_search_vector() the way the index does to use it.
+CREATE OR REPLACE FUNCTION trip_search_vector(destination TEXT, description TEXT) RETURNS TSVECTOR AS $$
+    SELECT
+        setweight(to_tsvector('simple', destination), 'A') ||
+        setweight(to_tsvector('simple', COALESCE(description, '')), 'B');
+$$ LANGUAGE SQL IMMUTABLE PARALLEL SAFE;
+
+CREATE INDEX IF NOT EXISTS trips_search_idx ON trips USING GIN (trip_search_vector("destination", "description"));
+
+DROP INDEX IF EXISTS trips_search_vector_idx;
+
+ALTER TABLE trips
+    DROP COLUMN IF EXISTS "search_vector";
+
+---- create above / drop below ----
+
+ALTER TABLE trips
+    ADD COLUMN IF NOT EXISTS "search_vector"    TSVECTOR    NOT NULL
+        GENERATED ALWAYS AS (
+            setweight(to_tsvector('simple', "destination"), 'A') ||
+            setweight(to_tsvector('simple', COALESCE("description", '')), 'B')
+        ) STORED;
+
+CREATE INDEX IF NOT EXISTS trips_search_vector_idx ON trips USING GIN ("search_vector");
+
+DROP INDEX IF EXISTS trips_search_idx;
+
+DROP FUNCTION IF EXISTS trip_search_vector(TEXT, TEXT);
@@ -10,32 +10,83 @@ import (
 )
 
 type Activity struct {
-	ID       uuid.UUID        `db:"id" json:"id"`
-	TripID   uuid.UUID        `db:"trip_id" json:"trip_id"`
-	Title    string           `db:"title" json:"title"`
-	OccursAt pgtype.Timestamp `db:"occurs_at" json:"occurs_at"`
+	ID        uuid.UUID        `db:"id" json:"id"`
+	TripID    uuid.UUID        `db:"trip_id" json:"trip_id"`
+	Title     string           `db:"title" json:"title"`
+	OccursAt  pgtype.Timestamp `db:"occurs_at" json:"occurs_at"`
+	Tags      []string         `db:"tags" json:"tags"`
+	CreatedAt pgtype.Timestamp `db:"created_at" json:"created_at"`
+	UpdatedAt pgtype.Timestamp `db:"updated_at" json:"updated_at"`
+}
+
+type EmailLog struct {
+	ID            uuid.UUID        `db:"id" json:"id"`
+	TripID        uuid.UUID        `db:"trip_id" json:"trip_id"`
+	ParticipantID pgtype.UUID      `db:"participant_id" json:"participant_id"`
+	Recipient     string           `db:"recipient" json:"recipient"`
+	Kind          string           `db:"kind" json:"kind"`
+	Status        string           `db:"status" json:"status"`
+	Error         pgtype.Text      `db:"error" json:"error"`
+	CreatedAt     pgtype.Timestamp `db:"created_at" json:"created_at"`
+}
+
+type EmailOutbox struct {
+	ID          uuid.UUID        `db:"id" json:"id"`
+	Kind        string           `db:"kind" json:"kind"`
+	SubjectID   uuid.UUID        `db:"subject_id" json:"subject_id"`
+	Status      string           `db:"status" json:"status"`
+	Attempts    int32            `db:"attempts" json:"attempts"`
+	LastError   pgtype.Text      `db:"last_error" json:"last_error"`
+	AvailableAt pgtype.Timestamp `db:"available_at" json:"available_at"`
+	CreatedAt   pgtype.Timestamp `db:"created_at" json:"created_at"`
+	SentAt      pgtype.Timestamp `db:"sent_at" json:"sent_at"`
+	Payload     []byte           `db:"payload" json:"payload"`
+}
+
+type EmailSuppression struct {
+	Email     string           `db:"email" json:"email"`
+	CreatedAt pgtype.Timestamp `db:"created_at" json:"created_at"`
 }
 
 type Link struct {
-	ID     uuid.UUID `db:"id" json:"id"`
-	TripID uuid.UUID `db:"trip_id" json:"trip_id"`
-	Title  string    `db:"title" json:"title"`
-	Url    string    `db:"url" json:"url"`
+	ID        uuid.UUID        `db:"id" json:"id"`
+	TripID    uuid.UUID        `db:"trip_id" json:"trip_id"`
+	Title     string           `db:"title" json:"title"`
+	Url       string           `db:"url" json:"url"`
+	CreatedAt pgtype.Timestamp `db:"created_at" json:"created_at"`
+	UpdatedAt pgtype.Timestamp `db:"updated_at" json:"updated_at"`
 }
 
 type Participant struct {
-	ID          uuid.UUID `db:"id" json:"id"`
-	TripID      uuid.UUID `db:"trip_id" json:"trip_id"`
-	Email       string    `db:"email" json:"email"`
-	IsConfirmed bool      `db:"is_confirmed" json:"is_confirmed"`
+	ID               uuid.UUID        `db:"id" json:"id"`
+	TripID           uuid.UUID        `db:"trip_id" json:"trip_id"`
+	Email            string           `db:"email" json:"email"`
+	IsConfirmed      bool             `db:"is_confirmed" json:"is_confirmed"`
+	Name             pgtype.Text      `db:"name" json:"name"`
+	IsDeclined       bool             `db:"is_declined" json:"is_declined"`
+	InviteResentAt   pgtype.Timestamp `db:"invite_resent_at" json:"invite_resent_at"`
+	UnsubscribeToken uuid.UUID        `db:"unsubscribe_token" json:"unsubscribe_token"`
+	InviteSentAt     pgtype.Timestamp `db:"invite_sent_at" json:"invite_sent_at"`
+	LastInviteError  pgtype.Text      `db:"last_invite_error" json:"last_invite_error"`
 }
 
 type Trip struct {
-	ID          uuid.UUID        `db:"id" json:"id"`
-	Destination string           `db:"destination" json:"destination"`
-	OwnerEmail  string           `db:"owner_email" json:"owner_email"`
-	OwnerName   string           `db:"owner_name" json:"owner_name"`
-	IsConfirmed bool             `db:"is_confirmed" json:"is_confirmed"`
-	StartsAt    pgtype.Timestamp `db:"starts_at" json:"starts_at"`
-	EndsAt      pgtype.Timestamp `db:"ends_at" json:"ends_at"`
+	ID                    uuid.UUID        `db:"id" json:"id"`
+	Destination           string           `db:"destination" json:"destination"`
+	OwnerEmail            string           `db:"owner_email" json:"owner_email"`
+	OwnerName             string           `db:"owner_name" json:"owner_name"`
+	IsConfirmed           bool             `db:"is_confirmed" json:"is_confirmed"`
+	StartsAt              pgtype.Timestamp `db:"starts_at" json:"starts_at"`
+	EndsAt                pgtype.Timestamp `db:"ends_at" json:"ends_at"`
+	CreatedAt             pgtype.Timestamp `db:"created_at" json:"created_at"`
+	Description           pgtype.Text      `db:"description" json:"description"`
+	ImageUrl              pgtype.Text      `db:"image_url" json:"image_url"`
+	Archived              bool             `db:"archived" json:"archived"`
+	UpdatedAt             pgtype.Timestamp `db:"updated_at" json:"updated_at"`
+	Status                string           `db:"status" json:"status"`
+	InvitationsQueuedAt   pgtype.Timestamp `db:"invitations_queued_at" json:"invitations_queued_at"`
+	InvitationsSentAt     pgtype.Timestamp `db:"invitations_sent_at" json:"invitations_sent_at"`
+	Version               int32            `db:"version" json:"version"`
+	Locale                string           `db:"locale" json:"locale"`
+	OwnerUnsubscribeToken uuid.UUID        `db:"owner_unsubscribe_token" json:"owner_unsubscribe_token"`
 }
@@ -12,10 +12,83 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const cancelTrip = `-- name: CancelTrip :execrows
+UPDATE trips
+SET
+    "status" = 'cancelled',
+    "updated_at" = now(),
+    "version" = "version" + 1
+WHERE
+    id = $1 AND "status" <> 'cancelled'
+`
+
+func (q *Queries) CancelTrip(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, cancelTrip, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const claimPendingEmails = `-- name: ClaimPendingEmails :many
+UPDATE email_outbox
+SET
+    "attempts" = "attempts" + 1,
+    "available_at" = now() + $1::interval
+WHERE
+    id IN (
+        SELECT id
+        FROM email_outbox
+        WHERE
+            "status" = 'pending' AND "available_at" <= now()
+        ORDER BY "available_at"
+        LIMIT $2
+        FOR UPDATE SKIP LOCKED
+    )
+RETURNING "id", "kind", "subject_id", "status", "attempts", "last_error", "available_at", "created_at", "sent_at", "payload"
+`
+
+type ClaimPendingEmailsParams struct {
+	Lease pgtype.Interval `db:"lease" json:"lease"`
+	Limit int32           `db:"limit" json:"limit"`
+}
+
+func (q *Queries) ClaimPendingEmails(ctx context.Context, arg ClaimPendingEmailsParams) ([]EmailOutbox, error) {
+	rows, err := q.db.Query(ctx, claimPendingEmails, arg.Lease, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []EmailOutbox
+	for rows.Next() {
+		var i EmailOutbox
+		if err := rows.Scan(
+			&i.ID,
+			&i.Kind,
+			&i.SubjectID,
+			&i.Status,
+			&i.Attempts,
+			&i.LastError,
+			&i.AvailableAt,
+			&i.CreatedAt,
+			&i.SentAt,
+			&i.Payload,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const confirmParticipant = `-- name: ConfirmParticipant :exec
-SELECT
-    "id", "trip_id", "email", "is_confirmed"
-FROM participants
+UPDATE participants
+SET
+    "is_confirmed" = TRUE,
+    "is_declined" = FALSE
 WHERE
     id = $1
 `
@@ -25,10 +98,136 @@ func (q *Queries) ConfirmParticipant(ctx context.Context, id uuid.UUID) error {
 	return err
 }
 
+const confirmTrip = `-- name: ConfirmTrip :execrows
+-- The invitations are only queued anew the first time, or after the trip
+-- changed, so confirming it again doesn't invite anyone twice.
+UPDATE trips
+SET
+    "is_confirmed" = TRUE,
+    "invitations_queued_at" = COALESCE("invitations_queued_at", now()),
+    "invitations_sent_at" = NULL,
+    "updated_at" = now(),
+    "version" = "version" + 1
+WHERE
+    id = $1 AND NOT "is_confirmed"
+`
+
+func (q *Queries) ConfirmTrip(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.Exec(ctx, confirmTrip, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const countParticipants = `-- name: CountParticipants :one
+SELECT COUNT(*)
+FROM participants
+WHERE
+    trip_id = $1
+    AND ($2::boolean IS NULL OR "is_confirmed" = $2)
+`
+
+type CountParticipantsParams struct {
+	TripID      uuid.UUID   `db:"trip_id" json:"trip_id"`
+	IsConfirmed pgtype.Bool `db:"is_confirmed" json:"is_confirmed"`
+}
+
+func (q *Queries) CountParticipants(ctx context.Context, arg CountParticipantsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countParticipants, arg.TripID, arg.IsConfirmed)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countSearchTrips = `-- name: CountSearchTrips :one
+SELECT COUNT(*)
+FROM trips
+WHERE
+    trip_search_vector("destination", "description") @@ websearch_to_tsquery('simple', $1)
+    OR "destination" ILIKE $2 OR "owner_name" ILIKE $2
+`
+
+type CountSearchTripsParams struct {
+	Query   string `db:"query" json:"query"`
+	Pattern string `db:"pattern" json:"pattern"`
+}
+
+func (q *Queries) CountSearchTrips(ctx context.Context, arg CountSearchTripsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countSearchTrips, arg.Query, arg.Pattern)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countTripEmailLog = `-- name: CountTripEmailLog :one
+SELECT COUNT(*)
+FROM email_log
+WHERE
+    trip_id = $1
+`
+
+func (q *Queries) CountTripEmailLog(ctx context.Context, tripID uuid.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countTripEmailLog, tripID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countTripLinks = `-- name: CountTripLinks :one
+SELECT COUNT(*)
+FROM links
+WHERE
+    trip_id = $1
+`
+
+func (q *Queries) CountTripLinks(ctx context.Context, tripID uuid.UUID) (int64, error) {
+	row := q.db.QueryRow(ctx, countTripLinks, tripID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countTrips = `-- name: CountTrips :one
+SELECT COUNT(*)
+FROM trips
+WHERE
+    ($1::boolean IS NULL OR "is_confirmed" = $1)
+    AND ($2::text IS NULL OR "owner_email" = $2)
+    AND ($3::boolean OR NOT "archived")
+`
+
+type CountTripsParams struct {
+	IsConfirmed     pgtype.Bool `db:"is_confirmed" json:"is_confirmed"`
+	OwnerEmail      pgtype.Text `db:"owner_email" json:"owner_email"`
+	IncludeArchived bool        `db:"include_archived" json:"include_archived"`
+}
+
+func (q *Queries) CountTrips(ctx context.Context, arg CountTripsParams) (int64, error) {
+	row := q.db.QueryRow(ctx, countTrips, arg.IsConfirmed, arg.OwnerEmail, arg.IncludeArchived)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const countTripsByParticipantEmail = `-- name: CountTripsByParticipantEmail :one
+SELECT COUNT(*)
+FROM participants
+WHERE
+    email = $1
+`
+
+func (q *Queries) CountTripsByParticipantEmail(ctx context.Context, email string) (int64, error) {
+	row := q.db.QueryRow(ctx, countTripsByParticipantEmail, email)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createActivity = `-- name: CreateActivity :one
 INSERT INTO activities
-    ( "trip_id", "title", "occurs_at" ) VALUES
-    ( $1, $2, $3 )
+    ( "trip_id", "title", "occurs_at", "tags" ) VALUES
+    ( $1, $2, $3, $4 )
 RETURNING "id"
 `
 
@@ -36,10 +235,16 @@ type CreateActivityParams struct {
 	TripID   uuid.UUID        `db:"trip_id" json:"trip_id"`
 	Title    string           `db:"title" json:"title"`
 	OccursAt pgtype.Timestamp `db:"occurs_at" json:"occurs_at"`
+	Tags     []string         `db:"tags" json:"tags"`
 }
 
 func (q *Queries) CreateActivity(ctx context.Context, arg CreateActivityParams) (uuid.UUID, error) {
-	row := q.db.QueryRow(ctx, createActivity, arg.TripID, arg.Title, arg.OccursAt)
+	row := q.db.QueryRow(ctx, createActivity,
+		arg.TripID,
+		arg.Title,
+		arg.OccursAt,
+		arg.Tags,
+	)
 	var id uuid.UUID
 	err := row.Scan(&id)
 	return id, err
@@ -65,9 +270,91 @@ func (q *Queries) CreateTripLink(ctx context.Context, arg CreateTripLinkParams)
 	return id, err
 }
 
+const declineParticipant = `-- name: DeclineParticipant :exec
+UPDATE participants
+SET
+    "is_declined" = TRUE
+WHERE
+    id = $1
+`
+
+func (q *Queries) DeclineParticipant(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, declineParticipant, id)
+	return err
+}
+
+const deleteParticipant = `-- name: DeleteParticipant :exec
+DELETE
+FROM participants
+WHERE
+    id = $1
+`
+
+func (q *Queries) DeleteParticipant(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteParticipant, id)
+	return err
+}
+
+const deleteTrip = `-- name: DeleteTrip :exec
+DELETE
+FROM trips
+WHERE
+    id = $1
+`
+
+func (q *Queries) DeleteTrip(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, deleteTrip, id)
+	return err
+}
+
+const enqueueEmail = `-- name: EnqueueEmail :exec
+INSERT INTO email_outbox
+    ( "kind", "subject_id", "payload" ) VALUES
+    ( $1, $2, $3 )
+`
+
+type EnqueueEmailParams struct {
+	Kind      string    `db:"kind" json:"kind"`
+	SubjectID uuid.UUID `db:"subject_id" json:"subject_id"`
+	Payload   []byte    `db:"payload" json:"payload"`
+}
+
+func (q *Queries) EnqueueEmail(ctx context.Context, arg EnqueueEmailParams) error {
+	_, err := q.db.Exec(ctx, enqueueEmail, arg.Kind, arg.SubjectID, arg.Payload)
+	return err
+}
+
+const getActivity = `-- name: GetActivity :one
+SELECT
+    "id", "trip_id", "title", "occurs_at", "tags", "created_at", "updated_at"
+FROM activities
+WHERE
+    id = $1 AND trip_id = $2
+`
+
+type GetActivityParams struct {
+	ID     uuid.UUID `db:"id" json:"id"`
+	TripID uuid.UUID `db:"trip_id" json:"trip_id"`
+}
+
+func (q *Queries) GetActivity(ctx context.Context, arg GetActivityParams) (Activity, error) {
+	row := q.db.QueryRow(ctx, getActivity, arg.ID, arg.TripID)
+	var i Activity
+	err := row.Scan(
+		&i.ID,
+		&i.TripID,
+		&i.Title,
+		&i.OccursAt,
+		&i.Tags,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const getAllTrips = `-- name: GetAllTrips :many
 SELECT 
-    "id", "destination", "owner_email", "owner_name", "is_confirmed", "starts_at", "ends_at"
+    "id", "destination", "owner_email", "owner_name", "is_confirmed", "starts_at", "ends_at", "created_at", "description", "image_url", "archived", "updated_at", "status", "invitations_queued_at", "invitations_sent_at", "version", "locale", "owner_unsubscribe_token"
 FROM trips
 `
 
@@ -88,6 +375,17 @@ func (q *Queries) GetAllTrips(ctx context.Context) ([]Trip, error) {
 			&i.IsConfirmed,
 			&i.StartsAt,
 			&i.EndsAt,
+			&i.CreatedAt,
+			&i.Description,
+			&i.ImageUrl,
+			&i.Archived,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.InvitationsQueuedAt,
+			&i.InvitationsSentAt,
+			&i.Version,
+			&i.Locale,
+			&i.OwnerUnsubscribeToken,
 		); err != nil {
 			return nil, err
 		}
@@ -99,9 +397,45 @@ func (q *Queries) GetAllTrips(ctx context.Context) ([]Trip, error) {
 	return items, nil
 }
 
+const getEmailByUnsubscribeToken = `-- name: GetEmailByUnsubscribeToken :one
+SELECT "email" FROM participants WHERE "unsubscribe_token" = $1
+UNION
+SELECT "owner_email" FROM trips WHERE "owner_unsubscribe_token" = $1
+LIMIT 1
+`
+
+func (q *Queries) GetEmailByUnsubscribeToken(ctx context.Context, unsubscribeToken uuid.UUID) (string, error) {
+	row := q.db.QueryRow(ctx, getEmailByUnsubscribeToken, unsubscribeToken)
+	var email string
+	err := row.Scan(&email)
+	return email, err
+}
+
+const getLink = `-- name: GetLink :one
+SELECT
+    "id", "trip_id", "title", "url", "created_at", "updated_at"
+FROM links
+WHERE
+    id = $1
+`
+
+func (q *Queries) GetLink(ctx context.Context, id uuid.UUID) (Link, error) {
+	row := q.db.QueryRow(ctx, getLink, id)
+	var i Link
+	err := row.Scan(
+		&i.ID,
+		&i.TripID,
+		&i.Title,
+		&i.Url,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const getParticipant = `-- name: GetParticipant :one
 SELECT
-    "id", "trip_id", "email", "is_confirmed"
+    "id", "trip_id", "email", "is_confirmed", "name", "is_declined", "invite_resent_at", "unsubscribe_token", "invite_sent_at", "last_invite_error"
 FROM participants
 WHERE
     id = $1
@@ -115,13 +449,91 @@ func (q *Queries) GetParticipant(ctx context.Context, id uuid.UUID) (Participant
 		&i.TripID,
 		&i.Email,
 		&i.IsConfirmed,
+		&i.Name,
+		&i.IsDeclined,
+		&i.InviteResentAt,
+		&i.UnsubscribeToken,
+		&i.InviteSentAt,
+		&i.LastInviteError,
+	)
+	return i, err
+}
+
+const getParticipantByEmail = `-- name: GetParticipantByEmail :one
+SELECT
+    "id", "trip_id", "email", "is_confirmed", "name", "is_declined", "invite_resent_at", "unsubscribe_token", "invite_sent_at", "last_invite_error"
+FROM participants
+WHERE
+    trip_id = $1 AND email = $2
+`
+
+type GetParticipantByEmailParams struct {
+	TripID uuid.UUID `db:"trip_id" json:"trip_id"`
+	Email  string    `db:"email" json:"email"`
+}
+
+func (q *Queries) GetParticipantByEmail(ctx context.Context, arg GetParticipantByEmailParams) (Participant, error) {
+	row := q.db.QueryRow(ctx, getParticipantByEmail, arg.TripID, arg.Email)
+	var i Participant
+	err := row.Scan(
+		&i.ID,
+		&i.TripID,
+		&i.Email,
+		&i.IsConfirmed,
+		&i.Name,
+		&i.IsDeclined,
+		&i.InviteResentAt,
+		&i.UnsubscribeToken,
+		&i.InviteSentAt,
+		&i.LastInviteError,
+	)
+	return i, err
+}
+
+const getParticipantWithTrip = `-- name: GetParticipantWithTrip :one
+SELECT
+    p."id", p."trip_id", p."email", p."is_confirmed", p."name", p."is_declined",
+    t."destination", t."owner_name", t."starts_at", t."ends_at"
+FROM participants p
+JOIN trips t ON t.id = p.trip_id
+WHERE
+    p.id = $1
+`
+
+type GetParticipantWithTripRow struct {
+	ID          uuid.UUID        `db:"id" json:"id"`
+	TripID      uuid.UUID        `db:"trip_id" json:"trip_id"`
+	Email       string           `db:"email" json:"email"`
+	IsConfirmed bool             `db:"is_confirmed" json:"is_confirmed"`
+	Name        pgtype.Text      `db:"name" json:"name"`
+	IsDeclined  bool             `db:"is_declined" json:"is_declined"`
+	Destination string           `db:"destination" json:"destination"`
+	OwnerName   string           `db:"owner_name" json:"owner_name"`
+	StartsAt    pgtype.Timestamp `db:"starts_at" json:"starts_at"`
+	EndsAt      pgtype.Timestamp `db:"ends_at" json:"ends_at"`
+}
+
+func (q *Queries) GetParticipantWithTrip(ctx context.Context, id uuid.UUID) (GetParticipantWithTripRow, error) {
+	row := q.db.QueryRow(ctx, getParticipantWithTrip, id)
+	var i GetParticipantWithTripRow
+	err := row.Scan(
+		&i.ID,
+		&i.TripID,
+		&i.Email,
+		&i.IsConfirmed,
+		&i.Name,
+		&i.IsDeclined,
+		&i.Destination,
+		&i.OwnerName,
+		&i.StartsAt,
+		&i.EndsAt,
 	)
 	return i, err
 }
 
 const getParticipants = `-- name: GetParticipants :many
 SELECT
-    "id", "trip_id", "email", "is_confirmed"
+    "id", "trip_id", "email", "is_confirmed", "name", "is_declined", "invite_resent_at", "unsubscribe_token", "invite_sent_at", "last_invite_error"
 FROM participants
 WHERE
     trip_id = $1
@@ -141,6 +553,12 @@ func (q *Queries) GetParticipants(ctx context.Context, tripID uuid.UUID) ([]Part
 			&i.TripID,
 			&i.Email,
 			&i.IsConfirmed,
+			&i.Name,
+			&i.IsDeclined,
+			&i.InviteResentAt,
+			&i.UnsubscribeToken,
+			&i.InviteSentAt,
+			&i.LastInviteError,
 		); err != nil {
 			return nil, err
 		}
@@ -154,7 +572,7 @@ func (q *Queries) GetParticipants(ctx context.Context, tripID uuid.UUID) ([]Part
 
 const getTrip = `-- name: GetTrip :one
 SELECT
-    "id", "destination", "owner_email", "owner_name", "is_confirmed", "starts_at", "ends_at"
+    "id", "destination", "owner_email", "owner_name", "is_confirmed", "starts_at", "ends_at", "created_at", "description", "image_url", "archived", "updated_at", "status", "invitations_queued_at", "invitations_sent_at", "version", "locale", "owner_unsubscribe_token"
 FROM trips
 WHERE
     id = $1
@@ -171,20 +589,47 @@ func (q *Queries) GetTrip(ctx context.Context, id uuid.UUID) (Trip, error) {
 		&i.IsConfirmed,
 		&i.StartsAt,
 		&i.EndsAt,
+		&i.CreatedAt,
+		&i.Description,
+		&i.ImageUrl,
+		&i.Archived,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.InvitationsQueuedAt,
+		&i.InvitationsSentAt,
+		&i.Version,
+		&i.Locale,
+		&i.OwnerUnsubscribeToken,
 	)
 	return i, err
 }
 
 const getTripActivities = `-- name: GetTripActivities :many
 SELECT
-    "id", "trip_id", "title", "occurs_at"
+    "id", "trip_id", "title", "occurs_at", "tags", "created_at", "updated_at"
 FROM activities
 WHERE
     trip_id = $1
+    AND ($2::timestamp IS NULL OR "occurs_at" >= $2)
+    AND ($3::timestamp IS NULL OR "occurs_at" < $3)
+    AND ($4::text IS NULL OR "tags" @> ARRAY[$4::text])
+ORDER BY "occurs_at", "id"
 `
 
-func (q *Queries) GetTripActivities(ctx context.Context, tripID uuid.UUID) ([]Activity, error) {
-	rows, err := q.db.Query(ctx, getTripActivities, tripID)
+type GetTripActivitiesParams struct {
+	TripID      uuid.UUID        `db:"trip_id" json:"trip_id"`
+	OccursFrom  pgtype.Timestamp `db:"occurs_from" json:"occurs_from"`
+	OccursUntil pgtype.Timestamp `db:"occurs_until" json:"occurs_until"`
+	Tag         pgtype.Text      `db:"tag" json:"tag"`
+}
+
+func (q *Queries) GetTripActivities(ctx context.Context, arg GetTripActivitiesParams) ([]Activity, error) {
+	rows, err := q.db.Query(ctx, getTripActivities,
+		arg.TripID,
+		arg.OccursFrom,
+		arg.OccursUntil,
+		arg.Tag,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -197,6 +642,9 @@ func (q *Queries) GetTripActivities(ctx context.Context, tripID uuid.UUID) ([]Ac
 			&i.TripID,
 			&i.Title,
 			&i.OccursAt,
+			&i.Tags,
+			&i.CreatedAt,
+			&i.UpdatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -210,10 +658,11 @@ func (q *Queries) GetTripActivities(ctx context.Context, tripID uuid.UUID) ([]Ac
 
 const getTripLinks = `-- name: GetTripLinks :many
 SELECT
-    "id", "trip_id", "title", "url"
+    "id", "trip_id", "title", "url", "created_at", "updated_at"
 FROM links
 WHERE
     trip_id = $1
+ORDER BY "created_at", "id"
 `
 
 func (q *Queries) GetTripLinks(ctx context.Context, tripID uuid.UUID) ([]Link, error) {
@@ -230,6 +679,125 @@ func (q *Queries) GetTripLinks(ctx context.Context, tripID uuid.UUID) ([]Link, e
 			&i.TripID,
 			&i.Title,
 			&i.Url,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getTripSummary = `-- name: GetTripSummary :one
+SELECT
+    t."id", t."destination", t."owner_email", t."owner_name", t."is_confirmed", t."starts_at", t."ends_at", t."created_at", t."description", t."image_url", t."archived", t."updated_at", t."status", t."invitations_queued_at", t."invitations_sent_at", t."version", t."locale", t."owner_unsubscribe_token",
+    (SELECT COUNT(*) FROM participants p WHERE p.trip_id = t.id) AS "participants_count",
+    (SELECT COUNT(*) FROM participants p WHERE p.trip_id = t.id AND p.is_confirmed) AS "confirmed_participants_count",
+    (SELECT COUNT(*) FROM activities a WHERE a.trip_id = t.id) AS "activities_count",
+    (SELECT COUNT(*) FROM links l WHERE l.trip_id = t.id) AS "links_count"
+FROM trips t
+WHERE
+    t.id = $1
+`
+
+type GetTripSummaryRow struct {
+	ID                         uuid.UUID        `db:"id" json:"id"`
+	Destination                string           `db:"destination" json:"destination"`
+	OwnerEmail                 string           `db:"owner_email" json:"owner_email"`
+	OwnerName                  string           `db:"owner_name" json:"owner_name"`
+	IsConfirmed                bool             `db:"is_confirmed" json:"is_confirmed"`
+	StartsAt                   pgtype.Timestamp `db:"starts_at" json:"starts_at"`
+	EndsAt                     pgtype.Timestamp `db:"ends_at" json:"ends_at"`
+	CreatedAt                  pgtype.Timestamp `db:"created_at" json:"created_at"`
+	Description                pgtype.Text      `db:"description" json:"description"`
+	ImageUrl                   pgtype.Text      `db:"image_url" json:"image_url"`
+	Archived                   bool             `db:"archived" json:"archived"`
+	UpdatedAt                  pgtype.Timestamp `db:"updated_at" json:"updated_at"`
+	Status                     string           `db:"status" json:"status"`
+	InvitationsQueuedAt        pgtype.Timestamp `db:"invitations_queued_at" json:"invitations_queued_at"`
+	InvitationsSentAt          pgtype.Timestamp `db:"invitations_sent_at" json:"invitations_sent_at"`
+	Version                    int32            `db:"version" json:"version"`
+	Locale                     string           `db:"locale" json:"locale"`
+	OwnerUnsubscribeToken      uuid.UUID        `db:"owner_unsubscribe_token" json:"owner_unsubscribe_token"`
+	ParticipantsCount          int64            `db:"participants_count" json:"participants_count"`
+	ConfirmedParticipantsCount int64            `db:"confirmed_participants_count" json:"confirmed_participants_count"`
+	ActivitiesCount            int64            `db:"activities_count" json:"activities_count"`
+	LinksCount                 int64            `db:"links_count" json:"links_count"`
+}
+
+func (q *Queries) GetTripSummary(ctx context.Context, id uuid.UUID) (GetTripSummaryRow, error) {
+	row := q.db.QueryRow(ctx, getTripSummary, id)
+	var i GetTripSummaryRow
+	err := row.Scan(
+		&i.ID,
+		&i.Destination,
+		&i.OwnerEmail,
+		&i.OwnerName,
+		&i.IsConfirmed,
+		&i.StartsAt,
+		&i.EndsAt,
+		&i.CreatedAt,
+		&i.Description,
+		&i.ImageUrl,
+		&i.Archived,
+		&i.UpdatedAt,
+		&i.Status,
+		&i.InvitationsQueuedAt,
+		&i.InvitationsSentAt,
+		&i.Version,
+		&i.Locale,
+		&i.OwnerUnsubscribeToken,
+		&i.ParticipantsCount,
+		&i.ConfirmedParticipantsCount,
+		&i.ActivitiesCount,
+		&i.LinksCount,
+	)
+	return i, err
+}
+
+const getUnconfirmedParticipants = `-- name: GetUnconfirmedParticipants :many
+-- ListParticipants filtering the unconfirmed ones, spelled the way the partial
+-- index participants_unconfirmed_trip_id_idx is so it's used.
+SELECT
+    "id", "trip_id", "email", "is_confirmed", "name", "is_declined", "invite_resent_at", "unsubscribe_token", "invite_sent_at", "last_invite_error"
+FROM participants
+WHERE
+    trip_id = $1
+    AND NOT "is_confirmed"
+ORDER BY "email", "id"
+LIMIT $2 OFFSET $3
+`
+
+type GetUnconfirmedParticipantsParams struct {
+	TripID uuid.UUID `db:"trip_id" json:"trip_id"`
+	Limit  int32     `db:"limit" json:"limit"`
+	Offset int32     `db:"offset" json:"offset"`
+}
+
+func (q *Queries) GetUnconfirmedParticipants(ctx context.Context, arg GetUnconfirmedParticipantsParams) ([]Participant, error) {
+	rows, err := q.db.Query(ctx, getUnconfirmedParticipants, arg.TripID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Participant
+	for rows.Next() {
+		var i Participant
+		if err := rows.Scan(
+			&i.ID,
+			&i.TripID,
+			&i.Email,
+			&i.IsConfirmed,
+			&i.Name,
+			&i.IsDeclined,
+			&i.InviteResentAt,
+			&i.UnsubscribeToken,
+			&i.InviteSentAt,
+			&i.LastInviteError,
 		); err != nil {
 			return nil, err
 		}
@@ -241,11 +809,38 @@ func (q *Queries) GetTripLinks(ctx context.Context, tripID uuid.UUID) ([]Link, e
 	return items, nil
 }
 
+const insertEmailLog = `-- name: InsertEmailLog :exec
+INSERT INTO email_log
+    ( "trip_id", "participant_id", "recipient", "kind", "status", "error" ) VALUES
+    ( $1, $2, $3, $4, $5, $6 )
+`
+
+type InsertEmailLogParams struct {
+	TripID        uuid.UUID   `db:"trip_id" json:"trip_id"`
+	ParticipantID pgtype.UUID `db:"participant_id" json:"participant_id"`
+	Recipient     string      `db:"recipient" json:"recipient"`
+	Kind          string      `db:"kind" json:"kind"`
+	Status        string      `db:"status" json:"status"`
+	Error         pgtype.Text `db:"error" json:"error"`
+}
+
+func (q *Queries) InsertEmailLog(ctx context.Context, arg InsertEmailLogParams) error {
+	_, err := q.db.Exec(ctx, insertEmailLog,
+		arg.TripID,
+		arg.ParticipantID,
+		arg.Recipient,
+		arg.Kind,
+		arg.Status,
+		arg.Error,
+	)
+	return err
+}
+
 const insertTrip = `-- name: InsertTrip :one
 INSERT
 INTO trips
-    ( "destination", "owner_email", "owner_name", "starts_at", "ends_at") VALUES
-    ( $1, $2, $3, $4, $5 )
+    ( "destination", "owner_email", "owner_name", "starts_at", "ends_at", "description", "image_url", "locale") VALUES
+    ( $1, $2, $3, $4, $5, $6, $7, $8 )
 RETURNING "id"
 `
 
@@ -255,6 +850,9 @@ type InsertTripParams struct {
 	OwnerName   string           `db:"owner_name" json:"owner_name"`
 	StartsAt    pgtype.Timestamp `db:"starts_at" json:"starts_at"`
 	EndsAt      pgtype.Timestamp `db:"ends_at" json:"ends_at"`
+	Description pgtype.Text      `db:"description" json:"description"`
+	ImageUrl    pgtype.Text      `db:"image_url" json:"image_url"`
+	Locale      string           `db:"locale" json:"locale"`
 }
 
 func (q *Queries) InsertTrip(ctx context.Context, arg InsertTripParams) (uuid.UUID, error) {
@@ -264,43 +862,806 @@ func (q *Queries) InsertTrip(ctx context.Context, arg InsertTripParams) (uuid.UU
 		arg.OwnerName,
 		arg.StartsAt,
 		arg.EndsAt,
+		arg.Description,
+		arg.ImageUrl,
+		arg.Locale,
 	)
 	var id uuid.UUID
 	err := row.Scan(&id)
 	return id, err
 }
 
-type InviteParticipantsToTripParams struct {
+const inviteParticipantToTrip = `-- name: InviteParticipantToTrip :one
+-- A participant who declined is invited again, anyone else already invited
+-- returns no row. created is false for the revived participants.
+INSERT INTO participants
+    ( "trip_id", "email" ) VALUES
+    ( $1, $2 )
+ON CONFLICT ("trip_id", LOWER("email")) DO UPDATE
+SET
+    "is_declined" = FALSE,
+    "is_confirmed" = FALSE,
+    "invite_sent_at" = NULL,
+    "last_invite_error" = NULL
+WHERE
+    participants.is_declined
+RETURNING "id", (xmax = 0)::boolean AS "created"
+`
+
+type InviteParticipantToTripParams struct {
+	TripID uuid.UUID `db:"trip_id" json:"trip_id"`
+	Email  string    `db:"email" json:"email"`
+}
+
+type InviteParticipantToTripRow struct {
+	ID      uuid.UUID `db:"id" json:"id"`
+	Created bool      `db:"created" json:"created"`
+}
+
+// A participant who declined is invited again, anyone else already invited
+// returns no row. created is false for the revived participants.
+func (q *Queries) InviteParticipantToTrip(ctx context.Context, arg InviteParticipantToTripParams) (InviteParticipantToTripRow, error) {
+	row := q.db.QueryRow(ctx, inviteParticipantToTrip, arg.TripID, arg.Email)
+	var i InviteParticipantToTripRow
+	err := row.Scan(&i.ID, &i.Created)
+	return i, err
+}
+
+type InviteParticipantsToTripParams struct {
 	TripID uuid.UUID `db:"trip_id" json:"trip_id"`
 	Email  string    `db:"email" json:"email"`
 }
 
-const updateTrip = `-- name: UpdateTrip :exec
+const isEmailSuppressed = `-- name: IsEmailSuppressed :one
+SELECT EXISTS (
+    SELECT 1 FROM email_suppressions WHERE "email" = $1
+)
+`
+
+func (q *Queries) IsEmailSuppressed(ctx context.Context, email string) (bool, error) {
+	row := q.db.QueryRow(ctx, isEmailSuppressed, email)
+	var exists bool
+	err := row.Scan(&exists)
+	return exists, err
+}
+
+const listActivitiesOutsideRange = `-- name: ListActivitiesOutsideRange :many
+SELECT
+    "id", "occurs_at"
+FROM activities
+WHERE
+    trip_id = $1
+    AND ("occurs_at" < $2 OR "occurs_at" >= $3)
+ORDER BY "occurs_at", "id"
+`
+
+type ListActivitiesOutsideRangeParams struct {
+	TripID      uuid.UUID        `db:"trip_id" json:"trip_id"`
+	OccursFrom  pgtype.Timestamp `db:"occurs_from" json:"occurs_from"`
+	OccursUntil pgtype.Timestamp `db:"occurs_until" json:"occurs_until"`
+}
+
+type ListActivitiesOutsideRangeRow struct {
+	ID       uuid.UUID        `db:"id" json:"id"`
+	OccursAt pgtype.Timestamp `db:"occurs_at" json:"occurs_at"`
+}
+
+func (q *Queries) ListActivitiesOutsideRange(ctx context.Context, arg ListActivitiesOutsideRangeParams) ([]ListActivitiesOutsideRangeRow, error) {
+	rows, err := q.db.Query(ctx, listActivitiesOutsideRange, arg.TripID, arg.OccursFrom, arg.OccursUntil)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListActivitiesOutsideRangeRow
+	for rows.Next() {
+		var i ListActivitiesOutsideRangeRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.OccursAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listParticipants = `-- name: ListParticipants :many
+SELECT
+    "id", "trip_id", "email", "is_confirmed", "name", "is_declined", "invite_resent_at", "unsubscribe_token", "invite_sent_at", "last_invite_error"
+FROM participants
+WHERE
+    trip_id = $1
+    AND ($2::boolean IS NULL OR "is_confirmed" = $2)
+ORDER BY "email", "id"
+LIMIT $3 OFFSET $4
+`
+
+type ListParticipantsParams struct {
+	TripID      uuid.UUID   `db:"trip_id" json:"trip_id"`
+	IsConfirmed pgtype.Bool `db:"is_confirmed" json:"is_confirmed"`
+	Limit       int32       `db:"limit" json:"limit"`
+	Offset      int32       `db:"offset" json:"offset"`
+}
+
+func (q *Queries) ListParticipants(ctx context.Context, arg ListParticipantsParams) ([]Participant, error) {
+	rows, err := q.db.Query(ctx, listParticipants,
+		arg.TripID,
+		arg.IsConfirmed,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Participant
+	for rows.Next() {
+		var i Participant
+		if err := rows.Scan(
+			&i.ID,
+			&i.TripID,
+			&i.Email,
+			&i.IsConfirmed,
+			&i.Name,
+			&i.IsDeclined,
+			&i.InviteResentAt,
+			&i.UnsubscribeToken,
+			&i.InviteSentAt,
+			&i.LastInviteError,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSuppressedEmails = `-- name: ListSuppressedEmails :many
+SELECT "email"
+FROM email_suppressions
+WHERE
+    "email" = ANY($1::text[])
+`
+
+func (q *Queries) ListSuppressedEmails(ctx context.Context, emails []string) ([]string, error) {
+	rows, err := q.db.Query(ctx, listSuppressedEmails, emails)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var email string
+		if err := rows.Scan(&email); err != nil {
+			return nil, err
+		}
+		items = append(items, email)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTripEmailLog = `-- name: ListTripEmailLog :many
+SELECT
+    "id", "trip_id", "participant_id", "recipient", "kind", "status", "error", "created_at"
+FROM email_log
+WHERE
+    trip_id = $1
+ORDER BY "created_at" DESC, "id"
+LIMIT $2 OFFSET $3
+`
+
+type ListTripEmailLogParams struct {
+	TripID uuid.UUID `db:"trip_id" json:"trip_id"`
+	Limit  int32     `db:"limit" json:"limit"`
+	Offset int32     `db:"offset" json:"offset"`
+}
+
+func (q *Queries) ListTripEmailLog(ctx context.Context, arg ListTripEmailLogParams) ([]EmailLog, error) {
+	rows, err := q.db.Query(ctx, listTripEmailLog, arg.TripID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []EmailLog
+	for rows.Next() {
+		var i EmailLog
+		if err := rows.Scan(
+			&i.ID,
+			&i.TripID,
+			&i.ParticipantID,
+			&i.Recipient,
+			&i.Kind,
+			&i.Status,
+			&i.Error,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTripLinks = `-- name: ListTripLinks :many
+SELECT
+    "id", "trip_id", "title", "url", "created_at", "updated_at"
+FROM links
+WHERE
+    trip_id = $1
+ORDER BY "created_at", "id"
+LIMIT $2 OFFSET $3
+`
+
+type ListTripLinksParams struct {
+	TripID uuid.UUID   `db:"trip_id" json:"trip_id"`
+	Limit  pgtype.Int4 `db:"limit" json:"limit"`
+	Offset int32       `db:"offset" json:"offset"`
+}
+
+func (q *Queries) ListTripLinks(ctx context.Context, arg ListTripLinksParams) ([]Link, error) {
+	rows, err := q.db.Query(ctx, listTripLinks, arg.TripID, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Link
+	for rows.Next() {
+		var i Link
+		if err := rows.Scan(
+			&i.ID,
+			&i.TripID,
+			&i.Title,
+			&i.Url,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTrips = `-- name: ListTrips :many
+SELECT
+    "id", "destination", "owner_email", "owner_name", "is_confirmed", "starts_at", "ends_at", "created_at", "description", "image_url", "archived", "updated_at", "status", "invitations_queued_at", "invitations_sent_at", "version", "locale", "owner_unsubscribe_token"
+FROM trips
+WHERE
+    ($1::boolean IS NULL OR "is_confirmed" = $1)
+    AND ($2::text IS NULL OR "owner_email" = $2)
+    AND ($3::boolean OR NOT "archived")
+ORDER BY
+    CASE WHEN $4::text = 'starts_at' AND NOT $5::boolean THEN "starts_at" END ASC,
+    CASE WHEN $4::text = 'starts_at' AND $5::boolean THEN "starts_at" END DESC,
+    CASE WHEN $4::text = 'ends_at' AND NOT $5::boolean THEN "ends_at" END ASC,
+    CASE WHEN $4::text = 'ends_at' AND $5::boolean THEN "ends_at" END DESC,
+    CASE WHEN $4::text = 'created_at' AND NOT $5::boolean THEN "created_at" END ASC,
+    CASE WHEN $4::text = 'created_at' AND $5::boolean THEN "created_at" END DESC,
+    CASE WHEN $4::text = 'destination' AND NOT $5::boolean THEN "destination" END ASC,
+    CASE WHEN $4::text = 'destination' AND $5::boolean THEN "destination" END DESC,
+    "id"
+LIMIT $6 OFFSET $7
+`
+
+type ListTripsParams struct {
+	IsConfirmed     pgtype.Bool `db:"is_confirmed" json:"is_confirmed"`
+	OwnerEmail      pgtype.Text `db:"owner_email" json:"owner_email"`
+	IncludeArchived bool        `db:"include_archived" json:"include_archived"`
+	SortBy          string      `db:"sort_by" json:"sort_by"`
+	SortDesc        bool        `db:"sort_desc" json:"sort_desc"`
+	Limit           int32       `db:"limit" json:"limit"`
+	Offset          int32       `db:"offset" json:"offset"`
+}
+
+func (q *Queries) ListTrips(ctx context.Context, arg ListTripsParams) ([]Trip, error) {
+	rows, err := q.db.Query(ctx, listTrips,
+		arg.IsConfirmed,
+		arg.OwnerEmail,
+		arg.IncludeArchived,
+		arg.SortBy,
+		arg.SortDesc,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Trip
+	for rows.Next() {
+		var i Trip
+		if err := rows.Scan(
+			&i.ID,
+			&i.Destination,
+			&i.OwnerEmail,
+			&i.OwnerName,
+			&i.IsConfirmed,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.CreatedAt,
+			&i.Description,
+			&i.ImageUrl,
+			&i.Archived,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.InvitationsQueuedAt,
+			&i.InvitationsSentAt,
+			&i.Version,
+			&i.Locale,
+			&i.OwnerUnsubscribeToken,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTripsAfter = `-- name: ListTripsAfter :many
+SELECT
+    "id", "destination", "owner_email", "owner_name", "is_confirmed", "starts_at", "ends_at", "created_at", "description", "image_url", "archived", "updated_at", "status", "invitations_queued_at", "invitations_sent_at", "version", "locale", "owner_unsubscribe_token"
+FROM trips
+WHERE
+    ($1::boolean IS NULL OR "is_confirmed" = $1)
+    AND ($2::text IS NULL OR "owner_email" = $2)
+    AND ($3::boolean OR NOT "archived")
+    AND ("starts_at", "id") > ($4::timestamp, $5::uuid)
+ORDER BY
+    "starts_at", "id"
+LIMIT $6
+`
+
+type ListTripsAfterParams struct {
+	IsConfirmed     pgtype.Bool      `db:"is_confirmed" json:"is_confirmed"`
+	OwnerEmail      pgtype.Text      `db:"owner_email" json:"owner_email"`
+	IncludeArchived bool             `db:"include_archived" json:"include_archived"`
+	AfterStartsAt   pgtype.Timestamp `db:"after_starts_at" json:"after_starts_at"`
+	AfterID         uuid.UUID        `db:"after_id" json:"after_id"`
+	Limit           int32            `db:"limit" json:"limit"`
+}
+
+func (q *Queries) ListTripsAfter(ctx context.Context, arg ListTripsAfterParams) ([]Trip, error) {
+	rows, err := q.db.Query(ctx, listTripsAfter,
+		arg.IsConfirmed,
+		arg.OwnerEmail,
+		arg.IncludeArchived,
+		arg.AfterStartsAt,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Trip
+	for rows.Next() {
+		var i Trip
+		if err := rows.Scan(
+			&i.ID,
+			&i.Destination,
+			&i.OwnerEmail,
+			&i.OwnerName,
+			&i.IsConfirmed,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.CreatedAt,
+			&i.Description,
+			&i.ImageUrl,
+			&i.Archived,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.InvitationsQueuedAt,
+			&i.InvitationsSentAt,
+			&i.Version,
+			&i.Locale,
+			&i.OwnerUnsubscribeToken,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listTripsByParticipantEmail = `-- name: ListTripsByParticipantEmail :many
+SELECT
+    t."id", t."destination", t."owner_email", t."owner_name", t."is_confirmed", t."starts_at", t."ends_at", t."created_at", t."description", t."image_url", t."archived", t."updated_at", t."status", t."invitations_queued_at", t."invitations_sent_at", t."version", t."locale", t."owner_unsubscribe_token",
+    p."id" AS "participant_id", p."is_confirmed" AS "participant_is_confirmed", p."is_declined" AS "participant_is_declined"
+FROM participants p
+JOIN trips t ON t.id = p.trip_id
+WHERE
+    p.email = $1
+ORDER BY t."starts_at", t."id"
+LIMIT $2 OFFSET $3
+`
+
+type ListTripsByParticipantEmailParams struct {
+	Email  string `db:"email" json:"email"`
+	Limit  int32  `db:"limit" json:"limit"`
+	Offset int32  `db:"offset" json:"offset"`
+}
+
+type ListTripsByParticipantEmailRow struct {
+	ID                     uuid.UUID        `db:"id" json:"id"`
+	Destination            string           `db:"destination" json:"destination"`
+	OwnerEmail             string           `db:"owner_email" json:"owner_email"`
+	OwnerName              string           `db:"owner_name" json:"owner_name"`
+	IsConfirmed            bool             `db:"is_confirmed" json:"is_confirmed"`
+	StartsAt               pgtype.Timestamp `db:"starts_at" json:"starts_at"`
+	EndsAt                 pgtype.Timestamp `db:"ends_at" json:"ends_at"`
+	CreatedAt              pgtype.Timestamp `db:"created_at" json:"created_at"`
+	Description            pgtype.Text      `db:"description" json:"description"`
+	ImageUrl               pgtype.Text      `db:"image_url" json:"image_url"`
+	Archived               bool             `db:"archived" json:"archived"`
+	UpdatedAt              pgtype.Timestamp `db:"updated_at" json:"updated_at"`
+	Status                 string           `db:"status" json:"status"`
+	InvitationsQueuedAt    pgtype.Timestamp `db:"invitations_queued_at" json:"invitations_queued_at"`
+	InvitationsSentAt      pgtype.Timestamp `db:"invitations_sent_at" json:"invitations_sent_at"`
+	Version                int32            `db:"version" json:"version"`
+	Locale                 string           `db:"locale" json:"locale"`
+	OwnerUnsubscribeToken  uuid.UUID        `db:"owner_unsubscribe_token" json:"owner_unsubscribe_token"`
+	ParticipantID          uuid.UUID        `db:"participant_id" json:"participant_id"`
+	ParticipantIsConfirmed bool             `db:"participant_is_confirmed" json:"participant_is_confirmed"`
+	ParticipantIsDeclined  bool             `db:"participant_is_declined" json:"participant_is_declined"`
+}
+
+func (q *Queries) ListTripsByParticipantEmail(ctx context.Context, arg ListTripsByParticipantEmailParams) ([]ListTripsByParticipantEmailRow, error) {
+	rows, err := q.db.Query(ctx, listTripsByParticipantEmail, arg.Email, arg.Limit, arg.Offset)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListTripsByParticipantEmailRow
+	for rows.Next() {
+		var i ListTripsByParticipantEmailRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Destination,
+			&i.OwnerEmail,
+			&i.OwnerName,
+			&i.IsConfirmed,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.CreatedAt,
+			&i.Description,
+			&i.ImageUrl,
+			&i.Archived,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.InvitationsQueuedAt,
+			&i.InvitationsSentAt,
+			&i.Version,
+			&i.Locale,
+			&i.OwnerUnsubscribeToken,
+			&i.ParticipantID,
+			&i.ParticipantIsConfirmed,
+			&i.ParticipantIsDeclined,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markEmailFailed = `-- name: MarkEmailFailed :exec
+UPDATE email_outbox
+SET
+    "status" = $1,
+    "last_error" = $2,
+    "available_at" = now() + $3::interval
+WHERE
+    id = $4
+`
+
+type MarkEmailFailedParams struct {
+	Status     string          `db:"status" json:"status"`
+	LastError  pgtype.Text     `db:"last_error" json:"last_error"`
+	RetryAfter pgtype.Interval `db:"retry_after" json:"retry_after"`
+	ID         uuid.UUID       `db:"id" json:"id"`
+}
+
+func (q *Queries) MarkEmailFailed(ctx context.Context, arg MarkEmailFailedParams) error {
+	_, err := q.db.Exec(ctx, markEmailFailed,
+		arg.Status,
+		arg.LastError,
+		arg.RetryAfter,
+		arg.ID,
+	)
+	return err
+}
+
+const markEmailSent = `-- name: MarkEmailSent :exec
+UPDATE email_outbox
+SET
+    "status" = 'sent',
+    "last_error" = NULL,
+    "sent_at" = now()
+WHERE
+    id = $1
+`
+
+func (q *Queries) MarkEmailSent(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, markEmailSent, id)
+	return err
+}
+
+const markParticipantInviteResent = `-- name: MarkParticipantInviteResent :execrows
+UPDATE participants
+SET
+    "invite_resent_at" = NOW()
+WHERE
+    id = $1
+    AND ("invite_resent_at" IS NULL OR "invite_resent_at" < NOW() - $2::interval)
+`
+
+type MarkParticipantInviteResentParams struct {
+	ID       uuid.UUID       `db:"id" json:"id"`
+	Cooldown pgtype.Interval `db:"cooldown" json:"cooldown"`
+}
+
+func (q *Queries) MarkParticipantInviteResent(ctx context.Context, arg MarkParticipantInviteResentParams) (int64, error) {
+	result, err := q.db.Exec(ctx, markParticipantInviteResent, arg.ID, arg.Cooldown)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const markTripInvitationsSent = `-- name: MarkTripInvitationsSent :exec
+UPDATE trips
+SET
+    "invitations_sent_at" = now()
+WHERE
+    id = $1
+`
+
+func (q *Queries) MarkTripInvitationsSent(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.Exec(ctx, markTripInvitationsSent, id)
+	return err
+}
+
+const recordParticipantInvite = `-- name: RecordParticipantInvite :exec
+-- A failed attempt keeps when the invite was last sent, if ever.
+UPDATE participants
+SET
+    "invite_sent_at" = CASE WHEN $1::text IS NULL THEN now() ELSE "invite_sent_at" END,
+    "last_invite_error" = $1
+WHERE
+    id = $2
+`
+
+type RecordParticipantInviteParams struct {
+	Error pgtype.Text `db:"error" json:"error"`
+	ID    uuid.UUID   `db:"id" json:"id"`
+}
+
+// A failed attempt keeps when the invite was last sent, if ever.
+func (q *Queries) RecordParticipantInvite(ctx context.Context, arg RecordParticipantInviteParams) error {
+	_, err := q.db.Exec(ctx, recordParticipantInvite, arg.Error, arg.ID)
+	return err
+}
+
+const resetParticipantsConfirmation = `-- name: ResetParticipantsConfirmation :exec
+UPDATE participants
+SET
+    "is_confirmed" = FALSE
+WHERE
+    trip_id = $1
+`
+
+func (q *Queries) ResetParticipantsConfirmation(ctx context.Context, tripID uuid.UUID) error {
+	_, err := q.db.Exec(ctx, resetParticipantsConfirmation, tripID)
+	return err
+}
+
+const searchTrips = `-- name: SearchTrips :many
+SELECT
+    "id", "destination", "owner_email", "owner_name", "is_confirmed", "starts_at", "ends_at", "created_at", "description", "image_url", "archived", "updated_at", "status", "invitations_queued_at", "invitations_sent_at", "version", "locale", "owner_unsubscribe_token"
+FROM trips
+WHERE
+    trip_search_vector("destination", "description") @@ websearch_to_tsquery('simple', $1)
+    OR "destination" ILIKE $2 OR "owner_name" ILIKE $2
+ORDER BY
+    ts_rank(trip_search_vector("destination", "description"), websearch_to_tsquery('simple', $1)) DESC,
+    GREATEST(similarity("destination", $1), similarity("owner_name", $1)) DESC,
+    "id"
+LIMIT $3 OFFSET $4
+`
+
+type SearchTripsParams struct {
+	Query   string `db:"query" json:"query"`
+	Pattern string `db:"pattern" json:"pattern"`
+	Limit   int32  `db:"limit" json:"limit"`
+	Offset  int32  `db:"offset" json:"offset"`
+}
+
+func (q *Queries) SearchTrips(ctx context.Context, arg SearchTripsParams) ([]Trip, error) {
+	rows, err := q.db.Query(ctx, searchTrips,
+		arg.Query,
+		arg.Pattern,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Trip
+	for rows.Next() {
+		var i Trip
+		if err := rows.Scan(
+			&i.ID,
+			&i.Destination,
+			&i.OwnerEmail,
+			&i.OwnerName,
+			&i.IsConfirmed,
+			&i.StartsAt,
+			&i.EndsAt,
+			&i.CreatedAt,
+			&i.Description,
+			&i.ImageUrl,
+			&i.Archived,
+			&i.UpdatedAt,
+			&i.Status,
+			&i.InvitationsQueuedAt,
+			&i.InvitationsSentAt,
+			&i.Version,
+			&i.Locale,
+			&i.OwnerUnsubscribeToken,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setTripArchived = `-- name: SetTripArchived :exec
+UPDATE trips
+SET
+    "archived" = $2,
+    "updated_at" = now(),
+    "version" = "version" + 1
+WHERE
+    id = $1
+`
+
+type SetTripArchivedParams struct {
+	ID       uuid.UUID `db:"id" json:"id"`
+	Archived bool      `db:"archived" json:"archived"`
+}
+
+func (q *Queries) SetTripArchived(ctx context.Context, arg SetTripArchivedParams) error {
+	_, err := q.db.Exec(ctx, setTripArchived, arg.ID, arg.Archived)
+	return err
+}
+
+const suppressEmail = `-- name: SuppressEmail :exec
+INSERT INTO email_suppressions
+    ( "email" ) VALUES
+    ( $1 )
+ON CONFLICT ("email") DO NOTHING
+`
+
+func (q *Queries) SuppressEmail(ctx context.Context, email string) error {
+	_, err := q.db.Exec(ctx, suppressEmail, email)
+	return err
+}
+
+const updateLink = `-- name: UpdateLink :exec
+UPDATE links
+SET
+    "title" = $1,
+    "url" = $2,
+    "updated_at" = now()
+WHERE
+    id = $3
+`
+
+type UpdateLinkParams struct {
+	Title string    `db:"title" json:"title"`
+	Url   string    `db:"url" json:"url"`
+	ID    uuid.UUID `db:"id" json:"id"`
+}
+
+func (q *Queries) UpdateLink(ctx context.Context, arg UpdateLinkParams) error {
+	_, err := q.db.Exec(ctx, updateLink, arg.Title, arg.Url, arg.ID)
+	return err
+}
+
+const updateParticipantName = `-- name: UpdateParticipantName :exec
+UPDATE participants
+SET
+    "name" = $1
+WHERE
+    id = $2
+`
+
+type UpdateParticipantNameParams struct {
+	Name pgtype.Text `db:"name" json:"name"`
+	ID   uuid.UUID   `db:"id" json:"id"`
+}
+
+func (q *Queries) UpdateParticipantName(ctx context.Context, arg UpdateParticipantNameParams) error {
+	_, err := q.db.Exec(ctx, updateParticipantName, arg.Name, arg.ID)
+	return err
+}
+
+const updateTrip = `-- name: UpdateTrip :execrows
 UPDATE trips
 SET 
     "destination" = $1,
     "ends_at" = $2,
     "starts_at" = $3,
-    "is_confirmed" = $4
+    "is_confirmed" = $4,
+    "description" = $5,
+    "image_url" = $6,
+    -- Invites sent before the destination or the dates changed are stale, the
+    -- next confirmation sends them again
+    "invitations_queued_at" = CASE
+        WHEN ("destination", "starts_at", "ends_at") IS DISTINCT FROM ($1::text, $3::timestamp, $2::timestamp) THEN NULL
+        ELSE "invitations_queued_at"
+    END,
+    "updated_at" = now(),
+    "version" = "version" + 1
 WHERE
-    id = $5
+    id = $7
+    AND ($8::integer IS NULL OR "version" = $8)
 `
 
 type UpdateTripParams struct {
-	Destination string           `db:"destination" json:"destination"`
-	EndsAt      pgtype.Timestamp `db:"ends_at" json:"ends_at"`
-	StartsAt    pgtype.Timestamp `db:"starts_at" json:"starts_at"`
-	IsConfirmed bool             `db:"is_confirmed" json:"is_confirmed"`
-	ID          uuid.UUID        `db:"id" json:"id"`
+	Destination     string           `db:"destination" json:"destination"`
+	EndsAt          pgtype.Timestamp `db:"ends_at" json:"ends_at"`
+	StartsAt        pgtype.Timestamp `db:"starts_at" json:"starts_at"`
+	IsConfirmed     bool             `db:"is_confirmed" json:"is_confirmed"`
+	Description     pgtype.Text      `db:"description" json:"description"`
+	ImageUrl        pgtype.Text      `db:"image_url" json:"image_url"`
+	ID              uuid.UUID        `db:"id" json:"id"`
+	ExpectedVersion pgtype.Int4      `db:"expected_version" json:"expected_version"`
 }
 
-func (q *Queries) UpdateTrip(ctx context.Context, arg UpdateTripParams) error {
-	_, err := q.db.Exec(ctx, updateTrip,
+func (q *Queries) UpdateTrip(ctx context.Context, arg UpdateTripParams) (int64, error) {
+	result, err := q.db.Exec(ctx, updateTrip,
 		arg.Destination,
 		arg.EndsAt,
 		arg.StartsAt,
 		arg.IsConfirmed,
+		arg.Description,
+		arg.ImageUrl,
 		arg.ID,
+		arg.ExpectedVersion,
 	)
-	return err
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
 }
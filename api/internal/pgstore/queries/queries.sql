@@ -1,54 +1,287 @@
 -- name: InsertTrip :one
 INSERT
 INTO trips
-    ( "destination", "owner_email", "owner_name", "starts_at", "ends_at") VALUES
-    ( $1, $2, $3, $4, $5 )
+    ( "destination", "owner_email", "owner_name", "starts_at", "ends_at", "description", "image_url", "locale") VALUES
+    ( $1, $2, $3, $4, $5, $6, $7, $8 )
 RETURNING "id";
 
 -- name: GetTrip :one
 SELECT
-    "id", "destination", "owner_email", "owner_name", "is_confirmed", "starts_at", "ends_at"
+    "id", "destination", "owner_email", "owner_name", "is_confirmed", "starts_at", "ends_at", "created_at", "description", "image_url", "archived", "updated_at", "status", "invitations_queued_at", "invitations_sent_at", "version", "locale", "owner_unsubscribe_token"
 FROM trips
 WHERE
     id = $1;
 
+-- name: GetTripSummary :one
+SELECT
+    t."id", t."destination", t."owner_email", t."owner_name", t."is_confirmed", t."starts_at", t."ends_at", t."created_at", t."description", t."image_url", t."archived", t."updated_at", t."status", t."invitations_queued_at", t."invitations_sent_at", t."version", t."locale", t."owner_unsubscribe_token",
+    (SELECT COUNT(*) FROM participants p WHERE p.trip_id = t.id) AS "participants_count",
+    (SELECT COUNT(*) FROM participants p WHERE p.trip_id = t.id AND p.is_confirmed) AS "confirmed_participants_count",
+    (SELECT COUNT(*) FROM activities a WHERE a.trip_id = t.id) AS "activities_count",
+    (SELECT COUNT(*) FROM links l WHERE l.trip_id = t.id) AS "links_count"
+FROM trips t
+WHERE
+    t.id = $1;
+
 -- name: GetAllTrips :many
 SELECT 
-    "id", "destination", "owner_email", "owner_name", "is_confirmed", "starts_at", "ends_at"
+    "id", "destination", "owner_email", "owner_name", "is_confirmed", "starts_at", "ends_at", "created_at", "description", "image_url", "archived", "updated_at", "status", "invitations_queued_at", "invitations_sent_at", "version", "locale", "owner_unsubscribe_token"
 FROM trips;
 
--- name: UpdateTrip :exec
+-- name: ListTrips :many
+SELECT
+    "id", "destination", "owner_email", "owner_name", "is_confirmed", "starts_at", "ends_at", "created_at", "description", "image_url", "archived", "updated_at", "status", "invitations_queued_at", "invitations_sent_at", "version", "locale", "owner_unsubscribe_token"
+FROM trips
+WHERE
+    (sqlc.narg('is_confirmed')::boolean IS NULL OR "is_confirmed" = sqlc.narg('is_confirmed'))
+    AND (sqlc.narg('owner_email')::text IS NULL OR "owner_email" = sqlc.narg('owner_email'))
+    AND (sqlc.arg('include_archived')::boolean OR NOT "archived")
+ORDER BY
+    CASE WHEN sqlc.arg('sort_by')::text = 'starts_at' AND NOT sqlc.arg('sort_desc')::boolean THEN "starts_at" END ASC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'starts_at' AND sqlc.arg('sort_desc')::boolean THEN "starts_at" END DESC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'ends_at' AND NOT sqlc.arg('sort_desc')::boolean THEN "ends_at" END ASC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'ends_at' AND sqlc.arg('sort_desc')::boolean THEN "ends_at" END DESC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'created_at' AND NOT sqlc.arg('sort_desc')::boolean THEN "created_at" END ASC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'created_at' AND sqlc.arg('sort_desc')::boolean THEN "created_at" END DESC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'destination' AND NOT sqlc.arg('sort_desc')::boolean THEN "destination" END ASC,
+    CASE WHEN sqlc.arg('sort_by')::text = 'destination' AND sqlc.arg('sort_desc')::boolean THEN "destination" END DESC,
+    "id"
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListTripsAfter :many
+SELECT
+    "id", "destination", "owner_email", "owner_name", "is_confirmed", "starts_at", "ends_at", "created_at", "description", "image_url", "archived", "updated_at", "status", "invitations_queued_at", "invitations_sent_at", "version", "locale", "owner_unsubscribe_token"
+FROM trips
+WHERE
+    (sqlc.narg('is_confirmed')::boolean IS NULL OR "is_confirmed" = sqlc.narg('is_confirmed'))
+    AND (sqlc.narg('owner_email')::text IS NULL OR "owner_email" = sqlc.narg('owner_email'))
+    AND (sqlc.arg('include_archived')::boolean OR NOT "archived")
+    AND ("starts_at", "id") > (sqlc.arg('after_starts_at')::timestamp, sqlc.arg('after_id')::uuid)
+ORDER BY
+    "starts_at", "id"
+LIMIT sqlc.arg('limit');
+
+-- name: CountTrips :one
+SELECT COUNT(*)
+FROM trips
+WHERE
+    (sqlc.narg('is_confirmed')::boolean IS NULL OR "is_confirmed" = sqlc.narg('is_confirmed'))
+    AND (sqlc.narg('owner_email')::text IS NULL OR "owner_email" = sqlc.narg('owner_email'))
+    AND (sqlc.arg('include_archived')::boolean OR NOT "archived");
+
+-- name: SearchTrips :many
+SELECT
+    "id", "destination", "owner_email", "owner_name", "is_confirmed", "starts_at", "ends_at", "created_at", "description", "image_url", "archived", "updated_at", "status", "invitations_queued_at", "invitations_sent_at", "version", "locale", "owner_unsubscribe_token"
+FROM trips
+WHERE
+    trip_search_vector("destination", "description") @@ websearch_to_tsquery('simple', sqlc.arg('query'))
+    OR "destination" ILIKE sqlc.arg('pattern') OR "owner_name" ILIKE sqlc.arg('pattern')
+ORDER BY
+    ts_rank(trip_search_vector("destination", "description"), websearch_to_tsquery('simple', sqlc.arg('query'))) DESC,
+    GREATEST(similarity("destination", sqlc.arg('query')), similarity("owner_name", sqlc.arg('query'))) DESC,
+    "id"
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountSearchTrips :one
+SELECT COUNT(*)
+FROM trips
+WHERE
+    trip_search_vector("destination", "description") @@ websearch_to_tsquery('simple', sqlc.arg('query'))
+    OR "destination" ILIKE sqlc.arg('pattern') OR "owner_name" ILIKE sqlc.arg('pattern');
+
+-- name: ListTripsByParticipantEmail :many
+SELECT
+    t."id", t."destination", t."owner_email", t."owner_name", t."is_confirmed", t."starts_at", t."ends_at", t."created_at", t."description", t."image_url", t."archived", t."updated_at", t."status", t."invitations_queued_at", t."invitations_sent_at", t."version", t."locale", t."owner_unsubscribe_token",
+    p."id" AS "participant_id", p."is_confirmed" AS "participant_is_confirmed", p."is_declined" AS "participant_is_declined"
+FROM participants p
+JOIN trips t ON t.id = p.trip_id
+WHERE
+    p.email = sqlc.arg('email')
+ORDER BY t."starts_at", t."id"
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountTripsByParticipantEmail :one
+SELECT COUNT(*)
+FROM participants
+WHERE
+    email = $1;
+
+-- name: UpdateTrip :execrows
 UPDATE trips
 SET 
-    "destination" = $1,
-    "ends_at" = $2,
-    "starts_at" = $3,
-    "is_confirmed" = $4
+    "destination" = sqlc.arg('destination'),
+    "ends_at" = sqlc.arg('ends_at'),
+    "starts_at" = sqlc.arg('starts_at'),
+    "is_confirmed" = sqlc.arg('is_confirmed'),
+    "description" = sqlc.arg('description'),
+    "image_url" = sqlc.arg('image_url'),
+    -- Invites sent before the destination or the dates changed are stale, the
+    -- next confirmation sends them again
+    "invitations_queued_at" = CASE
+        WHEN ("destination", "starts_at", "ends_at") IS DISTINCT FROM (sqlc.arg('destination')::text, sqlc.arg('starts_at')::timestamp, sqlc.arg('ends_at')::timestamp) THEN NULL
+        ELSE "invitations_queued_at"
+    END,
+    "updated_at" = now(),
+    "version" = "version" + 1
+WHERE
+    id = sqlc.arg('id')
+    AND (sqlc.narg('expected_version')::integer IS NULL OR "version" = sqlc.narg('expected_version'));
+
+-- name: SetTripArchived :exec
+UPDATE trips
+SET
+    "archived" = $2,
+    "updated_at" = now(),
+    "version" = "version" + 1
 WHERE
-    id = $5;
+    id = $1;
+
+-- name: ConfirmTrip :execrows
+-- The invitations are only queued anew the first time, or after the trip
+-- changed, so confirming it again doesn't invite anyone twice.
+UPDATE trips
+SET
+    "is_confirmed" = TRUE,
+    "invitations_queued_at" = COALESCE("invitations_queued_at", now()),
+    "invitations_sent_at" = NULL,
+    "updated_at" = now(),
+    "version" = "version" + 1
+WHERE
+    id = $1 AND NOT "is_confirmed";
+
+-- name: MarkTripInvitationsSent :exec
+UPDATE trips
+SET
+    "invitations_sent_at" = now()
+WHERE
+    id = $1;
+
+-- name: CancelTrip :execrows
+UPDATE trips
+SET
+    "status" = 'cancelled',
+    "updated_at" = now(),
+    "version" = "version" + 1
+WHERE
+    id = $1 AND "status" <> 'cancelled';
+
+-- name: DeleteTrip :exec
+DELETE
+FROM trips
+WHERE
+    id = $1;
 
 -- name: GetParticipant :one
 SELECT
-    "id", "trip_id", "email", "is_confirmed"
+    "id", "trip_id", "email", "is_confirmed", "name", "is_declined", "invite_resent_at", "unsubscribe_token", "invite_sent_at", "last_invite_error"
 FROM participants
 WHERE
     id = $1;
 
 -- name: ConfirmParticipant :exec
+UPDATE participants
+SET
+    "is_confirmed" = TRUE,
+    "is_declined" = FALSE
+WHERE
+    id = $1;
+
+
+-- name: GetParticipantByEmail :one
 SELECT
-    "id", "trip_id", "email", "is_confirmed"
+    "id", "trip_id", "email", "is_confirmed", "name", "is_declined", "invite_resent_at", "unsubscribe_token", "invite_sent_at", "last_invite_error"
 FROM participants
 WHERE
-    id = $1;
+    trip_id = $1 AND email = $2;
+
+-- name: GetParticipantWithTrip :one
+SELECT
+    p."id", p."trip_id", p."email", p."is_confirmed", p."name", p."is_declined",
+    t."destination", t."owner_name", t."starts_at", t."ends_at"
+FROM participants p
+JOIN trips t ON t.id = p.trip_id
+WHERE
+    p.id = $1;
 
+-- name: ResetParticipantsConfirmation :exec
+UPDATE participants
+SET
+    "is_confirmed" = FALSE
+WHERE
+    trip_id = $1;
 
 -- name: GetParticipants :many
 SELECT
-    "id", "trip_id", "email", "is_confirmed"
+    "id", "trip_id", "email", "is_confirmed", "name", "is_declined", "invite_resent_at", "unsubscribe_token", "invite_sent_at", "last_invite_error"
 FROM participants
 WHERE
     trip_id = $1;
 
+-- name: GetUnconfirmedParticipants :many
+-- ListParticipants filtering the unconfirmed ones, spelled the way the partial
+-- index participants_unconfirmed_trip_id_idx is so it's used.
+SELECT
+    "id", "trip_id", "email", "is_confirmed", "name", "is_declined", "invite_resent_at", "unsubscribe_token", "invite_sent_at", "last_invite_error"
+FROM participants
+WHERE
+    trip_id = sqlc.arg('trip_id')
+    AND NOT "is_confirmed"
+ORDER BY "email", "id"
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: ListParticipants :many
+SELECT
+    "id", "trip_id", "email", "is_confirmed", "name", "is_declined", "invite_resent_at", "unsubscribe_token", "invite_sent_at", "last_invite_error"
+FROM participants
+WHERE
+    trip_id = sqlc.arg('trip_id')
+    AND (sqlc.narg('is_confirmed')::boolean IS NULL OR "is_confirmed" = sqlc.narg('is_confirmed'))
+ORDER BY "email", "id"
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountParticipants :one
+SELECT COUNT(*)
+FROM participants
+WHERE
+    trip_id = sqlc.arg('trip_id')
+    AND (sqlc.narg('is_confirmed')::boolean IS NULL OR "is_confirmed" = sqlc.narg('is_confirmed'));
+
+-- name: DeleteParticipant :exec
+DELETE
+FROM participants
+WHERE
+    id = $1;
+
+-- name: UpdateParticipantName :exec
+UPDATE participants
+SET
+    "name" = $1
+WHERE
+    id = $2;
+
+-- name: DeclineParticipant :exec
+UPDATE participants
+SET
+    "is_declined" = TRUE
+WHERE
+    id = $1;
+
+-- name: InviteParticipantToTrip :one
+-- A participant who declined is invited again, anyone else already invited
+-- returns no row. created is false for the revived participants.
+INSERT INTO participants
+    ( "trip_id", "email" ) VALUES
+    ( $1, $2 )
+ON CONFLICT ("trip_id", LOWER("email")) DO UPDATE
+SET
+    "is_declined" = FALSE,
+    "is_confirmed" = FALSE,
+    "invite_sent_at" = NULL,
+    "last_invite_error" = NULL
+WHERE
+    participants.is_declined
+RETURNING "id", (xmax = 0)::boolean AS "created";
+
 -- name: InviteParticipantsToTrip :copyfrom
 INSERT INTO participants
     ( "trip_id", "email" ) VALUES
@@ -56,16 +289,36 @@ INSERT INTO participants
 
 -- name: CreateActivity :one
 INSERT INTO activities
-    ( "trip_id", "title", "occurs_at" ) VALUES
-    ( $1, $2, $3 )
+    ( "trip_id", "title", "occurs_at", "tags" ) VALUES
+    ( $1, $2, $3, $4 )
 RETURNING "id";
 
 -- name: GetTripActivities :many
 SELECT
-    "id", "trip_id", "title", "occurs_at"
+    "id", "trip_id", "title", "occurs_at", "tags", "created_at", "updated_at"
 FROM activities
 WHERE
-    trip_id = $1;
+    trip_id = sqlc.arg('trip_id')
+    AND (sqlc.narg('occurs_from')::timestamp IS NULL OR "occurs_at" >= sqlc.narg('occurs_from'))
+    AND (sqlc.narg('occurs_until')::timestamp IS NULL OR "occurs_at" < sqlc.narg('occurs_until'))
+    AND (sqlc.narg('tag')::text IS NULL OR "tags" @> ARRAY[sqlc.narg('tag')::text])
+ORDER BY "occurs_at", "id";
+
+-- name: ListActivitiesOutsideRange :many
+SELECT
+    "id", "occurs_at"
+FROM activities
+WHERE
+    trip_id = sqlc.arg('trip_id')
+    AND ("occurs_at" < sqlc.arg('occurs_from') OR "occurs_at" >= sqlc.arg('occurs_until'))
+ORDER BY "occurs_at", "id";
+
+-- name: GetActivity :one
+SELECT
+    "id", "trip_id", "title", "occurs_at", "tags", "created_at", "updated_at"
+FROM activities
+WHERE
+    id = $1 AND trip_id = $2;
 
 -- name: CreateTripLink :one
 INSERT INTO links
@@ -75,9 +328,139 @@ RETURNING "id";
 
 -- name: GetTripLinks :many
 SELECT
-    "id", "trip_id", "title", "url"
+    "id", "trip_id", "title", "url", "created_at", "updated_at"
+FROM links
+WHERE
+    trip_id = $1
+ORDER BY "created_at", "id";
+
+-- name: ListTripLinks :many
+SELECT
+    "id", "trip_id", "title", "url", "created_at", "updated_at"
+FROM links
+WHERE
+    trip_id = sqlc.arg('trip_id')
+ORDER BY "created_at", "id"
+LIMIT sqlc.narg('limit') OFFSET sqlc.arg('offset');
+
+-- name: CountTripLinks :one
+SELECT COUNT(*)
 FROM links
 WHERE
     trip_id = $1;
 
+-- name: GetLink :one
+SELECT
+    "id", "trip_id", "title", "url", "created_at", "updated_at"
+FROM links
+WHERE
+    id = $1;
+
+-- name: UpdateLink :exec
+UPDATE links
+SET
+    "title" = $1,
+    "url" = $2,
+    "updated_at" = now()
+WHERE
+    id = $3;
+
+-- name: MarkParticipantInviteResent :execrows
+UPDATE participants
+SET
+    "invite_resent_at" = NOW()
+WHERE
+    id = sqlc.arg('id')
+    AND ("invite_resent_at" IS NULL OR "invite_resent_at" < NOW() - sqlc.arg('cooldown')::interval);
+
+-- name: RecordParticipantInvite :exec
+-- A failed attempt keeps when the invite was last sent, if ever.
+UPDATE participants
+SET
+    "invite_sent_at" = CASE WHEN sqlc.narg('error')::text IS NULL THEN now() ELSE "invite_sent_at" END,
+    "last_invite_error" = sqlc.narg('error')
+WHERE
+    id = sqlc.arg('id');
+
+-- name: EnqueueEmail :exec
+INSERT INTO email_outbox
+    ( "kind", "subject_id", "payload" ) VALUES
+    ( $1, $2, $3 );
+
+-- name: ClaimPendingEmails :many
+UPDATE email_outbox
+SET
+    "attempts" = "attempts" + 1,
+    "available_at" = now() + sqlc.arg('lease')::interval
+WHERE
+    id IN (
+        SELECT id
+        FROM email_outbox
+        WHERE
+            "status" = 'pending' AND "available_at" <= now()
+        ORDER BY "available_at"
+        LIMIT sqlc.arg('limit')
+        FOR UPDATE SKIP LOCKED
+    )
+RETURNING "id", "kind", "subject_id", "status", "attempts", "last_error", "available_at", "created_at", "sent_at", "payload";
 
+-- name: MarkEmailSent :exec
+UPDATE email_outbox
+SET
+    "status" = 'sent',
+    "last_error" = NULL,
+    "sent_at" = now()
+WHERE
+    id = $1;
+
+-- name: MarkEmailFailed :exec
+UPDATE email_outbox
+SET
+    "status" = sqlc.arg('status'),
+    "last_error" = sqlc.arg('last_error'),
+    "available_at" = now() + sqlc.arg('retry_after')::interval
+WHERE
+    id = sqlc.arg('id');
+
+-- name: GetEmailByUnsubscribeToken :one
+SELECT "email" FROM participants WHERE "unsubscribe_token" = $1
+UNION
+SELECT "owner_email" FROM trips WHERE "owner_unsubscribe_token" = $1
+LIMIT 1;
+
+-- name: SuppressEmail :exec
+INSERT INTO email_suppressions
+    ( "email" ) VALUES
+    ( $1 )
+ON CONFLICT ("email") DO NOTHING;
+
+-- name: IsEmailSuppressed :one
+SELECT EXISTS (
+    SELECT 1 FROM email_suppressions WHERE "email" = $1
+);
+
+-- name: ListSuppressedEmails :many
+SELECT "email"
+FROM email_suppressions
+WHERE
+    "email" = ANY(sqlc.arg('emails')::text[]);
+
+-- name: InsertEmailLog :exec
+INSERT INTO email_log
+    ( "trip_id", "participant_id", "recipient", "kind", "status", "error" ) VALUES
+    ( $1, $2, $3, $4, $5, $6 );
+
+-- name: ListTripEmailLog :many
+SELECT
+    "id", "trip_id", "participant_id", "recipient", "kind", "status", "error", "created_at"
+FROM email_log
+WHERE
+    trip_id = $1
+ORDER BY "created_at" DESC, "id"
+LIMIT $2 OFFSET $3;
+
+-- name: CountTripEmailLog :one
+SELECT COUNT(*)
+FROM email_log
+WHERE
+    trip_id = $1;
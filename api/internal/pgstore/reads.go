@@ -0,0 +1,82 @@
+package pgstore
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// The queries below are the ones of the read-heavy endpoints, run on the read
+// replica when there is one. The queries the writes depend on, such as GetTrip
+// before updating a trip or GetParticipantWithTrip before confirming a
+// participant, stay on the primary so they never see stale rows.
+
+// GetTripDetails is GetTrip for the trip details endpoint, which only reads
+// it.
+func (s *Store) GetTripDetails(ctx context.Context, id uuid.UUID) (Trip, error) {
+	return s.reads.GetTrip(ctx, id)
+}
+
+func (s *Store) CountSearchTrips(ctx context.Context, arg CountSearchTripsParams) (int64, error) {
+	return s.reads.CountSearchTrips(ctx, arg)
+}
+
+func (s *Store) CountTripEmailLog(ctx context.Context, tripID uuid.UUID) (int64, error) {
+	return s.reads.CountTripEmailLog(ctx, tripID)
+}
+
+func (s *Store) CountTripLinks(ctx context.Context, tripID uuid.UUID) (int64, error) {
+	return s.reads.CountTripLinks(ctx, tripID)
+}
+
+func (s *Store) CountTrips(ctx context.Context, arg CountTripsParams) (int64, error) {
+	return s.reads.CountTrips(ctx, arg)
+}
+
+func (s *Store) CountTripsByParticipantEmail(ctx context.Context, email string) (int64, error) {
+	return s.reads.CountTripsByParticipantEmail(ctx, email)
+}
+
+func (s *Store) GetActivity(ctx context.Context, arg GetActivityParams) (Activity, error) {
+	return s.reads.GetActivity(ctx, arg)
+}
+
+func (s *Store) GetTripActivities(ctx context.Context, arg GetTripActivitiesParams) ([]Activity, error) {
+	return s.reads.GetTripActivities(ctx, arg)
+}
+
+func (s *Store) GetUnconfirmedParticipants(ctx context.Context, arg GetUnconfirmedParticipantsParams) ([]Participant, error) {
+	return s.reads.GetUnconfirmedParticipants(ctx, arg)
+}
+
+func (s *Store) GetTripSummary(ctx context.Context, id uuid.UUID) (GetTripSummaryRow, error) {
+	return s.reads.GetTripSummary(ctx, id)
+}
+
+func (s *Store) ListParticipants(ctx context.Context, arg ListParticipantsParams) ([]Participant, error) {
+	return s.reads.ListParticipants(ctx, arg)
+}
+
+func (s *Store) ListTripEmailLog(ctx context.Context, arg ListTripEmailLogParams) ([]EmailLog, error) {
+	return s.reads.ListTripEmailLog(ctx, arg)
+}
+
+func (s *Store) ListTripLinks(ctx context.Context, arg ListTripLinksParams) ([]Link, error) {
+	return s.reads.ListTripLinks(ctx, arg)
+}
+
+func (s *Store) ListTrips(ctx context.Context, arg ListTripsParams) ([]Trip, error) {
+	return s.reads.ListTrips(ctx, arg)
+}
+
+func (s *Store) ListTripsAfter(ctx context.Context, arg ListTripsAfterParams) ([]Trip, error) {
+	return s.reads.ListTripsAfter(ctx, arg)
+}
+
+func (s *Store) ListTripsByParticipantEmail(ctx context.Context, arg ListTripsByParticipantEmailParams) ([]ListTripsByParticipantEmailRow, error) {
+	return s.reads.ListTripsByParticipantEmail(ctx, arg)
+}
+
+func (s *Store) SearchTrips(ctx context.Context, arg SearchTripsParams) ([]Trip, error) {
+	return s.reads.SearchTrips(ctx, arg)
+}
@@ -0,0 +1,50 @@
+package pgstore
+
+// Trip is the trip the row was read from, without the columns joined to it.
+func (r GetTripSummaryRow) Trip() Trip {
+	return Trip{
+		ID:                    r.ID,
+		Destination:           r.Destination,
+		OwnerEmail:            r.OwnerEmail,
+		OwnerName:             r.OwnerName,
+		IsConfirmed:           r.IsConfirmed,
+		StartsAt:              r.StartsAt,
+		EndsAt:                r.EndsAt,
+		CreatedAt:             r.CreatedAt,
+		Description:           r.Description,
+		ImageUrl:              r.ImageUrl,
+		Archived:              r.Archived,
+		UpdatedAt:             r.UpdatedAt,
+		Status:                r.Status,
+		InvitationsQueuedAt:   r.InvitationsQueuedAt,
+		InvitationsSentAt:     r.InvitationsSentAt,
+		Version:               r.Version,
+		Locale:                r.Locale,
+		OwnerUnsubscribeToken: r.OwnerUnsubscribeToken,
+	}
+}
+
+// Trip is the trip the row was read from, without the columns of the
+// participant.
+func (r ListTripsByParticipantEmailRow) Trip() Trip {
+	return Trip{
+		ID:                    r.ID,
+		Destination:           r.Destination,
+		OwnerEmail:            r.OwnerEmail,
+		OwnerName:             r.OwnerName,
+		IsConfirmed:           r.IsConfirmed,
+		StartsAt:              r.StartsAt,
+		EndsAt:                r.EndsAt,
+		CreatedAt:             r.CreatedAt,
+		Description:           r.Description,
+		ImageUrl:              r.ImageUrl,
+		Archived:              r.Archived,
+		UpdatedAt:             r.UpdatedAt,
+		Status:                r.Status,
+		InvitationsQueuedAt:   r.InvitationsQueuedAt,
+		InvitationsSentAt:     r.InvitationsSentAt,
+		Version:               r.Version,
+		Locale:                r.Locale,
+		OwnerUnsubscribeToken: r.OwnerUnsubscribeToken,
+	}
+}
@@ -0,0 +1,80 @@
+package pgstore
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// planNode is a node of the plan EXPLAIN (FORMAT JSON) outputs.
+type planNode struct {
+	NodeType  string     `json:"Node Type"`
+	IndexName string     `json:"Index Name"`
+	Plans     []planNode `json:"Plans"`
+}
+
+// indexes are the indexes the nodes under n scan.
+func (n planNode) indexes() []string {
+	var names []string
+	if n.IndexName != "" {
+		names = append(names, n.IndexName)
+	}
+	for _, child := range n.Plans {
+		names = append(names, child.indexes()...)
+	}
+	return names
+}
+
+// TestSearchTripsPlan checks SearchTrips can find the trips through the full
+// text and trigram indexes instead of reading every trip, against the database
+// at JOURNEY_TEST_DATABASE_URL. Sequential scans are disabled, a table with a
+// few trips being cheaper to read whole.
+func TestSearchTripsPlan(t *testing.T) {
+	url := os.Getenv("JOURNEY_TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("JOURNEY_TEST_DATABASE_URL is not set")
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.New(ctx, url)
+	if err != nil {
+		t.Fatalf("pgxpool.New: %v", err)
+	}
+	t.Cleanup(pool.Close)
+	if err := Migrate(ctx, pool, zap.NewNop()); err != nil {
+		t.Fatalf("Migrate: %v", err)
+	}
+
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		t.Fatalf("Begin: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	if _, err := tx.Exec(ctx, "SET LOCAL enable_seqscan = off"); err != nil {
+		t.Fatalf("disabling sequential scans: %v", err)
+	}
+
+	var output []byte
+	if err := tx.QueryRow(ctx, "EXPLAIN (FORMAT JSON) "+searchTrips, "lisbon", "%lisbon%", 20, 0).Scan(&output); err != nil {
+		t.Fatalf("EXPLAIN SearchTrips: %v", err)
+	}
+	var plans []struct {
+		Plan planNode `json:"Plan"`
+	}
+	if err := json.Unmarshal(output, &plans); err != nil || len(plans) != 1 {
+		t.Fatalf("decoding the plan %s: %v", output, err)
+	}
+
+	indexes := plans[0].Plan.indexes()
+	for _, want := range []string{"trips_search_idx", "trips_destination_trgm_idx", "trips_owner_name_trgm_idx"} {
+		if !slices.Contains(indexes, want) {
+			t.Errorf("SearchTrips scans the indexes %v, not %s:\n%s", indexes, want, output)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package pgstore_test
+
+import (
+	"context"
+	"journey/internal/pgstore"
+	"journey/internal/storetest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"go.uber.org/zap"
+)
+
+// newPool is a pool of connections to the database at
+// JOURNEY_TEST_DATABASE_URL, which is migrated first, skipping tb when it isn't
+// set. configure, if not nil, changes the config of the pool before it's
+// created.
+func newPool(tb testing.TB, configure func(config *pgxpool.Config)) *pgxpool.Pool {
+	tb.Helper()
+
+	url := os.Getenv("JOURNEY_TEST_DATABASE_URL")
+	if url == "" {
+		tb.Skip("JOURNEY_TEST_DATABASE_URL is not set")
+	}
+
+	config, err := pgxpool.ParseConfig(url)
+	if err != nil {
+		tb.Fatalf("pgxpool.ParseConfig: %v", err)
+	}
+	if configure != nil {
+		configure(config)
+	}
+
+	ctx := context.Background()
+	pool, err := pgxpool.NewWithConfig(ctx, config)
+	if err != nil {
+		tb.Fatalf("pgxpool.NewWithConfig: %v", err)
+	}
+	tb.Cleanup(pool.Close)
+	if err := pgstore.Migrate(ctx, pool, zap.NewNop()); err != nil {
+		tb.Fatalf("Migrate: %v", err)
+	}
+
+	return pool
+}
+
+// newStore is a Store on a newPool. The trips created on it are left behind,
+// every test using trips of its own.
+func newStore(tb testing.TB) *pgstore.Store {
+	pool := newPool(tb, nil)
+	return pgstore.NewStore(pool, pool, 5*time.Second)
+}
+
+func TestStore(t *testing.T) {
+	store := newStore(t)
+	storetest.Run(t, func(t *testing.T) storetest.Store {
+		return store
+	})
+}
+
+func BenchmarkCreateTrip(b *testing.B) {
+	storetest.BenchmarkCreateTrip(b, newStore(b))
+}
@@ -0,0 +1,96 @@
+package pgstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ErrQueryTimeout is a query of the Store that took longer than its timeout,
+// the deadline error staying wrapped along with it.
+var ErrQueryTimeout = errors.New("pgstore: query timed out")
+
+// DefaultQueryTimeout is how long a query of the Store may take when
+// JOURNEY_DATABASE_QUERY_TIMEOUT isn't set, well within the server's write
+// timeout so a slow query still gets a response.
+const DefaultQueryTimeout = 2 * time.Second
+
+// timeoutDB runs every query on db with its own deadline, timeout from when
+// it's sent. The rows of a query are read under the same deadline.
+type timeoutDB struct {
+	db      DBTX
+	timeout time.Duration
+}
+
+func (t timeoutDB) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	tag, err := t.db.Exec(ctx, sql, args...)
+	return tag, timeoutError(ctx, err)
+}
+
+func (t timeoutDB) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+
+	rows, err := t.db.Query(ctx, sql, args...)
+	if err != nil {
+		cancel()
+		return nil, timeoutError(ctx, err)
+	}
+	return &timeoutRows{rows, ctx, cancel}, nil
+}
+
+func (t timeoutDB) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	return timeoutRow{t.db.QueryRow(ctx, sql, args...), ctx, cancel}
+}
+
+func (t timeoutDB) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	ctx, cancel := context.WithTimeout(ctx, t.timeout)
+	defer cancel()
+
+	n, err := t.db.CopyFrom(ctx, tableName, columnNames, rowSrc)
+	return n, timeoutError(ctx, err)
+}
+
+// timeoutRows releases the deadline of its query once closed.
+type timeoutRows struct {
+	pgx.Rows
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (r *timeoutRows) Close() {
+	r.Rows.Close()
+	r.cancel()
+}
+
+func (r *timeoutRows) Err() error {
+	return timeoutError(r.ctx, r.Rows.Err())
+}
+
+// timeoutRow releases the deadline of its query once scanned.
+type timeoutRow struct {
+	row    pgx.Row
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (r timeoutRow) Scan(dest ...any) error {
+	defer r.cancel()
+	return timeoutError(r.ctx, r.row.Scan(dest...))
+}
+
+// timeoutError reports err as ErrQueryTimeout if ctx, the context of the
+// query that failed, ran out of time.
+func timeoutError(ctx context.Context, err error) error {
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %w", ErrQueryTimeout, err)
+	}
+	return err
+}
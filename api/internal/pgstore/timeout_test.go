@@ -0,0 +1,136 @@
+package pgstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// slowDB is a database answering every query after delay, unless its context
+// is done first, failing as pgx does then.
+type slowDB struct {
+	delay time.Duration
+}
+
+func (db slowDB) wait(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return fmt.Errorf("timeout: context done: %w", ctx.Err())
+	case <-time.After(db.delay):
+		return nil
+	}
+}
+
+func (db slowDB) Exec(ctx context.Context, sql string, args ...interface{}) (pgconn.CommandTag, error) {
+	return pgconn.CommandTag{}, db.wait(ctx)
+}
+
+func (db slowDB) Query(ctx context.Context, sql string, args ...interface{}) (pgx.Rows, error) {
+	if err := db.wait(ctx); err != nil {
+		return nil, err
+	}
+	return noRows{}, nil
+}
+
+func (db slowDB) QueryRow(ctx context.Context, sql string, args ...interface{}) pgx.Row {
+	return slowRow{db, ctx}
+}
+
+func (db slowDB) CopyFrom(ctx context.Context, tableName pgx.Identifier, columnNames []string, rowSrc pgx.CopyFromSource) (int64, error) {
+	return 0, db.wait(ctx)
+}
+
+// slowRow is read, like pgx rows are, under the context of its query.
+type slowRow struct {
+	db  slowDB
+	ctx context.Context
+}
+
+func (r slowRow) Scan(dest ...any) error {
+	return r.db.wait(r.ctx)
+}
+
+// noRows is the empty result of a query. Only the methods reading it are
+// implemented.
+type noRows struct {
+	pgx.Rows
+}
+
+func (noRows) Next() bool { return false }
+func (noRows) Err() error { return nil }
+func (noRows) Close()     {}
+
+func TestTimeoutDB(t *testing.T) {
+	queries := map[string]func(ctx context.Context, db DBTX) error{
+		"Exec": func(ctx context.Context, db DBTX) error {
+			_, err := db.Exec(ctx, "UPDATE trips SET archived = TRUE")
+			return err
+		},
+		"Query": func(ctx context.Context, db DBTX) error {
+			rows, err := db.Query(ctx, "SELECT id FROM trips")
+			if err != nil {
+				return err
+			}
+			defer rows.Close()
+			for rows.Next() {
+			}
+			return rows.Err()
+		},
+		"QueryRow": func(ctx context.Context, db DBTX) error {
+			var id string
+			return db.QueryRow(ctx, "SELECT id FROM trips LIMIT 1").Scan(&id)
+		},
+		"CopyFrom": func(ctx context.Context, db DBTX) error {
+			_, err := db.CopyFrom(ctx, pgx.Identifier{"participants"}, []string{"email"}, pgx.CopyFromRows(nil))
+			return err
+		},
+	}
+
+	for name, query := range queries {
+		t.Run(name, func(t *testing.T) {
+			t.Run("within the timeout", func(t *testing.T) {
+				db := timeoutDB{slowDB{delay: time.Millisecond}, time.Second}
+				if err := query(context.Background(), db); err != nil {
+					t.Errorf("%s = %v, want no error", name, err)
+				}
+			})
+
+			t.Run("over the timeout", func(t *testing.T) {
+				db := timeoutDB{slowDB{delay: time.Minute}, 10 * time.Millisecond}
+				start := time.Now()
+				err := query(context.Background(), db)
+				if !errors.Is(err, ErrQueryTimeout) || !errors.Is(err, context.DeadlineExceeded) {
+					t.Errorf("%s = %v, want ErrQueryTimeout wrapping context.DeadlineExceeded", name, err)
+				}
+				if elapsed := time.Since(start); elapsed > 5*time.Second {
+					t.Errorf("%s returned after %v, well after its timeout", name, elapsed)
+				}
+			})
+
+			t.Run("cancelled by the caller", func(t *testing.T) {
+				db := timeoutDB{slowDB{delay: time.Minute}, time.Minute}
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				err := query(ctx, db)
+				if !errors.Is(err, context.Canceled) || errors.Is(err, ErrQueryTimeout) {
+					t.Errorf("%s = %v, want context.Canceled and no ErrQueryTimeout", name, err)
+				}
+			})
+
+			t.Run("caller's deadline expired", func(t *testing.T) {
+				db := timeoutDB{slowDB{delay: time.Minute}, time.Minute}
+				ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+				defer cancel()
+				err := query(ctx, db)
+				if !errors.Is(err, context.DeadlineExceeded) {
+					t.Errorf("%s = %v, want context.DeadlineExceeded", name, err)
+				}
+			})
+		})
+	}
+}
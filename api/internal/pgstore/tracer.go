@@ -0,0 +1,98 @@
+package pgstore
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+	"github.com/jackc/pgx/v5"
+	"go.uber.org/zap"
+)
+
+// DefaultSlowQueryThreshold is how long a query may take before the
+// QueryTracer warns about it when JOURNEY_DATABASE_SLOW_QUERY_THRESHOLD isn't
+// set.
+const DefaultSlowQueryThreshold = 500 * time.Millisecond
+
+// QueryTracer measures every query a pool runs for the metrics. With a logger
+// it logs them too, with how long they took and how many rows they returned
+// or changed, at debug level. The ones slower than slowThreshold are logged at
+// warn level, along with the request and the trip they were run for.
+type QueryTracer struct {
+	logger        *zap.Logger
+	slowThreshold time.Duration
+}
+
+// NewQueryTracer traces the queries, logging them with logger unless it's nil.
+func NewQueryTracer(logger *zap.Logger, slowThreshold time.Duration) *QueryTracer {
+	if logger != nil {
+		logger = logger.Named("pgstore")
+	}
+	return &QueryTracer{logger, slowThreshold}
+}
+
+type queryTraceKey struct{}
+
+// queryTrace is what TraceQueryStart keeps of a query for TraceQueryEnd.
+type queryTrace struct {
+	sql     string
+	startAt time.Time
+}
+
+func (t *QueryTracer) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	return context.WithValue(ctx, queryTraceKey{}, queryTrace{data.SQL, time.Now()})
+}
+
+func (t *QueryTracer) TraceQueryEnd(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryEndData) {
+	trace, ok := ctx.Value(queryTraceKey{}).(queryTrace)
+	if !ok {
+		return
+	}
+	duration := time.Since(trace.startAt)
+	name := queryName(trace.sql)
+	observeQuery(name, duration, data.Err)
+	if t.logger == nil {
+		return
+	}
+
+	// The statements sqlc didn't generate are logged by their first line
+	if name == "" {
+		name, _, _ = strings.Cut(strings.TrimSpace(trace.sql), "\n")
+	}
+	fields := []zap.Field{
+		zap.String("query", name),
+		zap.Duration("duration", duration),
+		zap.Int64("rows", data.CommandTag.RowsAffected()),
+	}
+	if data.Err != nil {
+		fields = append(fields, zap.Error(data.Err))
+	}
+
+	if duration < t.slowThreshold {
+		t.logger.Debug("Query", fields...)
+		return
+	}
+
+	if requestID := middleware.GetReqID(ctx); requestID != "" {
+		fields = append(fields, zap.String("req_id", requestID))
+	}
+	if routeCtx := chi.RouteContext(ctx); routeCtx != nil {
+		if tripID := routeCtx.URLParam("tripId"); tripID != "" {
+			fields = append(fields, zap.String("trip_id", tripID))
+		}
+	}
+	t.logger.Warn("Slow query", fields...)
+}
+
+// queryName is the name of the query sql is, from the "-- name:" comment
+// sqlc starts it with, empty if it has none.
+func queryName(sql string) string {
+	name, ok := strings.CutPrefix(strings.TrimSpace(sql), "-- name: ")
+	if !ok {
+		return ""
+	}
+	name, _, _ = strings.Cut(name, " ")
+	return name
+}
@@ -2,51 +2,483 @@ package pgstore
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"journey/internal/api/spec"
+	"journey/internal/locales"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-func (q *Queries) CreateTrip(ctx context.Context, pool *pgxpool.Pool, params spec.CreateTripRequest) (uuid.UUID, error) {
-	tx, err := pool.Begin(ctx)
+// The kinds of email_outbox rows, each naming what its subject_id is the ID of.
+const (
+	// EmailConfirmTripOwner asks the owner of a trip to confirm it.
+	EmailConfirmTripOwner = "confirm_trip_owner"
+	// EmailConfirmTripParticipants invites every participant of a trip.
+	EmailConfirmTripParticipants = "confirm_trip_participants"
+	// EmailConfirmTripParticipant invites a single participant.
+	EmailConfirmTripParticipant = "confirm_trip_participant"
+	// EmailInviteReminder reminds a participant of their invitation.
+	EmailInviteReminder = "invite_reminder"
+	// EmailTripCancelled tells the participants of a trip it was cancelled.
+	EmailTripCancelled = "trip_cancelled"
+	// EmailTripCancelledParticipant tells a single participant, one that
+	// couldn't be emailed along with the others.
+	EmailTripCancelledParticipant = "trip_cancelled_participant"
+	// EmailTripItinerary sends the owner of a confirmed trip its activities and
+	// links.
+	EmailTripItinerary = "trip_itinerary"
+	// EmailTripUpdated tells the confirmed participants of a trip what changed
+	// in it, the changes being the payload.
+	EmailTripUpdated = "trip_updated"
+	// EmailTripUpdatedParticipant tells a single confirmed participant, one
+	// that couldn't be emailed along with the others, with the same payload.
+	EmailTripUpdatedParticipant = "trip_updated_participant"
+)
+
+// The statuses of email_outbox rows, failed ones are never retried.
+const (
+	EmailPending = "pending"
+	EmailSent    = "sent"
+	EmailFailed  = "failed"
+)
+
+// The statuses of email_log rows, one per attempt to send an email.
+const (
+	DeliverySent   = "sent"
+	DeliveryFailed = "failed"
+	// DeliverySuppressed is an email dropped because its address unsubscribed.
+	DeliverySuppressed = "suppressed"
+)
+
+// Store is the queries along with the pool their transactions are begun on,
+// so the callers of the functions below don't have to know about it.
+type Store struct {
+	*Queries
+	pool *pgxpool.Pool
+	// reads and readPool serve the read-heavy endpoints, see reads.go.
+	reads    *Queries
+	readPool *pgxpool.Pool
+	// queryTimeout is how long every query may take, see timeout.go.
+	queryTimeout time.Duration
+}
+
+// NewStore runs the queries on pool, except for the ones of the read-heavy
+// endpoints, run on readPool when it isn't nil. Without a replica everything
+// goes to pool. A query taking longer than queryTimeout fails with
+// ErrQueryTimeout.
+func NewStore(pool, readPool *pgxpool.Pool, queryTimeout time.Duration) *Store {
+	if readPool == nil {
+		readPool = pool
+	}
+	return &Store{
+		Queries:      New(timeoutDB{pool, queryTimeout}),
+		pool:         pool,
+		reads:        New(timeoutDB{readPool, queryTimeout}),
+		readPool:     readPool,
+		queryTimeout: queryTimeout,
+	}
+}
+
+// withTx is the queries run in tx, each with the timeout of the Store.
+func (s *Store) withTx(tx pgx.Tx) *Queries {
+	return New(timeoutDB{tx, s.queryTimeout})
+}
+
+// inTx runs fn in a transaction, committing it if fn succeeds. name is the
+// function the errors are reported for, the constraint violations of fn being
+// mapped to their errors.
+func (s *Store) inTx(ctx context.Context, name string, fn func(qtx *Queries) error) error {
+	tx, err := s.pool.Begin(ctx)
 	if err != nil {
-		return uuid.UUID{}, fmt.Errorf("pgstore: failed to begin tx for CreateTrip: %w", err)
+		return fmt.Errorf("pgstore: failed to begin tx for %s: %w", name, err)
 	}
 
 	defer tx.Rollback(ctx)
 
-	qtx := q.WithTx(tx)
+	if err := fn(s.withTx(tx)); err != nil {
+		return mapConstraintError(err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("pgstore: failed to commit tx for %s: %w", name, err)
+	}
+
+	return nil
+}
 
-	tripID, err := qtx.InsertTrip(ctx, InsertTripParams{
+// NewInsertTripParams is the trip a CreateTripRequest inserts, its optional
+// fields left NULL when they're missing or empty.
+func NewInsertTripParams(params spec.CreateTripRequest) InsertTripParams {
+	var description pgtype.Text
+	if params.Description != nil && *params.Description != "" {
+		description = pgtype.Text{Valid: true, String: *params.Description}
+	}
+
+	var imageURL pgtype.Text
+	if params.ImageURL != nil && *params.ImageURL != "" {
+		imageURL = pgtype.Text{Valid: true, String: *params.ImageURL}
+	}
+
+	locale := locales.Default
+	if params.Locale != nil {
+		locale = params.Locale.ToValue()
+	}
+
+	return InsertTripParams{
 		Destination: params.Destination,
 		OwnerEmail:  string(params.OwnerEmail),
 		OwnerName:   params.OwnerName,
 		StartsAt:    pgtype.Timestamp{Valid: true, Time: params.StartsAt},
 		EndsAt:      pgtype.Timestamp{Valid: true, Time: params.EndsAt},
+		Description: description,
+		ImageUrl:    imageURL,
+		Locale:      locale,
+	}
+}
+
+// CreateTrip inserts a trip and invites its participants, enqueuing the email
+// asking the owner to confirm it. Nothing is kept if any of it fails.
+func (s *Store) CreateTrip(ctx context.Context, params spec.CreateTripRequest) (uuid.UUID, error) {
+	var tripID uuid.UUID
+	err := s.inTx(ctx, "CreateTrip", func(qtx *Queries) error {
+		var err error
+		tripID, err = qtx.InsertTrip(ctx, NewInsertTripParams(params))
+		if err != nil {
+			return fmt.Errorf("pgstore: failed to insert trip for CreateTrip: %w", err)
+		}
+
+		participants := make([]InviteParticipantsToTripParams, len(params.EmailsToInvite))
+		for i, eti := range params.EmailsToInvite {
+			participants[i] = InviteParticipantsToTripParams{
+				TripID: tripID,
+				Email:  string(eti),
+			}
+		}
+
+		// A single COPY invites them all, the row it failed at telling whose email it was
+		if _, err := qtx.InviteParticipantsToTrip(ctx, participants); err != nil {
+			if line := copyLine(err); line > 0 && line <= len(participants) {
+				return fmt.Errorf("pgstore: failed to invite %s for CreateTrip: %w", participants[line-1].Email, mapConstraintError(err))
+			}
+			return fmt.Errorf("pgstore: failed to invite participants for CreateTrip: %w", mapConstraintError(err))
+		}
+
+		if err := qtx.EnqueueEmail(ctx, EnqueueEmailParams{Kind: EmailConfirmTripOwner, SubjectID: tripID}); err != nil {
+			return fmt.Errorf("pgstore: failed to enqueue email for CreateTrip: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	return tripID, nil
+}
+
+// DuplicateTrip clones a trip, its activities and its links into a new unconfirmed trip
+// with the same owner, shifting every date by offsetDays. Participants are not copied.
+func (s *Store) DuplicateTrip(ctx context.Context, tripID uuid.UUID, offsetDays int) (uuid.UUID, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return uuid.UUID{}, fmt.Errorf("pgstore: failed to begin tx for DuplicateTrip: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	qtx := s.withTx(tx)
+
+	trip, err := qtx.GetTrip(ctx, tripID)
+	if err != nil {
+		return uuid.UUID{}, fmt.Errorf("pgstore: failed to get trip for DuplicateTrip: %w", err)
+	}
+
+	shift := func(ts pgtype.Timestamp) pgtype.Timestamp {
+		return pgtype.Timestamp{Valid: ts.Valid, Time: ts.Time.AddDate(0, 0, offsetDays)}
+	}
+
+	newTripID, err := qtx.InsertTrip(ctx, InsertTripParams{
+		Destination: trip.Destination,
+		OwnerEmail:  trip.OwnerEmail,
+		OwnerName:   trip.OwnerName,
+		StartsAt:    shift(trip.StartsAt),
+		EndsAt:      shift(trip.EndsAt),
+		Description: trip.Description,
+		ImageUrl:    trip.ImageUrl,
+		Locale:      trip.Locale,
 	})
+	if err != nil {
+		return uuid.UUID{}, fmt.Errorf("pgstore: failed to insert trip for DuplicateTrip: %w", err)
+	}
 
+	activities, err := qtx.GetTripActivities(ctx, GetTripActivitiesParams{TripID: tripID})
 	if err != nil {
-		return uuid.UUID{}, fmt.Errorf("pgstore: failed to insert trip for CreateTrip: %w", err)
+		return uuid.UUID{}, fmt.Errorf("pgstore: failed to get activities for DuplicateTrip: %w", err)
 	}
 
-	participants := make([]InviteParticipantsToTripParams, len(params.EmailsToInvite))
-	for i, eti := range params.EmailsToInvite {
-		participants[i] = InviteParticipantsToTripParams{
-			TripID: tripID,
-			Email:  string(eti),
+	for _, activity := range activities {
+		if _, err := qtx.CreateActivity(ctx, CreateActivityParams{
+			TripID:   newTripID,
+			Title:    activity.Title,
+			OccursAt: shift(activity.OccursAt),
+			Tags:     activity.Tags,
+		}); err != nil {
+			return uuid.UUID{}, fmt.Errorf("pgstore: failed to create activity for DuplicateTrip: %w", err)
 		}
 	}
 
-	if _, err := qtx.InviteParticipantsToTrip(ctx, participants); err != nil {
-		return uuid.UUID{}, fmt.Errorf("pgstore: failed to invite participants for CreateTrip: %w", err)
+	links, err := qtx.GetTripLinks(ctx, tripID)
+	if err != nil {
+		return uuid.UUID{}, fmt.Errorf("pgstore: failed to get links for DuplicateTrip: %w", err)
+	}
+
+	for _, link := range links {
+		if _, err := qtx.CreateTripLink(ctx, CreateTripLinkParams{
+			TripID: newTripID,
+			Title:  link.Title,
+			Url:    link.Url,
+		}); err != nil {
+			return uuid.UUID{}, fmt.Errorf("pgstore: failed to create link for DuplicateTrip: %w", err)
+		}
+	}
+
+	if err := qtx.EnqueueEmail(ctx, EnqueueEmailParams{Kind: EmailConfirmTripOwner, SubjectID: newTripID}); err != nil {
+		return uuid.UUID{}, fmt.Errorf("pgstore: failed to enqueue email for DuplicateTrip: %w", err)
 	}
 
 	if err := tx.Commit(ctx); err != nil {
-		return uuid.UUID{}, fmt.Errorf("pgstore: failed to commit tx for CreateTrip: %w", err)
+		return uuid.UUID{}, fmt.Errorf("pgstore: failed to commit tx for DuplicateTrip: %w", err)
 	}
 
-	return tripID, nil
-}
\ No newline at end of file
+	return newTripID, nil
+}
+
+// ConfirmTripAndInvite confirms a trip and enqueues the invitations of its
+// participants and the itinerary for its owner. It returns 0, enqueuing nothing,
+// if the trip was already confirmed.
+func (s *Store) ConfirmTripAndInvite(ctx context.Context, tripID uuid.UUID) (int64, error) {
+	var confirmed int64
+	err := s.inTx(ctx, "ConfirmTripAndInvite", func(qtx *Queries) error {
+		var err error
+		confirmed, err = qtx.ConfirmTrip(ctx, tripID)
+		if err != nil {
+			return fmt.Errorf("pgstore: failed to confirm trip for ConfirmTripAndInvite: %w", err)
+		}
+		if confirmed == 0 {
+			return nil
+		}
+
+		for _, kind := range []string{EmailConfirmTripParticipants, EmailTripItinerary} {
+			if err := qtx.EnqueueEmail(ctx, EnqueueEmailParams{Kind: kind, SubjectID: tripID}); err != nil {
+				return fmt.Errorf("pgstore: failed to enqueue email for ConfirmTripAndInvite: %w", err)
+			}
+		}
+		return nil
+	})
+	return confirmed, err
+}
+
+// CancelTripAndNotify cancels a trip and enqueues the notice to its participants.
+// It returns 0, enqueuing nothing, if the trip was already cancelled.
+func (s *Store) CancelTripAndNotify(ctx context.Context, tripID uuid.UUID) (int64, error) {
+	var cancelled int64
+	err := s.inTx(ctx, "CancelTripAndNotify", func(qtx *Queries) error {
+		var err error
+		cancelled, err = qtx.CancelTrip(ctx, tripID)
+		if err != nil {
+			return fmt.Errorf("pgstore: failed to cancel trip for CancelTripAndNotify: %w", err)
+		}
+		if cancelled == 0 {
+			return nil
+		}
+
+		if err := qtx.EnqueueEmail(ctx, EnqueueEmailParams{Kind: EmailTripCancelled, SubjectID: tripID}); err != nil {
+			return fmt.Errorf("pgstore: failed to enqueue email for CancelTripAndNotify: %w", err)
+		}
+		return nil
+	})
+	return cancelled, err
+}
+
+// TripChanges is the destination and dates of a trip before and after an
+// update, the payload of an EmailTripUpdated email.
+type TripChanges struct {
+	OldDestination string    `json:"old_destination"`
+	NewDestination string    `json:"new_destination"`
+	OldStartsAt    time.Time `json:"old_starts_at"`
+	NewStartsAt    time.Time `json:"new_starts_at"`
+	OldEndsAt      time.Time `json:"old_ends_at"`
+	NewEndsAt      time.Time `json:"new_ends_at"`
+}
+
+func (c TripChanges) DestinationChanged() bool {
+	return c.OldDestination != c.NewDestination
+}
+
+func (c TripChanges) DatesChanged() bool {
+	return !c.OldStartsAt.Equal(c.NewStartsAt) || !c.OldEndsAt.Equal(c.NewEndsAt)
+}
+
+// Any reports whether the update changed anything the participants are told about.
+func (c TripChanges) Any() bool {
+	return c.DestinationChanged() || c.DatesChanged()
+}
+
+// UpdateTripAndNotify updates a trip and, unless changes is nil, enqueues the
+// email telling its participants about them. It returns 0, enqueuing nothing,
+// if the trip wasn't updated.
+func (s *Store) UpdateTripAndNotify(ctx context.Context, params UpdateTripParams, changes *TripChanges) (int64, error) {
+	var updated int64
+	err := s.inTx(ctx, "UpdateTripAndNotify", func(qtx *Queries) error {
+		var err error
+		updated, err = qtx.UpdateTrip(ctx, params)
+		if err != nil {
+			return fmt.Errorf("pgstore: failed to update trip for UpdateTripAndNotify: %w", err)
+		}
+		if updated == 0 || changes == nil {
+			return nil
+		}
+
+		payload, err := json.Marshal(changes)
+		if err != nil {
+			return fmt.Errorf("pgstore: failed to encode changes for UpdateTripAndNotify: %w", err)
+		}
+
+		if err := qtx.EnqueueEmail(ctx, EnqueueEmailParams{Kind: EmailTripUpdated, SubjectID: params.ID, Payload: payload}); err != nil {
+			return fmt.Errorf("pgstore: failed to enqueue email for UpdateTripAndNotify: %w", err)
+		}
+		return nil
+	})
+	return updated, err
+}
+
+// ResendParticipantInvite stamps the invite of a participant as resent and enqueues
+// the reminder. It returns 0, enqueuing nothing, if the cooldown hasn't passed yet.
+func (s *Store) ResendParticipantInvite(ctx context.Context, params MarkParticipantInviteResentParams) (int64, error) {
+	var resent int64
+	err := s.inTx(ctx, "ResendParticipantInvite", func(qtx *Queries) error {
+		var err error
+		resent, err = qtx.MarkParticipantInviteResent(ctx, params)
+		if err != nil {
+			return fmt.Errorf("pgstore: failed to mark invite as resent for ResendParticipantInvite: %w", err)
+		}
+		if resent == 0 {
+			return nil
+		}
+
+		if err := qtx.EnqueueEmail(ctx, EnqueueEmailParams{Kind: EmailInviteReminder, SubjectID: params.ID}); err != nil {
+			return fmt.Errorf("pgstore: failed to enqueue email for ResendParticipantInvite: %w", err)
+		}
+		return nil
+	})
+	return resent, err
+}
+
+// InviteParticipant invites a participant to a trip, enqueuing their invitation
+// only if notify is set. A participant who declined is invited again, created
+// being false then, anyone else already invited is ErrDuplicateParticipant.
+func (s *Store) InviteParticipant(ctx context.Context, params InviteParticipantToTripParams, notify bool) (uuid.UUID, bool, error) {
+	var participant InviteParticipantToTripRow
+	err := s.inTx(ctx, "InviteParticipant", func(qtx *Queries) error {
+		var err error
+		participant, err = qtx.InviteParticipantToTrip(ctx, params)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrDuplicateParticipant
+		}
+		if err != nil {
+			return fmt.Errorf("pgstore: failed to invite participant for InviteParticipant: %w", err)
+		}
+		if !notify {
+			return nil
+		}
+
+		if err := qtx.EnqueueEmail(ctx, EnqueueEmailParams{Kind: EmailConfirmTripParticipant, SubjectID: participant.ID}); err != nil {
+			return fmt.Errorf("pgstore: failed to enqueue email for InviteParticipant: %w", err)
+		}
+		return nil
+	})
+	return participant.ID, participant.Created, err
+}
+
+// CreateActivities creates every activity in a single transaction, returning their IDs
+// in the same order as params.
+func (s *Store) CreateActivities(ctx context.Context, params []CreateActivityParams) ([]uuid.UUID, error) {
+	tx, err := s.pool.Begin(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("pgstore: failed to begin tx for CreateActivities: %w", err)
+	}
+
+	defer tx.Rollback(ctx)
+
+	qtx := s.withTx(tx)
+
+	ids := make([]uuid.UUID, len(params))
+	for i, p := range params {
+		id, err := qtx.CreateActivity(ctx, p)
+		if err != nil {
+			return nil, mapConstraintError(fmt.Errorf("pgstore: failed to create activity for CreateActivities: %w", err))
+		}
+		ids[i] = id
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return nil, fmt.Errorf("pgstore: failed to commit tx for CreateActivities: %w", err)
+	}
+
+	return ids, nil
+}
+
+// TripExport is a trip along with everything that belongs to it.
+type TripExport struct {
+	Trip         Trip
+	Participants []Participant
+	Activities   []Activity
+	Links        []Link
+}
+
+// ExportTrip fetches a trip, its participants, its activities and its links in a single
+// round trip, on the read replica if any. It returns pgx.ErrNoRows, wrapped, if the trip
+// doesn't exist.
+func (s *Store) ExportTrip(ctx context.Context, tripID uuid.UUID) (TripExport, error) {
+	var export TripExport
+	batch := &pgx.Batch{}
+
+	batch.Queue(getTrip, tripID).Query(func(rows pgx.Rows) error {
+		var err error
+		export.Trip, err = pgx.CollectExactlyOneRow(rows, pgx.RowToStructByName[Trip])
+		return err
+	})
+
+	batch.Queue(getParticipants, tripID).Query(func(rows pgx.Rows) error {
+		var err error
+		export.Participants, err = pgx.CollectRows(rows, pgx.RowToStructByName[Participant])
+		return err
+	})
+
+	batch.Queue(getTripActivities, tripID, pgtype.Timestamp{}, pgtype.Timestamp{}, pgtype.Text{}).Query(func(rows pgx.Rows) error {
+		var err error
+		export.Activities, err = pgx.CollectRows(rows, pgx.RowToStructByName[Activity])
+		return err
+	})
+
+	batch.Queue(getTripLinks, tripID).Query(func(rows pgx.Rows) error {
+		var err error
+		export.Links, err = pgx.CollectRows(rows, pgx.RowToStructByName[Link])
+		return err
+	})
+
+	ctx, cancel := context.WithTimeout(ctx, s.queryTimeout)
+	defer cancel()
+
+	if err := s.readPool.SendBatch(ctx, batch).Close(); err != nil {
+		return TripExport{}, fmt.Errorf("pgstore: failed to send batch for ExportTrip: %w", timeoutError(ctx, err))
+	}
+
+	return export, nil
+}
@@ -0,0 +1,163 @@
+package sqlitestore
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"journey/internal/pgstore"
+
+	"github.com/google/uuid"
+)
+
+const activityColumns = `"id", "trip_id", "title", "occurs_at", "tags", "created_at", "updated_at"`
+
+func scanActivity(row scanner) (pgstore.Activity, error) {
+	var i pgstore.Activity
+	err := row.Scan(
+		&i.ID,
+		&i.TripID,
+		&i.Title,
+		ts(&i.OccursAt),
+		tagsScanner{&i.Tags},
+		ts(&i.CreatedAt),
+		ts(&i.UpdatedAt),
+	)
+	return i, err
+}
+
+func (s *Store) createActivity(ctx context.Context, q querier, arg pgstore.CreateActivityParams) (uuid.UUID, error) {
+	id := newID()
+	created := timestamp(now())
+	_, err := q.ExecContext(ctx, `INSERT INTO activities ( "id", "trip_id", "title", "occurs_at", "tags", "created_at", "updated_at" ) VALUES ( ?, ?, ?, ?, ?, ?, ? )`,
+		id, arg.TripID, arg.Title, timestamp(arg.OccursAt), tags(arg.Tags), created, created)
+	return id, err
+}
+
+func (s *Store) CreateActivity(ctx context.Context, arg pgstore.CreateActivityParams) (uuid.UUID, error) {
+	id, err := s.createActivity(ctx, s.db, arg)
+	return id, mapConstraintError(err)
+}
+
+// CreateActivities creates every activity in a single transaction, returning their IDs
+// in the same order as params.
+func (s *Store) CreateActivities(ctx context.Context, params []pgstore.CreateActivityParams) ([]uuid.UUID, error) {
+	ids := make([]uuid.UUID, len(params))
+	err := s.inTx(ctx, "CreateActivities", func(q querier) error {
+		for i, p := range params {
+			id, err := s.createActivity(ctx, q, p)
+			if err != nil {
+				return fmt.Errorf("sqlitestore: failed to create activity for CreateActivities: %w", err)
+			}
+			ids[i] = id
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+const getTripActivities = `
+SELECT ` + activityColumns + `
+FROM activities
+WHERE
+    trip_id = @trip_id
+    AND (@occurs_from IS NULL OR "occurs_at" >= @occurs_from)
+    AND (@occurs_until IS NULL OR "occurs_at" < @occurs_until)
+    AND (@tag IS NULL OR EXISTS (SELECT 1 FROM json_each("tags") WHERE value = @tag))
+ORDER BY "occurs_at", "id"
+`
+
+func (s *Store) getTripActivities(ctx context.Context, q querier, arg pgstore.GetTripActivitiesParams) ([]pgstore.Activity, error) {
+	return queryRows(ctx, q, scanActivity, getTripActivities,
+		sql.Named("trip_id", arg.TripID),
+		sql.Named("occurs_from", timestamp(arg.OccursFrom)),
+		sql.Named("occurs_until", timestamp(arg.OccursUntil)),
+		sql.Named("tag", arg.Tag),
+	)
+}
+
+func (s *Store) GetTripActivities(ctx context.Context, arg pgstore.GetTripActivitiesParams) ([]pgstore.Activity, error) {
+	return s.getTripActivities(ctx, s.db, arg)
+}
+
+const listActivitiesOutsideRange = `
+SELECT
+    "id", "occurs_at"
+FROM activities
+WHERE
+    trip_id = ?1
+    AND ("occurs_at" < ?2 OR "occurs_at" >= ?3)
+ORDER BY "occurs_at", "id"
+`
+
+func (s *Store) ListActivitiesOutsideRange(ctx context.Context, arg pgstore.ListActivitiesOutsideRangeParams) ([]pgstore.ListActivitiesOutsideRangeRow, error) {
+	return queryRows(ctx, s.db, func(row scanner) (pgstore.ListActivitiesOutsideRangeRow, error) {
+		var i pgstore.ListActivitiesOutsideRangeRow
+		err := row.Scan(&i.ID, ts(&i.OccursAt))
+		return i, err
+	}, listActivitiesOutsideRange, arg.TripID, timestamp(arg.OccursFrom), timestamp(arg.OccursUntil))
+}
+
+func (s *Store) GetActivity(ctx context.Context, arg pgstore.GetActivityParams) (pgstore.Activity, error) {
+	return queryRow(ctx, s.db, scanActivity, `SELECT `+activityColumns+` FROM activities WHERE id = ? AND trip_id = ?`, arg.ID, arg.TripID)
+}
+
+const linkColumns = `"id", "trip_id", "title", "url", "created_at", "updated_at"`
+
+func scanLink(row scanner) (pgstore.Link, error) {
+	var i pgstore.Link
+	err := row.Scan(
+		&i.ID,
+		&i.TripID,
+		&i.Title,
+		&i.Url,
+		ts(&i.CreatedAt),
+		ts(&i.UpdatedAt),
+	)
+	return i, err
+}
+
+func (s *Store) createTripLink(ctx context.Context, q querier, arg pgstore.CreateTripLinkParams) (uuid.UUID, error) {
+	id := newID()
+	created := timestamp(now())
+	_, err := q.ExecContext(ctx, `INSERT INTO links ( "id", "trip_id", "title", "url", "created_at", "updated_at" ) VALUES ( ?, ?, ?, ?, ?, ? )`,
+		id, arg.TripID, arg.Title, arg.Url, created, created)
+	return id, err
+}
+
+func (s *Store) CreateTripLink(ctx context.Context, arg pgstore.CreateTripLinkParams) (uuid.UUID, error) {
+	id, err := s.createTripLink(ctx, s.db, arg)
+	return id, mapConstraintError(err)
+}
+
+func (s *Store) UpdateLink(ctx context.Context, arg pgstore.UpdateLinkParams) error {
+	_, err := s.db.ExecContext(ctx, `UPDATE links SET "title" = ?, "url" = ?, "updated_at" = ? WHERE id = ?`,
+		arg.Title, arg.Url, timestamp(now()), arg.ID)
+	return mapConstraintError(err)
+}
+
+func (s *Store) getTripLinks(ctx context.Context, q querier, tripID uuid.UUID) ([]pgstore.Link, error) {
+	return queryRows(ctx, q, scanLink, `SELECT `+linkColumns+` FROM links WHERE trip_id = ? ORDER BY "created_at", "id"`, tripID)
+}
+
+// ListTripLinks pages through the links of a trip, all of them past the
+// offset without a limit. SQLite takes a negative LIMIT as none.
+func (s *Store) ListTripLinks(ctx context.Context, arg pgstore.ListTripLinksParams) ([]pgstore.Link, error) {
+	limit := int32(-1)
+	if arg.Limit.Valid {
+		limit = arg.Limit.Int32
+	}
+	return queryRows(ctx, s.db, scanLink, `SELECT `+linkColumns+` FROM links WHERE trip_id = ? ORDER BY "created_at", "id" LIMIT ? OFFSET ?`,
+		arg.TripID, limit, arg.Offset)
+}
+
+func (s *Store) CountTripLinks(ctx context.Context, tripID uuid.UUID) (int64, error) {
+	return count(ctx, s.db, `SELECT COUNT(*) FROM links WHERE trip_id = ?`, tripID)
+}
+
+func (s *Store) GetLink(ctx context.Context, id uuid.UUID) (pgstore.Link, error) {
+	return queryRow(ctx, s.db, scanLink, `SELECT `+linkColumns+` FROM links WHERE id = ?`, id)
+}
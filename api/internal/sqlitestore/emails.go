@@ -0,0 +1,143 @@
+package sqlitestore
+
+import (
+	"context"
+	"journey/internal/pgstore"
+
+	"github.com/google/uuid"
+)
+
+func (s *Store) SuppressEmail(ctx context.Context, email string) error {
+	_, err := s.db.ExecContext(ctx, `INSERT INTO email_suppressions ( "email", "created_at" ) VALUES ( ?, ? ) ON CONFLICT ("email") DO NOTHING`,
+		email, timestamp(now()))
+	return err
+}
+
+func (s *Store) IsEmailSuppressed(ctx context.Context, email string) (bool, error) {
+	var suppressed bool
+	err := s.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM email_suppressions WHERE "email" = ?)`, email).Scan(&suppressed)
+	return suppressed, err
+}
+
+// ListSuppressedEmails is the emails that are suppressed, passed to SQLite as
+// a JSON array since it has no arrays of its own.
+func (s *Store) ListSuppressedEmails(ctx context.Context, emails []string) ([]string, error) {
+	return queryRows(ctx, s.db, func(row scanner) (string, error) {
+		var email string
+		err := row.Scan(&email)
+		return email, err
+	}, `SELECT "email" FROM email_suppressions WHERE "email" IN (SELECT value FROM json_each(?))`, tags(emails))
+}
+
+func (s *Store) InsertEmailLog(ctx context.Context, arg pgstore.InsertEmailLogParams) error {
+	_, err := s.db.ExecContext(ctx, `INSERT INTO email_log ( "id", "trip_id", "participant_id", "recipient", "kind", "status", "error", "created_at" ) VALUES ( ?, ?, ?, ?, ?, ?, ?, ? )`,
+		uuid.New(),
+		arg.TripID,
+		arg.ParticipantID,
+		arg.Recipient,
+		arg.Kind,
+		arg.Status,
+		arg.Error,
+		timestamp(now()),
+	)
+	return mapConstraintError(err)
+}
+
+const listTripEmailLog = `
+SELECT
+    "id", "trip_id", "participant_id", "recipient", "kind", "status", "error", "created_at"
+FROM email_log
+WHERE
+    trip_id = ?
+ORDER BY "created_at" DESC, "id"
+LIMIT ? OFFSET ?
+`
+
+func (s *Store) ListTripEmailLog(ctx context.Context, arg pgstore.ListTripEmailLogParams) ([]pgstore.EmailLog, error) {
+	return queryRows(ctx, s.db, func(row scanner) (pgstore.EmailLog, error) {
+		var i pgstore.EmailLog
+		err := row.Scan(
+			&i.ID,
+			&i.TripID,
+			&i.ParticipantID,
+			&i.Recipient,
+			&i.Kind,
+			&i.Status,
+			&i.Error,
+			ts(&i.CreatedAt),
+		)
+		return i, err
+	}, listTripEmailLog, arg.TripID, arg.Limit, arg.Offset)
+}
+
+func (s *Store) CountTripEmailLog(ctx context.Context, tripID uuid.UUID) (int64, error) {
+	return count(ctx, s.db, `SELECT COUNT(*) FROM email_log WHERE trip_id = ?`, tripID)
+}
+
+func (s *Store) enqueueEmail(ctx context.Context, q querier, kind string, subjectID uuid.UUID, payload []byte) error {
+	created := timestamp(now())
+	_, err := q.ExecContext(ctx, `INSERT INTO email_outbox ( "id", "kind", "subject_id", "available_at", "created_at", "payload" ) VALUES ( ?, ?, ?, ?, ?, ? )`,
+		uuid.New(), kind, subjectID, created, created, payload)
+	return err
+}
+
+func (s *Store) EnqueueEmail(ctx context.Context, arg pgstore.EnqueueEmailParams) error {
+	return s.enqueueEmail(ctx, s.db, arg.Kind, arg.SubjectID, arg.Payload)
+}
+
+const claimPendingEmails = `
+UPDATE email_outbox
+SET
+    "attempts" = "attempts" + 1,
+    "available_at" = ?1
+WHERE
+    id IN (
+        SELECT id
+        FROM email_outbox
+        WHERE
+            "status" = 'pending' AND "available_at" <= ?2
+        ORDER BY "available_at"
+        LIMIT ?3
+    )
+RETURNING "id", "kind", "subject_id", "status", "attempts", "last_error", "available_at", "created_at", "sent_at", "payload"
+`
+
+// ClaimPendingEmails leases the pending emails that are due, the ones
+// available the longest first. There's no need to skip the rows another
+// claim locked, the single connection running one claim at a time.
+func (s *Store) ClaimPendingEmails(ctx context.Context, arg pgstore.ClaimPendingEmailsParams) ([]pgstore.EmailOutbox, error) {
+	claimedAt := now()
+	leasedUntil := claimedAt
+	leasedUntil.Time = leasedUntil.Time.Add(interval(arg.Lease))
+
+	return queryRows(ctx, s.db, func(row scanner) (pgstore.EmailOutbox, error) {
+		var i pgstore.EmailOutbox
+		err := row.Scan(
+			&i.ID,
+			&i.Kind,
+			&i.SubjectID,
+			&i.Status,
+			&i.Attempts,
+			&i.LastError,
+			ts(&i.AvailableAt),
+			ts(&i.CreatedAt),
+			ts(&i.SentAt),
+			&i.Payload,
+		)
+		return i, err
+	}, claimPendingEmails, timestamp(leasedUntil), timestamp(claimedAt), arg.Limit)
+}
+
+func (s *Store) MarkEmailSent(ctx context.Context, id uuid.UUID) error {
+	_, err := s.db.ExecContext(ctx, `UPDATE email_outbox SET "status" = 'sent', "last_error" = NULL, "sent_at" = ? WHERE id = ?`,
+		timestamp(now()), id)
+	return err
+}
+
+func (s *Store) MarkEmailFailed(ctx context.Context, arg pgstore.MarkEmailFailedParams) error {
+	retryAt := now()
+	retryAt.Time = retryAt.Time.Add(interval(arg.RetryAfter))
+	_, err := s.db.ExecContext(ctx, `UPDATE email_outbox SET "status" = ?, "last_error" = ?, "available_at" = ? WHERE id = ?`,
+		arg.Status, arg.LastError, timestamp(retryAt), arg.ID)
+	return err
+}
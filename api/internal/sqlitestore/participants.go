@@ -0,0 +1,240 @@
+package sqlitestore
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"journey/internal/pgstore"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const participantColumns = `"id", "trip_id", "email", "is_confirmed", "name", "is_declined", "invite_resent_at", "unsubscribe_token", "invite_sent_at", "last_invite_error"`
+
+func scanParticipant(row scanner) (pgstore.Participant, error) {
+	var i pgstore.Participant
+	err := row.Scan(
+		&i.ID,
+		&i.TripID,
+		&i.Email,
+		&i.IsConfirmed,
+		&i.Name,
+		&i.IsDeclined,
+		ts(&i.InviteResentAt),
+		&i.UnsubscribeToken,
+		ts(&i.InviteSentAt),
+		&i.LastInviteError,
+	)
+	return i, err
+}
+
+func (s *Store) insertParticipant(ctx context.Context, q querier, tripID uuid.UUID, email string) (uuid.UUID, error) {
+	id := newID()
+	_, err := q.ExecContext(ctx, `INSERT INTO participants ( "id", "trip_id", "email", "unsubscribe_token" ) VALUES ( ?, ?, ?, ? )`,
+		id, tripID, email, uuid.New())
+	return id, err
+}
+
+func (s *Store) GetParticipant(ctx context.Context, id uuid.UUID) (pgstore.Participant, error) {
+	return queryRow(ctx, s.db, scanParticipant, `SELECT `+participantColumns+` FROM participants WHERE id = ?`, id)
+}
+
+const getParticipantWithTrip = `
+SELECT
+    p."id", p."trip_id", p."email", p."is_confirmed", p."name", p."is_declined",
+    t."destination", t."owner_name", t."starts_at", t."ends_at"
+FROM participants p
+JOIN trips t ON t.id = p.trip_id
+WHERE
+    p.id = ?
+`
+
+func (s *Store) GetParticipantWithTrip(ctx context.Context, id uuid.UUID) (pgstore.GetParticipantWithTripRow, error) {
+	return queryRow(ctx, s.db, func(row scanner) (pgstore.GetParticipantWithTripRow, error) {
+		var i pgstore.GetParticipantWithTripRow
+		err := row.Scan(
+			&i.ID,
+			&i.TripID,
+			&i.Email,
+			&i.IsConfirmed,
+			&i.Name,
+			&i.IsDeclined,
+			&i.Destination,
+			&i.OwnerName,
+			ts(&i.StartsAt),
+			ts(&i.EndsAt),
+		)
+		return i, err
+	}, getParticipantWithTrip, id)
+}
+
+func (s *Store) getParticipantByEmail(ctx context.Context, q querier, arg pgstore.GetParticipantByEmailParams) (pgstore.Participant, error) {
+	return queryRow(ctx, q, scanParticipant, `SELECT `+participantColumns+` FROM participants WHERE trip_id = ? AND email = ?`, arg.TripID, arg.Email)
+}
+
+func (s *Store) GetParticipantByEmail(ctx context.Context, arg pgstore.GetParticipantByEmailParams) (pgstore.Participant, error) {
+	return s.getParticipantByEmail(ctx, s.db, arg)
+}
+
+// getParticipants is the participants of a trip by ("email", "id"), where
+// pgstore leaves them in no particular order.
+func (s *Store) getParticipants(ctx context.Context, q querier, tripID uuid.UUID) ([]pgstore.Participant, error) {
+	return queryRows(ctx, q, scanParticipant, `SELECT `+participantColumns+` FROM participants WHERE trip_id = ? ORDER BY "email", "id"`, tripID)
+}
+
+func (s *Store) GetParticipants(ctx context.Context, tripID uuid.UUID) ([]pgstore.Participant, error) {
+	return s.getParticipants(ctx, s.db, tripID)
+}
+
+const participantsFilter = `
+    trip_id = @trip_id
+    AND (@is_confirmed IS NULL OR "is_confirmed" = @is_confirmed)
+`
+
+func (s *Store) ListParticipants(ctx context.Context, arg pgstore.ListParticipantsParams) ([]pgstore.Participant, error) {
+	return queryRows(ctx, s.db, scanParticipant, `SELECT `+participantColumns+` FROM participants WHERE `+participantsFilter+` ORDER BY "email", "id" LIMIT @limit OFFSET @offset`,
+		sql.Named("trip_id", arg.TripID),
+		sql.Named("is_confirmed", arg.IsConfirmed),
+		sql.Named("limit", arg.Limit),
+		sql.Named("offset", arg.Offset),
+	)
+}
+
+func (s *Store) GetUnconfirmedParticipants(ctx context.Context, arg pgstore.GetUnconfirmedParticipantsParams) ([]pgstore.Participant, error) {
+	return queryRows(ctx, s.db, scanParticipant, `SELECT `+participantColumns+` FROM participants WHERE trip_id = ? AND NOT "is_confirmed" ORDER BY "email", "id" LIMIT ? OFFSET ?`,
+		arg.TripID, arg.Limit, arg.Offset)
+}
+
+func (s *Store) CountParticipants(ctx context.Context, arg pgstore.CountParticipantsParams) (int64, error) {
+	return count(ctx, s.db, `SELECT COUNT(*) FROM participants WHERE `+participantsFilter,
+		sql.Named("trip_id", arg.TripID),
+		sql.Named("is_confirmed", arg.IsConfirmed),
+	)
+}
+
+func (s *Store) ConfirmParticipant(ctx context.Context, id uuid.UUID) error {
+	_, err := s.db.ExecContext(ctx, `UPDATE participants SET "is_confirmed" = TRUE, "is_declined" = FALSE WHERE id = ?`, id)
+	return err
+}
+
+func (s *Store) DeclineParticipant(ctx context.Context, id uuid.UUID) error {
+	_, err := s.db.ExecContext(ctx, `UPDATE participants SET "is_declined" = TRUE WHERE id = ?`, id)
+	return err
+}
+
+func (s *Store) UpdateParticipantName(ctx context.Context, arg pgstore.UpdateParticipantNameParams) error {
+	_, err := s.db.ExecContext(ctx, `UPDATE participants SET "name" = ? WHERE id = ?`, arg.Name, arg.ID)
+	return err
+}
+
+func (s *Store) ResetParticipantsConfirmation(ctx context.Context, tripID uuid.UUID) error {
+	_, err := s.db.ExecContext(ctx, `UPDATE participants SET "is_confirmed" = FALSE WHERE trip_id = ?`, tripID)
+	return err
+}
+
+func (s *Store) DeleteParticipant(ctx context.Context, id uuid.UUID) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM participants WHERE id = ?`, id)
+	return err
+}
+
+const markParticipantInviteResent = `
+UPDATE participants
+SET
+    "invite_resent_at" = ?1
+WHERE
+    id = ?2
+    AND ("invite_resent_at" IS NULL OR "invite_resent_at" < ?3)
+`
+
+// ResendParticipantInvite stamps the invite of a participant as resent and enqueues
+// the reminder. It returns 0, enqueuing nothing, if the cooldown hasn't passed yet.
+func (s *Store) ResendParticipantInvite(ctx context.Context, params pgstore.MarkParticipantInviteResentParams) (int64, error) {
+	var resent int64
+	err := s.inTx(ctx, "ResendParticipantInvite", func(q querier) error {
+		resentAt := now()
+		cooldownStart := resentAt
+		cooldownStart.Time = cooldownStart.Time.Add(-interval(params.Cooldown))
+
+		var err error
+		resent, err = execRows(ctx, q, markParticipantInviteResent, timestamp(resentAt), params.ID, timestamp(cooldownStart))
+		if err != nil {
+			return fmt.Errorf("sqlitestore: failed to mark invite as resent for ResendParticipantInvite: %w", err)
+		}
+		if resent == 0 {
+			return nil
+		}
+
+		if err := s.enqueueEmail(ctx, q, pgstore.EmailInviteReminder, params.ID, nil); err != nil {
+			return fmt.Errorf("sqlitestore: failed to enqueue email for ResendParticipantInvite: %w", err)
+		}
+		return nil
+	})
+	return resent, err
+}
+
+// InviteParticipant invites a participant to a trip, enqueuing their invitation
+// only if notify is set. A participant who declined is invited again, created
+// being false then, anyone else already invited is ErrDuplicateParticipant.
+func (s *Store) InviteParticipant(ctx context.Context, params pgstore.InviteParticipantToTripParams, notify bool) (uuid.UUID, bool, error) {
+	var participantID uuid.UUID
+	var created bool
+	err := s.inTx(ctx, "InviteParticipant", func(q querier) error {
+		// Matched whatever their case, like the unique index on the addresses
+		participant, err := queryRow(ctx, q, scanParticipant, `SELECT `+participantColumns+` FROM participants WHERE trip_id = ? AND LOWER(email) = LOWER(?)`, params.TripID, params.Email)
+		switch {
+		case errors.Is(err, pgx.ErrNoRows):
+			created = true
+			participantID, err = s.insertParticipant(ctx, q, params.TripID, params.Email)
+			if err != nil {
+				return fmt.Errorf("sqlitestore: failed to invite participant for InviteParticipant: %w", err)
+			}
+		case err != nil:
+			return fmt.Errorf("sqlitestore: failed to get participant for InviteParticipant: %w", err)
+		case !participant.IsDeclined:
+			return pgstore.ErrDuplicateParticipant
+		default:
+			participantID = participant.ID
+			if _, err := q.ExecContext(ctx, `UPDATE participants SET "is_declined" = FALSE, "is_confirmed" = FALSE, "invite_sent_at" = NULL, "last_invite_error" = NULL WHERE id = ?`, participantID); err != nil {
+				return fmt.Errorf("sqlitestore: failed to invite participant again for InviteParticipant: %w", err)
+			}
+		}
+		if !notify {
+			return nil
+		}
+
+		if err := s.enqueueEmail(ctx, q, pgstore.EmailConfirmTripParticipant, participantID, nil); err != nil {
+			return fmt.Errorf("sqlitestore: failed to enqueue email for InviteParticipant: %w", err)
+		}
+		return nil
+	})
+	return participantID, created, err
+}
+
+const recordParticipantInvite = `
+UPDATE participants
+SET
+    "invite_sent_at" = CASE WHEN ?1 IS NULL THEN ?2 ELSE "invite_sent_at" END,
+    "last_invite_error" = ?1
+WHERE
+    id = ?3
+`
+
+func (s *Store) RecordParticipantInvite(ctx context.Context, arg pgstore.RecordParticipantInviteParams) error {
+	_, err := s.db.ExecContext(ctx, recordParticipantInvite, arg.Error, timestamp(now()), arg.ID)
+	return err
+}
+
+const getEmailByUnsubscribeToken = `
+SELECT "email" FROM participants WHERE "unsubscribe_token" = ?1
+UNION
+SELECT "owner_email" FROM trips WHERE "owner_unsubscribe_token" = ?1
+LIMIT 1
+`
+
+func (s *Store) GetEmailByUnsubscribeToken(ctx context.Context, unsubscribeToken uuid.UUID) (string, error) {
+	var email string
+	err := s.db.QueryRowContext(ctx, getEmailByUnsubscribeToken, unsubscribeToken).Scan(&email)
+	return email, noRows(err)
+}
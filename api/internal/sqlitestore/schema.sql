@@ -0,0 +1,121 @@
+-- The schema of the Postgres migrations, up to 038, as SQLite has it. It's
+-- applied on every start, so a change to the migrations must be mirrored here
+-- in a way that's harmless to run again. The full text index of the trips
+-- isn't, the search matching their words with text_match instead.
+--
+-- UUIDs are stored as their canonical text, timestamps as the microseconds
+-- since the Unix epoch, both sorting the way they do on Postgres.
+
+CREATE TABLE IF NOT EXISTS trips (
+    "id"                        TEXT        PRIMARY KEY NOT NULL,
+    "destination"               TEXT                    NOT NULL,
+    "owner_email"               TEXT                    NOT NULL,
+    "owner_name"                TEXT                    NOT NULL,
+    "is_confirmed"              INTEGER                 NOT NULL    DEFAULT FALSE,
+    "starts_at"                 INTEGER                 NOT NULL,
+    "ends_at"                   INTEGER                 NOT NULL,
+    "created_at"                INTEGER                 NOT NULL,
+    "description"               TEXT,
+    "image_url"                 TEXT,
+    "archived"                  INTEGER                 NOT NULL    DEFAULT FALSE,
+    "updated_at"                INTEGER                 NOT NULL,
+    "status"                    TEXT                    NOT NULL    DEFAULT 'active'
+        CHECK ("status" IN ('active', 'cancelled')),
+    "invitations_queued_at"     INTEGER,
+    "invitations_sent_at"       INTEGER,
+    "version"                   INTEGER                 NOT NULL    DEFAULT 1,
+    "locale"                    TEXT                    NOT NULL    DEFAULT 'pt-BR',
+    "owner_unsubscribe_token"   TEXT                    NOT NULL    UNIQUE
+);
+
+CREATE INDEX IF NOT EXISTS trips_owner_email_idx ON trips ("owner_email");
+CREATE INDEX IF NOT EXISTS trips_starts_at_id_idx ON trips ("starts_at", "id");
+
+CREATE TABLE IF NOT EXISTS participants (
+    "id"                TEXT        PRIMARY KEY NOT NULL,
+    "trip_id"           TEXT                    NOT NULL,
+    "email"             TEXT                    NOT NULL,
+    "is_confirmed"      INTEGER                 NOT NULL    DEFAULT FALSE,
+    "name"              TEXT,
+    "is_declined"       INTEGER                 NOT NULL    DEFAULT FALSE,
+    "invite_resent_at"  INTEGER,
+    "unsubscribe_token" TEXT                    NOT NULL    UNIQUE,
+    "invite_sent_at"    INTEGER,
+    "last_invite_error" TEXT,
+
+    FOREIGN KEY (trip_id) REFERENCES trips(id)
+        ON UPDATE CASCADE
+        ON DELETE CASCADE
+);
+
+DROP INDEX IF EXISTS participants_trip_id_email_idx;
+CREATE UNIQUE INDEX IF NOT EXISTS participants_trip_id_lower_email_idx ON participants ("trip_id", LOWER("email"));
+CREATE INDEX IF NOT EXISTS participants_email_idx ON participants ("email");
+CREATE INDEX IF NOT EXISTS participants_unconfirmed_trip_id_idx ON participants ("trip_id") WHERE NOT "is_confirmed";
+
+CREATE TABLE IF NOT EXISTS activities (
+    "id"            TEXT        PRIMARY KEY NOT NULL,
+    "trip_id"       TEXT                    NOT NULL,
+    "title"         TEXT                    NOT NULL,
+    "occurs_at"     INTEGER                 NOT NULL,
+    -- The tags as a JSON array
+    "tags"          TEXT                    NOT NULL    DEFAULT '[]',
+    "created_at"    INTEGER                 NOT NULL,
+    "updated_at"    INTEGER                 NOT NULL,
+
+    FOREIGN KEY (trip_id) REFERENCES trips(id)
+        ON UPDATE CASCADE
+        ON DELETE CASCADE
+);
+
+CREATE INDEX IF NOT EXISTS activities_trip_id_idx ON activities ("trip_id", "occurs_at");
+
+CREATE TABLE IF NOT EXISTS links (
+    "id"            TEXT        PRIMARY KEY NOT NULL,
+    "trip_id"       TEXT                    NOT NULL,
+    "title"         TEXT                    NOT NULL,
+    "url"           TEXT                    NOT NULL,
+    "created_at"    INTEGER                 NOT NULL,
+    "updated_at"    INTEGER                 NOT NULL,
+
+    FOREIGN KEY (trip_id) REFERENCES trips(id)
+        ON UPDATE CASCADE
+        ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS links_trip_id_url_idx ON links ("trip_id", "url");
+
+CREATE TABLE IF NOT EXISTS email_outbox (
+    "id"            TEXT        PRIMARY KEY NOT NULL,
+    "kind"          TEXT                    NOT NULL,
+    "subject_id"    TEXT                    NOT NULL,
+    "status"        TEXT                    NOT NULL    DEFAULT 'pending',
+    "attempts"      INTEGER                 NOT NULL    DEFAULT 0,
+    "last_error"    TEXT,
+    "available_at"  INTEGER                 NOT NULL,
+    "created_at"    INTEGER                 NOT NULL,
+    "sent_at"       INTEGER,
+    "payload"       BLOB
+);
+
+CREATE INDEX IF NOT EXISTS email_outbox_pending_idx ON email_outbox ("available_at") WHERE "status" = 'pending';
+
+CREATE TABLE IF NOT EXISTS email_suppressions (
+    "email"         TEXT        PRIMARY KEY NOT NULL,
+    "created_at"    INTEGER                 NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS email_log (
+    "id"                TEXT        PRIMARY KEY NOT NULL,
+    "trip_id"           TEXT                    NOT NULL,
+    "participant_id"    TEXT,
+    "recipient"         TEXT                    NOT NULL,
+    "kind"              TEXT                    NOT NULL,
+    "status"            TEXT                    NOT NULL,
+    "error"             TEXT,
+    "created_at"        INTEGER                 NOT NULL,
+    FOREIGN KEY (trip_id) REFERENCES trips(id) ON DELETE CASCADE,
+    FOREIGN KEY (participant_id) REFERENCES participants(id) ON DELETE SET NULL
+);
+
+CREATE INDEX IF NOT EXISTS email_log_trip_id_idx ON email_log ("trip_id", "created_at");
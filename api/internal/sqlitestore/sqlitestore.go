@@ -0,0 +1,295 @@
+// Package sqlitestore keeps the trips in a SQLite database, for the
+// deployments a single process serves, behaving like pgstore does on
+// Postgres: a missing row is pgx.ErrNoRows, a violated constraint is the
+// pgstore error it's mapped to and the rows read back are the ones pgstore
+// would have read. It serves the API with JOURNEY_STORE=sqlite.
+package sqlitestore
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"journey/internal/pgstore"
+	"journey/internal/textsearch"
+	"journey/internal/trigram"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+//go:embed schema.sql
+var schema string
+
+func init() {
+	// The search ranks its results like pg_trgm does on Postgres
+	sqlite.MustRegisterDeterministicScalarFunction("similarity", 2, func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		a, _ := args[0].(string)
+		b, _ := args[1].(string)
+		return trigram.Similarity(a, b), nil
+	})
+	// and matches the words of the trips like the full text search does
+	sqlite.MustRegisterDeterministicScalarFunction("text_match", 2, func(_ *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		query, _ := args[0].(string)
+		document, _ := args[1].(string)
+		return textsearch.Match(query, document), nil
+	})
+}
+
+// querier is the database or a transaction on it.
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// Store runs the queries on a SQLite database, on a single connection as
+// SQLite only has one writer at a time anyway.
+type Store struct {
+	db *sql.DB
+}
+
+// Open opens the database at path, creating it along with its schema if it
+// doesn't exist yet.
+func Open(ctx context.Context, path string) (*Store, error) {
+	pragmas := url.Values{"_pragma": {"foreign_keys(1)", "journal_mode(WAL)", "busy_timeout(5000)"}}
+	db, err := sql.Open("sqlite", "file:"+path+"?"+pragmas.Encode())
+	if err != nil {
+		return nil, fmt.Errorf("sqlitestore: failed to open %s: %w", path, err)
+	}
+	db.SetMaxOpenConns(1)
+
+	if _, err := db.ExecContext(ctx, schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("sqlitestore: failed to create the schema of %s: %w", path, err)
+	}
+
+	if err := addColumns(ctx, db); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("sqlitestore: failed to upgrade the schema of %s: %w", path, err)
+	}
+
+	return &Store{db}, nil
+}
+
+// addedColumns are the columns added to the tables of schema.sql since they
+// were first created, which CREATE TABLE IF NOT EXISTS leaves out of the
+// databases created before.
+var addedColumns = []struct{ table, column, definition string }{
+	{"participants", "invite_sent_at", "INTEGER"},
+	{"participants", "last_invite_error", "TEXT"},
+}
+
+// addColumns adds the addedColumns a database doesn't have yet.
+func addColumns(ctx context.Context, db *sql.DB) error {
+	for _, c := range addedColumns {
+		n, err := count(ctx, db, `SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`, c.table, c.column)
+		if err != nil {
+			return err
+		}
+		if n > 0 {
+			continue
+		}
+
+		if _, err := db.ExecContext(ctx, `ALTER TABLE `+c.table+` ADD COLUMN "`+c.column+`" `+c.definition); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// inTx runs fn in a transaction, committing it if fn succeeds. name is the
+// function the errors are reported for, the constraint violations of fn being
+// mapped to their errors.
+func (s *Store) inTx(ctx context.Context, name string, fn func(q querier) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("sqlitestore: failed to begin tx for %s: %w", name, err)
+	}
+
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return mapConstraintError(err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("sqlitestore: failed to commit tx for %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// uniqueErrors are the errors each unique index is reported as when violated,
+// by the columns SQLite names in the violation, or the index for the ones on
+// expressions.
+var uniqueErrors = map[string]error{
+	"index 'participants_trip_id_lower_email_idx'": pgstore.ErrDuplicateParticipant,
+	"links.trip_id, links.url":                     pgstore.ErrDuplicateLink,
+}
+
+// mapConstraintError turns the violation of a unique index or a foreign key
+// into the error pgstore reports it as, leaving any other error as is. The
+// only foreign keys the inserts can violate are the ones to trips.
+func mapConstraintError(err error) error {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return err
+	}
+
+	switch sqliteErr.Code() {
+	case sqlite3.SQLITE_CONSTRAINT_UNIQUE:
+		for columns, mapped := range uniqueErrors {
+			if strings.Contains(sqliteErr.Error(), columns) {
+				return fmt.Errorf("%w: %w", mapped, err)
+			}
+		}
+	case sqlite3.SQLITE_CONSTRAINT_FOREIGNKEY:
+		return fmt.Errorf("%w: %w", pgstore.ErrTripNotFound, err)
+	}
+
+	return err
+}
+
+// noRows reports a missing row as pgx.ErrNoRows, what the API expects from
+// pgstore.
+func noRows(err error) error {
+	if errors.Is(err, sql.ErrNoRows) {
+		return pgx.ErrNoRows
+	}
+	return err
+}
+
+// newID is the ID of a new trip, participant, activity or link, time ordered
+// like the uuidv7() Postgres gives them.
+func newID() uuid.UUID {
+	return uuid.Must(uuid.NewV7())
+}
+
+// now is the value of now() in a TIMESTAMP column.
+func now() pgtype.Timestamp {
+	return pgtype.Timestamp{Valid: true, Time: time.Now().UTC()}
+}
+
+// interval is the duration of an INTERVAL, a month counting as 30 days like
+// Postgres does when adding one to a date isn't possible.
+func interval(i pgtype.Interval) time.Duration {
+	days := time.Duration(i.Months)*30 + time.Duration(i.Days)
+	return days*24*time.Hour + time.Duration(i.Microseconds)*time.Microsecond
+}
+
+// timestamp is how t is stored, NULL if it isn't valid. Like pgx does for a
+// TIMESTAMP, its time zone is dropped, keeping the wall clock, and it's
+// truncated to the microsecond.
+func timestamp(t pgtype.Timestamp) any {
+	if !t.Valid {
+		return nil
+	}
+	wall := time.Date(t.Time.Year(), t.Time.Month(), t.Time.Day(), t.Time.Hour(), t.Time.Minute(), t.Time.Second(), t.Time.Nanosecond(), time.UTC)
+	return wall.UnixMicro()
+}
+
+// timestampScanner reads a timestamp back as pgx does, in UTC.
+type timestampScanner struct {
+	dst *pgtype.Timestamp
+}
+
+func (s timestampScanner) Scan(src any) error {
+	switch src := src.(type) {
+	case nil:
+		*s.dst = pgtype.Timestamp{}
+	case int64:
+		*s.dst = pgtype.Timestamp{Valid: true, Time: time.UnixMicro(src).UTC()}
+	default:
+		return fmt.Errorf("sqlitestore: cannot scan %T into a timestamp", src)
+	}
+	return nil
+}
+
+func ts(dst *pgtype.Timestamp) sql.Scanner {
+	return timestampScanner{dst}
+}
+
+// tags is how the tags of an activity are stored, as a JSON array.
+func tags(t []string) string {
+	if t == nil {
+		t = []string{}
+	}
+	b, _ := json.Marshal(t)
+	return string(b)
+}
+
+// tagsScanner reads the tags of an activity back.
+type tagsScanner struct {
+	dst *[]string
+}
+
+func (s tagsScanner) Scan(src any) error {
+	raw, ok := src.(string)
+	if !ok {
+		return fmt.Errorf("sqlitestore: cannot scan %T into tags", src)
+	}
+	return json.Unmarshal([]byte(raw), s.dst)
+}
+
+// scanner is a row of the result of a query.
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// queryRow is the single row query returns, pgx.ErrNoRows if there's none.
+func queryRow[T any](ctx context.Context, q querier, scan func(scanner) (T, error), query string, args ...any) (T, error) {
+	row, err := scan(q.QueryRowContext(ctx, query, args...))
+	return row, noRows(err)
+}
+
+// queryRows is every row query returns, nil if there's none like pgstore.
+func queryRows[T any](ctx context.Context, q querier, scan func(scanner) (T, error), query string, args ...any) ([]T, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var items []T
+	for rows.Next() {
+		item, err := scan(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+// count is the single number query returns.
+func count(ctx context.Context, q querier, query string, args ...any) (int64, error) {
+	var n int64
+	err := q.QueryRowContext(ctx, query, args...).Scan(&n)
+	return n, err
+}
+
+// execRows is the number of rows the statement changed.
+func execRows(ctx context.Context, q querier, query string, args ...any) (int64, error) {
+	result, err := q.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,29 @@
+package sqlitestore_test
+
+import (
+	"context"
+	"journey/internal/sqlitestore"
+	"journey/internal/storetest"
+	"path/filepath"
+	"testing"
+)
+
+func TestStore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T) storetest.Store {
+		store, err := sqlitestore.Open(context.Background(), filepath.Join(t.TempDir(), "journey.db"))
+		if err != nil {
+			t.Fatalf("Open: %v", err)
+		}
+		t.Cleanup(func() { store.Close() })
+		return store
+	})
+}
+
+func BenchmarkCreateTrip(b *testing.B) {
+	store, err := sqlitestore.Open(context.Background(), filepath.Join(b.TempDir(), "journey.db"))
+	if err != nil {
+		b.Fatalf("Open: %v", err)
+	}
+	defer store.Close()
+	storetest.BenchmarkCreateTrip(b, store)
+}
@@ -0,0 +1,571 @@
+package sqlitestore
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"journey/internal/api/spec"
+	"journey/internal/pgstore"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const tripColumns = `"id", "destination", "owner_email", "owner_name", "is_confirmed", "starts_at", "ends_at", "created_at", "description", "image_url", "archived", "updated_at", "status", "invitations_queued_at", "invitations_sent_at", "version", "locale", "owner_unsubscribe_token"`
+
+// joinedTripColumns are the tripColumns of trips t, for the queries joining it.
+const joinedTripColumns = `t."id", t."destination", t."owner_email", t."owner_name", t."is_confirmed", t."starts_at", t."ends_at", t."created_at", t."description", t."image_url", t."archived", t."updated_at", t."status", t."invitations_queued_at", t."invitations_sent_at", t."version", t."locale", t."owner_unsubscribe_token"`
+
+// tripDest is where the tripColumns of a row are scanned into.
+func tripDest(t *pgstore.Trip) []any {
+	return []any{
+		&t.ID,
+		&t.Destination,
+		&t.OwnerEmail,
+		&t.OwnerName,
+		&t.IsConfirmed,
+		ts(&t.StartsAt),
+		ts(&t.EndsAt),
+		ts(&t.CreatedAt),
+		&t.Description,
+		&t.ImageUrl,
+		&t.Archived,
+		ts(&t.UpdatedAt),
+		&t.Status,
+		ts(&t.InvitationsQueuedAt),
+		ts(&t.InvitationsSentAt),
+		&t.Version,
+		&t.Locale,
+		&t.OwnerUnsubscribeToken,
+	}
+}
+
+func scanTrip(row scanner) (pgstore.Trip, error) {
+	var t pgstore.Trip
+	err := row.Scan(tripDest(&t)...)
+	return t, err
+}
+
+const insertTrip = `
+INSERT INTO trips
+    ( "id", "destination", "owner_email", "owner_name", "starts_at", "ends_at", "description", "image_url", "locale", "created_at", "updated_at", "owner_unsubscribe_token" ) VALUES
+    ( ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ? )
+`
+
+func (s *Store) insertTrip(ctx context.Context, q querier, arg pgstore.InsertTripParams) (uuid.UUID, error) {
+	id := newID()
+	created := timestamp(now())
+	_, err := q.ExecContext(ctx, insertTrip,
+		id,
+		arg.Destination,
+		arg.OwnerEmail,
+		arg.OwnerName,
+		timestamp(arg.StartsAt),
+		timestamp(arg.EndsAt),
+		arg.Description,
+		arg.ImageUrl,
+		arg.Locale,
+		created,
+		created,
+		uuid.New(),
+	)
+	return id, err
+}
+
+// CreateTrip inserts a trip and invites its participants, enqueuing the email
+// asking the owner to confirm it. Nothing is kept if any of it fails.
+func (s *Store) CreateTrip(ctx context.Context, params spec.CreateTripRequest) (uuid.UUID, error) {
+	var tripID uuid.UUID
+	err := s.inTx(ctx, "CreateTrip", func(q querier) error {
+		var err error
+		tripID, err = s.insertTrip(ctx, q, pgstore.NewInsertTripParams(params))
+		if err != nil {
+			return fmt.Errorf("sqlitestore: failed to insert trip for CreateTrip: %w", err)
+		}
+
+		for _, email := range params.EmailsToInvite {
+			if _, err := s.insertParticipant(ctx, q, tripID, string(email)); err != nil {
+				return fmt.Errorf("sqlitestore: failed to invite %s for CreateTrip: %w", email, mapConstraintError(err))
+			}
+		}
+
+		if err := s.enqueueEmail(ctx, q, pgstore.EmailConfirmTripOwner, tripID, nil); err != nil {
+			return fmt.Errorf("sqlitestore: failed to enqueue email for CreateTrip: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	return tripID, nil
+}
+
+// DuplicateTrip clones a trip, its activities and its links into a new unconfirmed trip
+// with the same owner, shifting every date by offsetDays. Participants are not copied.
+func (s *Store) DuplicateTrip(ctx context.Context, tripID uuid.UUID, offsetDays int) (uuid.UUID, error) {
+	var newTripID uuid.UUID
+	err := s.inTx(ctx, "DuplicateTrip", func(q querier) error {
+		trip, err := s.getTrip(ctx, q, tripID)
+		if err != nil {
+			return fmt.Errorf("sqlitestore: failed to get trip for DuplicateTrip: %w", err)
+		}
+
+		shift := func(ts pgtype.Timestamp) pgtype.Timestamp {
+			return pgtype.Timestamp{Valid: ts.Valid, Time: ts.Time.AddDate(0, 0, offsetDays)}
+		}
+
+		newTripID, err = s.insertTrip(ctx, q, pgstore.InsertTripParams{
+			Destination: trip.Destination,
+			OwnerEmail:  trip.OwnerEmail,
+			OwnerName:   trip.OwnerName,
+			StartsAt:    shift(trip.StartsAt),
+			EndsAt:      shift(trip.EndsAt),
+			Description: trip.Description,
+			ImageUrl:    trip.ImageUrl,
+			Locale:      trip.Locale,
+		})
+		if err != nil {
+			return fmt.Errorf("sqlitestore: failed to insert trip for DuplicateTrip: %w", err)
+		}
+
+		activities, err := s.getTripActivities(ctx, q, pgstore.GetTripActivitiesParams{TripID: tripID})
+		if err != nil {
+			return fmt.Errorf("sqlitestore: failed to get activities for DuplicateTrip: %w", err)
+		}
+
+		for _, activity := range activities {
+			if _, err := s.createActivity(ctx, q, pgstore.CreateActivityParams{
+				TripID:   newTripID,
+				Title:    activity.Title,
+				OccursAt: shift(activity.OccursAt),
+				Tags:     activity.Tags,
+			}); err != nil {
+				return fmt.Errorf("sqlitestore: failed to create activity for DuplicateTrip: %w", err)
+			}
+		}
+
+		links, err := s.getTripLinks(ctx, q, tripID)
+		if err != nil {
+			return fmt.Errorf("sqlitestore: failed to get links for DuplicateTrip: %w", err)
+		}
+
+		for _, link := range links {
+			if _, err := s.createTripLink(ctx, q, pgstore.CreateTripLinkParams{
+				TripID: newTripID,
+				Title:  link.Title,
+				Url:    link.Url,
+			}); err != nil {
+				return fmt.Errorf("sqlitestore: failed to create link for DuplicateTrip: %w", err)
+			}
+		}
+
+		if err := s.enqueueEmail(ctx, q, pgstore.EmailConfirmTripOwner, newTripID, nil); err != nil {
+			return fmt.Errorf("sqlitestore: failed to enqueue email for DuplicateTrip: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return uuid.UUID{}, err
+	}
+
+	return newTripID, nil
+}
+
+// ExportTrip fetches a trip, its participants, its activities and its links in
+// a single transaction. It returns pgx.ErrNoRows, wrapped, if the trip doesn't
+// exist.
+func (s *Store) ExportTrip(ctx context.Context, tripID uuid.UUID) (pgstore.TripExport, error) {
+	var export pgstore.TripExport
+	err := s.inTx(ctx, "ExportTrip", func(q querier) error {
+		var err error
+		if export.Trip, err = s.getTrip(ctx, q, tripID); err != nil {
+			return fmt.Errorf("sqlitestore: failed to get trip for ExportTrip: %w", err)
+		}
+		if export.Participants, err = s.getParticipants(ctx, q, tripID); err != nil {
+			return fmt.Errorf("sqlitestore: failed to get participants for ExportTrip: %w", err)
+		}
+		if export.Activities, err = s.getTripActivities(ctx, q, pgstore.GetTripActivitiesParams{TripID: tripID}); err != nil {
+			return fmt.Errorf("sqlitestore: failed to get activities for ExportTrip: %w", err)
+		}
+		if export.Links, err = s.getTripLinks(ctx, q, tripID); err != nil {
+			return fmt.Errorf("sqlitestore: failed to get links for ExportTrip: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return pgstore.TripExport{}, err
+	}
+
+	return export, nil
+}
+
+const getTrip = `SELECT ` + tripColumns + ` FROM trips WHERE id = ?`
+
+func (s *Store) getTrip(ctx context.Context, q querier, id uuid.UUID) (pgstore.Trip, error) {
+	return queryRow(ctx, q, scanTrip, getTrip, id)
+}
+
+func (s *Store) GetTrip(ctx context.Context, id uuid.UUID) (pgstore.Trip, error) {
+	return s.getTrip(ctx, s.db, id)
+}
+
+// GetTripDetails is GetTrip, the Postgres store reading it from its replica.
+func (s *Store) GetTripDetails(ctx context.Context, id uuid.UUID) (pgstore.Trip, error) {
+	return s.GetTrip(ctx, id)
+}
+
+const getTripSummary = `
+SELECT
+    ` + tripColumns + `,
+    (SELECT COUNT(*) FROM participants p WHERE p.trip_id = t.id) AS "participants_count",
+    (SELECT COUNT(*) FROM participants p WHERE p.trip_id = t.id AND p.is_confirmed) AS "confirmed_participants_count",
+    (SELECT COUNT(*) FROM activities a WHERE a.trip_id = t.id) AS "activities_count",
+    (SELECT COUNT(*) FROM links l WHERE l.trip_id = t.id) AS "links_count"
+FROM trips t
+WHERE
+    t.id = ?
+`
+
+func (s *Store) GetTripSummary(ctx context.Context, id uuid.UUID) (pgstore.GetTripSummaryRow, error) {
+	return queryRow(ctx, s.db, func(row scanner) (pgstore.GetTripSummaryRow, error) {
+		var t pgstore.Trip
+		var i pgstore.GetTripSummaryRow
+		err := row.Scan(append(tripDest(&t),
+			&i.ParticipantsCount,
+			&i.ConfirmedParticipantsCount,
+			&i.ActivitiesCount,
+			&i.LinksCount,
+		)...)
+
+		i.ID = t.ID
+		i.Destination = t.Destination
+		i.OwnerEmail = t.OwnerEmail
+		i.OwnerName = t.OwnerName
+		i.IsConfirmed = t.IsConfirmed
+		i.StartsAt = t.StartsAt
+		i.EndsAt = t.EndsAt
+		i.CreatedAt = t.CreatedAt
+		i.Description = t.Description
+		i.ImageUrl = t.ImageUrl
+		i.Archived = t.Archived
+		i.UpdatedAt = t.UpdatedAt
+		i.Status = t.Status
+		i.InvitationsQueuedAt = t.InvitationsQueuedAt
+		i.InvitationsSentAt = t.InvitationsSentAt
+		i.Version = t.Version
+		i.Locale = t.Locale
+		i.OwnerUnsubscribeToken = t.OwnerUnsubscribeToken
+		return i, err
+	}, getTripSummary, id)
+}
+
+// tripsFilter is the WHERE clause of the filters of GET /trips.
+const tripsFilter = `
+    (@is_confirmed IS NULL OR "is_confirmed" = @is_confirmed)
+    AND (@owner_email IS NULL OR "owner_email" = @owner_email)
+    AND (@include_archived OR NOT "archived")
+`
+
+func tripsFilterArgs(isConfirmed pgtype.Bool, ownerEmail pgtype.Text, includeArchived bool) []any {
+	return []any{
+		sql.Named("is_confirmed", isConfirmed),
+		sql.Named("owner_email", ownerEmail),
+		sql.Named("include_archived", includeArchived),
+	}
+}
+
+const listTrips = `
+SELECT ` + tripColumns + `
+FROM trips
+WHERE ` + tripsFilter + `
+ORDER BY
+    CASE WHEN @sort_by = 'starts_at' AND NOT @sort_desc THEN "starts_at" END ASC,
+    CASE WHEN @sort_by = 'starts_at' AND @sort_desc THEN "starts_at" END DESC,
+    CASE WHEN @sort_by = 'ends_at' AND NOT @sort_desc THEN "ends_at" END ASC,
+    CASE WHEN @sort_by = 'ends_at' AND @sort_desc THEN "ends_at" END DESC,
+    CASE WHEN @sort_by = 'created_at' AND NOT @sort_desc THEN "created_at" END ASC,
+    CASE WHEN @sort_by = 'created_at' AND @sort_desc THEN "created_at" END DESC,
+    CASE WHEN @sort_by = 'destination' AND NOT @sort_desc THEN "destination" END ASC,
+    CASE WHEN @sort_by = 'destination' AND @sort_desc THEN "destination" END DESC,
+    "id"
+LIMIT @limit OFFSET @offset
+`
+
+func (s *Store) ListTrips(ctx context.Context, arg pgstore.ListTripsParams) ([]pgstore.Trip, error) {
+	return queryRows(ctx, s.db, scanTrip, listTrips, append(tripsFilterArgs(arg.IsConfirmed, arg.OwnerEmail, arg.IncludeArchived),
+		sql.Named("sort_by", arg.SortBy),
+		sql.Named("sort_desc", arg.SortDesc),
+		sql.Named("limit", arg.Limit),
+		sql.Named("offset", arg.Offset),
+	)...)
+}
+
+const listTripsAfter = `
+SELECT ` + tripColumns + `
+FROM trips
+WHERE ` + tripsFilter + `
+    AND ("starts_at", "id") > (@after_starts_at, @after_id)
+ORDER BY
+    "starts_at", "id"
+LIMIT @limit
+`
+
+func (s *Store) ListTripsAfter(ctx context.Context, arg pgstore.ListTripsAfterParams) ([]pgstore.Trip, error) {
+	return queryRows(ctx, s.db, scanTrip, listTripsAfter, append(tripsFilterArgs(arg.IsConfirmed, arg.OwnerEmail, arg.IncludeArchived),
+		sql.Named("after_starts_at", timestamp(arg.AfterStartsAt)),
+		sql.Named("after_id", arg.AfterID),
+		sql.Named("limit", arg.Limit),
+	)...)
+}
+
+const countTrips = `SELECT COUNT(*) FROM trips WHERE ` + tripsFilter
+
+func (s *Store) CountTrips(ctx context.Context, arg pgstore.CountTripsParams) (int64, error) {
+	return count(ctx, s.db, countTrips, tripsFilterArgs(arg.IsConfirmed, arg.OwnerEmail, arg.IncludeArchived)...)
+}
+
+// SQLite's LIKE ignores the case of ASCII letters, as ILIKE does of all. The
+// trips matching every word of the query come first, as ts_rank puts them on
+// Postgres, without being ranked among themselves.
+const searchTripsFilter = `
+    "destination" LIKE @pattern ESCAPE '\' OR "owner_name" LIKE @pattern ESCAPE '\'
+    OR text_match(@query, "destination" || ' ' || COALESCE("description", ''))
+`
+
+const searchTrips = `
+SELECT ` + tripColumns + `
+FROM trips
+WHERE ` + searchTripsFilter + `
+ORDER BY
+    text_match(@query, "destination" || ' ' || COALESCE("description", '')) DESC,
+    MAX(similarity("destination", @query), similarity("owner_name", @query)) DESC,
+    "id"
+LIMIT @limit OFFSET @offset
+`
+
+func (s *Store) SearchTrips(ctx context.Context, arg pgstore.SearchTripsParams) ([]pgstore.Trip, error) {
+	return queryRows(ctx, s.db, scanTrip, searchTrips,
+		sql.Named("query", arg.Query),
+		sql.Named("pattern", arg.Pattern),
+		sql.Named("limit", arg.Limit),
+		sql.Named("offset", arg.Offset),
+	)
+}
+
+const countSearchTrips = `SELECT COUNT(*) FROM trips WHERE ` + searchTripsFilter
+
+func (s *Store) CountSearchTrips(ctx context.Context, arg pgstore.CountSearchTripsParams) (int64, error) {
+	return count(ctx, s.db, countSearchTrips,
+		sql.Named("query", arg.Query),
+		sql.Named("pattern", arg.Pattern),
+	)
+}
+
+const listTripsByParticipantEmail = `
+SELECT
+    ` + joinedTripColumns + `,
+    p."id" AS "participant_id", p."is_confirmed" AS "participant_is_confirmed", p."is_declined" AS "participant_is_declined"
+FROM participants p
+JOIN trips t ON t.id = p.trip_id
+WHERE
+    p.email = @email
+ORDER BY t."starts_at", t."id"
+LIMIT @limit OFFSET @offset
+`
+
+func (s *Store) ListTripsByParticipantEmail(ctx context.Context, arg pgstore.ListTripsByParticipantEmailParams) ([]pgstore.ListTripsByParticipantEmailRow, error) {
+	return queryRows(ctx, s.db, func(row scanner) (pgstore.ListTripsByParticipantEmailRow, error) {
+		var t pgstore.Trip
+		var i pgstore.ListTripsByParticipantEmailRow
+		err := row.Scan(append(tripDest(&t),
+			&i.ParticipantID,
+			&i.ParticipantIsConfirmed,
+			&i.ParticipantIsDeclined,
+		)...)
+
+		i.ID = t.ID
+		i.Destination = t.Destination
+		i.OwnerEmail = t.OwnerEmail
+		i.OwnerName = t.OwnerName
+		i.IsConfirmed = t.IsConfirmed
+		i.StartsAt = t.StartsAt
+		i.EndsAt = t.EndsAt
+		i.CreatedAt = t.CreatedAt
+		i.Description = t.Description
+		i.ImageUrl = t.ImageUrl
+		i.Archived = t.Archived
+		i.UpdatedAt = t.UpdatedAt
+		i.Status = t.Status
+		i.InvitationsQueuedAt = t.InvitationsQueuedAt
+		i.InvitationsSentAt = t.InvitationsSentAt
+		i.Version = t.Version
+		i.Locale = t.Locale
+		i.OwnerUnsubscribeToken = t.OwnerUnsubscribeToken
+		return i, err
+	}, listTripsByParticipantEmail,
+		sql.Named("email", arg.Email),
+		sql.Named("limit", arg.Limit),
+		sql.Named("offset", arg.Offset),
+	)
+}
+
+func (s *Store) CountTripsByParticipantEmail(ctx context.Context, email string) (int64, error) {
+	return count(ctx, s.db, `SELECT COUNT(*) FROM participants WHERE email = ?`, email)
+}
+
+const updateTrip = `
+UPDATE trips
+SET
+    "destination" = @destination,
+    "ends_at" = @ends_at,
+    "starts_at" = @starts_at,
+    "is_confirmed" = @is_confirmed,
+    "description" = @description,
+    "image_url" = @image_url,
+    "invitations_queued_at" = CASE
+        WHEN "destination" IS NOT @destination OR "starts_at" IS NOT @starts_at OR "ends_at" IS NOT @ends_at THEN NULL
+        ELSE "invitations_queued_at"
+    END,
+    "updated_at" = @now,
+    "version" = "version" + 1
+WHERE
+    id = @id
+    AND (@expected_version IS NULL OR "version" = @expected_version)
+`
+
+func (s *Store) updateTrip(ctx context.Context, q querier, arg pgstore.UpdateTripParams) (int64, error) {
+	return execRows(ctx, q, updateTrip,
+		sql.Named("destination", arg.Destination),
+		sql.Named("ends_at", timestamp(arg.EndsAt)),
+		sql.Named("starts_at", timestamp(arg.StartsAt)),
+		sql.Named("is_confirmed", arg.IsConfirmed),
+		sql.Named("description", arg.Description),
+		sql.Named("image_url", arg.ImageUrl),
+		sql.Named("now", timestamp(now())),
+		sql.Named("id", arg.ID),
+		sql.Named("expected_version", arg.ExpectedVersion),
+	)
+}
+
+func (s *Store) UpdateTrip(ctx context.Context, arg pgstore.UpdateTripParams) (int64, error) {
+	return s.updateTrip(ctx, s.db, arg)
+}
+
+// UpdateTripAndNotify updates a trip and, unless changes is nil, enqueues the
+// email telling its participants about them. It returns 0, enqueuing nothing,
+// if the trip wasn't updated.
+func (s *Store) UpdateTripAndNotify(ctx context.Context, params pgstore.UpdateTripParams, changes *pgstore.TripChanges) (int64, error) {
+	var updated int64
+	err := s.inTx(ctx, "UpdateTripAndNotify", func(q querier) error {
+		var err error
+		updated, err = s.updateTrip(ctx, q, params)
+		if err != nil {
+			return fmt.Errorf("sqlitestore: failed to update trip for UpdateTripAndNotify: %w", err)
+		}
+		if updated == 0 || changes == nil {
+			return nil
+		}
+
+		payload, err := json.Marshal(changes)
+		if err != nil {
+			return fmt.Errorf("sqlitestore: failed to encode changes for UpdateTripAndNotify: %w", err)
+		}
+
+		if err := s.enqueueEmail(ctx, q, pgstore.EmailTripUpdated, params.ID, payload); err != nil {
+			return fmt.Errorf("sqlitestore: failed to enqueue email for UpdateTripAndNotify: %w", err)
+		}
+		return nil
+	})
+	return updated, err
+}
+
+func (s *Store) SetTripArchived(ctx context.Context, arg pgstore.SetTripArchivedParams) error {
+	_, err := s.db.ExecContext(ctx, `UPDATE trips SET "archived" = ?, "updated_at" = ?, "version" = "version" + 1 WHERE id = ?`,
+		arg.Archived, timestamp(now()), arg.ID)
+	return err
+}
+
+const confirmTrip = `
+UPDATE trips
+SET
+    "is_confirmed" = TRUE,
+    "invitations_queued_at" = COALESCE("invitations_queued_at", ?1),
+    "invitations_sent_at" = NULL,
+    "updated_at" = ?1,
+    "version" = "version" + 1
+WHERE
+    id = ?2 AND NOT "is_confirmed"
+`
+
+// ConfirmTripAndInvite confirms a trip and enqueues the invitations of its
+// participants and the itinerary for its owner. It returns 0, enqueuing nothing,
+// if the trip was already confirmed.
+func (s *Store) ConfirmTripAndInvite(ctx context.Context, tripID uuid.UUID) (int64, error) {
+	var confirmed int64
+	err := s.inTx(ctx, "ConfirmTripAndInvite", func(q querier) error {
+		var err error
+		confirmed, err = execRows(ctx, q, confirmTrip, timestamp(now()), tripID)
+		if err != nil {
+			return fmt.Errorf("sqlitestore: failed to confirm trip for ConfirmTripAndInvite: %w", err)
+		}
+		if confirmed == 0 {
+			return nil
+		}
+
+		for _, kind := range []string{pgstore.EmailConfirmTripParticipants, pgstore.EmailTripItinerary} {
+			if err := s.enqueueEmail(ctx, q, kind, tripID, nil); err != nil {
+				return fmt.Errorf("sqlitestore: failed to enqueue email for ConfirmTripAndInvite: %w", err)
+			}
+		}
+		return nil
+	})
+	return confirmed, err
+}
+
+const cancelTrip = `
+UPDATE trips
+SET
+    "status" = 'cancelled',
+    "updated_at" = ?,
+    "version" = "version" + 1
+WHERE
+    id = ? AND "status" <> 'cancelled'
+`
+
+// CancelTripAndNotify cancels a trip and enqueues the notice to its participants.
+// It returns 0, enqueuing nothing, if the trip was already cancelled.
+func (s *Store) CancelTripAndNotify(ctx context.Context, tripID uuid.UUID) (int64, error) {
+	var cancelled int64
+	err := s.inTx(ctx, "CancelTripAndNotify", func(q querier) error {
+		var err error
+		cancelled, err = execRows(ctx, q, cancelTrip, timestamp(now()), tripID)
+		if err != nil {
+			return fmt.Errorf("sqlitestore: failed to cancel trip for CancelTripAndNotify: %w", err)
+		}
+		if cancelled == 0 {
+			return nil
+		}
+
+		if err := s.enqueueEmail(ctx, q, pgstore.EmailTripCancelled, tripID, nil); err != nil {
+			return fmt.Errorf("sqlitestore: failed to enqueue email for CancelTripAndNotify: %w", err)
+		}
+		return nil
+	})
+	return cancelled, err
+}
+
+func (s *Store) MarkTripInvitationsSent(ctx context.Context, id uuid.UUID) error {
+	_, err := s.db.ExecContext(ctx, `UPDATE trips SET "invitations_sent_at" = ? WHERE id = ?`, timestamp(now()), id)
+	return err
+}
+
+// DeleteTrip deletes a trip, its participants, activities, links and email log
+// going along with it.
+func (s *Store) DeleteTrip(ctx context.Context, id uuid.UUID) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM trips WHERE id = ?`, id)
+	return err
+}
@@ -0,0 +1,520 @@
+// Package storetest is the behavior the API and the mailer expect from a store,
+// run against each of pgstore, memstore and sqlitestore so they can't drift
+// apart.
+package storetest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"journey/internal/api"
+	"journey/internal/api/spec"
+	"journey/internal/mailer"
+	"journey/internal/pgstore"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/discord-gophers/goapi-gen/types"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Store is everything the API and the mailer read and write.
+type Store interface {
+	api.Store
+	mailer.Store
+}
+
+// Run runs the suite, newStore returning an empty store for each test.
+func Run(t *testing.T, newStore func(t *testing.T) Store) {
+	tests := []struct {
+		name string
+		test func(t *testing.T, s Store)
+	}{
+		{"CreateTrip", testCreateTrip},
+		{"CreateTripDuplicateEmails", testCreateTripDuplicateEmails},
+		{"GetMissingTrip", testGetMissingTrip},
+		{"InviteParticipant", testInviteParticipant},
+		{"InviteParticipantConcurrently", testInviteParticipantConcurrently},
+		{"ConfirmParticipant", testConfirmParticipant},
+		{"ListParticipants", testListParticipants},
+		{"RecordParticipantInvite", testRecordParticipantInvite},
+		{"ConfirmTripAgain", testConfirmTripAgain},
+		{"GetTripActivities", testGetTripActivities},
+		{"CreateTripLink", testCreateTripLink},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.test(t, newStore(t))
+		})
+	}
+}
+
+// startsAt is when the trips of the suite start, a whole second so every
+// store keeps it as is.
+var startsAt = time.Date(2030, time.March, 10, 9, 0, 0, 0, time.UTC)
+
+// createTrip creates a trip inviting emails, failing the test if it can't.
+func createTrip(t *testing.T, s Store, emails ...string) uuid.UUID {
+	t.Helper()
+
+	invites := make([]types.Email, len(emails))
+	for i, email := range emails {
+		invites[i] = types.Email(email)
+	}
+	tripID, err := s.CreateTrip(context.Background(), spec.CreateTripRequest{
+		Destination:    "Lisbon",
+		OwnerEmail:     "owner@example.com",
+		OwnerName:      "Owner",
+		EmailsToInvite: invites,
+		StartsAt:       startsAt,
+		EndsAt:         startsAt.Add(5 * 24 * time.Hour),
+	})
+	if err != nil {
+		t.Fatalf("CreateTrip: %v", err)
+	}
+	return tripID
+}
+
+// participantByEmail is the participant of tripID invited as email.
+func participantByEmail(t *testing.T, s Store, tripID uuid.UUID, email string) pgstore.Participant {
+	t.Helper()
+
+	participant, err := s.GetParticipantByEmail(context.Background(), pgstore.GetParticipantByEmailParams{TripID: tripID, Email: email})
+	if err != nil {
+		t.Fatalf("GetParticipantByEmail(%s): %v", email, err)
+	}
+	return participant
+}
+
+func testCreateTrip(t *testing.T, s Store) {
+	ctx := context.Background()
+	tripID := createTrip(t, s, "ana@example.com", "bruno@example.com")
+
+	trip, err := s.GetTrip(ctx, tripID)
+	if err != nil {
+		t.Fatalf("GetTrip: %v", err)
+	}
+	if trip.Destination != "Lisbon" || trip.OwnerEmail != "owner@example.com" || trip.IsConfirmed {
+		t.Errorf("GetTrip = %+v, want the unconfirmed trip to Lisbon of owner@example.com", trip)
+	}
+	if !trip.StartsAt.Time.Equal(startsAt) {
+		t.Errorf("StartsAt = %v, want %v", trip.StartsAt.Time, startsAt)
+	}
+
+	participants, err := s.GetParticipants(ctx, tripID)
+	if err != nil {
+		t.Fatalf("GetParticipants: %v", err)
+	}
+	if len(participants) != 2 {
+		t.Fatalf("GetParticipants returned %d participants, want 2", len(participants))
+	}
+	for _, participant := range participants {
+		if participant.IsConfirmed || participant.IsDeclined || participant.InviteSentAt.Valid {
+			t.Errorf("participant %s = %+v, want it invited and nothing else", participant.Email, participant)
+		}
+	}
+}
+
+func testCreateTripDuplicateEmails(t *testing.T, s Store) {
+	_, err := s.CreateTrip(context.Background(), spec.CreateTripRequest{
+		Destination:    "Lisbon",
+		OwnerEmail:     "owner@example.com",
+		OwnerName:      "Owner",
+		EmailsToInvite: []types.Email{"ana@example.com", "Ana@Example.com"},
+		StartsAt:       startsAt,
+		EndsAt:         startsAt.Add(5 * 24 * time.Hour),
+	})
+	if !errors.Is(err, pgstore.ErrDuplicateParticipant) {
+		t.Errorf("CreateTrip inviting an address twice in different cases = %v, want ErrDuplicateParticipant", err)
+	}
+}
+
+func testGetMissingTrip(t *testing.T, s Store) {
+	if _, err := s.GetTrip(context.Background(), uuid.New()); !errors.Is(err, pgx.ErrNoRows) {
+		t.Errorf("GetTrip of a missing trip = %v, want pgx.ErrNoRows", err)
+	}
+}
+
+func testInviteParticipant(t *testing.T, s Store) {
+	ctx := context.Background()
+	tripID := createTrip(t, s)
+	params := pgstore.InviteParticipantToTripParams{TripID: tripID, Email: "carla@example.com"}
+
+	participantID, created, err := s.InviteParticipant(ctx, params, false)
+	if err != nil || !created {
+		t.Fatalf("InviteParticipant = %v, %v, want a new participant", created, err)
+	}
+
+	if _, _, err := s.InviteParticipant(ctx, params, false); !errors.Is(err, pgstore.ErrDuplicateParticipant) {
+		t.Errorf("InviteParticipant of an invited address = %v, want ErrDuplicateParticipant", err)
+	}
+	upper := pgstore.InviteParticipantToTripParams{TripID: tripID, Email: "Carla@Example.com"}
+	if _, _, err := s.InviteParticipant(ctx, upper, false); !errors.Is(err, pgstore.ErrDuplicateParticipant) {
+		t.Errorf("InviteParticipant of an invited address in another case = %v, want ErrDuplicateParticipant", err)
+	}
+
+	// A participant who declined is invited again instead
+	if err := s.DeclineParticipant(ctx, participantID); err != nil {
+		t.Fatalf("DeclineParticipant: %v", err)
+	}
+	revivedID, created, err := s.InviteParticipant(ctx, params, false)
+	if err != nil || created || revivedID != participantID {
+		t.Errorf("InviteParticipant of a declined participant = %s, %v, %v, want %s revived", revivedID, created, err, participantID)
+	}
+
+	missing := pgstore.InviteParticipantToTripParams{TripID: uuid.New(), Email: "carla@example.com"}
+	if _, _, err := s.InviteParticipant(ctx, missing, false); !errors.Is(err, pgstore.ErrTripNotFound) {
+		t.Errorf("InviteParticipant to a missing trip = %v, want ErrTripNotFound", err)
+	}
+}
+
+// testInviteParticipantConcurrently invites an address from many goroutines
+// at once: a single one may invite it. The addresses reach the store
+// normalized, so they're spelled the same.
+func testInviteParticipantConcurrently(t *testing.T, s Store) {
+	ctx := context.Background()
+	tripID := createTrip(t, s)
+	params := pgstore.InviteParticipantToTripParams{TripID: tripID, Email: "dora@example.com"}
+
+	const invites = 16
+	var wg sync.WaitGroup
+	results := make(chan error, invites)
+	created := make(chan bool, invites)
+	for range invites {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, ok, err := s.InviteParticipant(ctx, params, false)
+			results <- err
+			created <- ok
+		}()
+	}
+	wg.Wait()
+	close(results)
+	close(created)
+
+	invited := 0
+	for err := range results {
+		switch {
+		case err == nil:
+			invited++
+		case !errors.Is(err, pgstore.ErrDuplicateParticipant):
+			t.Errorf("InviteParticipant = %v, want nil or ErrDuplicateParticipant", err)
+		}
+	}
+	newParticipants := 0
+	for ok := range created {
+		if ok {
+			newParticipants++
+		}
+	}
+	if invited != 1 || newParticipants != 1 {
+		t.Errorf("%d of %d concurrent invites succeeded, %d created a participant, want 1 and 1", invited, invites, newParticipants)
+	}
+
+	participants, err := s.ListParticipants(ctx, pgstore.ListParticipantsParams{TripID: tripID, Limit: 100})
+	if err != nil {
+		t.Fatalf("ListParticipants: %v", err)
+	}
+	var emails []string
+	for _, participant := range participants {
+		emails = append(emails, participant.Email)
+	}
+	if len(emails) != 1 || emails[0] != params.Email {
+		t.Errorf("participants = %v, want a single dora@example.com", emails)
+	}
+}
+
+func testConfirmParticipant(t *testing.T, s Store) {
+	ctx := context.Background()
+	tripID := createTrip(t, s, "ana@example.com")
+	participantID := participantByEmail(t, s, tripID, "ana@example.com").ID
+
+	// A participant who declined can still change their mind
+	if err := s.DeclineParticipant(ctx, participantID); err != nil {
+		t.Fatalf("DeclineParticipant: %v", err)
+	}
+	if err := s.ConfirmParticipant(ctx, participantID); err != nil {
+		t.Fatalf("ConfirmParticipant: %v", err)
+	}
+
+	participant, err := s.GetParticipant(ctx, participantID)
+	if err != nil {
+		t.Fatalf("GetParticipant: %v", err)
+	}
+	if !participant.IsConfirmed || participant.IsDeclined {
+		t.Errorf("after ConfirmParticipant, participant = %+v, want it confirmed and not declined", participant)
+	}
+}
+
+func testListParticipants(t *testing.T, s Store) {
+	ctx := context.Background()
+	tripID := createTrip(t, s, "clara@example.com", "ana@example.com", "bruno@example.com")
+	if err := s.ConfirmParticipant(ctx, participantByEmail(t, s, tripID, "bruno@example.com").ID); err != nil {
+		t.Fatalf("ConfirmParticipant: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		isConfirmed pgtype.Bool
+		want        []string
+	}{
+		{"all", pgtype.Bool{}, []string{"ana@example.com", "bruno@example.com", "clara@example.com"}},
+		{"confirmed", pgtype.Bool{Valid: true, Bool: true}, []string{"bruno@example.com"}},
+		{"unconfirmed", pgtype.Bool{Valid: true, Bool: false}, []string{"ana@example.com", "clara@example.com"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			participants, err := s.ListParticipants(ctx, pgstore.ListParticipantsParams{TripID: tripID, IsConfirmed: tt.isConfirmed, Limit: 10})
+			if err != nil {
+				t.Fatalf("ListParticipants: %v", err)
+			}
+			var got []string
+			for _, participant := range participants {
+				got = append(got, participant.Email)
+			}
+			if !equal(got, tt.want) {
+				t.Errorf("ListParticipants = %v, want %v", got, tt.want)
+			}
+
+			total, err := s.CountParticipants(ctx, pgstore.CountParticipantsParams{TripID: tripID, IsConfirmed: tt.isConfirmed})
+			if err != nil || total != int64(len(tt.want)) {
+				t.Errorf("CountParticipants = %d, %v, want %d", total, err, len(tt.want))
+			}
+		})
+	}
+
+	// GetUnconfirmedParticipants pages through the unconfirmed ones alone
+	var got []string
+	for offset := int32(0); offset < 3; offset++ {
+		participants, err := s.GetUnconfirmedParticipants(ctx, pgstore.GetUnconfirmedParticipantsParams{TripID: tripID, Limit: 1, Offset: offset})
+		if err != nil {
+			t.Fatalf("GetUnconfirmedParticipants: %v", err)
+		}
+		for _, participant := range participants {
+			got = append(got, participant.Email)
+		}
+	}
+	if want := []string{"ana@example.com", "clara@example.com"}; !equal(got, want) {
+		t.Errorf("GetUnconfirmedParticipants one at a time = %v, want %v", got, want)
+	}
+}
+
+func testRecordParticipantInvite(t *testing.T, s Store) {
+	ctx := context.Background()
+	tripID := createTrip(t, s, "ana@example.com")
+	participantID := participantByEmail(t, s, tripID, "ana@example.com").ID
+
+	if err := s.RecordParticipantInvite(ctx, pgstore.RecordParticipantInviteParams{ID: participantID}); err != nil {
+		t.Fatalf("RecordParticipantInvite: %v", err)
+	}
+	sent := participantByEmail(t, s, tripID, "ana@example.com")
+	if !sent.InviteSentAt.Valid || sent.LastInviteError.Valid {
+		t.Fatalf("after a sent invite, participant = %+v, want invite_sent_at set and no error", sent)
+	}
+
+	// A failed attempt keeps when the invite was last sent
+	failure := pgtype.Text{Valid: true, String: "mailbox full"}
+	if err := s.RecordParticipantInvite(ctx, pgstore.RecordParticipantInviteParams{ID: participantID, Error: failure}); err != nil {
+		t.Fatalf("RecordParticipantInvite: %v", err)
+	}
+	failed := participantByEmail(t, s, tripID, "ana@example.com")
+	if !failed.InviteSentAt.Time.Equal(sent.InviteSentAt.Time) || failed.LastInviteError != failure {
+		t.Errorf("after a failed invite, participant = %+v, want invite_sent_at %v and the error", failed, sent.InviteSentAt.Time)
+	}
+}
+
+func testConfirmTripAgain(t *testing.T, s Store) {
+	ctx := context.Background()
+	tripID := createTrip(t, s, "ana@example.com")
+
+	confirm := func() pgstore.Trip {
+		t.Helper()
+		if _, err := s.ConfirmTripAndInvite(ctx, tripID); err != nil {
+			t.Fatalf("ConfirmTripAndInvite: %v", err)
+		}
+		trip, err := s.GetTrip(ctx, tripID)
+		if err != nil {
+			t.Fatalf("GetTrip: %v", err)
+		}
+		return trip
+	}
+	update := func(trip pgstore.Trip, destination string) pgstore.Trip {
+		t.Helper()
+		if _, err := s.UpdateTrip(ctx, pgstore.UpdateTripParams{
+			ID:          tripID,
+			Destination: destination,
+			StartsAt:    trip.StartsAt,
+			EndsAt:      trip.EndsAt,
+			Description: trip.Description,
+			ImageUrl:    trip.ImageUrl,
+		}); err != nil {
+			t.Fatalf("UpdateTrip: %v", err)
+		}
+		updated, err := s.GetTrip(ctx, tripID)
+		if err != nil {
+			t.Fatalf("GetTrip: %v", err)
+		}
+		return updated
+	}
+
+	first := confirm()
+	if !first.InvitationsQueuedAt.Valid {
+		t.Fatalf("after confirming, InvitationsQueuedAt isn't set")
+	}
+
+	// Unconfirming and confirming again keeps the invites already sent
+	update(first, first.Destination)
+	again := confirm()
+	if !again.InvitationsQueuedAt.Time.Equal(first.InvitationsQueuedAt.Time) {
+		t.Errorf("confirmed again, InvitationsQueuedAt = %v, want %v unchanged", again.InvitationsQueuedAt.Time, first.InvitationsQueuedAt.Time)
+	}
+
+	// A new destination makes them stale, queued anew on the next confirmation
+	if moved := update(again, "Porto"); moved.InvitationsQueuedAt.Valid {
+		t.Errorf("after changing the destination, InvitationsQueuedAt = %v, want it unset", moved.InvitationsQueuedAt.Time)
+	}
+	if requeued := confirm(); !requeued.InvitationsQueuedAt.Valid {
+		t.Errorf("confirmed after changing the destination, InvitationsQueuedAt isn't set")
+	}
+}
+
+func testGetTripActivities(t *testing.T, s Store) {
+	ctx := context.Background()
+	tripID := createTrip(t, s)
+
+	titles := map[uuid.UUID]string{}
+	for _, activity := range []struct {
+		title string
+		at    time.Time
+	}{
+		{"Dinner", startsAt.Add(10 * time.Hour)},
+		{"Museum", startsAt.Add(time.Hour)},
+		{"Breakfast", startsAt},
+	} {
+		id, err := s.CreateActivity(ctx, pgstore.CreateActivityParams{
+			TripID:   tripID,
+			Title:    activity.title,
+			OccursAt: pgtype.Timestamp{Valid: true, Time: activity.at},
+			Tags:     []string{},
+		})
+		if err != nil {
+			t.Fatalf("CreateActivity(%s): %v", activity.title, err)
+		}
+		titles[id] = activity.title
+	}
+
+	activities, err := s.GetTripActivities(ctx, pgstore.GetTripActivitiesParams{TripID: tripID})
+	if err != nil {
+		t.Fatalf("GetTripActivities: %v", err)
+	}
+	var got []string
+	for _, activity := range activities {
+		got = append(got, titles[activity.ID])
+	}
+	if want := []string{"Breakfast", "Museum", "Dinner"}; !equal(got, want) {
+		t.Errorf("GetTripActivities = %v, want them by occurs_at %v", got, want)
+	}
+
+	// Activities at the same time are ordered by id, the same on every read
+	sameTime := startsAt.Add(24 * time.Hour)
+	for _, title := range []string{"Boat", "Bus", "Train"} {
+		if _, err := s.CreateActivity(ctx, pgstore.CreateActivityParams{TripID: tripID, Title: title, OccursAt: pgtype.Timestamp{Valid: true, Time: sameTime}, Tags: []string{}}); err != nil {
+			t.Fatalf("CreateActivity(%s): %v", title, err)
+		}
+	}
+	activities, err = s.GetTripActivities(ctx, pgstore.GetTripActivitiesParams{TripID: tripID})
+	if err != nil {
+		t.Fatalf("GetTripActivities: %v", err)
+	}
+	for i := 1; i < len(activities); i++ {
+		prev, activity := activities[i-1], activities[i]
+		if activity.OccursAt.Time.Before(prev.OccursAt.Time) || (activity.OccursAt.Time.Equal(prev.OccursAt.Time) && bytes.Compare(activity.ID[:], prev.ID[:]) <= 0) {
+			t.Errorf("GetTripActivities lists %s (%s, %s) after %s (%s, %s)", activity.Title, activity.OccursAt.Time, activity.ID, prev.Title, prev.OccursAt.Time, prev.ID)
+		}
+	}
+
+	missing := pgstore.CreateActivityParams{TripID: uuid.New(), Title: "Nowhere", OccursAt: pgtype.Timestamp{Valid: true, Time: startsAt}, Tags: []string{}}
+	if _, err := s.CreateActivity(ctx, missing); !errors.Is(err, pgstore.ErrTripNotFound) {
+		t.Errorf("CreateActivity on a missing trip = %v, want ErrTripNotFound", err)
+	}
+}
+
+func testCreateTripLink(t *testing.T, s Store) {
+	ctx := context.Background()
+	tripID := createTrip(t, s)
+	params := pgstore.CreateTripLinkParams{TripID: tripID, Title: "Hotel", Url: "https://example.com/hotel"}
+
+	linkID, err := s.CreateTripLink(ctx, params)
+	if err != nil {
+		t.Fatalf("CreateTripLink: %v", err)
+	}
+	link, err := s.GetLink(ctx, linkID)
+	if err != nil || link.Url != params.Url || link.TripID != tripID {
+		t.Errorf("GetLink = %+v, %v, want the link to %s", link, err, params.Url)
+	}
+
+	if _, err := s.CreateTripLink(ctx, params); !errors.Is(err, pgstore.ErrDuplicateLink) {
+		t.Errorf("CreateTripLink of a linked url = %v, want ErrDuplicateLink", err)
+	}
+
+	// The longest URL the API accepts
+	long := params
+	long.Url = "https://example.com/" + strings.Repeat("a", 2048-len("https://example.com/"))
+	linkID, err = s.CreateTripLink(ctx, long)
+	if err != nil {
+		t.Fatalf("CreateTripLink of a %d characters url: %v", len(long.Url), err)
+	}
+	if link, err := s.GetLink(ctx, linkID); err != nil || link.Url != long.Url {
+		t.Errorf("GetLink of a %d characters url = %d characters, %v, want it whole", len(long.Url), len(link.Url), err)
+	}
+
+	params.TripID = uuid.New()
+	if _, err := s.CreateTripLink(ctx, params); !errors.Is(err, pgstore.ErrTripNotFound) {
+		t.Errorf("CreateTripLink on a missing trip = %v, want ErrTripNotFound", err)
+	}
+}
+
+func equal(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// BenchmarkCreateTrip creates trips inviting 10, 100 and 500 participants.
+func BenchmarkCreateTrip(b *testing.B, s Store) {
+	for _, invitees := range []int{10, 100, 500} {
+		b.Run(strconv.Itoa(invitees)+" invitees", func(b *testing.B) {
+			invites := make([]types.Email, invitees)
+			for i := range invites {
+				invites[i] = types.Email("participant" + strconv.Itoa(i) + "@example.com")
+			}
+			request := spec.CreateTripRequest{
+				Destination:    "Lisbon",
+				OwnerEmail:     "owner@example.com",
+				OwnerName:      "Owner",
+				EmailsToInvite: invites,
+				StartsAt:       startsAt,
+				EndsAt:         startsAt.Add(5 * 24 * time.Hour),
+			}
+
+			b.ResetTimer()
+			for range b.N {
+				if _, err := s.CreateTrip(context.Background(), request); err != nil {
+					b.Fatalf("CreateTrip: %v", err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+// Package textsearch matches the words of a search the way the full text
+// search of Postgres does with the simple configuration, for the stores that
+// don't run on it.
+package textsearch
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+)
+
+// Match reports whether every word of query is a word of document, what
+// websearch_to_tsquery('simple', query) @@ to_tsvector('simple', document)
+// is for a query of plain words. A query without any word matches nothing.
+func Match(query, document string) bool {
+	queryWords := words(query)
+	if len(queryWords) == 0 {
+		return false
+	}
+
+	documentWords := words(document)
+	for _, word := range queryWords {
+		if !slices.Contains(documentWords, word) {
+			return false
+		}
+	}
+	return true
+}
+
+// words are the lowercased words of s, split on anything that isn't a letter
+// or a digit.
+func words(s string) []string {
+	return strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
@@ -0,0 +1,41 @@
+// Package trigram ranks search results the way pg_trgm does on Postgres, for
+// the stores that don't run on it.
+package trigram
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Similarity is the similarity function of pg_trgm: the trigrams a and b
+// share out of all of theirs, from 0 to 1.
+func Similarity(a, b string) float64 {
+	ta, tb := trigrams(a), trigrams(b)
+	if len(ta) == 0 || len(tb) == 0 {
+		return 0
+	}
+
+	shared := 0
+	for t := range ta {
+		if tb[t] {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(ta)+len(tb)-shared)
+}
+
+// trigrams are the trigrams of every word of s, padded with two spaces before
+// and one after like pg_trgm does.
+func trigrams(s string) map[string]bool {
+	set := make(map[string]bool)
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		padded := []rune("  " + word + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			set[string(padded[i:i+3])] = true
+		}
+	}
+	return set
+}